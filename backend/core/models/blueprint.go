@@ -30,20 +30,26 @@ const (
 
 // @Description CronConfig
 type Blueprint struct {
-	Name         string                 `json:"name" validate:"required"`
-	ProjectName  string                 `json:"projectName" gorm:"type:varchar(255)"`
-	Mode         string                 `json:"mode" gorm:"varchar(20)" validate:"required,oneof=NORMAL ADVANCED"`
-	Plan         PipelinePlan           `json:"plan" gorm:"serializer:encdec"`
-	Enable       bool                   `json:"enable"`
-	CronConfig   string                 `json:"cronConfig" format:"* * * * *" example:"0 0 * * 1"`
-	IsManual     bool                   `json:"isManual"`
-	BeforePlan   PipelinePlan           `json:"beforePlan" gorm:"serializer:encdec"`
-	AfterPlan    PipelinePlan           `json:"afterPlan" gorm:"serializer:encdec"`
-	Labels       []string               `json:"labels" gorm:"-"`
-	Connections  []*BlueprintConnection `json:"connections" gorm:"-"`
-	Priority     int                    `json:"priority"` // greater is higher
-	SyncPolicy   `gorm:"embedded"`
-	common.Model `swaggerignore:"true"`
+	Name        string       `json:"name" validate:"required"`
+	ProjectName string       `json:"projectName" gorm:"type:varchar(255)"`
+	Mode        string       `json:"mode" gorm:"varchar(20)" validate:"required,oneof=NORMAL ADVANCED"`
+	Plan        PipelinePlan `json:"plan" gorm:"serializer:encdec"`
+	Enable      bool         `json:"enable"`
+	CronConfig  string       `json:"cronConfig" format:"* * * * *" example:"0 0 * * 1"`
+	IsManual    bool         `json:"isManual"`
+	// TimeZone is an IANA time zone name for CronConfig and BlackoutWindows, server time is used if empty
+	TimeZone        string           `json:"timeZone" gorm:"type:varchar(64)" example:"Europe/Berlin"`
+	BlackoutWindows []BlackoutWindow `json:"blackoutWindows" gorm:"type:json;serializer:json"`
+	// JitterSeconds delays cron runs by a deterministic duration between 0 and JitterSeconds per blueprint
+	JitterSeconds int                    `json:"jitterSeconds" validate:"min=0"`
+	NextRuns      []time.Time            `json:"nextRuns" gorm:"-"`
+	BeforePlan    PipelinePlan           `json:"beforePlan" gorm:"serializer:encdec"`
+	AfterPlan     PipelinePlan           `json:"afterPlan" gorm:"serializer:encdec"`
+	Labels        []string               `json:"labels" gorm:"-"`
+	Connections   []*BlueprintConnection `json:"connections" gorm:"-"`
+	Priority      int                    `json:"priority"` // greater is higher
+	SyncPolicy    `gorm:"embedded"`
+	common.Model  `swaggerignore:"true"`
 }
 
 func (Blueprint) TableName() string {
@@ -83,6 +89,16 @@ func (BlueprintScope) TableName() string {
 	return "_devlake_blueprint_scopes"
 }
 
+// BlackoutWindow prevents the blueprint from being triggered by cron between Start and End (HH:MM) on Days,
+// runs falling into the window are deferred to the End of it. The window spans midnight if End is not
+// later than Start
+type BlackoutWindow struct {
+	// Days are abbreviated weekdays of Start, all days if empty
+	Days  []string `json:"days" validate:"dive,oneof=Mon Tue Wed Thu Fri Sat Sun" example:"Mon,Tue,Wed,Thu,Fri"`
+	Start string   `json:"start" validate:"required" example:"09:00"`
+	End   string   `json:"end" validate:"required" example:"18:00"`
+}
+
 type TriggerSyncPolicy struct {
 	SkipCollectors bool `json:"skipCollectors"`
 	FullSync       bool `json:"fullSync"`
@@ -82,8 +82,11 @@ func NewNoPKModel() NoPKModel {
 }
 
 type Scope struct {
-	NoPKModel     `mapstructure:",squash"`
-	ConnectionId  uint64 `json:"connectionId" gorm:"primaryKey" validate:"required" mapstructure:"connectionId,omitempty"`
+	NoPKModel `mapstructure:",squash"`
+	// ConnectionId is part of the composite primary key of the scope, gorm would take it as an auto increment
+	// column otherwise, which makes it the only primary key on sqlite. Tables of scopes are created by migration
+	// scripts with their own models, so no migration is needed for the existing ones
+	ConnectionId  uint64 `json:"connectionId" gorm:"primaryKey;autoIncrement:false" validate:"required" mapstructure:"connectionId,omitempty"`
 	ScopeConfigId uint64 `json:"scopeConfigId,omitempty" mapstructure:"scopeConfigId,omitempty"`
 }
 
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossdomain
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+const (
+	DORA_SNAPSHOT_PERIOD_WEEK  = "week"
+	DORA_SNAPSHOT_PERIOD_MONTH = "month"
+)
+
+// DoraMetricSnapshot materializes the DORA metrics of a project for a period, snapshots of closed periods are
+// never recalculated so the reported values stay the same unless the definition (version) changes
+type DoraMetricSnapshot struct {
+	ProjectName string    `json:"projectName" gorm:"primaryKey;type:varchar(100)"`
+	PeriodType  string    `json:"periodType" gorm:"primaryKey;type:varchar(20)"`
+	PeriodStart time.Time `json:"periodStart" gorm:"primaryKey"`
+	// DefinitionVersion is the hash of DoraDefinition, check the DoraDefinition for the detail
+	DefinitionVersion string    `json:"definitionVersion" gorm:"primaryKey;type:varchar(20)"`
+	DoraDefinition    string    `json:"doraDefinition" gorm:"type:text"`
+	PeriodEnd         time.Time `json:"periodEnd"`
+	IsClosed          bool      `json:"isClosed"`
+	CalculatedAt      time.Time `json:"calculatedAt"`
+
+	DeploymentCount     int     `json:"deploymentCount"`
+	DeploymentDays      int     `json:"deploymentDays"`
+	DeploymentFrequency float64 `json:"deploymentFrequency"` // deployments per day
+	// LeadTimeMinutes is the median cycle time of pull requests deployed in the period
+	LeadTimeMinutes       *int64   `json:"leadTimeMinutes"`
+	FailedDeploymentCount int      `json:"failedDeploymentCount"`
+	ChangeFailureRate     *float64 `json:"changeFailureRate"`
+	// MttrMinutes is the median time to restore of incidents resolved in the period
+	MttrMinutes   *int64 `json:"mttrMinutes"`
+	IncidentCount int    `json:"incidentCount"`
+	common.NoPKModel
+}
+
+func (DoraMetricSnapshot) TableName() string {
+	return "dora_metric_snapshots"
+}
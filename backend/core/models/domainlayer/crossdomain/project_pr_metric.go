@@ -40,6 +40,11 @@ type ProjectPrMetric struct {
 	PrCreatedDate           *time.Time
 	PrMergedDate            *time.Time
 	PrDeployedDate          *time.Time
+
+	// IsHotfix tells whether the PR is a rework/hotfix per the DoraDefinition
+	IsHotfix bool
+	// DoraDefinition is the json of definitions used for calculating the row, i.e. production environments
+	DoraDefinition string `gorm:"type:text"`
 }
 
 func (ProjectPrMetric) TableName() string {
@@ -0,0 +1,39 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossdomain
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// TeamMembership records the period a user belonged to a team, the membership is still effective if EffectiveTo
+// is nil. An event at time t of the user is attributed to the team with
+// effective_from <= t AND (effective_to IS NULL OR effective_to > t)
+type TeamMembership struct {
+	TeamId        string    `gorm:"primaryKey;type:varchar(255)"`
+	UserId        string    `gorm:"primaryKey;type:varchar(255)"`
+	EffectiveFrom time.Time `gorm:"primaryKey"`
+	EffectiveTo   *time.Time
+	common.NoPKModel
+}
+
+func (TeamMembership) TableName() string {
+	return "team_memberships"
+}
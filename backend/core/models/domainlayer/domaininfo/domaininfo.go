@@ -59,6 +59,8 @@ func GetDomainTablesInfo() []dal.Tabler {
 		// crossdomain
 		&crossdomain.Account{},
 		&crossdomain.BoardRepo{},
+		&crossdomain.DoraMetricSnapshot{},
+		&crossdomain.IncidentAttribution{},
 		&crossdomain.IssueCommit{},
 		&crossdomain.IssueRepoCommit{},
 		&crossdomain.ProjectMapping{},
@@ -67,6 +69,8 @@ func GetDomainTablesInfo() []dal.Tabler {
 		&crossdomain.PullRequestIssue{},
 		&crossdomain.RefsIssuesDiffs{},
 		&crossdomain.Team{},
+		&crossdomain.TeamClosure{},
+		&crossdomain.TeamMembership{},
 		&crossdomain.TeamUser{},
 		&crossdomain.User{},
 		&crossdomain.UserAccount{},
@@ -0,0 +1,49 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addDoraDefinitionToProjectPrMetric)(nil)
+
+type projectPrMetric20261017 struct {
+	IsHotfix       bool
+	DoraDefinition string `gorm:"type:text"`
+}
+
+func (projectPrMetric20261017) TableName() string {
+	return "project_pr_metrics"
+}
+
+type addDoraDefinitionToProjectPrMetric struct{}
+
+func (*addDoraDefinitionToProjectPrMetric) Up(basicRes context.BasicRes) errors.Error {
+	return basicRes.GetDal().AutoMigrate(&projectPrMetric20261017{})
+}
+
+func (*addDoraDefinitionToProjectPrMetric) Version() uint64 {
+	return 20261017140000
+}
+
+func (*addDoraDefinitionToProjectPrMetric) Name() string {
+	return "add is_hotfix and dora_definition to project_pr_metrics"
+}
@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addDoraMetricSnapshots)(nil)
+
+type doraMetricSnapshot20261017 struct {
+	ProjectName           string    `gorm:"primaryKey;type:varchar(100)"`
+	PeriodType            string    `gorm:"primaryKey;type:varchar(20)"`
+	PeriodStart           time.Time `gorm:"primaryKey"`
+	DefinitionVersion     string    `gorm:"primaryKey;type:varchar(20)"`
+	DoraDefinition        string    `gorm:"type:text"`
+	PeriodEnd             time.Time
+	IsClosed              bool
+	CalculatedAt          time.Time
+	DeploymentCount       int
+	DeploymentDays        int
+	DeploymentFrequency   float64
+	LeadTimeMinutes       *int64
+	FailedDeploymentCount int
+	ChangeFailureRate     *float64
+	MttrMinutes           *int64
+	IncidentCount         int
+	archived.NoPKModel
+}
+
+func (doraMetricSnapshot20261017) TableName() string {
+	return "dora_metric_snapshots"
+}
+
+type addDoraMetricSnapshots struct{}
+
+func (*addDoraMetricSnapshots) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &doraMetricSnapshot20261017{})
+}
+
+func (*addDoraMetricSnapshots) Version() uint64 {
+	return 20261017150000
+}
+
+func (*addDoraMetricSnapshots) Name() string {
+	return "add dora_metric_snapshots"
+}
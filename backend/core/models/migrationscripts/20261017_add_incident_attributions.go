@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addIncidentAttributions)(nil)
+
+type incidentAttribution20261017 struct {
+	IncidentId           string `gorm:"primaryKey;type:varchar(255)"`
+	CicdScopeId          string `gorm:"type:varchar(255)"`
+	CausedByDeploymentId string `gorm:"type:varchar(255)"`
+	archived.NoPKModel
+}
+
+func (incidentAttribution20261017) TableName() string {
+	return "incident_attributions"
+}
+
+type addIncidentAttributions struct{}
+
+func (*addIncidentAttributions) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &incidentAttribution20261017{})
+}
+
+func (*addIncidentAttributions) Version() uint64 {
+	return 20261017180000
+}
+
+func (*addIncidentAttributions) Name() string {
+	return "add incident_attributions"
+}
@@ -0,0 +1,73 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addNotificationRules)(nil)
+
+type addNotificationRules struct{}
+
+type notificationRule20261017 struct {
+	archived.Model
+	Name          string `gorm:"type:varchar(255)"`
+	Enable        bool
+	ProjectName   string `gorm:"type:varchar(255);index"`
+	BlueprintId   uint64 `gorm:"index"`
+	Events        string `gorm:"type:json"`
+	ChannelType   string `gorm:"type:varchar(20)"`
+	ChannelConfig string `gorm:"type:text"`
+	Template      string `gorm:"type:text"`
+	MaxRetries    int
+}
+
+func (notificationRule20261017) TableName() string {
+	return "_devlake_notification_rules"
+}
+
+type notification20261017 struct {
+	RuleId      uint64 `gorm:"index"`
+	ChannelType string `gorm:"type:varchar(20)"`
+	PipelineId  uint64 `gorm:"index"`
+	Event       string `gorm:"type:varchar(20)"`
+	Status      string `gorm:"type:varchar(20)"`
+	Attempts    int
+	Message     string
+}
+
+func (notification20261017) TableName() string {
+	return "_devlake_notifications"
+}
+
+func (script *addNotificationRules) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, new(notificationRule20261017), new(notification20261017))
+}
+
+func (*addNotificationRules) Version() uint64 {
+	return 20261017100000
+}
+
+func (*addNotificationRules) Name() string {
+	return "add notification rules and delivery outcomes of notifications"
+}
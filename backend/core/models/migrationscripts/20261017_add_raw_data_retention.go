@@ -0,0 +1,74 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addRawDataRetention)(nil)
+
+type rawDataRetentionPolicy20261017 struct {
+	archived.Model
+	Table           string `gorm:"type:varchar(255);uniqueIndex"`
+	Enable          bool
+	KeepCollections int
+	KeepDays        int
+	SkipPersistence bool
+}
+
+func (rawDataRetentionPolicy20261017) TableName() string {
+	return "_devlake_raw_data_retention_policies"
+}
+
+type rawDataCollection20261017 struct {
+	ID            uint64 `gorm:"primaryKey"`
+	RawDataTable  string `gorm:"type:varchar(255);index:idx_raw_data_collection"`
+	RawDataParams string `gorm:"type:varchar(255);index:idx_raw_data_collection"`
+	Incremental   bool
+	StartedAt     time.Time
+	FinishedAt    time.Time
+}
+
+func (rawDataCollection20261017) TableName() string {
+	return "_devlake_raw_data_collections"
+}
+
+type addRawDataRetention struct{}
+
+func (*addRawDataRetention) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&rawDataRetentionPolicy20261017{},
+		&rawDataCollection20261017{},
+	)
+}
+
+func (*addRawDataRetention) Version() uint64 {
+	return 20261017130000
+}
+
+func (*addRawDataRetention) Name() string {
+	return "add retention policies and collections of raw tables"
+}
@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addRefdiffReleaseNotes)(nil)
+
+type refdiffReleaseNotes20261017 struct {
+	NewRefId     string `gorm:"primaryKey;type:varchar(255)"`
+	OldRefId     string `gorm:"primaryKey;type:varchar(255)"`
+	RepoId       string `gorm:"type:varchar(255);index"`
+	NewCommitSha string `gorm:"type:varchar(40)"`
+	OldCommitSha string `gorm:"type:varchar(40)"`
+	Markdown     string `gorm:"type:text"`
+	Content      string `gorm:"type:text"`
+	archived.NoPKModel
+}
+
+func (refdiffReleaseNotes20261017) TableName() string {
+	return "_tool_refdiff_release_notes"
+}
+
+type addRefdiffReleaseNotes struct{}
+
+func (*addRefdiffReleaseNotes) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &refdiffReleaseNotes20261017{})
+}
+
+func (*addRefdiffReleaseNotes) Version() uint64 {
+	return 20261017170000
+}
+
+func (*addRefdiffReleaseNotes) Name() string {
+	return "add _tool_refdiff_release_notes"
+}
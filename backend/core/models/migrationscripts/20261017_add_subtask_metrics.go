@@ -0,0 +1,65 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addSubtaskMetrics)(nil)
+
+type subtaskMetric20261017 struct {
+	TaskID          uint64 `gorm:"primaryKey"`
+	Name            string `gorm:"primaryKey;type:varchar(255)"`
+	PipelineID      uint64 `gorm:"index"`
+	Plugin          string `gorm:"type:varchar(255)"`
+	BeganAt         *time.Time
+	FinishedAt      *time.Time
+	DurationMs      int64
+	RowsRead        int64
+	RowsWritten     int64
+	HttpRequests    int64
+	HttpRetries     int64
+	HttpRateLimited int64
+	IsFailed        bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (subtaskMetric20261017) TableName() string {
+	return "_devlake_subtask_metrics"
+}
+
+type addSubtaskMetrics struct{}
+
+func (*addSubtaskMetrics) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &subtaskMetric20261017{})
+}
+
+func (*addSubtaskMetrics) Version() uint64 {
+	return 20261017190000
+}
+
+func (*addSubtaskMetrics) Name() string {
+	return "add _devlake_subtask_metrics"
+}
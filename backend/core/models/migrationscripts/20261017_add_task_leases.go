@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addTaskLeases)(nil)
+
+type worker20261017 struct {
+	Id          string `gorm:"primaryKey;type:varchar(255)"`
+	HostName    string `gorm:"type:varchar(255)"`
+	Labels      string `gorm:"type:json"`
+	Concurrency int
+	StartedAt   time.Time
+	HeartbeatAt time.Time `gorm:"index"`
+}
+
+func (worker20261017) TableName() string {
+	return "_devlake_workers"
+}
+
+type taskLease20261017 struct {
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	TaskId          uint64 `gorm:"primaryKey;autoIncrement:false"`
+	PipelineId      uint64 `gorm:"index"`
+	Selectors       string `gorm:"type:json"`
+	Status          string `gorm:"type:varchar(20);index"`
+	WorkerId        string `gorm:"type:varchar(255);index"`
+	LeasedAt        *time.Time
+	Attempts        int
+	CancelRequested bool
+	Message         string
+}
+
+func (taskLease20261017) TableName() string {
+	return "_devlake_task_leases"
+}
+
+type addTaskLeases struct{}
+
+func (*addTaskLeases) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&worker20261017{},
+		&taskLease20261017{},
+	)
+}
+
+func (*addTaskLeases) Version() uint64 {
+	return 20261017110000
+}
+
+func (*addTaskLeases) Name() string {
+	return "add task leases and workers for distributed task execution"
+}
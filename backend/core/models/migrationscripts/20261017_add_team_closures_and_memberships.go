@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addTeamClosuresAndMemberships)(nil)
+
+type teamClosure20261017 struct {
+	AncestorId   string `gorm:"primaryKey;type:varchar(255)"`
+	DescendantId string `gorm:"primaryKey;type:varchar(255)"`
+	Depth        int
+	archived.NoPKModel
+}
+
+func (teamClosure20261017) TableName() string {
+	return "team_closures"
+}
+
+type teamMembership20261017 struct {
+	TeamId        string    `gorm:"primaryKey;type:varchar(255)"`
+	UserId        string    `gorm:"primaryKey;type:varchar(255)"`
+	EffectiveFrom time.Time `gorm:"primaryKey"`
+	EffectiveTo   *time.Time
+	archived.NoPKModel
+}
+
+func (teamMembership20261017) TableName() string {
+	return "team_memberships"
+}
+
+type addTeamClosuresAndMemberships struct{}
+
+func (*addTeamClosuresAndMemberships) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &teamClosure20261017{}, &teamMembership20261017{})
+}
+
+func (*addTeamClosuresAndMemberships) Version() uint64 {
+	return 20261017160000
+}
+
+func (*addTeamClosuresAndMemberships) Name() string {
+	return "add team_closures and team_memberships"
+}
@@ -140,5 +140,16 @@ func All() []plugin.MigrationScript {
 		new(extendFieldSizeForCq),
 		new(addIssueFixVerion),
 		new(addPipelinePriority),
+		new(addNotificationRules),
+		new(addTaskLeases),
+		new(addBlueprintScheduleOptions),
+		new(addRawDataRetention),
+		new(addDoraDefinitionToProjectPrMetric),
+		new(addDoraMetricSnapshots),
+		new(addTeamClosuresAndMemberships),
+		new(addRefdiffReleaseNotes),
+		new(addIncidentAttributions),
+		new(addSubtaskMetrics),
+		new(addTraceParentToTaskLeases),
 	}
 }
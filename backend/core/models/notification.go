@@ -27,17 +27,64 @@ const (
 	NotificationPipelineStatusChanged NotificationType = "PipelineStatusChanged"
 )
 
+const (
+	NOTIFICATION_PENDING   = "PENDING"
+	NOTIFICATION_DELIVERED = "DELIVERED"
+	NOTIFICATION_FAILED    = "FAILED"
+)
+
 // Notification records notifications sent by lake
 type Notification struct {
 	common.Model
-	Type         NotificationType
-	Endpoint     string
-	Nonce        string
-	ResponseCode int
-	Response     string
-	Data         string
+	Type         NotificationType `json:"type"`
+	Endpoint     string           `json:"endpoint"`
+	Nonce        string           `json:"nonce"`
+	ResponseCode int              `json:"responseCode"`
+	Response     string           `json:"response"`
+	Data         string           `json:"data"`
+	// the following fields are only set for notifications sent by NotificationRules
+	RuleId      uint64 `json:"ruleId" gorm:"index"`
+	ChannelType string `json:"channelType" gorm:"type:varchar(20)"`
+	PipelineId  uint64 `json:"pipelineId" gorm:"index"`
+	Event       string `json:"event" gorm:"type:varchar(20)"`
+	Status      string `json:"status" gorm:"type:varchar(20)"`
+	Attempts    int    `json:"attempts"`
+	Message     string `json:"message"`
 }
 
 func (Notification) TableName() string {
 	return "_devlake_notifications"
 }
+
+const (
+	NOTIFICATION_EVENT_SUCCESS  = "success"
+	NOTIFICATION_EVENT_FAILURE  = "failure"
+	NOTIFICATION_EVENT_PARTIAL  = "partial"
+	NOTIFICATION_EVENT_RECOVERY = "recovery"
+)
+
+const (
+	NOTIFICATION_CHANNEL_SLACK = "slack"
+	NOTIFICATION_CHANNEL_EMAIL = "email"
+	NOTIFICATION_CHANNEL_HTTP  = "http"
+)
+
+// NotificationRule decides which finished pipelines should be notified through which channel,
+// an empty ProjectName or zero BlueprintId matches all pipelines
+type NotificationRule struct {
+	common.Model
+	Name          string                 `json:"name" gorm:"type:varchar(255)" validate:"required"`
+	Enable        bool                   `json:"enable"`
+	ProjectName   string                 `json:"projectName" gorm:"type:varchar(255);index"`
+	BlueprintId   uint64                 `json:"blueprintId" gorm:"index"`
+	Events        []string               `json:"events" gorm:"type:json;serializer:json" validate:"required,min=1,dive,oneof=success failure partial recovery"`
+	ChannelType   string                 `json:"channelType" gorm:"type:varchar(20)" validate:"required,oneof=slack email http"`
+	ChannelConfig map[string]interface{} `json:"channelConfig" gorm:"serializer:encdec"`
+	// Template is a go text/template rendering the message body, check services.NotificationTemplateData for available fields
+	Template   string `json:"template" gorm:"type:text"`
+	MaxRetries int    `json:"maxRetries"` // 0 means retrying 3 times
+}
+
+func (NotificationRule) TableName() string {
+	return "_devlake_notification_rules"
+}
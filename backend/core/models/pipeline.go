@@ -27,6 +27,11 @@ type GenericPipelineTask[T any] struct {
 	Plugin   string   `json:"plugin" binding:"required"`
 	Subtasks []string `json:"subtasks"`
 	Options  T        `json:"options"`
+	// Id is optional, tasks without Id could be referred as "<row>-<col>" (1-based) by DependsOn of other tasks
+	Id string `json:"id,omitempty"`
+	// DependsOn lists Ids of tasks to be finished before the task, tasks without DependsOn wait for
+	// all tasks of the previous PipelineStage instead
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // PipelineTask represents a smallest unit of execution inside a PipelinePlan
@@ -35,7 +40,8 @@ type PipelineTask GenericPipelineTask[map[string]interface{}]
 // PipelineStage consist of multiple PipelineTasks, they will be executed in parallel
 type PipelineStage []*PipelineTask
 
-// PipelinePlan consist of multiple PipelineStages, they will be executed in sequential order
+// PipelinePlan consist of multiple PipelineStages, they will be executed in sequential order,
+// unless tasks declare DependsOn, check pipeline_dag.go for detail
 type PipelinePlan []PipelineStage
 
 // IsEmpty checks if a PipelinePlan is empty
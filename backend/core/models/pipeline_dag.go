@@ -0,0 +1,164 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// A PipelinePlan could be executed as a DAG (directed acyclic graph) in addition to sequential stages:
+// tasks declaring DependsOn start as soon as the named tasks are finished, while tasks without DependsOn
+// wait for all tasks of the previous non-empty stage, which is exactly how stages work. Tasks without Id
+// could be referred by their positions, i.e. "2-1" for the first task of the second stage. So a DAG plan
+// could be written in a single stage:
+//
+//	[[
+//	  {"id": "jira", "plugin": "jira", ...},
+//	  {"id": "gitlab", "plugin": "gitlab", ...},
+//	  {"id": "dora", "plugin": "dora", "dependsOn": ["gitlab"], ...}
+//	]]
+
+// PipelineTaskPosition locates a task in a PipelinePlan, both Row and Col are 1-based just like
+// the PipelineRow and PipelineCol of the Task
+type PipelineTaskPosition struct {
+	Row int
+	Col int
+}
+
+func (p PipelineTaskPosition) String() string {
+	return fmt.Sprintf("%d-%d", p.Row, p.Col)
+}
+
+// IsDag checks if any task of the PipelinePlan declares DependsOn
+func (plan PipelinePlan) IsDag() bool {
+	for _, stage := range plan {
+		for _, task := range stage {
+			if len(task.DependsOn) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Dependencies resolves positions of tasks which each task depends on, an error would be returned
+// if there were duplicated ids, unknown dependencies or cycles in the PipelinePlan
+func (plan PipelinePlan) Dependencies() (map[PipelineTaskPosition][]PipelineTaskPosition, errors.Error) {
+	ids := make(map[string]PipelineTaskPosition)
+	for i, stage := range plan {
+		for j, task := range stage {
+			pos := PipelineTaskPosition{Row: i + 1, Col: j + 1}
+			id := task.Id
+			if id == "" {
+				id = pos.String()
+			}
+			if _, ok := ids[id]; ok {
+				return nil, errors.BadInput.New(fmt.Sprintf("duplicated task id %s in the plan", id))
+			}
+			ids[id] = pos
+		}
+	}
+	deps := make(map[PipelineTaskPosition][]PipelineTaskPosition)
+	var previous []PipelineTaskPosition
+	for i, stage := range plan {
+		current := make([]PipelineTaskPosition, 0, len(stage))
+		for j, task := range stage {
+			pos := PipelineTaskPosition{Row: i + 1, Col: j + 1}
+			current = append(current, pos)
+			if len(task.DependsOn) == 0 {
+				deps[pos] = previous
+				continue
+			}
+			deps[pos] = make([]PipelineTaskPosition, 0, len(task.DependsOn))
+			for _, id := range task.DependsOn {
+				dep, ok := ids[id]
+				if !ok {
+					return nil, errors.BadInput.New(fmt.Sprintf("task %s depends on unknown task %s", pos, id))
+				}
+				deps[pos] = append(deps[pos], dep)
+			}
+		}
+		if len(current) > 0 {
+			previous = current
+		}
+	}
+	if err := detectPipelinePlanCycle(deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+func detectPipelinePlanCycle(deps map[PipelineTaskPosition][]PipelineTaskPosition) errors.Error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[PipelineTaskPosition]int, len(deps))
+	var visit func(pos PipelineTaskPosition) errors.Error
+	visit = func(pos PipelineTaskPosition) errors.Error {
+		switch states[pos] {
+		case visiting:
+			return errors.BadInput.New(fmt.Sprintf("cyclic dependency detected at task %s", pos))
+		case visited:
+			return nil
+		}
+		states[pos] = visiting
+		for _, dep := range deps[pos] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		states[pos] = visited
+		return nil
+	}
+	for pos := range deps {
+		if err := visit(pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToDag converts the PipelinePlan into a single stage with dependencies of all tasks declared explicitly,
+// tasks without Id are named after their positions. The original PipelinePlan is left untouched
+func (plan PipelinePlan) ToDag() PipelinePlan {
+	dag := make(PipelineStage, 0)
+	var previous []string
+	for i, stage := range plan {
+		current := make([]string, 0, len(stage))
+		for j, task := range stage {
+			t := *task
+			if t.Id == "" {
+				t.Id = PipelineTaskPosition{Row: i + 1, Col: j + 1}.String()
+			}
+			if len(task.DependsOn) > 0 {
+				t.DependsOn = append([]string{}, task.DependsOn...)
+			} else {
+				t.DependsOn = append([]string{}, previous...)
+			}
+			current = append(current, t.Id)
+			dag = append(dag, &t)
+		}
+		if len(current) > 0 {
+			previous = current
+		}
+	}
+	return PipelinePlan{dag}
+}
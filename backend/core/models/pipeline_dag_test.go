@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelinePlanDependencies(t *testing.T) {
+	// stages only
+	plan := PipelinePlan{
+		{{Plugin: "jira"}, {Plugin: "gitlab"}},
+		{},
+		{{Plugin: "dora"}},
+	}
+	assert.False(t, plan.IsDag())
+	deps, err := plan.Dependencies()
+	assert.Nil(t, err)
+	assert.Empty(t, deps[PipelineTaskPosition{1, 1}])
+	assert.Equal(t, []PipelineTaskPosition{{1, 1}, {1, 2}}, deps[PipelineTaskPosition{3, 1}])
+
+	// dag
+	plan = PipelinePlan{
+		{{Plugin: "jira", Id: "jira"}, {Plugin: "gitlab", Id: "gitlab"}, {Plugin: "dora", DependsOn: []string{"gitlab"}}},
+		{{Plugin: "refdiff"}},
+	}
+	assert.True(t, plan.IsDag())
+	deps, err = plan.Dependencies()
+	assert.Nil(t, err)
+	assert.Equal(t, []PipelineTaskPosition{{1, 2}}, deps[PipelineTaskPosition{1, 3}])
+	assert.Len(t, deps[PipelineTaskPosition{2, 1}], 3)
+
+	// invalid
+	_, err = PipelinePlan{{{Id: "a"}, {Id: "a"}}}.Dependencies()
+	assert.NotNil(t, err)
+	_, err = PipelinePlan{{{Id: "a", DependsOn: []string{"b"}}}}.Dependencies()
+	assert.NotNil(t, err)
+	_, err = PipelinePlan{{{Id: "a", DependsOn: []string{"b"}}, {Id: "b", DependsOn: []string{"a"}}}}.Dependencies()
+	assert.NotNil(t, err)
+	_, err = PipelinePlan{{{Id: "a", DependsOn: []string{"a"}}}}.Dependencies()
+	assert.NotNil(t, err)
+	_, err = PipelinePlan{{{Id: "1-2"}, {Plugin: "gitlab"}}}.Dependencies()
+	assert.NotNil(t, err)
+}
+
+func TestPipelinePlanToDag(t *testing.T) {
+	plan := PipelinePlan{
+		{{Plugin: "jira", Id: "jira"}, {Plugin: "gitlab"}},
+		{{Plugin: "dora", DependsOn: []string{"1-2"}}, {Plugin: "refdiff"}},
+	}
+	dag := plan.ToDag()
+	assert.Len(t, dag, 1)
+	assert.Len(t, dag[0], 4)
+	assert.Equal(t, "jira", dag[0][0].Id)
+	assert.Empty(t, dag[0][0].DependsOn)
+	assert.Equal(t, "1-2", dag[0][1].Id)
+	assert.Equal(t, []string{"1-2"}, dag[0][2].DependsOn)
+	assert.Equal(t, []string{"jira", "1-2"}, dag[0][3].DependsOn)
+	// original plan is untouched
+	assert.Equal(t, "", plan[0][1].Id)
+	assert.Nil(t, plan[1][1].DependsOn)
+
+	// the converted plan resolves to the same dependencies
+	deps, err := plan.Dependencies()
+	assert.Nil(t, err)
+	assert.Equal(t, []PipelineTaskPosition{{1, 2}}, deps[PipelineTaskPosition{2, 1}])
+	deps, err = dag.Dependencies()
+	assert.Nil(t, err)
+	assert.Equal(t, []PipelineTaskPosition{{1, 2}}, deps[PipelineTaskPosition{1, 3}])
+	assert.Equal(t, []PipelineTaskPosition{{1, 1}, {1, 2}}, deps[PipelineTaskPosition{1, 4}])
+}
@@ -0,0 +1,74 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"path"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// RawDataRetentionPolicy decides how long records of `_raw_*` tables are kept
+type RawDataRetentionPolicy struct {
+	common.Model
+	// Table is a raw table name or a glob pattern of it, i.e. `_raw_github_*` for all raw tables of github,
+	// the exact match wins over patterns, and the longest pattern wins over shorter ones
+	Table  string `json:"table" gorm:"type:varchar(255);uniqueIndex" validate:"required" example:"_raw_github_*"`
+	Enable bool   `json:"enable"`
+	// KeepCollections keeps records of the last N full collections of each `_raw_data_params`, along with the
+	// incremental ones after them, 0 means unlimited
+	KeepCollections int `json:"keepCollections" validate:"min=0"`
+	// KeepDays keeps records collected within the last N days, and those of the full collection the incremental
+	// ones within the period were based on, 0 means unlimited
+	KeepDays int `json:"keepDays" validate:"min=0"`
+	// SkipPersistence hands over collected records to the extractor in memory instead of saving them into the table
+	SkipPersistence bool `json:"skipPersistence"`
+}
+
+func (RawDataRetentionPolicy) TableName() string {
+	return "_devlake_raw_data_retention_policies"
+}
+
+// RawDataCollection records a successful collection of a raw table, records of the collection were created after StartedAt
+type RawDataCollection struct {
+	ID            uint64    `json:"id" gorm:"primaryKey"`
+	RawDataTable  string    `json:"rawDataTable" gorm:"type:varchar(255);index:idx_raw_data_collection"`
+	RawDataParams string    `json:"rawDataParams" gorm:"type:varchar(255);index:idx_raw_data_collection"`
+	Incremental   bool      `json:"incremental"`
+	StartedAt     time.Time `json:"startedAt"`
+	FinishedAt    time.Time `json:"finishedAt"`
+}
+
+func (RawDataCollection) TableName() string {
+	return "_devlake_raw_data_collections"
+}
+
+// MatchRawDataRetentionPolicy returns the most specific policy for the table, nil if none matches
+func MatchRawDataRetentionPolicy(policies []*RawDataRetentionPolicy, table string) *RawDataRetentionPolicy {
+	var matched *RawDataRetentionPolicy
+	for _, policy := range policies {
+		if policy.Table == table {
+			return policy
+		}
+		if ok, _ := path.Match(policy.Table, table); ok && (matched == nil || len(policy.Table) > len(matched.Table)) {
+			matched = policy
+		}
+	}
+	return matched
+}
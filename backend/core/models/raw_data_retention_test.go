@@ -0,0 +1,37 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRawDataRetentionPolicy(t *testing.T) {
+	all := &RawDataRetentionPolicy{Table: "_raw_*"}
+	github := &RawDataRetentionPolicy{Table: "_raw_github_*"}
+	githubIssues := &RawDataRetentionPolicy{Table: "_raw_github_api_issues"}
+	policies := []*RawDataRetentionPolicy{all, githubIssues, github}
+
+	assert.Equal(t, githubIssues, MatchRawDataRetentionPolicy(policies, "_raw_github_api_issues"))
+	assert.Equal(t, github, MatchRawDataRetentionPolicy(policies, "_raw_github_api_pull_requests"))
+	assert.Equal(t, all, MatchRawDataRetentionPolicy(policies, "_raw_jira_api_issues"))
+	assert.Nil(t, MatchRawDataRetentionPolicy(policies, "_tool_github_issues"))
+	assert.Nil(t, MatchRawDataRetentionPolicy(nil, "_raw_jira_api_issues"))
+}
@@ -102,21 +102,45 @@ func (Subtask) TableName() string {
 	return "_devlake_subtasks"
 }
 
-type SubtaskDetails struct {
-	ID              uint64     `json:"id"`
-	CreatedAt       time.Time  `json:"createdAt"`
-	UpdatedAt       time.Time  `json:"updatedAt"`
-	TaskID          uint64     `json:"taskId"`
-	Name            string     `json:"name"`
-	Number          int        `json:"number"`
+// SubtaskMetric records the timing, record counts and api calls of a subtask run
+type SubtaskMetric struct {
+	TaskID          uint64     `json:"taskId" gorm:"primaryKey"`
+	Name            string     `json:"name" gorm:"primaryKey;type:varchar(255)"`
+	PipelineID      uint64     `json:"pipelineId" gorm:"index"`
+	Plugin          string     `json:"plugin" gorm:"type:varchar(255)"`
 	BeganAt         *time.Time `json:"beganAt"`
 	FinishedAt      *time.Time `json:"finishedAt"`
-	SpentSeconds    int64      `json:"spentSeconds"`
-	FinishedRecords int        `json:"finishedRecords"`
-	Sequence        int        `json:"sequence"`
-	IsCollector     bool       `json:"isCollector"`
+	DurationMs      int64      `json:"durationMs"`
+	RowsRead        int64      `json:"rowsRead"`
+	RowsWritten     int64      `json:"rowsWritten"`
+	HttpRequests    int64      `json:"httpRequests"`
+	HttpRetries     int64      `json:"httpRetries"`
+	HttpRateLimited int64      `json:"httpRateLimited"`
 	IsFailed        bool       `json:"isFailed"`
-	Message         string     `json:"message"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+func (SubtaskMetric) TableName() string {
+	return "_devlake_subtask_metrics"
+}
+
+type SubtaskDetails struct {
+	ID              uint64         `json:"id"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	TaskID          uint64         `json:"taskId"`
+	Name            string         `json:"name"`
+	Number          int            `json:"number"`
+	BeganAt         *time.Time     `json:"beganAt"`
+	FinishedAt      *time.Time     `json:"finishedAt"`
+	SpentSeconds    int64          `json:"spentSeconds"`
+	FinishedRecords int            `json:"finishedRecords"`
+	Sequence        int            `json:"sequence"`
+	IsCollector     bool           `json:"isCollector"`
+	IsFailed        bool           `json:"isFailed"`
+	Message         string         `json:"message"`
+	Metrics         *SubtaskMetric `json:"metrics"`
 }
 
 type SubtasksInfo struct {
@@ -0,0 +1,71 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	TASK_LEASE_QUEUED    = "QUEUED"
+	TASK_LEASE_LEASED    = "LEASED"
+	TASK_LEASE_SUCCEEDED = "SUCCEEDED"
+	TASK_LEASE_FAILED    = "FAILED"
+	TASK_LEASE_CANCELLED = "CANCELLED"
+)
+
+// WORKER_LABEL_PREFIX marks pipeline labels as selectors, i.e. a pipeline labeled `worker/region-eu` could
+// only be executed by workers with the `region-eu` label
+const WORKER_LABEL_PREFIX = "worker/"
+
+// Worker is a devlake process executing tasks dispatched by the api server, it keeps
+// sending heartbeats, or its leases would be reclaimed by the api server
+type Worker struct {
+	Id          string    `json:"id" gorm:"primaryKey;type:varchar(255)"`
+	HostName    string    `json:"hostName" gorm:"type:varchar(255)"`
+	Labels      []string  `json:"labels" gorm:"type:json;serializer:json"`
+	Concurrency int       `json:"concurrency"`
+	StartedAt   time.Time `json:"startedAt"`
+	HeartbeatAt time.Time `json:"heartbeatAt" gorm:"index"`
+}
+
+func (Worker) TableName() string {
+	return "_devlake_workers"
+}
+
+// TaskLease dispatches a task to workers, the task could be leased by a worker only if all Selectors
+// are found in the labels of the worker
+type TaskLease struct {
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	TaskId          uint64     `json:"taskId" gorm:"primaryKey;autoIncrement:false"`
+	PipelineId      uint64     `json:"pipelineId" gorm:"index"`
+	Selectors       []string   `json:"selectors" gorm:"type:json;serializer:json"`
+	Status          string     `json:"status" gorm:"type:varchar(20);index"`
+	WorkerId        string     `json:"workerId" gorm:"type:varchar(255);index"`
+	LeasedAt        *time.Time `json:"leasedAt"`
+	Attempts        int        `json:"attempts"`
+	CancelRequested bool       `json:"cancelRequested"`
+	Message         string     `json:"message"`
+	// TraceParent is the W3C traceparent of the pipeline, spans of the task are created under it by the worker
+	TraceParent string `json:"traceParent" gorm:"type:varchar(255)"`
+}
+
+func (TaskLease) TableName() string {
+	return "_devlake_task_leases"
+}
@@ -30,6 +30,8 @@ type ApiResourceInput struct {
 	Params  map[string]string      // path variables
 	Query   url.Values             // query string
 	Body    map[string]interface{} // json body
+	RawBody []byte                 // raw json body, kept for signature verification
+	Header  http.Header            // request headers
 	Request *http.Request
 
 	User *common.User
@@ -70,6 +70,12 @@ type TaskContext interface {
 	SubTaskContext(subtask string) (SubTaskContext, errors.Error)
 }
 
+// RunningSubTaskTracker is implemented by the TaskContext which keeps track of the running subtask
+type RunningSubTaskTracker interface {
+	// RunningSubTask returns the SubTaskContext being executed, nil if there is none
+	RunningSubTask() SubTaskContext
+}
+
 type SubTask interface {
 	// Execute FIXME ...
 	Execute() errors.Error
@@ -0,0 +1,33 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// SecretProvider is responsible for encrypting/decrypting sensitive values (connection credentials,
+// pipeline plans, task options etc.) before they are persisted by the `encdec` serializer
+type SecretProvider interface {
+	// Encrypt encrypts the plainText with the newest key
+	Encrypt(plainText string) (string, errors.Error)
+	// Decrypt decrypts the cipherText with whichever key it was encrypted with
+	Decrypt(cipherText string) (string, errors.Error)
+	// IsStale returns true if the cipherText was NOT produced by the newest key/mode, which means it should be re-encrypted
+	IsStale(cipherText string) bool
+}
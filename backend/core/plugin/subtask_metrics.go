@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import "sync/atomic"
+
+// SubTaskMetrics accumulates the metrics of a running subtask, it is safe for concurrent use
+// and all methods are no-op on a nil receiver so callers don't have to check
+type SubTaskMetrics struct {
+	rowsRead        int64
+	rowsWritten     int64
+	httpRequests    int64
+	httpRetries     int64
+	httpRateLimited int64
+}
+
+// SubTaskMetricsRecorder is implemented by the TaskContext and SubTaskContext which keep track of subtask metrics
+type SubTaskMetricsRecorder interface {
+	// SubTaskMetrics returns the metrics of the running subtask, nil if there is none
+	SubTaskMetrics() *SubTaskMetrics
+}
+
+// GetSubTaskMetrics returns the metrics of the running subtask of the context, or nil if the context doesn't
+// record metrics. Structs embedding a SubTaskContext are resolved through their TaskContext
+func GetSubTaskMetrics(ctx interface{}) *SubTaskMetrics {
+	switch ctx := ctx.(type) {
+	case SubTaskMetricsRecorder:
+		return ctx.SubTaskMetrics()
+	case SubTaskContext:
+		if taskCtx := ctx.TaskContext(); taskCtx != nil {
+			return GetSubTaskMetrics(taskCtx)
+		}
+	}
+	return nil
+}
+
+// IncRowsRead counts rows loaded from the database
+func (m *SubTaskMetrics) IncRowsRead(quantity int) {
+	if m != nil {
+		atomic.AddInt64(&m.rowsRead, int64(quantity))
+	}
+}
+
+// IncRowsWritten counts rows saved to the database
+func (m *SubTaskMetrics) IncRowsWritten(quantity int) {
+	if m != nil {
+		atomic.AddInt64(&m.rowsWritten, int64(quantity))
+	}
+}
+
+// IncHttpRequests counts http requests sent, including retries
+func (m *SubTaskMetrics) IncHttpRequests() {
+	if m != nil {
+		atomic.AddInt64(&m.httpRequests, 1)
+	}
+}
+
+// IncHttpRetries counts http requests being retried
+func (m *SubTaskMetrics) IncHttpRetries() {
+	if m != nil {
+		atomic.AddInt64(&m.httpRetries, 1)
+	}
+}
+
+// IncHttpRateLimited counts responses with status 429 Too Many Requests
+func (m *SubTaskMetrics) IncHttpRateLimited() {
+	if m != nil {
+		atomic.AddInt64(&m.httpRateLimited, 1)
+	}
+}
+
+func (m *SubTaskMetrics) RowsRead() int64 {
+	if m == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&m.rowsRead)
+}
+
+func (m *SubTaskMetrics) RowsWritten() int64 {
+	if m == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&m.rowsWritten)
+}
+
+func (m *SubTaskMetrics) HttpRequests() int64 {
+	if m == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&m.httpRequests)
+}
+
+func (m *SubTaskMetrics) HttpRetries() int64 {
+	if m == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&m.httpRetries)
+}
+
+func (m *SubTaskMetrics) HttpRateLimited() int64 {
+	if m == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&m.httpRateLimited)
+}
@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetricsRecorder struct {
+	metrics *SubTaskMetrics
+}
+
+func (r *testMetricsRecorder) SubTaskMetrics() *SubTaskMetrics {
+	return r.metrics
+}
+
+func TestSubTaskMetrics(t *testing.T) {
+	metrics := &SubTaskMetrics{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metrics.IncRowsRead(2)
+			metrics.IncRowsWritten(3)
+			metrics.IncHttpRequests()
+			metrics.IncHttpRetries()
+			metrics.IncHttpRateLimited()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(20), metrics.RowsRead())
+	assert.Equal(t, int64(30), metrics.RowsWritten())
+	assert.Equal(t, int64(10), metrics.HttpRequests())
+	assert.Equal(t, int64(10), metrics.HttpRetries())
+	assert.Equal(t, int64(10), metrics.HttpRateLimited())
+
+	var nilMetrics *SubTaskMetrics
+	nilMetrics.IncRowsRead(1)
+	nilMetrics.IncHttpRequests()
+	assert.Equal(t, int64(0), nilMetrics.RowsRead())
+	assert.Equal(t, int64(0), nilMetrics.HttpRequests())
+}
+
+func TestGetSubTaskMetrics(t *testing.T) {
+	metrics := &SubTaskMetrics{}
+	assert.Same(t, metrics, GetSubTaskMetrics(&testMetricsRecorder{metrics}))
+	assert.Nil(t, GetSubTaskMetrics(nil))
+	assert.Nil(t, GetSubTaskMetrics("not a context"))
+}
@@ -26,12 +26,14 @@ import (
 	contextimpl "github.com/apache/incubator-devlake/impls/context"
 	"github.com/apache/incubator-devlake/impls/dalgorm"
 	"github.com/apache/incubator-devlake/impls/logruslog"
+	"github.com/apache/incubator-devlake/impls/secretprovider"
 	"gorm.io/gorm"
 	"sync"
 )
 
 var app_lock sync.Mutex
 var app_inited bool
+var secretProvider plugin.SecretProvider
 
 // CreateAppBasicRes returns an application level BasicRes instance based on .env/environment variables
 // it is useful because multiple places need BasicRes including `main.go` `directrun` and `worker`
@@ -49,10 +51,19 @@ func CreateAppBasicRes() context.BasicRes {
 	if err != nil {
 		panic(err)
 	}
-	dalgorm.Init(cfg.GetString(plugin.EncodeKeyEnvStr))
+	secretProvider, err = secretprovider.NewSecretProvider(cfg)
+	if err != nil {
+		panic(err)
+	}
+	dalgorm.Init(secretProvider)
 	return CreateBasicRes(cfg, logger, db)
 }
 
+// GetSecretProvider returns the SecretProvider used by the `encdec` serializer, available after CreateAppBasicRes
+func GetSecretProvider() plugin.SecretProvider {
+	return secretProvider
+}
+
 // CreateBasicRes returns a BasicRes based on what was given
 func CreateBasicRes(cfg config.ConfigReader, logger log.Logger, db *gorm.DB) context.BasicRes {
 	return contextimpl.NewDefaultBasicRes(cfg, logger, dalgorm.NewDalgorm(db))
@@ -35,6 +35,7 @@ import (
 	tlsMysql "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
 )
@@ -120,6 +121,29 @@ func sanitizeQuery(query url.Values) string {
 	return query.Encode()
 }
 
+// sqliteDsn converts urls like `sqlite:///var/lib/devlake/lake.db`, `sqlite://lake.db` or `sqlite::memory:`
+// to the dsn of the sqlite driver
+func sqliteDsn(u *url.URL) string {
+	path := u.Opaque
+	if path == "" {
+		path = u.Host + u.Path
+	}
+	query := u.Query()
+	if query.Get("_busy_timeout") == "" {
+		// sqlite allows only one writer at a time, wait for the lock instead of failing immediately
+		query.Set("_busy_timeout", "5000")
+	}
+	if path == ":memory:" {
+		// connections of the pool must share the same in-memory database
+		path = "file::memory:"
+		query.Set("cache", "shared")
+	} else if query.Get("_journal_mode") == "" {
+		// WAL allows reading a table through a cursor while writing to it from another connection
+		query.Set("_journal_mode", "WAL")
+	}
+	return fmt.Sprintf("%s?%s", path, query.Encode())
+}
+
 func MakeDbConnection(dbUrl string, conf *gorm.Config) (*gorm.DB, error) {
 	u, err := url.Parse(dbUrl)
 	if err != nil {
@@ -154,6 +178,8 @@ func MakeDbConnection(dbUrl string, conf *gorm.Config) (*gorm.DB, error) {
 		return gorm.Open(mysql.Open(dbUrl), conf)
 	case "postgresql", "postgres", "pg":
 		return gorm.Open(postgres.Open(dbUrl), conf)
+	case "sqlite", "sqlite3":
+		return gorm.Open(sqlite.Open(sqliteDsn(u)), conf)
 	default:
 		return nil, fmt.Errorf("invalid DB_URL:%s", dbUrl)
 	}
@@ -27,7 +27,8 @@ import (
 	"github.com/apache/incubator-devlake/core/models"
 )
 
-// RunPipeline FIXME ...
+// RunPipeline runs pending tasks of the pipeline, a task would be started as soon as all tasks it
+// depends on are finished, check models.PipelinePlan.Dependencies for detail
 func RunPipeline(
 	basicRes context.BasicRes,
 	pipelineId uint64,
@@ -44,58 +45,142 @@ func RunPipeline(
 	if err != nil {
 		return err
 	}
-	taskIds := make([][]uint64, 0)
+	// load pipeline from db
+	dbPipeline := &models.Pipeline{}
+	err = db.First(dbPipeline, dal.Where("id = ?", pipelineId))
+	if err != nil {
+		return err
+	}
+	deps, err := dbPipeline.Plan.Dependencies()
+	if err != nil {
+		return err
+	}
+	return runPipelineTasks(basicRes, dbPipeline, tasks, resolveTaskDependencies(tasks, deps), runTasks)
+}
+
+// resolveTaskDependencies maps dependencies of plan positions to pending tasks, tasks which are not
+// pending (i.e. finished before rerunning the pipeline) are treated as satisfied
+func resolveTaskDependencies(
+	tasks []models.Task,
+	deps map[models.PipelineTaskPosition][]models.PipelineTaskPosition,
+) map[uint64][]uint64 {
+	pending := make(map[models.PipelineTaskPosition]uint64, len(tasks))
+	for _, task := range tasks {
+		pending[models.PipelineTaskPosition{Row: task.PipelineRow, Col: task.PipelineCol}] = task.ID
+	}
+	taskDeps := make(map[uint64][]uint64, len(tasks))
 	for _, task := range tasks {
-		for len(taskIds) < task.PipelineRow {
-			taskIds = append(taskIds, make([]uint64, 0))
+		taskDeps[task.ID] = make([]uint64, 0)
+		positions, ok := deps[models.PipelineTaskPosition{Row: task.PipelineRow, Col: task.PipelineCol}]
+		if !ok {
+			// the task doesn't match the plan, fallback to wait for all tasks of previous rows
+			for _, t := range tasks {
+				if t.PipelineRow < task.PipelineRow {
+					taskDeps[task.ID] = append(taskDeps[task.ID], t.ID)
+				}
+			}
+			continue
+		}
+		for _, pos := range positions {
+			if id, ok := pending[pos]; ok {
+				taskDeps[task.ID] = append(taskDeps[task.ID], id)
+			}
 		}
-		taskIds[task.PipelineRow-1] = append(taskIds[task.PipelineRow-1], task.ID)
 	}
-	return runPipelineTasks(basicRes, pipelineId, taskIds, runTasks)
+	return taskDeps
+}
+
+type taskResult struct {
+	taskId uint64
+	err    errors.Error
 }
 
 func runPipelineTasks(
 	basicRes context.BasicRes,
-	pipelineId uint64,
-	taskIds [][]uint64,
+	dbPipeline *models.Pipeline,
+	tasks []models.Task,
+	taskDeps map[uint64][]uint64,
 	runTasks func([]uint64) errors.Error,
 ) errors.Error {
 	db := basicRes.GetDal()
 	log := basicRes.GetLogger()
-	// load pipeline from db
-	dbPipeline := &models.Pipeline{}
-	err := db.First(dbPipeline, dal.Where("id = ?", pipelineId))
-	if err != nil {
-		return err
-	}
 
 	// if pipeline has been cancelled, just return.
 	if dbPipeline.Status == models.TASK_CANCELLED {
 		return nil
 	}
 
-	// This double for loop executes each set of tasks sequentially while
-	// executing the set of tasks concurrently.
-	for i, row := range taskIds {
-		// update stage
-		err = db.UpdateColumns(dbPipeline, []dal.DalSet{
-			{ColumnName: "status", Value: models.TASK_RUNNING},
-			{ColumnName: "stage", Value: i + 1},
-		})
-		if err != nil {
-			log.Error(err, "update pipeline state failed")
+	rows := make(map[uint64]int, len(tasks))
+	waiting := make(map[uint64]int, len(tasks))
+	dependents := make(map[uint64][]uint64, len(tasks))
+	ready := make([]uint64, 0)
+	for _, task := range tasks {
+		rows[task.ID] = task.PipelineRow
+		waiting[task.ID] = len(taskDeps[task.ID])
+		for _, dep := range taskDeps[task.ID] {
+			dependents[dep] = append(dependents[dep], task.ID)
+		}
+		if waiting[task.ID] == 0 {
+			ready = append(ready, task.ID)
+		}
+	}
+
+	// Tasks are started as soon as their dependencies are finished, and run in parallel with each other.
+	// The stage of the pipeline is the greatest row of started tasks.
+	var err, stopErr errors.Error
+	stage := 0
+	running := 0
+	results := make(chan taskResult)
+	for {
+		for _, taskId := range ready {
+			if stopErr != nil {
+				break
+			}
+			if rows[taskId] > stage {
+				stage = rows[taskId]
+				e := db.UpdateColumns(dbPipeline, []dal.DalSet{
+					{ColumnName: "status", Value: models.TASK_RUNNING},
+					{ColumnName: "stage", Value: stage},
+				})
+				if e != nil {
+					log.Error(e, "update pipeline state failed")
+					stopErr = e
+					break
+				}
+			}
+			running++
+			go func(id uint64) {
+				results <- taskResult{taskId: id, err: runTasks([]uint64{id})}
+			}(taskId)
+		}
+		ready = ready[:0]
+		if running == 0 {
 			break
 		}
-		// run tasks in parallel
-		err = runTasks(row)
-		if err != nil {
-			log.Error(err, "run tasks failed")
-			if errors.Is(err, gocontext.Canceled) || !dbPipeline.SkipOnFail {
-				log.Info("return error")
-				return err
+		// wait for any task to finish
+		result := <-results
+		running--
+		if result.err != nil {
+			log.Error(result.err, "run tasks failed")
+			err = result.err
+			if errors.Is(result.err, gocontext.Canceled) || !dbPipeline.SkipOnFail {
+				// stop starting new tasks, and wait for running ones
+				if stopErr == nil || errors.Is(result.err, gocontext.Canceled) {
+					stopErr = result.err
+				}
+			}
+		}
+		for _, dependent := range dependents[result.taskId] {
+			waiting[dependent]--
+			if waiting[dependent] == 0 {
+				ready = append(ready, dependent)
 			}
 		}
 	}
+	if stopErr != nil {
+		log.Info("return error")
+		return stopErr
+	}
 	if dbPipeline.BeganAt != nil {
 		log.Info("pipeline finished in %d ms: %v", time.Now().UnixMilli()-dbPipeline.BeganAt.UnixMilli(), err)
 	} else {
@@ -0,0 +1,56 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTaskDependencies(t *testing.T) {
+	plan := models.PipelinePlan{
+		{{Plugin: "jira"}, {Plugin: "gitlab", Id: "gitlab"}},
+		{{Plugin: "dora", DependsOn: []string{"gitlab"}}, {Plugin: "refdiff"}},
+	}
+	deps, err := plan.Dependencies()
+	assert.Nil(t, err)
+	newTask := func(id uint64, row, col int) models.Task {
+		return models.Task{Model: common.Model{ID: id}, PipelineRow: row, PipelineCol: col}
+	}
+
+	tasks := []models.Task{newTask(1, 1, 1), newTask(2, 1, 2), newTask(3, 2, 1), newTask(4, 2, 2)}
+	taskDeps := resolveTaskDependencies(tasks, deps)
+	assert.Empty(t, taskDeps[1])
+	assert.Empty(t, taskDeps[2])
+	assert.Equal(t, []uint64{2}, taskDeps[3])
+	assert.Equal(t, []uint64{1, 2}, taskDeps[4])
+
+	// rerun: finished tasks are treated as satisfied
+	tasks = []models.Task{newTask(5, 1, 1), newTask(6, 2, 1), newTask(7, 2, 2)}
+	taskDeps = resolveTaskDependencies(tasks, deps)
+	assert.Empty(t, taskDeps[6])
+	assert.Equal(t, []uint64{5}, taskDeps[7])
+
+	// tasks not found in the plan wait for previous rows
+	tasks = []models.Task{newTask(8, 1, 1), newTask(9, 3, 1)}
+	taskDeps = resolveTaskDependencies(tasks, deps)
+	assert.Equal(t, []uint64{8}, taskDeps[9])
+}
@@ -33,8 +33,10 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/utils"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/helpers/tracinghelper"
 	contextimpl "github.com/apache/incubator-devlake/impls/context"
 	"github.com/apache/incubator-devlake/impls/logruslog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // RunTask FIXME ...
@@ -54,10 +56,19 @@ func RunTask(
 		return err
 	}
 
+	ctx, span := tracinghelper.Start(
+		ctx,
+		"task",
+		attribute.Int64("devlake.task.id", int64(task.ID)),
+		attribute.Int64("devlake.pipeline.id", int64(task.PipelineId)),
+		attribute.String("devlake.plugin", task.Plugin),
+	)
+	defer span.End()
 	logger, err := getTaskLogger(basicRes.GetLogger(), task)
 	if err != nil {
 		return err
 	}
+	logger = logruslog.WithTraceId(logger, tracinghelper.TraceId(ctx))
 	beganAt := time.Now()
 	if task.BeganAt != nil {
 		beganAt = *task.BeganAt
@@ -87,6 +98,7 @@ func RunTask(
 			} else {
 				lakeErr = errors.Convert(err)
 			}
+			tracinghelper.SetError(span, lakeErr)
 			dbe := db.UpdateColumns(task, []dal.DalSet{
 				{ColumnName: "status", Value: models.TASK_FAILED},
 				{ColumnName: "message", Value: lakeErr.Error()},
@@ -172,19 +184,8 @@ func RunPluginTask(
 	)
 }
 
-// RunPluginSubTasks FIXME ...
-func RunPluginSubTasks(
-	ctx gocontext.Context,
-	basicRes context.BasicRes,
-	task *models.Task,
-	pluginTask plugin.PluginTask,
-	progress chan plugin.RunningProgress,
-	syncPolicy *models.SyncPolicy,
-) errors.Error {
-	logger := basicRes.GetLogger()
-	logger.Info("start plugin")
-	// find out all possible subtasks this plugin can offer
-	subtaskMetas := pluginTask.SubTaskMetas()
+// GetSubtasksFlag determines which subtasks of the plugin should be executed
+func GetSubtasksFlag(subtaskMetas []plugin.SubTaskMeta, specifiedTasks []string, syncPolicy *models.SyncPolicy) (map[string]bool, errors.Error) {
 	subtasksFlag := make(map[string]bool)
 	for _, subtaskMeta := range subtaskMetas {
 		subtasksFlag[subtaskMeta.Name] = subtaskMeta.EnabledByDefault
@@ -197,26 +198,17 @@ func RunPluginSubTasks(
 	}
 	*/
 
-	// user specifies what subtasks to run
-	if len(task.Subtasks) != 0 {
-		// decode user specified subtasks
-		var specifiedTasks []string
-		err := api.Decode(task.Subtasks, &specifiedTasks, nil)
-		if err != nil {
-			return errors.Default.Wrap(err, "subtasks could not be decoded")
+	if len(specifiedTasks) > 0 {
+		// first, disable all subtasks
+		for task := range subtasksFlag {
+			subtasksFlag[task] = false
 		}
-		if len(specifiedTasks) > 0 {
-			// first, disable all subtasks
-			for task := range subtasksFlag {
-				subtasksFlag[task] = false
-			}
-			// second, check specified subtasks is valid and enable them if so
-			for _, task := range specifiedTasks {
-				if _, ok := subtasksFlag[task]; ok {
-					subtasksFlag[task] = true
-				} else {
-					return errors.Default.New(fmt.Sprintf("subtask %s does not exist", task))
-				}
+		// second, check specified subtasks is valid and enable them if so
+		for _, task := range specifiedTasks {
+			if _, ok := subtasksFlag[task]; ok {
+				subtasksFlag[task] = true
+			} else {
+				return nil, errors.Default.New(fmt.Sprintf("subtask %s does not exist", task))
 			}
 		}
 	}
@@ -231,6 +223,35 @@ func RunPluginSubTasks(
 			subtasksFlag[subtaskMeta.Name] = true
 		}
 	}
+	return subtasksFlag, nil
+}
+
+// RunPluginSubTasks FIXME ...
+func RunPluginSubTasks(
+	ctx gocontext.Context,
+	basicRes context.BasicRes,
+	task *models.Task,
+	pluginTask plugin.PluginTask,
+	progress chan plugin.RunningProgress,
+	syncPolicy *models.SyncPolicy,
+) errors.Error {
+	logger := basicRes.GetLogger()
+	logger.Info("start plugin")
+	// find out all possible subtasks this plugin can offer
+	subtaskMetas := pluginTask.SubTaskMetas()
+	// user specifies what subtasks to run
+	var specifiedTasks []string
+	if len(task.Subtasks) != 0 {
+		// decode user specified subtasks
+		err := api.Decode(task.Subtasks, &specifiedTasks, nil)
+		if err != nil {
+			return errors.Default.Wrap(err, "subtasks could not be decoded")
+		}
+	}
+	subtasksFlag, err := GetSubtasksFlag(subtaskMetas, specifiedTasks, syncPolicy)
+	if err != nil {
+		return err
+	}
 
 	// calculate total step(number of task to run)
 	steps := 0
@@ -241,6 +262,7 @@ func RunPluginSubTasks(
 	}
 
 	taskCtx := contextimpl.NewDefaultTaskContext(ctx, basicRes, task.Plugin, subtasksFlag, progress)
+	defer api.DropInMemoryRawData(taskCtx)
 	if closeablePlugin, ok := pluginTask.(plugin.CloseablePluginTask); ok {
 		defer closeablePlugin.Close(taskCtx)
 	}
@@ -327,7 +349,7 @@ func RunPluginSubTasks(
 		} else {
 			logger.Info("executing subtask %s", subtaskMeta.Name)
 			start := time.Now()
-			err = runSubtask(basicRes, subtaskCtx, task.ID, subtaskNumber, subtaskMeta.EntryPoint)
+			err = runSubtask(basicRes, subtaskCtx, task, subtaskNumber, subtaskMeta.EntryPoint)
 			logger.Info("subtask %s finished in %d ms", subtaskMeta.Name, time.Since(start).Milliseconds())
 			if err != nil {
 				err = errors.SubtaskErr.Wrap(err, fmt.Sprintf("subtask %s ended unexpectedly", subtaskMeta.Name), errors.WithData(&subtaskMeta))
@@ -402,29 +424,62 @@ func UpdateProgressDetail(basicRes context.BasicRes, taskId uint64, progressDeta
 func runSubtask(
 	basicRes context.BasicRes,
 	ctx plugin.SubTaskContext,
-	parentID uint64,
+	task *models.Task,
 	subtaskNumber int,
 	entryPoint plugin.SubTaskEntryPoint,
-) errors.Error {
+) (err errors.Error) {
 	beginAt := time.Now()
 	subtask := &models.Subtask{
 		Name:    ctx.GetName(),
-		TaskID:  parentID,
+		TaskID:  task.ID,
 		Number:  subtaskNumber,
 		BeganAt: &beginAt,
 	}
 	recordSubtask(basicRes, subtask)
+	spanCtx, span := tracinghelper.Start(
+		ctx.GetContext(),
+		"subtask",
+		attribute.String("devlake.subtask.name", subtask.Name),
+		attribute.Int64("devlake.task.id", int64(task.ID)),
+		attribute.String("devlake.plugin", task.Plugin),
+	)
+	var metrics *plugin.SubTaskMetrics
+	if subtaskCtx, ok := ctx.(*contextimpl.DefaultSubTaskContext); ok {
+		metrics = subtaskCtx.Begin(spanCtx)
+	}
 	// defer to record subtask status
 	defer func() {
+		tracinghelper.End(span, err)
 		finishedAt := time.Now()
 		subtask.FinishedAt = &finishedAt
 		subtask.SpentSeconds = finishedAt.Unix() - beginAt.Unix()
 
 		recordSubtask(basicRes, subtask)
+		recordSubtaskMetrics(basicRes, &models.SubtaskMetric{
+			TaskID:          task.ID,
+			Name:            subtask.Name,
+			PipelineID:      task.PipelineId,
+			Plugin:          task.Plugin,
+			BeganAt:         &beginAt,
+			FinishedAt:      &finishedAt,
+			DurationMs:      finishedAt.Sub(beginAt).Milliseconds(),
+			RowsRead:        metrics.RowsRead(),
+			RowsWritten:     metrics.RowsWritten(),
+			HttpRequests:    metrics.HttpRequests(),
+			HttpRetries:     metrics.HttpRetries(),
+			HttpRateLimited: metrics.HttpRateLimited(),
+			IsFailed:        err != nil,
+		})
 	}()
 	return entryPoint(ctx)
 }
 
+func recordSubtaskMetrics(basicRes context.BasicRes, metric *models.SubtaskMetric) {
+	if err := basicRes.GetDal().CreateOrUpdate(metric); err != nil {
+		basicRes.GetLogger().Error(err, "error writing subtask %s metrics to DB", metric.Name)
+	}
+}
+
 func recordSubtask(basicRes context.BasicRes, subtask *models.Subtask) {
 	where := dal.Where("task_id = ? and name = ?", subtask.TaskID, subtask.Name)
 	if err := basicRes.GetDal().UpdateColumns(subtask, []dal.DalSet{
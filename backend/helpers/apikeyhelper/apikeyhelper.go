@@ -29,6 +29,7 @@ import (
 	"github.com/apache/incubator-devlake/core/models"
 	common "github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/impls/secretprovider"
 	"github.com/spf13/viper"
 	"regexp"
 	"strings"
@@ -49,8 +50,10 @@ type ApiKeyHelper struct {
 
 func NewApiKeyHelper(basicRes context.BasicRes, logger log.Logger) *ApiKeyHelper {
 	cfg := config.GetConfig()
+	// api keys are 128 random letters, the ENCRYPTION_SECRET only keys their digests when it is set, so
+	// the file/kv secret providers work without it
 	encryptionSecret := strings.TrimSpace(cfg.GetString(EncodeKeyEnvStr))
-	if encryptionSecret == "" {
+	if encryptionSecret == "" && secretprovider.RequiresLegacySecret(cfg) {
 		panic("ENCRYPTION_SECRET must be set in environment variable or .env file")
 	}
 	return &ApiKeyHelper{
@@ -0,0 +1,48 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apikeyhelper
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/impls/secretprovider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewApiKeyHelperWithoutEncryptionSecret(t *testing.T) {
+	cfg := config.GetConfig()
+	encryptionSecret, providerType := cfg.GetString(EncodeKeyEnvStr), cfg.GetString(secretprovider.SecretProviderEnvStr)
+	defer func() {
+		cfg.Set(EncodeKeyEnvStr, encryptionSecret)
+		cfg.Set(secretprovider.SecretProviderEnvStr, providerType)
+	}()
+	cfg.Set(EncodeKeyEnvStr, "")
+
+	cfg.Set(secretprovider.SecretProviderEnvStr, "env")
+	assert.Panics(t, func() { NewApiKeyHelper(nil, nil) })
+
+	cfg.Set(secretprovider.SecretProviderEnvStr, "file")
+	helper := NewApiKeyHelper(nil, nil)
+	apiKey, hashedApiKey, err := helper.generateApiKey()
+	assert.Nil(t, err)
+	assert.Len(t, apiKey, apiKeyLen)
+	digest, err := helper.DigestToken(apiKey)
+	assert.Nil(t, err)
+	assert.Equal(t, hashedApiKey, digest)
+}
@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshelper
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "devlake"
+
+// Registry holds all metrics exposed by the `/metrics` endpoint
+var Registry = prometheus.NewRegistry()
+
+// ApiRequestDuration observes the latencies of http requests sent by plugins, labelled by plugin and status code
+var ApiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "api_request_duration_seconds",
+	Help:      "Latencies of http requests sent to the data sources by plugins.",
+	Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+}, []string{"plugin", "code"})
+
+// ApiRateLimitWait observes the time requests spent waiting for the rate limiter before being sent
+var ApiRateLimitWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "api_rate_limit_wait_seconds",
+	Help:      "Time http requests spent waiting for the rate limiter before being sent.",
+	Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+}, []string{"plugin"})
+
+// ApiRateLimit reports the request rate calculated for the api clients
+var ApiRateLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "api_rate_limit_requests_per_second",
+	Help:      "Request rate calculated by the rate limiter of the latest api client created by the plugin.",
+}, []string{"plugin"})
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ApiRequestDuration,
+		ApiRateLimitWait,
+		ApiRateLimit,
+	)
+}
+
+// NewGauge creates a gauge in the devlake namespace and registers it to the Registry
+func NewGauge(name string, help string) prometheus.Gauge {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help})
+	Registry.MustRegister(gauge)
+	return gauge
+}
+
+// NewGaugeVec creates a gauge vector in the devlake namespace and registers it to the Registry
+func NewGaugeVec(name string, help string, labels ...string) *prometheus.GaugeVec {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help}, labels)
+	Registry.MustRegister(gauge)
+	return gauge
+}
+
+// NewCounterVec creates a counter vector in the devlake namespace and registers it to the Registry
+func NewCounterVec(name string, help string, labels ...string) *prometheus.CounterVec {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help}, labels)
+	Registry.MustRegister(counter)
+	return counter
+}
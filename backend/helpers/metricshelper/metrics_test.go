@@ -0,0 +1,43 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshelper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	counter := NewCounterVec("test_total", "Counter for testing.", "plugin")
+	counter.WithLabelValues("github").Add(2)
+	ApiRequestDuration.WithLabelValues("github", "200").Observe(0.3)
+
+	err := testutil.GatherAndCompare(Registry, strings.NewReader(`
+# HELP devlake_test_total Counter for testing.
+# TYPE devlake_test_total counter
+devlake_test_total{plugin="github"} 2
+`), "devlake_test_total")
+	assert.Nil(t, err)
+
+	count, err := testutil.GatherAndCount(Registry, "devlake_api_request_duration_seconds", "go_goroutines")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -24,12 +24,17 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
 	plugin "github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
+	"github.com/apache/incubator-devlake/helpers/tracinghelper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // HttpMinStatusRetryCode is which status will retry
@@ -44,6 +49,7 @@ type ApiAsyncClient struct {
 	maxRetry     int
 	numOfWorkers int
 	logger       log.Logger
+	taskCtx      plugin.TaskContext
 }
 
 const defaultTimeout = 120 * time.Second
@@ -121,6 +127,8 @@ func CreateAsyncApiClient(
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "failed to create scheduler")
 	}
+	scheduler.tickWait = metricshelper.ApiRateLimitWait.WithLabelValues(taskCtx.GetName())
+	metricshelper.ApiRateLimit.WithLabelValues(taskCtx.GetName()).Set(float64(requests) / duration.Seconds())
 
 	// finally, wrap around api client with async sematic
 	return &ApiAsyncClient{
@@ -129,6 +137,7 @@ func CreateAsyncApiClient(
 		retry,
 		numOfWorkers,
 		logger,
+		taskCtx,
 	}, nil
 }
 
@@ -154,6 +163,27 @@ func (apiClient *ApiAsyncClient) DoAsync(
 	handler plugin.ApiAsyncCallback,
 	retry int,
 ) {
+	apiClient.doAsync(method, path, "", query, body, header, handler, retry)
+}
+
+// doAsync carries out an asynchronous request, the urlTemplate is attached to the tracing spans if provided
+func (apiClient *ApiAsyncClient) doAsync(
+	method string,
+	path string,
+	urlTemplate string,
+	query url.Values,
+	body interface{},
+	header http.Header,
+	handler plugin.ApiAsyncCallback,
+	retry int,
+) {
+	// resolve the metrics and the tracing span when the request is enqueued, which is done by the running subtask
+	metrics := plugin.GetSubTaskMetrics(apiClient.taskCtx)
+	traceCtx := apiClient.traceContext()
+	pluginName := ""
+	if apiClient.taskCtx != nil {
+		pluginName = apiClient.taskCtx.GetName()
+	}
 	var request func() errors.Error
 	request = func() errors.Error {
 		var err error
@@ -161,7 +191,28 @@ func (apiClient *ApiAsyncClient) DoAsync(
 		var respBody []byte
 
 		apiClient.logger.Debug("endpoint: %s  method: %s  header: %s  body: %s query: %s", path, method, header, body, query)
+		metrics.IncHttpRequests()
+		attrs := []attribute.KeyValue{
+			attribute.String("http.request.method", method),
+			attribute.String("url.path", path),
+			attribute.Int("devlake.retry", retry),
+		}
+		if urlTemplate != "" {
+			attrs = append(attrs, attribute.String("url.template", urlTemplate))
+		}
+		_, span := tracinghelper.Start(traceCtx, "HTTP "+method, attrs...)
+		requestStart := time.Now()
 		res, err = apiClient.Do(method, path, query, body, header)
+		statusCode := "error"
+		if res != nil {
+			statusCode = strconv.Itoa(res.StatusCode)
+			span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+			if res.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, res.Status)
+			}
+		}
+		tracinghelper.End(span, err)
+		metricshelper.ApiRequestDuration.WithLabelValues(pluginName, statusCode).Observe(time.Since(requestStart).Seconds())
 		if err == ErrIgnoreAndContinue {
 			// make sure defer func got be executed
 			err = nil //nolint
@@ -186,6 +237,9 @@ func (apiClient *ApiAsyncClient) DoAsync(
 			needRetry = true
 			errMessage = err.Error()
 		} else if res.StatusCode >= HttpMinStatusRetryCode {
+			if res.StatusCode == http.StatusTooManyRequests {
+				metrics.IncHttpRateLimited()
+			}
 			needRetry = true
 			errMessage = fmt.Sprintf("Http DoAsync error calling [method:%s path:%s query:%s]. Response: %s", method, path, query, string(respBody))
 			err = errors.HttpStatus(res.StatusCode).New(errMessage)
@@ -197,6 +251,7 @@ func (apiClient *ApiAsyncClient) DoAsync(
 			if retry < apiClient.maxRetry && err != context.Canceled {
 				apiClient.logger.Warn(err, "retry #%d calling %s", retry, path)
 				retry++
+				metrics.IncHttpRetries()
 				apiClient.NextTick(func() errors.Error {
 					apiClient.SubmitBlocking(request)
 					return nil
@@ -218,6 +273,23 @@ func (apiClient *ApiAsyncClient) DoAsync(
 	apiClient.SubmitBlocking(request)
 }
 
+// traceContext returns the go context carrying the tracing span of the running subtask
+func (apiClient *ApiAsyncClient) traceContext() context.Context {
+	if apiClient.taskCtx == nil {
+		return context.Background()
+	}
+	ctx := apiClient.taskCtx.GetContext()
+	if tracker, ok := apiClient.taskCtx.(plugin.RunningSubTaskTracker); ok {
+		if subtaskCtx := tracker.RunningSubTask(); subtaskCtx != nil {
+			ctx = subtaskCtx.GetContext()
+		}
+	}
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
 // DoGetAsync Enqueue an api get request, the request may be sent sometime in future in parallel with other api requests
 func (apiClient *ApiAsyncClient) DoGetAsync(
 	path string,
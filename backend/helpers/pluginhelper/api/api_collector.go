@@ -98,6 +98,7 @@ type ApiCollector struct {
 	*RawDataSubTask
 	args        *ApiCollectorArgs
 	urlTemplate *template.Template
+	inMemory    *inMemoryCollection
 }
 
 // NewApiCollector allocates a new ApiCollector with the given args.
@@ -154,6 +155,7 @@ func (collector *ApiCollector) ensureRawTable() errors.Error {
 func (collector *ApiCollector) Execute() errors.Error {
 	logger := collector.args.Ctx.GetLogger()
 	logger.Info("start api collection")
+	startedAt := time.Now()
 
 	// make sure table is created
 	db := collector.args.Ctx.GetDal()
@@ -167,8 +169,15 @@ func (collector *ApiCollector) Execute() errors.Error {
 	if syncPolicy != nil && syncPolicy.FullSync {
 		isIncremental = false
 	}
-	// flush data if not incremental collection
-	if !isIncremental {
+	skipPersistence, err := collector.isPersistenceSkipped()
+	if err != nil {
+		return err
+	}
+	if skipPersistence {
+		logger.Info("raw data would be kept in memory instead of %s", collector.table)
+		collector.inMemory = collector.startInMemoryCollection(isIncremental)
+	} else if !isIncremental {
+		// flush data if not incremental collection
 		err = db.Delete(&RawData{}, dal.From(collector.table), dal.Where("params = ?", collector.params))
 		if err != nil {
 			return errors.Default.Wrap(err, "error deleting data from collector")
@@ -230,12 +239,13 @@ func (collector *ApiCollector) Execute() errors.Error {
 	err = collector.args.ApiClient.WaitAsync()
 	if err != nil {
 		logger.Error(err, "end api collection error")
-		err = errors.Default.Wrap(err, "Error waiting for async Collector execution")
-	} else {
-		logger.Info("end api collection without error")
+		return errors.Default.Wrap(err, "Error waiting for async Collector execution")
 	}
-
-	return err
+	logger.Info("end api collection without error")
+	if collector.inMemory != nil {
+		return nil
+	}
+	return collector.recordCollection(startedAt, isIncremental)
 }
 
 func (collector *ApiCollector) exec(input interface{}) {
@@ -381,16 +391,17 @@ func (collector *ApiCollector) fetchPagesUndetermined(reqData *RequestData, skip
 	}
 }
 
-func (collector *ApiCollector) generateUrl(pager *Pager, input interface{}) (string, errors.Error) {
+func (collector *ApiCollector) generateUrl(reqData *RequestData) (string, errors.Error) {
 	params := collector.args.Params
 	if collector.args.Options != nil {
 		params = collector.args.Options.GetParams()
 	}
 	var buf bytes.Buffer
 	err := collector.urlTemplate.Execute(&buf, &RequestData{
-		Pager:  pager,
-		Params: params,
-		Input:  input,
+		Pager:      reqData.Pager,
+		Params:     params,
+		Input:      reqData.Input,
+		CustomData: reqData.CustomData,
 	})
 	if err != nil {
 		return "", errors.Convert(err)
@@ -416,7 +427,7 @@ func (collector *ApiCollector) fetchAsync(reqData *RequestData, handler func(int
 			Skip: 0,
 		}
 	}
-	apiUrl, err := collector.generateUrl(reqData.Pager, reqData.Input)
+	apiUrl, err := collector.generateUrl(reqData)
 	if err != nil {
 		panic(err)
 	}
@@ -481,9 +492,14 @@ func (collector *ApiCollector) fetchAsync(reqData *RequestData, handler func(int
 				Input:  reqData.InputJSON,
 			}
 		}
-		err = db.Create(rows, dal.From(collector.table))
-		if err != nil {
-			return errors.Default.Wrap(err, fmt.Sprintf("error inserting raw rows into %s", collector.table))
+		if collector.inMemory != nil {
+			collector.inMemory.add(rows...)
+		} else {
+			err = db.Create(rows, dal.From(collector.table))
+			if err != nil {
+				return errors.Default.Wrap(err, fmt.Sprintf("error inserting raw rows into %s", collector.table))
+			}
+			plugin.GetSubTaskMetrics(collector.args.Ctx).IncRowsWritten(count)
 		}
 		logger.Debug("fetchAsync === total %d rows were saved into database", count)
 		// increase progress only when it was not nested
@@ -495,7 +511,14 @@ func (collector *ApiCollector) fetchAsync(reqData *RequestData, handler func(int
 		}
 		return nil
 	}
-	if collector.args.Method == http.MethodPost {
+	if asyncClient, ok := collector.args.ApiClient.(*ApiAsyncClient); ok {
+		// let the tracing spans of the request carry the url template
+		if collector.args.Method == http.MethodPost {
+			asyncClient.doAsync(http.MethodPost, apiUrl, collector.args.UrlTemplate, apiQuery, reqBody, apiHeader, responseHandler, 0)
+		} else {
+			asyncClient.doAsync(http.MethodGet, apiUrl, collector.args.UrlTemplate, apiQuery, nil, apiHeader, responseHandler, 0)
+		}
+	} else if collector.args.Method == http.MethodPost {
 		collector.args.ApiClient.DoPostAsync(apiUrl, apiQuery, reqBody, apiHeader, responseHandler)
 	} else {
 		collector.args.ApiClient.DoGetAsync(apiUrl, apiQuery, apiHeader, responseHandler)
@@ -47,6 +47,7 @@ func TestFetchPageUndetermined(t *testing.T) {
 	mockDal.On("AutoMigrate", mock.Anything, mock.Anything).Return(nil).Once()
 	mockDal.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 	mockDal.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	mockDal.On("HasTable", mock.Anything).Return(false)
 
 	mockCtx := unithelper.DummySubTaskContext(mockDal)
 
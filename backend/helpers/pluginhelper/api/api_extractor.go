@@ -71,9 +71,28 @@ func setRawDataOrigin(result interface{}, originValue common.RawDataOrigin) bool
 
 // Execute sub-task
 func (extractor *ApiExtractor) Execute() errors.Error {
+	// batch save divider
+	divider := NewBatchSaveDivider(extractor.args.Ctx, extractor.args.BatchSize, extractor.table, extractor.params)
+	// progress
+	extractor.args.Ctx.SetProgress(0, -1)
+
+	// records kept in memory by the collector take the precedence over the raw table
+	if collection := takeInMemoryCollection(extractor.table, extractor.params); collection != nil {
+		extractor.args.Ctx.GetLogger().Info("get %d records of %s from memory", len(collection.rows), extractor.table)
+		divider.SetIncrementalMode(collection.incremental)
+		for _, row := range collection.rows {
+			err := extractor.extract(divider, row)
+			if err != nil {
+				return err
+			}
+		}
+		return divider.Close()
+	}
+
 	// load data from database
 	db := extractor.args.Ctx.GetDal()
 	logger := extractor.args.Ctx.GetLogger()
+	metrics := plugin.GetSubTaskMetrics(extractor.args.Ctx)
 	if !db.HasTable(extractor.table) {
 		return nil
 	}
@@ -93,52 +112,56 @@ func (extractor *ApiExtractor) Execute() errors.Error {
 	}
 	logger.Info("get data from %s where params=%s and got %d", extractor.table, extractor.params, count)
 	defer cursor.Close()
-	// batch save divider
-	divider := NewBatchSaveDivider(extractor.args.Ctx, extractor.args.BatchSize, extractor.table, extractor.params)
 
-	// progress
-	extractor.args.Ctx.SetProgress(0, -1)
-	ctx := extractor.args.Ctx.GetContext()
 	// iterate all rows
 	for cursor.Next() {
-		select {
-		case <-ctx.Done():
-			return errors.Convert(ctx.Err())
-		default:
-		}
 		row := &RawData{}
 		err = db.Fetch(cursor, row)
 		if err != nil {
 			return errors.Default.Wrap(err, "error fetching row")
 		}
-
-		results, err := extractor.args.Extract(row)
+		metrics.IncRowsRead(1)
+		err = extractor.extract(divider, row)
 		if err != nil {
-			return errors.Default.Wrap(err, "error calling plugin Extract implementation")
+			return err
 		}
-		for _, result := range results {
-			// get the batch operator for the specific type
-			batch, err := divider.ForType(reflect.TypeOf(result))
-			if err != nil {
-				return errors.Default.Wrap(err, "error getting batch from result")
-			}
-			// set raw data origin field
-			setRawDataOrigin(result, common.RawDataOrigin{
-				RawDataTable:  extractor.table,
-				RawDataId:     row.ID,
-				RawDataParams: row.Params,
-			})
-			// records get saved into db when slots were max outed
-			err = batch.Add(result)
-			if err != nil {
-				return errors.Default.Wrap(err, "error adding result to batch")
-			}
-		}
-		extractor.args.Ctx.IncProgress(1)
 	}
 
 	// save the last batches
 	return divider.Close()
 }
 
+func (extractor *ApiExtractor) extract(divider *BatchSaveDivider, row *RawData) errors.Error {
+	ctx := extractor.args.Ctx.GetContext()
+	select {
+	case <-ctx.Done():
+		return errors.Convert(ctx.Err())
+	default:
+	}
+	results, err := extractor.args.Extract(row)
+	if err != nil {
+		return errors.Default.Wrap(err, "error calling plugin Extract implementation")
+	}
+	for _, result := range results {
+		// get the batch operator for the specific type
+		batch, err := divider.ForType(reflect.TypeOf(result))
+		if err != nil {
+			return errors.Default.Wrap(err, "error getting batch from result")
+		}
+		// set raw data origin field
+		setRawDataOrigin(result, common.RawDataOrigin{
+			RawDataTable:  extractor.table,
+			RawDataId:     row.ID,
+			RawDataParams: row.Params,
+		})
+		// records get saved into db when slots were max outed
+		err = batch.Add(result)
+		if err != nil {
+			return errors.Default.Wrap(err, "error adding result to batch")
+		}
+	}
+	extractor.args.Ctx.IncProgress(1)
+	return nil
+}
+
 var _ plugin.SubTask = (*ApiExtractor)(nil)
@@ -99,6 +99,27 @@ func (extractor *StatefulApiExtractor[InputType]) Execute() errors.Error {
 	logger := extractor.GetLogger()
 	table := extractor.GetRawDataTable()
 	params := extractor.GetRawDataParams()
+
+	// batch save divider
+	divider := NewBatchSaveDivider(extractor.SubTaskContext, extractor.GetBatchSize(), table, params)
+	divider.SetIncrementalMode(extractor.IsIncremental())
+
+	// progress
+	extractor.SetProgress(0, -1)
+
+	// records kept in memory by the collector take the precedence over the raw table
+	if collection := takeInMemoryCollection(table, params); collection != nil {
+		logger.Info("get %d records of %s from memory", len(collection.rows), table)
+		divider.SetIncrementalMode(extractor.IsIncremental() && collection.incremental)
+		for _, row := range collection.rows {
+			err := extractor.extract(divider, row)
+			if err != nil {
+				return err
+			}
+		}
+		return extractor.close(divider)
+	}
+
 	if !db.HasTable(table) {
 		return nil
 	}
@@ -132,70 +153,76 @@ func (extractor *StatefulApiExtractor[InputType]) Execute() errors.Error {
 		return errors.Default.Wrap(err, "error getting IDs")
 	}
 
-	// batch save divider
-	divider := NewBatchSaveDivider(extractor.SubTaskContext, extractor.GetBatchSize(), table, params)
-	divider.SetIncrementalMode(extractor.IsIncremental())
-
-	// progress
-	extractor.SetProgress(0, -1)
-	ctx := extractor.GetContext()
-
 	// process each record individually by ID
+	metrics := plugin.GetSubTaskMetrics(extractor.SubTaskContext)
 	for _, id := range ids {
-		select {
-		case <-ctx.Done():
-			return errors.Convert(ctx.Err())
-		default:
-		}
-
 		// load full record by ID
 		row := &RawData{}
 		err := db.First(row, dal.From(table), dal.Where("id = ?", id))
 		if err != nil {
 			return errors.Default.Wrap(err, "error loading full row by ID")
 		}
-
-		body := new(InputType)
-		err = errors.Convert(json.Unmarshal(row.Data, body))
+		metrics.IncRowsRead(1)
+		err = extractor.extract(divider, row)
 		if err != nil {
 			return err
 		}
+	}
 
-		if extractor.BeforeExtract != nil {
-			err = extractor.BeforeExtract(body, extractor.SubtaskStateManager)
-			if err != nil {
-				return err
-			}
-		}
+	return extractor.close(divider)
+}
 
-		results, err := extractor.Extract(body, row)
+func (extractor *StatefulApiExtractor[InputType]) extract(divider *BatchSaveDivider, row *RawData) errors.Error {
+	ctx := extractor.GetContext()
+	select {
+	case <-ctx.Done():
+		return errors.Convert(ctx.Err())
+	default:
+	}
+
+	body := new(InputType)
+	err := errors.Convert(json.Unmarshal(row.Data, body))
+	if err != nil {
+		return err
+	}
+
+	if extractor.BeforeExtract != nil {
+		err = extractor.BeforeExtract(body, extractor.SubtaskStateManager)
 		if err != nil {
-			return errors.Default.Wrap(err, "error calling plugin Extract implementation")
+			return err
 		}
+	}
 
-		for _, result := range results {
-			// get the batch operator for the specific type
-			batch, err := divider.ForType(reflect.TypeOf(result))
-			if err != nil {
-				return errors.Default.Wrap(err, "error getting batch from result")
-			}
-			// set raw data origin field
-			setRawDataOrigin(result, common.RawDataOrigin{
-				RawDataTable:  table,
-				RawDataParams: params,
-				RawDataId:     row.ID,
-			})
-			// records get saved into db when slots were max outed
-			err = batch.Add(result)
-			if err != nil {
-				return errors.Default.Wrap(err, "error adding result to batch")
-			}
+	results, err := extractor.Extract(body, row)
+	if err != nil {
+		return errors.Default.Wrap(err, "error calling plugin Extract implementation")
+	}
+
+	for _, result := range results {
+		// get the batch operator for the specific type
+		batch, err := divider.ForType(reflect.TypeOf(result))
+		if err != nil {
+			return errors.Default.Wrap(err, "error getting batch from result")
+		}
+		// set raw data origin field
+		setRawDataOrigin(result, common.RawDataOrigin{
+			RawDataTable:  extractor.GetRawDataTable(),
+			RawDataParams: extractor.GetRawDataParams(),
+			RawDataId:     row.ID,
+		})
+		// records get saved into db when slots were max outed
+		err = batch.Add(result)
+		if err != nil {
+			return errors.Default.Wrap(err, "error adding result to batch")
 		}
-		extractor.IncProgress(1)
 	}
+	extractor.IncProgress(1)
+	return nil
+}
 
+func (extractor *StatefulApiExtractor[InputType]) close(divider *BatchSaveDivider) errors.Error {
 	// save the last batches
-	err = divider.Close()
+	err := divider.Close()
 	if err != nil {
 		return err
 	}
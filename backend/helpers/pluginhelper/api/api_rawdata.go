@@ -54,6 +54,10 @@ type RawDataSubTaskArgs struct {
 	Params any `comment:"To identify a set of records with same UrlTemplate, i.e. {ConnectionId, BoardId} for jira entities"`
 
 	Options TaskOptions `comment:"To identify a set of records with same UrlTemplate, i.e. {ConnectionId, BoardId} for jira entities"`
+
+	// SkipPersistence hands over collected records to the extractor of the same Table and Params in memory
+	// instead of saving them into the raw table, it could be enabled by models.RawDataRetentionPolicy as well
+	SkipPersistence bool `comment:"Keep raw data in memory for the extractor instead of saving it into the raw table"`
 }
 
 // RawDataSubTask is Common features for raw data sub-tasks
@@ -0,0 +1,128 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+// inMemoryRawData holds records of collections skipping persistence by raw table and params, the records
+// are taken by the extractor of the same raw table and params, a new collection replaces the old one and
+// the ones never taken are dropped when the task ends
+var inMemoryRawData sync.Map
+
+type inMemoryCollection struct {
+	lock        sync.Mutex
+	owner       plugin.SubTaskContext
+	rows        []*RawData
+	incremental bool
+}
+
+func (c *inMemoryCollection) add(rows ...*RawData) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	for _, row := range rows {
+		// the records are never persisted, so they have no id and the extracted records get 0 as _raw_data_id
+		row.CreatedAt = now
+		c.rows = append(c.rows, row)
+	}
+}
+
+func inMemoryRawDataKey(table, params string) string {
+	return table + "\x00" + params
+}
+
+// startInMemoryCollection drops records left by the previous collection which were never extracted, nested
+// collectors of the same subtask (i.e. StatefulApiCollector) share the collection
+func (r *RawDataSubTask) startInMemoryCollection(incremental bool) *inMemoryCollection {
+	collection := &inMemoryCollection{owner: r.args.Ctx, incremental: incremental}
+	key := inMemoryRawDataKey(r.table, r.params)
+	if existing, loaded := inMemoryRawData.LoadOrStore(key, collection); loaded {
+		if existing := existing.(*inMemoryCollection); existing.owner == r.args.Ctx {
+			return existing
+		}
+		inMemoryRawData.Store(key, collection)
+	}
+	return collection
+}
+
+// takeInMemoryCollection returns records of the collection skipping persistence, nil if there was none
+func takeInMemoryCollection(table, params string) *inMemoryCollection {
+	collection, ok := inMemoryRawData.LoadAndDelete(inMemoryRawDataKey(table, params))
+	if !ok {
+		return nil
+	}
+	return collection.(*inMemoryCollection)
+}
+
+// DropInMemoryRawData drops the records kept in memory by the subtasks of the task which were never extracted,
+// i.e. the extractor was disabled or the task failed in between
+func DropInMemoryRawData(taskCtx plugin.TaskContext) {
+	inMemoryRawData.Range(func(key, value interface{}) bool {
+		if owner := value.(*inMemoryCollection).owner; owner != nil && owner.TaskContext() == taskCtx {
+			inMemoryRawData.CompareAndDelete(key, value)
+		}
+		return true
+	})
+}
+
+// isPersistenceSkipped checks whether collected records should be kept in memory instead of the raw table
+func (r *RawDataSubTask) isPersistenceSkipped() (bool, errors.Error) {
+	if r.args.SkipPersistence {
+		return true, nil
+	}
+	policy, err := loadRawDataRetentionPolicy(r.args.Ctx.GetDal(), r.table)
+	if err != nil {
+		return false, err
+	}
+	return policy != nil && policy.SkipPersistence, nil
+}
+
+// recordCollection records the collection for RawDataRetentionPolicy.KeepCollections
+func (r *RawDataSubTask) recordCollection(startedAt time.Time, incremental bool) errors.Error {
+	db := r.args.Ctx.GetDal()
+	if !db.HasTable(&models.RawDataCollection{}) {
+		return nil
+	}
+	return db.Create(&models.RawDataCollection{
+		RawDataTable:  r.table,
+		RawDataParams: r.params,
+		Incremental:   incremental,
+		StartedAt:     startedAt,
+		FinishedAt:    time.Now(),
+	})
+}
+
+func loadRawDataRetentionPolicy(db dal.Dal, table string) (*models.RawDataRetentionPolicy, errors.Error) {
+	if !db.HasTable(&models.RawDataRetentionPolicy{}) {
+		return nil, nil
+	}
+	policies := make([]*models.RawDataRetentionPolicy, 0)
+	err := db.All(&policies, dal.Where("enable = ?", true))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load raw data retention policies")
+	}
+	return models.MatchRawDataRetentionPolicy(policies, table), nil
+}
@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTaskContext struct {
+	plugin.TaskContext
+}
+
+type testSubTaskContext struct {
+	plugin.SubTaskContext
+	taskCtx plugin.TaskContext
+}
+
+func (c *testSubTaskContext) TaskContext() plugin.TaskContext {
+	return c.taskCtx
+}
+
+func TestInMemoryCollection(t *testing.T) {
+	subtask := &RawDataSubTask{args: &RawDataSubTaskArgs{}, table: "_raw_test_memory", params: `{"ConnectionId":1}`}
+
+	collection := subtask.startInMemoryCollection(false)
+	collection.add(&RawData{Data: []byte(`1`)}, &RawData{Data: []byte(`2`)})
+	// nested collectors of the same subtask share the collection
+	subtask.startInMemoryCollection(false).add(&RawData{Data: []byte(`3`)})
+
+	assert.Nil(t, takeInMemoryCollection(subtask.table, `{"ConnectionId":2}`))
+	taken := takeInMemoryCollection(subtask.table, subtask.params)
+	if assert.NotNil(t, taken) && assert.Len(t, taken.rows, 3) {
+		// the records are never persisted, so they have no id
+		assert.Equal(t, uint64(0), taken.rows[0].ID)
+		assert.Equal(t, []byte(`3`), taken.rows[2].Data)
+	}
+	// records are taken only once
+	assert.Nil(t, takeInMemoryCollection(subtask.table, subtask.params))
+}
+
+func TestDropInMemoryRawData(t *testing.T) {
+	task1, task2 := &testTaskContext{}, &testTaskContext{}
+	subtask1 := &RawDataSubTask{
+		args:   &RawDataSubTaskArgs{Ctx: &testSubTaskContext{taskCtx: task1}},
+		table:  "_raw_test_memory",
+		params: `{"ConnectionId":1}`,
+	}
+	subtask2 := &RawDataSubTask{
+		args:   &RawDataSubTaskArgs{Ctx: &testSubTaskContext{taskCtx: task2}},
+		table:  "_raw_test_memory",
+		params: `{"ConnectionId":2}`,
+	}
+	subtask1.startInMemoryCollection(false).add(&RawData{Data: []byte(`1`)})
+	subtask2.startInMemoryCollection(false).add(&RawData{Data: []byte(`2`)})
+
+	DropInMemoryRawData(task1)
+	assert.Nil(t, takeInMemoryCollection(subtask1.table, subtask1.params))
+	assert.NotNil(t, takeInMemoryCollection(subtask2.table, subtask2.params))
+}
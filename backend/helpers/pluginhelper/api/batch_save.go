@@ -27,6 +27,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/plugin"
 )
 
 // BatchSave performs multiple records persistence of a specific type in one sql query to improve the performance
@@ -45,6 +46,7 @@ type BatchSave struct {
 	tableName  string
 	mutex      sync.Mutex
 	lastErr    errors.Error
+	metrics    *plugin.SubTaskMetrics
 }
 
 // NewBatchSave creates a new BatchSave instance
@@ -74,6 +76,7 @@ func NewBatchSave(basicRes context.BasicRes, slotType reflect.Type, size int, ta
 		valueIndex: make(map[string]int),
 		primaryKey: primaryKey,
 		tableName:  tn,
+		metrics:    plugin.GetSubTaskMetrics(basicRes),
 	}, nil
 }
 
@@ -134,6 +137,7 @@ func (c *BatchSave) flushWithoutLocking() errors.Error {
 		return err
 	}
 	c.log.Debug("batch save flush total %d records to database", c.current)
+	c.metrics.IncRowsWritten(c.current)
 	c.current = 0
 	c.valueIndex = make(map[string]int)
 	return nil
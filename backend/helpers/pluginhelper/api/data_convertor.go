@@ -89,6 +89,7 @@ func (converter *DataConverter) Execute() errors.Error {
 	cursor := converter.args.Input
 	defer cursor.Close()
 	ctx := converter.args.Ctx.GetContext()
+	metrics := plugin.GetSubTaskMetrics(converter.args.Ctx)
 	// iterate all rows
 	for cursor.Next() {
 		select {
@@ -101,6 +102,7 @@ func (converter *DataConverter) Execute() errors.Error {
 		if err != nil {
 			return errors.Default.Wrap(err, "error fetching rows")
 		}
+		metrics.IncRowsRead(1)
 
 		results, err := converter.args.Convert(inputRow)
 		if err != nil {
@@ -142,6 +142,7 @@ func (converter *StatefulDataConverter[InputType]) Execute() errors.Error {
 	}
 	defer cursor.Close()
 	ctx := converter.GetContext()
+	metrics := plugin.GetSubTaskMetrics(converter.SubTaskContext)
 	// iterate all rows
 	for cursor.Next() {
 		select {
@@ -154,6 +155,7 @@ func (converter *StatefulDataConverter[InputType]) Execute() errors.Error {
 		if err != nil {
 			return errors.Default.Wrap(err, "error fetching rows")
 		}
+		metrics.IncRowsRead(1)
 
 		if converter.BeforeConvert != nil {
 			err = converter.BeforeConvert(inputRow, converter.SubtaskStateManager)
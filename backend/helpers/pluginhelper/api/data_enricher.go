@@ -75,6 +75,7 @@ func (enricher *DataEnricher[InputRowType]) Execute() errors.Error {
 	cursor := enricher.args.Input
 	defer cursor.Close()
 	ctx := enricher.args.Ctx.GetContext()
+	metrics := plugin.GetSubTaskMetrics(enricher.args.Ctx)
 	// iterate all rows
 	for cursor.Next() {
 		select {
@@ -87,6 +88,7 @@ func (enricher *DataEnricher[InputRowType]) Execute() errors.Error {
 		if err != nil {
 			return errors.Default.Wrap(err, "error fetching rows")
 		}
+		metrics.IncRowsRead(1)
 
 		results, err := enricher.args.Enrich(inputRow)
 		if err != nil {
@@ -88,6 +88,7 @@ type GraphqlCollector struct {
 	args         *GraphqlCollectorArgs
 	workerErrors []error
 	batchSave    *BatchSave
+	inMemory     *inMemoryCollection
 }
 
 // ErrFinishCollect is an error which will finish this collector
@@ -131,6 +132,7 @@ func NewGraphqlCollector(args GraphqlCollectorArgs) (*GraphqlCollector, errors.E
 func (collector *GraphqlCollector) Execute() errors.Error {
 	logger := collector.args.Ctx.GetLogger()
 	logger.Info("start graphql collection")
+	startedAt := time.Now()
 
 	// make sure table is created
 	db := collector.args.Ctx.GetDal()
@@ -138,8 +140,15 @@ func (collector *GraphqlCollector) Execute() errors.Error {
 	if err != nil {
 		return errors.Default.Wrap(err, "error running auto-migrate")
 	}
-	// flush data if not incremental collection
-	if !collector.args.Incremental {
+	skipPersistence, err := collector.isPersistenceSkipped()
+	if err != nil {
+		return err
+	}
+	if skipPersistence {
+		logger.Info("raw data would be kept in memory instead of %s", collector.table)
+		collector.inMemory = collector.startInMemoryCollection(collector.args.Incremental)
+	} else if !collector.args.Incremental {
+		// flush data if not incremental collection
 		err = db.Delete(&RawData{}, dal.From(collector.table), dal.Where("params = ?", collector.params))
 		if err != nil {
 			return errors.Default.Wrap(err, "error deleting data from collector")
@@ -195,7 +204,10 @@ func (collector *GraphqlCollector) Execute() errors.Error {
 	}
 
 	err = collector.batchSave.Close()
-	return err
+	if err != nil || collector.inMemory != nil {
+		return err
+	}
+	return collector.recordCollection(startedAt, collector.args.Incremental)
 }
 
 func (collector *GraphqlCollector) exec(input interface{}) {
@@ -300,11 +312,16 @@ func (collector *GraphqlCollector) fetchAsync(reqData *GraphqlRequestData, handl
 			Input:  variablesJson,
 		}
 		// collector.batchSave.Add(row)
+		if collector.inMemory != nil {
+			collector.inMemory.add(row)
+			continue
+		}
 		err = db.Create(row, dal.From(collector.table))
 		if err != nil {
 			collector.checkError(errors.Default.Wrap(err, `not created row table in graphql collector`))
 			return
 		}
+		plugin.GetSubTaskMetrics(collector.args.Ctx).IncRowsWritten(1)
 	}
 	if err != nil {
 		if errors.Is(err, ErrFinishCollect) {
@@ -28,6 +28,7 @@ import (
 	"github.com/apache/incubator-devlake/core/log"
 
 	"github.com/panjf2000/ants/v2"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // CalcTickInterval calculates tick interval for number of works to be
@@ -53,6 +54,8 @@ type WorkerScheduler struct {
 	counter      int32
 	logger       log.Logger
 	tickInterval time.Duration
+	// observes how long tasks wait for the ticker, which is the rate limit wait of api requests
+	tickWait prometheus.Observer
 }
 
 //var callframeEnabled = os.Getenv("ASYNC_CF") == "true"
@@ -104,10 +107,14 @@ func (s *WorkerScheduler) SubmitBlocking(task func() errors.Error) {
 		}
 
 		// normal error
+		waitStart := time.Now()
 		select {
 		case <-s.ctx.Done():
 			panic(s.ctx.Err())
 		case <-s.ticker.C:
+			if s.tickWait != nil {
+				s.tickWait.Observe(time.Since(waitStart).Seconds())
+			}
 			err := task()
 			if err != nil {
 				panic(err)
@@ -0,0 +1,153 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginhelper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// NdjsonFileIterator make iterating rows from JSON lines file easier, every non-empty line should be a JSON object
+// which would be turned into a `map[string]interface{}`. Scalar values are converted to their textual form so rows
+// look the same as the ones read from CSV, nested objects and arrays are kept as JSON text.
+//
+// Example NDJSON format:
+//
+//	{"id": 123, "name": "foobar", "json": {"url": "https://example.com"}, "created_at": "2022-05-05 09:56:43"}
+type NdjsonFileIterator struct {
+	file   io.ReadCloser
+	reader *bufio.Reader
+	fields []string
+	row    map[string]any
+	next   map[string]any
+	line   int
+}
+
+// NewNdjsonFileIteratorFromFile create a `*NdjsonFileIterator` from a file descriptor
+func NewNdjsonFileIteratorFromFile(file io.ReadCloser) (*NdjsonFileIterator, errors.Error) {
+	iterator := &NdjsonFileIterator{
+		file:   file,
+		reader: bufio.NewReader(file),
+	}
+	// the first record determines the columns
+	row, err := iterator.readRow()
+	if err != nil {
+		return nil, err
+	}
+	if row != nil {
+		for field := range row {
+			iterator.fields = append(iterator.fields, field)
+		}
+		sort.Strings(iterator.fields)
+	}
+	iterator.next = row
+	return iterator, nil
+}
+
+// Close releases resource
+func (ni *NdjsonFileIterator) Close() {
+	err := ni.file.Close()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// HasNextWithError returns a boolean to indicate whether there was any row to be `Fetch`
+func (ni *NdjsonFileIterator) HasNextWithError() (bool, errors.Error) {
+	if ni.next != nil {
+		ni.row, ni.next = ni.next, nil
+		return true, nil
+	}
+	row, err := ni.readRow()
+	ni.row = row
+	if err != nil {
+		return false, err
+	}
+	return row != nil, nil
+}
+
+// Fetch returns current row as a map
+func (ni *NdjsonFileIterator) Fetch() map[string]any {
+	row := make(map[string]any, len(ni.row))
+	for field, value := range ni.row {
+		row[field] = value
+	}
+	return row
+}
+
+// GetColumns returns the keys of the first record in alphabetical order
+func (ni *NdjsonFileIterator) GetColumns() []string {
+	return ni.fields
+}
+
+// readRow returns the next non-empty line as a row, or nil at the end of file
+func (ni *NdjsonFileIterator) readRow() (map[string]any, errors.Error) {
+	for {
+		line, err := ni.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Convert(err)
+		}
+		ni.line++
+		if ni.line == 1 {
+			line = bytes.TrimPrefix(line, utf8Bom)
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return ni.parseLine(line)
+		}
+		if err == io.EOF {
+			return nil, nil
+		}
+	}
+}
+
+func (ni *NdjsonFileIterator) parseLine(line []byte) (map[string]any, errors.Error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid JSON object on line %d", ni.line))
+	}
+	row := make(map[string]any, len(object))
+	for field, value := range object {
+		switch v := value.(type) {
+		case nil:
+			row[field] = nil
+		case string:
+			row[field] = v
+		case json.Number:
+			row[field] = v.String()
+		case bool:
+			row[field] = strconv.FormatBool(v)
+		default:
+			text, err := json.Marshal(v)
+			if err != nil {
+				return nil, errors.Convert(err)
+			}
+			row[field] = string(text)
+		}
+	}
+	return row, nil
+}
@@ -0,0 +1,73 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginhelper
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// RecordIterator iterates rows of a tabular file, each row is fetched as a map keyed by column names
+// with string values, or nil for absent values
+type RecordIterator interface {
+	// HasNextWithError returns a boolean to indicate whether there was any row to be `Fetch`
+	HasNextWithError() (bool, errors.Error)
+	// Fetch returns current row as a map
+	Fetch() map[string]any
+	// GetColumns returns the column names
+	GetColumns() []string
+	// Close releases resource
+	Close()
+}
+
+var _ RecordIterator = (*CsvFileIterator)(nil)
+var _ RecordIterator = (*NdjsonFileIterator)(nil)
+var _ RecordIterator = (*XlsxFileIterator)(nil)
+
+var xlsxMagic = []byte("PK\x03\x04")
+var utf8Bom = []byte("\xef\xbb\xbf")
+
+const sniffLen = 512
+
+// NewRecordIteratorFromFile creates a RecordIterator for the file by sniffing its content:
+// zip archives are read as XLSX workbooks, files starting with `{` as JSON lines, others as CSV
+func NewRecordIteratorFromFile(file io.ReadCloser) (RecordIterator, errors.Error) {
+	reader := bufio.NewReader(file)
+	head, err := reader.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, errors.Convert(err)
+	}
+	sniffed := readCloser{Reader: reader, Closer: file}
+	if bytes.HasPrefix(head, xlsxMagic) {
+		return NewXlsxFileIteratorFromFile(sniffed)
+	}
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, utf8Bom), " \t\r\n")
+	if len(head) > 0 && head[0] == '{' {
+		return NewNdjsonFileIteratorFromFile(sniffed)
+	}
+	return NewCsvFileIteratorFromFile(sniffed)
+}
+
+// readCloser reads from the sniffing buffer and closes the underlying file
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
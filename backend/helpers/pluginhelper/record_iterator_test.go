@@ -0,0 +1,136 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginhelper
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fetchAll(t *testing.T, iterator RecordIterator) []map[string]any {
+	var rows []map[string]any
+	for {
+		hasNext, err := iterator.HasNextWithError()
+		assert.Nil(t, err)
+		if !hasNext {
+			return rows
+		}
+		rows = append(rows, iterator.Fetch())
+	}
+}
+
+func TestRecordIteratorCsv(t *testing.T) {
+	iterator, err := NewRecordIteratorFromFile(io.NopCloser(strings.NewReader("id,name\n1,foo\n2,bar\n")))
+	assert.Nil(t, err)
+	assert.IsType(t, &CsvFileIterator{}, iterator)
+	assert.Equal(t, []map[string]any{
+		{"id": "1", "name": "foo"},
+		{"id": "2", "name": "bar"},
+	}, fetchAll(t, iterator))
+}
+
+func TestRecordIteratorNdjson(t *testing.T) {
+	content := "\xef\xbb\xbf{\"id\": 1, \"name\": \"foo\", \"done\": true, \"labels\": [\"a\",\"b\"], \"parent\": null}\n" +
+		"\n" +
+		"{\"id\": 2.5, \"name\": \"bar\"}"
+	iterator, err := NewRecordIteratorFromFile(io.NopCloser(strings.NewReader(content)))
+	assert.Nil(t, err)
+	assert.IsType(t, &NdjsonFileIterator{}, iterator)
+	assert.Equal(t, []string{"done", "id", "labels", "name", "parent"}, iterator.GetColumns())
+	assert.Equal(t, []map[string]any{
+		{"id": "1", "name": "foo", "done": "true", "labels": `["a","b"]`, "parent": nil},
+		{"id": "2.5", "name": "bar"},
+	}, fetchAll(t, iterator))
+
+	iterator, err = NewRecordIteratorFromFile(io.NopCloser(strings.NewReader("{\"id\": 1}\n{\"id\": \n")))
+	assert.Nil(t, err)
+	hasNext, err := iterator.HasNextWithError()
+	assert.True(t, hasNext)
+	assert.Nil(t, err)
+	hasNext, err = iterator.HasNextWithError()
+	assert.False(t, hasNext)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func buildXlsx(t *testing.T, files map[string]string) io.ReadCloser {
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	return io.NopCloser(buf)
+}
+
+func TestRecordIteratorXlsx(t *testing.T) {
+	file := buildXlsx(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Issues" sheetId="1" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="styles.xml"/><Relationship Id="rId2" Target="worksheets/issues.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>id</t></si><si><t>title</t></si><si><t>created_date</t></si><si><t>done</t></si><si><r><t>rich </t></r><r><t>text</t></r></si></sst>`,
+		"xl/styles.xml": `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<numFmts><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd hh:mm"/><numFmt numFmtId="165" formatCode="&quot;days&quot; 0.0"/></numFmts>` +
+			`<cellXfs><xf numFmtId="0"/><xf numFmtId="164"/><xf numFmtId="14"/><xf numFmtId="165"/></cellXfs></styleSheet>`,
+		"xl/worksheets/issues.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c></row>` +
+			`<row r="2"><c r="A2"><v>1</v></c><c r="B2" t="s"><v>4</v></c><c r="C2" s="1"><v>44927.5</v></c><c r="D2" t="b"><v>1</v></c></row>` +
+			`<row r="3"><c r="A3" s="3"><v>2.5</v></c><c r="C3" s="2"><v>44928</v></c></row>` +
+			`<row r="4"><c r="B4" t="inlineStr"><is><t></t></is></c></row>` +
+			`<row r="5"><c r="A5" t="str"><v>3</v></c><c r="B5" t="inlineStr"><is><t>inline</t></is></c></row>` +
+			// cells without reference follow the previous ones
+			`<row r="6"><c r="B6" t="str"><v>no ref</v></c><c s="2"><v>1</v></c><c t="b"><v>0</v></c></row>` +
+			`</sheetData></worksheet>`,
+	})
+	iterator, err := NewRecordIteratorFromFile(file)
+	assert.Nil(t, err)
+	assert.IsType(t, &XlsxFileIterator{}, iterator)
+	assert.Equal(t, []string{"id", "title", "created_date", "done"}, iterator.GetColumns())
+	assert.Equal(t, []map[string]any{
+		{"id": "1", "title": "rich text", "created_date": "2023-01-01 12:00:00", "done": "true"},
+		{"id": "2.5", "title": "", "created_date": "2023-01-02 00:00:00", "done": ""},
+		{"id": "3", "title": "inline", "created_date": "", "done": ""},
+		{"id": "", "title": "no ref", "created_date": "1900-01-01 00:00:00", "done": "false"},
+	}, fetchAll(t, iterator))
+	iterator.Close()
+}
+
+func TestXlsxDate(t *testing.T) {
+	format := func(serial float64, date1904 bool) string {
+		return xlsxDate(serial, date1904).Format("2006-01-02 15:04:05")
+	}
+	// time only values
+	assert.Equal(t, "1899-12-30 06:00:00", format(0.25, false))
+	// the 1900 date system counts 1900-02-29 which doesn't exist
+	assert.Equal(t, "1900-01-01 00:00:00", format(1, false))
+	assert.Equal(t, "1900-02-28 12:00:00", format(59.5, false))
+	assert.Equal(t, "1900-02-28 00:00:00", format(60, false))
+	assert.Equal(t, "1900-03-01 00:00:00", format(61, false))
+	assert.Equal(t, "2023-01-01 12:00:00", format(44927.5, false))
+	assert.Equal(t, "1904-01-02 00:00:00", format(1, true))
+	assert.Equal(t, "2023-01-01 12:00:00", format(43465.5, true))
+}
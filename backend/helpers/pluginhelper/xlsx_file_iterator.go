@@ -0,0 +1,377 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginhelper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// XlsxFileIterator make iterating rows from the first worksheet of a xlsx workbook easier, the first row is
+// treated as column names and every following non-empty row is turned into a `map[string]interface{}`.
+// Cell values are returned in their textual form, cells formatted as date are rendered as `2006-01-02 15:04:05`.
+type XlsxFileIterator struct {
+	file          io.ReadCloser
+	sheet         io.ReadCloser
+	decoder       *xml.Decoder
+	sharedStrings []string
+	dateStyles    []bool
+	date1904      bool
+	fields        []string
+	row           []string
+}
+
+type xlsxRichText struct {
+	T *string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt *xlsxRichText) text() string {
+	if rt.T != nil {
+		return *rt.T
+	}
+	var sb strings.Builder
+	for _, r := range rt.R {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxRow struct {
+	Cells []struct {
+		Ref    string        `xml:"r,attr"`
+		Type   string        `xml:"t,attr"`
+		Style  int           `xml:"s,attr"`
+		Value  *string       `xml:"v"`
+		Inline *xlsxRichText `xml:"is"`
+	} `xml:"c"`
+}
+
+// xlsxEpoch is the day 0 of the 1900 date system, which counts the non-existing 1900-02-29 as the day 60,
+// so the days before it are shifted by one in xlsxDate
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+var xlsxEpoch1904 = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+var xlsxNonDateParts = regexp.MustCompile(`"[^"]*"|\[[^\]]*\]|\\.`)
+
+// NewXlsxFileIteratorFromFile create a `*XlsxFileIterator` from a file descriptor
+func NewXlsxFileIteratorFromFile(file io.ReadCloser) (*XlsxFileIterator, errors.Error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid xlsx file")
+	}
+	entries := make(map[string]*zip.File, len(archive.File))
+	for _, entry := range archive.File {
+		entries[entry.Name] = entry
+	}
+	iterator := &XlsxFileIterator{file: file}
+	sheetPath, date1904, lakeErr := xlsxFirstSheet(entries)
+	if lakeErr != nil {
+		return nil, lakeErr
+	}
+	iterator.date1904 = date1904
+	if iterator.sharedStrings, lakeErr = xlsxSharedStrings(entries); lakeErr != nil {
+		return nil, lakeErr
+	}
+	if iterator.dateStyles, lakeErr = xlsxDateStyles(entries); lakeErr != nil {
+		return nil, lakeErr
+	}
+	sheet, ok := entries[sheetPath]
+	if !ok {
+		return nil, errors.BadInput.New(fmt.Sprintf("worksheet %s not found in xlsx file", sheetPath))
+	}
+	iterator.sheet, err = sheet.Open()
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	iterator.decoder = xml.NewDecoder(iterator.sheet)
+	// load field names
+	hasHeader, lakeErr := iterator.HasNextWithError()
+	if lakeErr != nil {
+		return nil, lakeErr
+	}
+	if !hasHeader {
+		return nil, errors.BadInput.New("the first worksheet is empty")
+	}
+	for _, field := range iterator.row {
+		iterator.fields = append(iterator.fields, strings.TrimSpace(field))
+	}
+	iterator.row = nil
+	return iterator, nil
+}
+
+// Close releases resource
+func (xi *XlsxFileIterator) Close() {
+	// nolint
+	xi.sheet.Close()
+	err := xi.file.Close()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// HasNextWithError returns a boolean to indicate whether there was any row to be `Fetch`, empty rows are skipped
+func (xi *XlsxFileIterator) HasNextWithError() (bool, errors.Error) {
+	for {
+		token, err := xi.decoder.Token()
+		if err == io.EOF {
+			xi.row = nil
+			return false, nil
+		}
+		if err != nil {
+			xi.row = nil
+			return false, errors.Convert(err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		row := &xlsxRow{}
+		if err := xi.decoder.DecodeElement(row, &start); err != nil {
+			xi.row = nil
+			return false, errors.Convert(err)
+		}
+		values, lakeErr := xi.rowValues(row)
+		if lakeErr != nil {
+			xi.row = nil
+			return false, lakeErr
+		}
+		if len(values) > 0 {
+			xi.row = values
+			return true, nil
+		}
+	}
+}
+
+// Fetch returns current row as a map
+func (xi *XlsxFileIterator) Fetch() map[string]any {
+	row := make(map[string]any)
+	for index, field := range xi.fields {
+		if field == "" {
+			continue
+		}
+		if index < len(xi.row) {
+			row[field] = xi.row[index]
+		} else {
+			row[field] = ""
+		}
+	}
+	return row
+}
+
+// GetColumns the column names of the worksheet
+func (xi *XlsxFileIterator) GetColumns() []string {
+	return xi.fields
+}
+
+// rowValues returns the cell values of the row by column position, or nil if all of them are empty
+func (xi *XlsxFileIterator) rowValues(row *xlsxRow) ([]string, errors.Error) {
+	var values []string
+	empty := true
+	column := -1
+	for _, cell := range row.Cells {
+		// a cell without reference follows the previous one
+		if cell.Ref != "" {
+			column = xlsxColumnIndex(cell.Ref)
+		} else {
+			column++
+		}
+		value, err := xi.cellValue(cell.Type, cell.Style, cell.Value, cell.Inline)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid value of cell %s", cell.Ref))
+		}
+		for len(values) <= column {
+			values = append(values, "")
+		}
+		values[column] = value
+		if value != "" {
+			empty = false
+		}
+	}
+	if empty {
+		return nil, nil
+	}
+	return values, nil
+}
+
+func (xi *XlsxFileIterator) cellValue(cellType string, style int, value *string, inline *xlsxRichText) (string, errors.Error) {
+	if cellType == "inlineStr" {
+		if inline == nil {
+			return "", nil
+		}
+		return inline.text(), nil
+	}
+	if value == nil {
+		return "", nil
+	}
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(*value)
+		if err != nil || index < 0 || index >= len(xi.sharedStrings) {
+			return "", errors.BadInput.New(fmt.Sprintf("invalid shared string index %s", *value))
+		}
+		return xi.sharedStrings[index], nil
+	case "b":
+		return strconv.FormatBool(*value == "1"), nil
+	case "", "n":
+		if style >= 0 && style < len(xi.dateStyles) && xi.dateStyles[style] {
+			serial, err := strconv.ParseFloat(*value, 64)
+			if err != nil {
+				return "", errors.Convert(err)
+			}
+			return xlsxDate(serial, xi.date1904).Format("2006-01-02 15:04:05"), nil
+		}
+	}
+	return *value, nil
+}
+
+// xlsxDate converts the serial number of a date cell to time, rounded to seconds
+func xlsxDate(serial float64, date1904 bool) time.Time {
+	days, fraction := math.Modf(serial)
+	epoch := xlsxEpoch
+	if date1904 {
+		epoch = xlsxEpoch1904
+	} else if days >= 1 && days < 60 {
+		// before the non-existing 1900-02-29, the day 60 falls on 1900-02-28 as well
+		days++
+	}
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(math.Round(fraction*86400)) * time.Second)
+}
+
+// xlsxColumnIndex converts the column letters of a cell reference like `AB12` to a zero-based index
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		index = index*26 + int(c-'A'+1)
+	}
+	return index - 1
+}
+
+func xlsxDecode(entries map[string]*zip.File, name string, v any) (bool, errors.Error) {
+	entry, ok := entries[name]
+	if !ok {
+		return false, nil
+	}
+	reader, err := entry.Open()
+	if err != nil {
+		return false, errors.Convert(err)
+	}
+	defer reader.Close()
+	if err := xml.NewDecoder(reader).Decode(v); err != nil {
+		return false, errors.BadInput.Wrap(err, fmt.Sprintf("invalid %s in xlsx file", name))
+	}
+	return true, nil
+}
+
+// xlsxFirstSheet returns the path of the first worksheet and whether the workbook uses the 1904 date system
+func xlsxFirstSheet(entries map[string]*zip.File) (string, bool, errors.Error) {
+	workbook := &struct {
+		Pr struct {
+			Date1904 bool `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			RelId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}{}
+	found, err := xlsxDecode(entries, "xl/workbook.xml", workbook)
+	if err != nil {
+		return "", false, err
+	}
+	if !found || len(workbook.Sheets) == 0 {
+		return "", false, errors.BadInput.New("no worksheet found in xlsx file")
+	}
+	rels := &struct {
+		Relationships []struct {
+			Id     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}{}
+	if _, err := xlsxDecode(entries, "xl/_rels/workbook.xml.rels", rels); err != nil {
+		return "", false, err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.Id != workbook.Sheets[0].RelId {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), workbook.Pr.Date1904, nil
+		}
+		return path.Join("xl", rel.Target), workbook.Pr.Date1904, nil
+	}
+	return "xl/worksheets/sheet1.xml", workbook.Pr.Date1904, nil
+}
+
+func xlsxSharedStrings(entries map[string]*zip.File) ([]string, errors.Error) {
+	sst := &struct {
+		Items []xlsxRichText `xml:"si"`
+	}{}
+	if _, err := xlsxDecode(entries, "xl/sharedStrings.xml", sst); err != nil {
+		return nil, err
+	}
+	sharedStrings := make([]string, len(sst.Items))
+	for i := range sst.Items {
+		sharedStrings[i] = sst.Items[i].text()
+	}
+	return sharedStrings, nil
+}
+
+// xlsxDateStyles returns whether each cell style renders numbers as date or time
+func xlsxDateStyles(entries map[string]*zip.File) ([]bool, errors.Error) {
+	styleSheet := &struct {
+		NumFmts []struct {
+			Id   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtId int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}{}
+	if _, err := xlsxDecode(entries, "xl/styles.xml", styleSheet); err != nil {
+		return nil, err
+	}
+	customDateFormats := make(map[int]bool)
+	for _, numFmt := range styleSheet.NumFmts {
+		code := strings.ToLower(xlsxNonDateParts.ReplaceAllString(numFmt.Code, ""))
+		customDateFormats[numFmt.Id] = strings.ContainsAny(code, "ymdhs")
+	}
+	dateStyles := make([]bool, len(styleSheet.CellXfs))
+	for i, xf := range styleSheet.CellXfs {
+		id := xf.NumFmtId
+		dateStyles[i] = (id >= 14 && id <= 22) || (id >= 45 && id <= 47) || customDateFormats[id]
+	}
+	return dateStyles, nil
+}
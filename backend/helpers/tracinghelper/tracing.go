@@ -0,0 +1,162 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracinghelper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	EXPORTER_OTLP = "otlp"
+	EXPORTER_FILE = "file"
+)
+
+var tracer = otel.Tracer("github.com/apache/incubator-devlake")
+
+// Init sets up the global tracer provider based on the configuration, tracing is disabled unless
+// `TRACING_EXPORTER` is set to:
+//   - otlp: spans are sent to the collector at `TRACING_OTLP_ENDPOINT`, e.g. http://localhost:4318
+//   - file: spans are appended to `TRACING_FILE_PATH` as json
+//
+// The returned function flushes the pending spans and releases the exporter
+func Init(cfg config.ConfigReader) (func(), errors.Error) {
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	switch cfg.GetString("TRACING_EXPORTER") {
+	case "":
+		return func() {}, nil
+	case EXPORTER_OTLP:
+		opts, err := otlpOptions(cfg.GetString("TRACING_OTLP_ENDPOINT"))
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = errors.Convert01(otlptracehttp.New(context.Background(), opts...))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to create the otlp exporter")
+		}
+	case EXPORTER_FILE:
+		path := cfg.GetString("TRACING_FILE_PATH")
+		if path == "" {
+			return nil, errors.BadInput.New("TRACING_FILE_PATH is required for the file exporter")
+		}
+		var err error
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to open %s", path))
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to create the file exporter")
+		}
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported TRACING_EXPORTER: %s", cfg.GetString("TRACING_EXPORTER")))
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "devlake"))),
+	)
+	otel.SetTracerProvider(provider)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = provider.Shutdown(ctx)
+		if file != nil {
+			_ = file.Close()
+		}
+	}, nil
+}
+
+func otlpOptions(endpoint string) ([]otlptracehttp.Option, errors.Error) {
+	// fallback to the OTEL_EXPORTER_OTLP_* environment variables honored by the exporter
+	if endpoint == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, errors.BadInput.New(fmt.Sprintf("invalid TRACING_OTLP_ENDPOINT: %s", endpoint))
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	return opts, nil
+}
+
+// Start creates a span as a child of the span carried by ctx, the span is a no-op unless tracing is enabled
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// SetError records the error on the span and marks the span as failed
+func SetError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// End records the error, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		SetError(span, err)
+	}
+	span.End()
+}
+
+// Inject returns the W3C traceparent of the span carried by ctx, or an empty string if there is none,
+// it is used to continue the trace in other processes, i.e. remote workers
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// Extract returns a copy of ctx carrying the remote span of the W3C traceparent, spans started with the
+// returned context belong to the same trace
+func Extract(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
+
+// TraceId returns the id of the trace carried by ctx, or an empty string if there is none
+func TraceId(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracinghelper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	cfg := viper.New()
+	cfg.Set("TRACING_EXPORTER", EXPORTER_FILE)
+	cfg.Set("TRACING_FILE_PATH", path)
+	shutdown, err := Init(cfg)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "", TraceId(context.Background()))
+	ctx, parent := Start(context.Background(), "pipeline")
+	traceId := TraceId(ctx)
+	assert.Len(t, traceId, 32)
+	childCtx, child := Start(ctx, "task", attribute.Int("devlake.task.id", 1))
+	assert.Equal(t, traceId, TraceId(childCtx))
+	End(child, nil)
+	parent.End()
+	shutdown()
+
+	content, readErr := os.ReadFile(path)
+	assert.Nil(t, readErr)
+	assert.Equal(t, 2, strings.Count(string(content), "\n"))
+	assert.Contains(t, string(content), `"Name":"pipeline"`)
+	assert.Contains(t, string(content), `"Name":"task"`)
+	assert.Contains(t, string(content), traceId)
+	assert.Contains(t, string(content), "devlake.task.id")
+}
+
+func TestPropagation(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("TRACING_EXPORTER", EXPORTER_FILE)
+	cfg.Set("TRACING_FILE_PATH", filepath.Join(t.TempDir(), "traces.json"))
+	shutdown, err := Init(cfg)
+	assert.Nil(t, err)
+	defer shutdown()
+
+	assert.Equal(t, "", Inject(context.Background()))
+	assert.Equal(t, context.Background(), Extract(context.Background(), ""))
+
+	ctx, span := Start(context.Background(), "pipeline")
+	defer span.End()
+	traceParent := Inject(ctx)
+	assert.Contains(t, traceParent, TraceId(ctx))
+	remoteCtx, child := Start(Extract(context.Background(), traceParent), "task")
+	defer child.End()
+	assert.Equal(t, TraceId(ctx), TraceId(remoteCtx))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("TRACING_EXPORTER", "zipkin")
+	_, err := Init(cfg)
+	assert.NotNil(t, err)
+
+	cfg.Set("TRACING_EXPORTER", EXPORTER_OTLP)
+	cfg.Set("TRACING_OTLP_ENDPOINT", "localhost")
+	_, err = Init(cfg)
+	assert.NotNil(t, err)
+}
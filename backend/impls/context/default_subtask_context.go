@@ -31,6 +31,7 @@ type DefaultSubTaskContext struct {
 	*defaultExecContext
 	taskCtx          *DefaultTaskContext
 	LastProgressTime time.Time
+	metrics          *plugin.SubTaskMetrics
 }
 
 // SetProgress FIXME ...
@@ -52,6 +53,22 @@ func (c *DefaultSubTaskContext) IncProgress(quantity int) {
 	}
 }
 
+// SubTaskMetrics returns the metrics of the subtask
+func (c *DefaultSubTaskContext) SubTaskMetrics() *plugin.SubTaskMetrics {
+	return c.metrics
+}
+
+// Begin resets the metrics, replaces the go context, e.g. with the one carrying the tracing span of the run,
+// and marks the subtask as the running one of the task
+func (c *DefaultSubTaskContext) Begin(ctx gocontext.Context) *plugin.SubTaskMetrics {
+	c.defaultExecContext.ctx = ctx
+	c.metrics = &plugin.SubTaskMetrics{}
+	if c.taskCtx != nil {
+		c.taskCtx.running.Store(c)
+	}
+	return c.metrics
+}
+
 // TaskContext FIXME ...
 func (c *DefaultSubTaskContext) TaskContext() plugin.TaskContext {
 	if c.taskCtx == nil {
@@ -74,11 +91,14 @@ func NewStandaloneSubTaskContext(
 ) plugin.SubTaskContext {
 	taskContext := &DefaultTaskContext{defaultExecContext: newDefaultExecContext(ctx, basicRes, pluginName, data, nil)}
 	taskContext.SetSyncPolicy(syncPolicy)
-	return &DefaultSubTaskContext{
+	subtaskContext := &DefaultSubTaskContext{
 		newDefaultExecContext(ctx, basicRes, name, data, nil),
 		taskContext,
 		time.Time{},
+		nil,
 	}
+	subtaskContext.Begin(ctx)
+	return subtaskContext
 }
 
 var _ plugin.SubTaskContext = (*DefaultSubTaskContext)(nil)
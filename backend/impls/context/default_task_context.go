@@ -20,6 +20,7 @@ package context
 import (
 	gocontext "context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/incubator-devlake/core/context"
@@ -34,6 +35,8 @@ type DefaultTaskContext struct {
 	subtasks    map[string]bool
 	subtaskCtxs map[string]*DefaultSubTaskContext
 	syncPolicy  *models.SyncPolicy
+	// the running subtask
+	running atomic.Pointer[DefaultSubTaskContext]
 }
 
 // SetProgress FIXME ...
@@ -68,6 +71,7 @@ func (c *DefaultTaskContext) SubTaskContext(subtask string) (plugin.SubTaskConte
 					c.defaultExecContext.fork(subtask),
 					c,
 					time.Time{},
+					&plugin.SubTaskMetrics{},
 				}
 			}
 			c.defaultExecContext.mu.Unlock()
@@ -80,6 +84,24 @@ func (c *DefaultTaskContext) SubTaskContext(subtask string) (plugin.SubTaskConte
 	return nil, errors.Default.New(fmt.Sprintf("subtask %s doesn't exist", subtask))
 }
 
+// SubTaskMetrics returns the metrics of the running subtask, so api clients created by the task context
+// could count requests into the subtask using them
+func (c *DefaultTaskContext) SubTaskMetrics() *plugin.SubTaskMetrics {
+	if running := c.running.Load(); running != nil {
+		return running.SubTaskMetrics()
+	}
+	return nil
+}
+
+// RunningSubTask returns the running subtask, so api clients created by the task context could trace requests
+// within the subtask
+func (c *DefaultTaskContext) RunningSubTask() plugin.SubTaskContext {
+	if running := c.running.Load(); running != nil {
+		return running
+	}
+	return nil
+}
+
 // SetData FIXME ...
 func (c *DefaultTaskContext) SetData(data interface{}) {
 	c.data = data
@@ -94,10 +116,9 @@ func NewDefaultTaskContext(
 	progress chan plugin.RunningProgress,
 ) plugin.TaskContext {
 	return &DefaultTaskContext{
-		newDefaultExecContext(ctx, basicRes, name, nil, progress),
-		subtasks,
-		make(map[string]*DefaultSubTaskContext),
-		nil,
+		defaultExecContext: newDefaultExecContext(ctx, basicRes, name, nil, progress),
+		subtasks:           subtasks,
+		subtaskCtxs:        make(map[string]*DefaultSubTaskContext),
 	}
 }
 
@@ -80,17 +80,25 @@ func transformParams(params []interface{}) []interface{} {
 	return tp
 }
 
+// dialectExpr adapts the sql expression to the dialect of the database
+func dialectExpr(db *gorm.DB, expr string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return sqliteQuoteReservedColumns(expr)
+	}
+	return expr
+}
+
 func buildTx(tx *gorm.DB, clauses []dal.Clause) *gorm.DB {
 	for _, c := range clauses {
 		t := c.Type
 		d := c.Data
 		switch t {
 		case dal.JoinClause:
-			tx = tx.Joins(d.(dal.DalClause).Expr, transformParams(d.(dal.DalClause).Params)...)
+			tx = tx.Joins(dialectExpr(tx, d.(dal.DalClause).Expr), transformParams(d.(dal.DalClause).Params)...)
 		case dal.WhereClause:
-			tx = tx.Where(d.(dal.DalClause).Expr, transformParams(d.(dal.DalClause).Params)...)
+			tx = tx.Where(dialectExpr(tx, d.(dal.DalClause).Expr), transformParams(d.(dal.DalClause).Params)...)
 		case dal.OrderbyClause:
-			tx = tx.Order(d.(string))
+			tx = tx.Order(dialectExpr(tx, d.(string)))
 		case dal.LimitClause:
 			tx = tx.Limit(d.(int))
 		case dal.OffsetClause:
@@ -100,7 +108,7 @@ func buildTx(tx *gorm.DB, clauses []dal.Clause) *gorm.DB {
 			case string:
 				tx = tx.Table(dd)
 			case dal.DalClause:
-				tx = tx.Table(dd.Expr, transformParams(dd.Params)...)
+				tx = tx.Table(dialectExpr(tx, dd.Expr), transformParams(dd.Params)...)
 			case dal.ClauseTable:
 				tx = tx.Table(" ? ", clause.Table{
 					Name:  dd.Name,
@@ -113,11 +121,11 @@ func buildTx(tx *gorm.DB, clauses []dal.Clause) *gorm.DB {
 				tx = tx.Model(d)
 			}
 		case dal.SelectClause:
-			tx = tx.Select(d.(dal.DalClause).Expr, transformParams(d.(dal.DalClause).Params)...)
+			tx = tx.Select(dialectExpr(tx, d.(dal.DalClause).Expr), transformParams(d.(dal.DalClause).Params)...)
 		case dal.GroupbyClause:
-			tx = tx.Group(d.(string))
+			tx = tx.Group(dialectExpr(tx, d.(string)))
 		case dal.HavingClause:
-			tx = tx.Having(d.(dal.DalClause).Expr, transformParams(d.(dal.DalClause).Params)...)
+			tx = tx.Having(dialectExpr(tx, d.(dal.DalClause).Expr), transformParams(d.(dal.DalClause).Params)...)
 		case dal.LockClause:
 			locking := clause.Locking{}
 			params := d.([]bool)
@@ -143,7 +151,7 @@ func (d *Dalgorm) Exec(query string, params ...interface{}) errors.Error {
 	if err != nil {
 		return err
 	}
-	return d.convertGormError(d.db.Exec(query, transformParams(params)...).Error)
+	return d.convertGormError(d.db.Exec(dialectExpr(d.db, query), transformParams(params)...).Error)
 }
 
 var txPattern = regexp.MustCompile(`(?i)^[\s;]*(begin|(start\s+transaction))[\s;]*$`)
@@ -176,7 +184,7 @@ func (d *Dalgorm) unwrapDynamic(entityPtr *interface{}, clausesPtr *[]dal.Clause
 func (d *Dalgorm) AutoMigrate(entity interface{}, clauses ...dal.Clause) errors.Error {
 	d.unwrapDynamic(&entity, &clauses)
 	err := buildTx(d.db, clauses).AutoMigrate(entity)
-	if err == nil {
+	if err == nil && d.db.Dialector.Name() == "postgres" {
 		// fix pg cache plan error
 		_ = d.First(entity, clauses...)
 	}
@@ -341,6 +349,12 @@ func (d *Dalgorm) DropColumns(table string, columnNames ...string) errors.Error
 		_ = d.Exec("SELECT * FROM ? LIMIT 1", clause.Table{Name: table})
 	}()
 	for _, columnName := range columnNames {
+		if d.isSqlite() {
+			err := d.sqliteDropIndexesOfColumn(table, columnName)
+			if err != nil {
+				return err
+			}
+		}
 		err := d.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: table}, clause.Column{Name: columnName})
 		// err := d.db.Migrator().DropColumn(table, columnName)
 		if err != nil {
@@ -374,6 +388,9 @@ func (d *Dalgorm) RenameColumn(table, oldColumnName, newColumnName string) error
 
 // ModifyColumnType modify column type
 func (d *Dalgorm) ModifyColumnType(table, columnName, columnType string) errors.Error {
+	if d.isSqlite() {
+		return d.sqliteModifyColumnType(table, columnName, columnType)
+	}
 	// work around the error `cached plan must not change result type` for postgres
 	// wrap in func(){} to make the linter happy
 	defer func() {
@@ -393,9 +410,12 @@ func (d *Dalgorm) ModifyColumnType(table, columnName, columnType string) errors.
 // AllTables returns all tables in the database
 func (d *Dalgorm) AllTables() ([]string, errors.Error) {
 	var tableSql string
-	if d.db.Dialector.Name() == "mysql" {
+	switch d.db.Dialector.Name() {
+	case "mysql":
 		tableSql = "show tables"
-	} else {
+	case "sqlite":
+		tableSql = "select name from sqlite_master where type = 'table' and name not like 'sqlite_%'"
+	default:
 		tableSql = "select table_name from information_schema.tables where table_schema = 'public' and table_name not like '_devlake%'"
 	}
 	var tables []string
@@ -453,6 +473,10 @@ func (d *Dalgorm) RenameTable(oldName, newName string) errors.Error {
 // DropIndexes drops indexes for specified table
 func (d *Dalgorm) DropIndexes(table string, indexNames ...string) errors.Error {
 	for _, indexName := range indexNames {
+		// index names are unique across the whole database in sqlite, make sure it belongs to the table
+		if d.isSqlite() && !d.db.Migrator().HasIndex(table, indexName) {
+			return errors.Default.New(fmt.Sprintf("index %s not found in table %s", indexName, table))
+		}
 		err := d.db.Migrator().DropIndex(table, indexName)
 		if err != nil {
 			return d.convertGormError(err)
@@ -472,6 +496,12 @@ func (d *Dalgorm) Dialect() string {
 	return d.db.Dialector.Name()
 }
 
+// SqlDB returns the underlying connection pool, e.g. for collecting its stats
+func (d *Dalgorm) SqlDB() (*sql.DB, errors.Error) {
+	sqlDB, err := d.db.DB()
+	return sqlDB, errors.Convert(err)
+}
+
 // Session creates a new manual transaction for special scenarios
 func (d *Dalgorm) Session(config dal.SessionConfig) dal.Dal {
 	session := d.db.Session(&gorm.Session{
@@ -493,7 +523,9 @@ func (d *Dalgorm) IsErrorNotFound(err error) bool {
 
 // IsDuplicationError checking if the sql error is not found.
 func (d *Dalgorm) IsDuplicationError(err error) bool {
-	return strings.Contains(strings.ToLower(err.Error()), "duplicate")
+	msg := strings.ToLower(err.Error())
+	// sqlite reports `UNIQUE constraint failed: table.column`
+	return strings.Contains(msg, "duplicate") || strings.Contains(msg, "unique constraint failed")
 }
 
 // IsCachedPlanError checks if the error is related to postgres cached query plan
@@ -515,7 +547,7 @@ func (d *Dalgorm) IsTableExist(err error) bool {
 
 // RawCursor (Deprecated) executes raw sql query and returns a database cursor
 func (d *Dalgorm) RawCursor(query string, params ...interface{}) (*sql.Rows, errors.Error) {
-	rows, err := d.db.Raw(query, params...).Rows()
+	rows, err := d.db.Raw(dialectExpr(d.db, query), params...).Rows()
 	return rows, d.convertGormError(err)
 }
 
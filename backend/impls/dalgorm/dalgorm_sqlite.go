@@ -0,0 +1,185 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dalgorm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"gorm.io/gorm"
+)
+
+type sqliteColumnInfo struct {
+	Cid       int
+	Name      string
+	Type      string
+	Notnull   bool
+	DfltValue *string
+	Pk        int
+}
+
+func (d *Dalgorm) isSqlite() bool {
+	return d.db.Dialector.Name() == "sqlite"
+}
+
+func sqliteQuote(identifier string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+}
+
+// sqliteReservedColumnPattern matches columns named after keywords and qualified by tables, i.e. `pm.table` of
+// project_mapping, which are accepted by mysql and postgres but not sqlite
+var sqliteReservedColumnPattern = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.(table)\b`)
+
+// sqliteQuoteReservedColumns quotes the qualified columns named after keywords in the expression, string literals
+// are kept as they are
+func sqliteQuoteReservedColumns(expr string) string {
+	if !sqliteReservedColumnPattern.MatchString(expr) {
+		return expr
+	}
+	// parts with odd indexes are inside quotes, escaped quotes result in empty parts which keep the parity
+	parts := strings.Split(expr, "'")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = sqliteReservedColumnPattern.ReplaceAllString(parts[i], `$1."$2"`)
+	}
+	return strings.Join(parts, "'")
+}
+
+func sqliteTableInfo(db *gorm.DB, table string) ([]sqliteColumnInfo, error) {
+	var columns []sqliteColumnInfo
+	err := db.Raw(`SELECT cid, name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, table).Scan(&columns).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	return columns, nil
+}
+
+// sqliteModifyColumnType rebuilds the table since sqlite doesn't support altering the type of a column:
+// a new table is created with the modified column, rows are copied over, then the indexes are recreated
+func (d *Dalgorm) sqliteModifyColumnType(table, columnName, columnType string) errors.Error {
+	return d.convertGormError(d.db.Transaction(func(tx *gorm.DB) error {
+		columns, err := sqliteTableInfo(tx, table)
+		if err != nil {
+			return err
+		}
+		var tableSql string
+		err = tx.Raw(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Row().Scan(&tableSql)
+		if err != nil {
+			return err
+		}
+		var indexSqls []string
+		err = tx.Raw(`SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL`, table).Scan(&indexSqls).Error
+		if err != nil {
+			return err
+		}
+		found := false
+		var pkColumns []sqliteColumnInfo
+		for i := range columns {
+			if columns[i].Name == columnName {
+				columns[i].Type = columnType
+				found = true
+			}
+			if columns[i].Pk > 0 {
+				pkColumns = append(pkColumns, columns[i])
+			}
+		}
+		if !found {
+			return fmt.Errorf("column %s not found in table %s", columnName, table)
+		}
+		sort.Slice(pkColumns, func(i, j int) bool { return pkColumns[i].Pk < pkColumns[j].Pk })
+		// keep the rowid alias of `INTEGER PRIMARY KEY AUTOINCREMENT` tables
+		autoIncrement := len(pkColumns) == 1 && strings.EqualFold(pkColumns[0].Type, "integer") &&
+			strings.Contains(strings.ToUpper(tableSql), "AUTOINCREMENT")
+
+		tmpTable := table + "__tmp"
+		var definitions, names []string
+		for _, column := range columns {
+			definition := fmt.Sprintf("%s %s", sqliteQuote(column.Name), column.Type)
+			if autoIncrement && column.Pk > 0 {
+				definition += " PRIMARY KEY AUTOINCREMENT"
+			}
+			if column.Notnull {
+				definition += " NOT NULL"
+			}
+			if column.DfltValue != nil {
+				definition += " DEFAULT " + *column.DfltValue
+			}
+			definitions = append(definitions, definition)
+			names = append(names, sqliteQuote(column.Name))
+		}
+		if len(pkColumns) > 0 && !autoIncrement {
+			var pkNames []string
+			for _, column := range pkColumns {
+				pkNames = append(pkNames, sqliteQuote(column.Name))
+			}
+			definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkNames, ", ")))
+		}
+		statements := []string{
+			fmt.Sprintf("DROP TABLE IF EXISTS %s", sqliteQuote(tmpTable)),
+			fmt.Sprintf("CREATE TABLE %s (%s)", sqliteQuote(tmpTable), strings.Join(definitions, ", ")),
+			fmt.Sprintf(
+				"INSERT INTO %s (%s) SELECT %s FROM %s",
+				sqliteQuote(tmpTable), strings.Join(names, ", "), strings.Join(names, ", "), sqliteQuote(table),
+			),
+			fmt.Sprintf("DROP TABLE %s", sqliteQuote(table)),
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", sqliteQuote(tmpTable), sqliteQuote(table)),
+		}
+		// indexes were dropped along with the original table
+		statements = append(statements, indexSqls...)
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// sqliteDropIndexesOfColumn drops the indexes containing the column, sqlite refuses to drop an indexed column
+// while mysql and postgres drop the indexes automatically
+func (d *Dalgorm) sqliteDropIndexesOfColumn(table, columnName string) errors.Error {
+	var indexNames []string
+	err := d.db.Raw(
+		`SELECT DISTINCT il.name FROM pragma_index_list(?) il, pragma_index_info(il.name) ii WHERE il.origin = 'c' AND ii.name = ?`,
+		table, columnName,
+	).Scan(&indexNames).Error
+	if err != nil {
+		return d.convertGormError(err)
+	}
+	return d.DropIndexes(table, indexNames...)
+}
+
+// sqliteLockTables emulates table locking, sqlite locks the whole database file instead of tables:
+// writing acquires the RESERVED lock which blocks other writers until the transaction ends.
+// Shared locks are taken the same way because a read transaction can't be upgraded to a write one
+// once another connection has committed in between
+func (t *DalgormTransaction) sqliteLockTables(lockTables dal.LockTables) errors.Error {
+	for _, lockTable := range lockTables {
+		err := t.Exec(fmt.Sprintf("DELETE FROM %s WHERE 0", sqliteQuote(lockTable.TableName())))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
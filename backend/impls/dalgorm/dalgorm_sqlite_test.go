@@ -0,0 +1,151 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dalgorm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type sqliteTestModel struct {
+	Id     uint64 `gorm:"primaryKey;autoIncrement"`
+	Name   string `gorm:"type:varchar(20);index"`
+	Status string `gorm:"type:varchar(20);index:idx_dal_test_models_status_name,priority:1"`
+	Remark string `gorm:"index:idx_dal_test_models_status_name,priority:2"`
+}
+
+func (sqliteTestModel) TableName() string {
+	return "dal_test_models"
+}
+
+func newSqliteDalgorm(t *testing.T) *Dalgorm {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	d := NewDalgorm(db)
+	assert.Nil(t, d.AutoMigrate(&sqliteTestModel{}))
+	assert.Nil(t, d.Create(&[]sqliteTestModel{
+		{Name: "foo", Status: "DONE", Remark: "a"},
+		{Name: "bar", Status: "TODO", Remark: "b"},
+	}))
+	return d
+}
+
+func sqliteIndexes(t *testing.T, d *Dalgorm) []string {
+	var indexes []string
+	assert.Nil(t, d.db.Raw(`SELECT name FROM pragma_index_list('dal_test_models') WHERE origin = 'c' ORDER BY name`).Scan(&indexes).Error)
+	return indexes
+}
+
+func TestSqliteModifyColumnType(t *testing.T) {
+	d := newSqliteDalgorm(t)
+	indexes := sqliteIndexes(t, d)
+	assert.Nil(t, d.ModifyColumnType("dal_test_models", "name", "TEXT"))
+
+	columns, err := sqliteTableInfo(d.db, "dal_test_models")
+	assert.Nil(t, err)
+	assert.Equal(t, "TEXT", columns[1].Type)
+	assert.Equal(t, 1, columns[0].Pk)
+	assert.Equal(t, indexes, sqliteIndexes(t, d))
+
+	var rows []sqliteTestModel
+	assert.Nil(t, d.All(&rows, dal.Orderby("id")))
+	assert.Equal(t, []sqliteTestModel{
+		{Id: 1, Name: "foo", Status: "DONE", Remark: "a"},
+		{Id: 2, Name: "bar", Status: "TODO", Remark: "b"},
+	}, rows)
+	// autoincrement survives the rebuilding
+	assert.Nil(t, d.Delete(&sqliteTestModel{}, dal.Where("id = ?", 2)))
+	row := &sqliteTestModel{Name: "baz"}
+	assert.Nil(t, d.Create(row))
+	assert.Equal(t, uint64(3), row.Id)
+
+	assert.NotNil(t, d.ModifyColumnType("dal_test_models", "no_such_column", "text"))
+}
+
+func TestSqliteDropColumns(t *testing.T) {
+	d := newSqliteDalgorm(t)
+	assert.Nil(t, d.DropColumns("dal_test_models", "status"))
+	assert.False(t, d.HasColumn("dal_test_models", "status"))
+	assert.Equal(t, []string{"idx_dal_test_models_name"}, sqliteIndexes(t, d))
+}
+
+func TestSqliteDropIndexes(t *testing.T) {
+	d := newSqliteDalgorm(t)
+	assert.Nil(t, d.DropIndex("dal_test_models", "name"))
+	assert.Equal(t, []string{"idx_dal_test_models_status_name"}, sqliteIndexes(t, d))
+	assert.NotNil(t, d.DropIndexes("other_models", "idx_dal_test_models_status_name"))
+	assert.Equal(t, []string{"idx_dal_test_models_status_name"}, sqliteIndexes(t, d))
+}
+
+func TestSqliteAllTables(t *testing.T) {
+	d := newSqliteDalgorm(t)
+	tables, err := d.AllTables()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dal_test_models"}, tables)
+}
+
+func TestSqliteIsDuplicationError(t *testing.T) {
+	d := newSqliteDalgorm(t)
+	err := d.Create(&sqliteTestModel{Id: 1, Name: "foo"})
+	assert.NotNil(t, err)
+	assert.True(t, d.IsDuplicationError(err))
+}
+
+func TestSqliteLockTables(t *testing.T) {
+	d := newSqliteDalgorm(t)
+	tx := d.Begin()
+	assert.Nil(t, tx.LockTables(dal.LockTables{{Table: "dal_test_models", Exclusive: true}}))
+	assert.Nil(t, tx.Update(&sqliteTestModel{Id: 1, Name: "locked"}))
+	assert.Nil(t, tx.UnlockTables())
+	assert.Nil(t, tx.Commit())
+	row := &sqliteTestModel{}
+	assert.Nil(t, d.First(row, dal.Where("id = ?", 1)))
+	assert.Equal(t, "locked", row.Name)
+}
+
+func TestSqliteQuoteReservedColumns(t *testing.T) {
+	assert.Equal(t,
+		`LEFT JOIN project_mapping pm ON (pm."table" = 'repos' AND pm.row_id = pr.base_repo_id)`,
+		sqliteQuoteReservedColumns("LEFT JOIN project_mapping pm ON (pm.table = 'repos' AND pm.row_id = pr.base_repo_id)"),
+	)
+	assert.Equal(t, `pm."table" = i."table"`, sqliteQuoteReservedColumns("pm.table = i.table"))
+	// string literals, unqualified columns and columns prefixed by the keyword are kept
+	assert.Equal(t, `pm.tables = 'pm.table' AND x = 'it''s pm.table'`, sqliteQuoteReservedColumns(`pm.tables = 'pm.table' AND x = 'it''s pm.table'`))
+	assert.Equal(t, `"table" = ?`, sqliteQuoteReservedColumns(`"table" = ?`))
+
+	d := newSqliteDalgorm(t)
+	assert.Nil(t, d.Exec(`CREATE TABLE dal_test_mappings ("table" varchar(20), row_id varchar(20))`))
+	assert.Nil(t, d.Exec(`INSERT INTO dal_test_mappings VALUES ('repos', 'foo'), ('boards', 'bar')`))
+	var names []string
+	assert.Nil(t, d.Pluck("m.name", &names,
+		dal.From("dal_test_models m"),
+		dal.Join("JOIN dal_test_mappings pm ON (pm.table = 'repos' AND pm.row_id = m.name)"),
+	))
+	assert.Equal(t, []string{"foo"}, names)
+	assert.Nil(t, d.Exec("DELETE FROM dal_test_mappings WHERE dal_test_mappings.table = ?", "boards"))
+	count, err := d.Count(dal.From("dal_test_mappings"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -82,6 +82,8 @@ func (t *DalgormTransaction) LockTables(lockTables dal.LockTables) errors.Error
 			}
 		}
 		return nil
+	case "sqlite":
+		return t.sqliteLockTables(lockTables)
 	default:
 		panic(fmt.Errorf("unknown dialect %s", t.Dialect()))
 	}
@@ -96,6 +98,9 @@ func (t *DalgormTransaction) UnlockTables() errors.Error {
 	case "postgres":
 		// pg has no unlock tables
 		return nil
+	case "sqlite":
+		// sqlite releases the locks when the transaction ends
+		return nil
 	default:
 		panic(fmt.Errorf("unknown dialect %s", t.Dialect()))
 	}
@@ -34,7 +34,7 @@ var _ schema.SerializerInterface = (*EncDecSerializer)(nil)
 // EncDecSerializer is responsible for field encryption/decryption in Application Level
 // Ref: https://gorm.io/docs/serializer.html
 type EncDecSerializer struct {
-	encryptionSecret string
+	secretProvider plugin.SecretProvider
 }
 
 // Scan implements serializer interface
@@ -52,7 +52,7 @@ func (es *EncDecSerializer) Scan(ctx context.Context, field *schema.Field, dst r
 			return fmt.Errorf("failed to decrypt value: %#v", dbValue)
 		}
 
-		decrypted, err := plugin.Decrypt(es.encryptionSecret, base64str)
+		decrypted, err := es.secretProvider.Decrypt(base64str)
 		if err != nil {
 			return err
 		}
@@ -96,10 +96,10 @@ func (es *EncDecSerializer) Value(ctx context.Context, field *schema.Field, dst
 	// 	gormTag, ok := field.Tag.Lookup("gorm")
 	// 	println(ok, gormTag)
 	// }
-	return plugin.Encrypt(es.encryptionSecret, target)
+	return es.secretProvider.Encrypt(target)
 }
 
 // Init the encdec serializer
-func Init(encryptionSecret string) {
-	schema.RegisterSerializer("encdec", &EncDecSerializer{encryptionSecret: encryptionSecret})
+func Init(secretProvider plugin.SecretProvider) {
+	schema.RegisterSerializer("encdec", &EncDecSerializer{secretProvider: secretProvider})
 }
@@ -32,6 +32,7 @@ var alreadyInBracketsRegex = regexp.MustCompile(`\[.*?]+`)
 type DefaultLogger struct {
 	log    *logrus.Logger
 	config *log.LoggerConfig
+	fields logrus.Fields
 }
 
 func NewDefaultLogger(logger *logrus.Logger) (log.Logger, errors.Error) {
@@ -55,7 +56,11 @@ func (l *DefaultLogger) Log(level log.LogLevel, format string, a ...interface{})
 		if l.config.Prefix != "" {
 			msg = fmt.Sprintf("%s %s", l.config.Prefix, msg)
 		}
-		l.log.Log(logrus.Level(level), msg)
+		if len(l.fields) > 0 {
+			l.log.WithFields(l.fields).Log(logrus.Level(level), msg)
+		} else {
+			l.log.Log(logrus.Level(level), msg)
+		}
 	}
 }
 
@@ -119,10 +124,30 @@ func (l *DefaultLogger) getLogger(prefix string) (log.Logger, errors.Error) {
 			Path:   l.config.Path,
 			Prefix: prefix,
 		},
+		fields: l.fields,
 	}
 	return newLogger, nil
 }
 
+// WithTraceId returns a logger which writes the trace id into each line, so they could be associated with the
+// tracing spans. The logger is returned as is if it is not a DefaultLogger or the trace id is empty
+func WithTraceId(logger log.Logger, traceId string) log.Logger {
+	defaultLogger, ok := logger.(*DefaultLogger)
+	if !ok || traceId == "" {
+		return logger
+	}
+	fields := logrus.Fields{}
+	for key, value := range defaultLogger.fields {
+		fields[key] = value
+	}
+	fields["trace_id"] = traceId
+	return &DefaultLogger{
+		log:    defaultLogger.log,
+		config: defaultLogger.config,
+		fields: fields,
+	}
+}
+
 func (l *DefaultLogger) createPrefix(newPrefix string) string {
 	newPrefix = strings.TrimSpace(newPrefix)
 	alreadyInBrackets := alreadyInBracketsRegex.MatchString(newPrefix)
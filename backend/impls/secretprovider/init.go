@@ -0,0 +1,80 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+const (
+	SecretProviderEnvStr         = "SECRET_PROVIDER"
+	SecretProviderFileEnvStr     = "SECRET_PROVIDER_FILE"
+	SecretProviderKvUrlEnvStr    = "SECRET_PROVIDER_KV_URL"
+	SecretProviderKvTokenEnvStr  = "SECRET_PROVIDER_KV_TOKEN"
+	SecretProviderKvPathEnvStr   = "SECRET_PROVIDER_KV_JSON_PATH"
+	SecretEnvelopeEncryptionFlag = "SECRET_ENVELOPE_ENCRYPTION"
+)
+
+// NewSecretProvider creates the plugin.SecretProvider based on the configuration:
+//   - env (default): values are encrypted by the ENCRYPTION_SECRET in the legacy format
+//   - file: the Keyring is loaded from SECRET_PROVIDER_FILE
+//   - kv: the Keyring is loaded from SECRET_PROVIDER_KV_URL
+//
+// The ENCRYPTION_SECRET is required by the env provider, the others only need it to decrypt values written in the legacy format
+func NewSecretProvider(cfg config.ConfigReader) (plugin.SecretProvider, errors.Error) {
+	legacySecret := cfg.GetString(plugin.EncodeKeyEnvStr)
+	envelope := cfg.GetBool(SecretEnvelopeEncryptionFlag)
+	var source KeySource
+	switch providerType := providerType(cfg); providerType {
+	case "", "env":
+	case "file":
+		source = &FileKeySource{Path: cfg.GetString(SecretProviderFileEnvStr)}
+	case "kv":
+		source = &KvKeySource{
+			Url:      cfg.GetString(SecretProviderKvUrlEnvStr),
+			Token:    cfg.GetString(SecretProviderKvTokenEnvStr),
+			JsonPath: cfg.GetString(SecretProviderKvPathEnvStr),
+		}
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported %s: %s", SecretProviderEnvStr, providerType))
+	}
+	var keyring *Keyring
+	if source != nil {
+		var err errors.Error
+		keyring, err = source.LoadKeyring()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewDefaultSecretProvider(legacySecret, keyring, envelope)
+}
+
+// RequiresLegacySecret tells whether the ENCRYPTION_SECRET is mandatory, which is the case for the env provider only
+func RequiresLegacySecret(cfg config.ConfigReader) bool {
+	providerType := providerType(cfg)
+	return providerType == "" || providerType == "env"
+}
+
+func providerType(cfg config.ConfigReader) string {
+	return strings.ToLower(strings.TrimSpace(cfg.GetString(SecretProviderEnvStr)))
+}
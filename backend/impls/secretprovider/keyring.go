@@ -0,0 +1,135 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/tidwall/gjson"
+)
+
+var keyIdPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// Keyring holds all known encryption keys indexed by their id, `Current` is the one used for encryption
+// while the others are kept for decrypting values that have not been re-encrypted yet, e.g.
+//
+//	{"current": "2024-10", "keys": {"2024-01": "old secret", "2024-10": "new secret"}}
+type Keyring struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// Validate checks if the keyring is well-formed
+func (k *Keyring) Validate() errors.Error {
+	if k.Current == "" {
+		return errors.BadInput.New("the current key id of the keyring is required")
+	}
+	if _, ok := k.Keys[k.Current]; !ok {
+		return errors.BadInput.New(fmt.Sprintf("the current key %s does not exist in the keyring", k.Current))
+	}
+	for id, secret := range k.Keys {
+		if !keyIdPattern.MatchString(id) {
+			return errors.BadInput.New(fmt.Sprintf("invalid key id %s, only letters, digits, '_', '.' and '-' are allowed", id))
+		}
+		if secret == "" {
+			return errors.BadInput.New(fmt.Sprintf("the secret of key %s is empty", id))
+		}
+	}
+	return nil
+}
+
+// KeySource loads the Keyring from somewhere
+type KeySource interface {
+	LoadKeyring() (*Keyring, errors.Error)
+}
+
+func parseKeyring(data []byte, jsonPath string) (*Keyring, errors.Error) {
+	if jsonPath != "" {
+		result := gjson.GetBytes(data, jsonPath)
+		if !result.Exists() {
+			return nil, errors.BadInput.New(fmt.Sprintf("path %s does not exist in the keyring document", jsonPath))
+		}
+		data = []byte(result.Raw)
+	}
+	keyring := &Keyring{}
+	if err := json.Unmarshal(data, keyring); err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to parse the keyring document")
+	}
+	if err := keyring.Validate(); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// FileKeySource loads the Keyring from a local json file
+type FileKeySource struct {
+	Path string
+}
+
+// LoadKeyring implements the KeySource interface
+func (s *FileKeySource) LoadKeyring() (*Keyring, errors.Error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to read keyring file %s", s.Path))
+	}
+	return parseKeyring(data, "")
+}
+
+// KvKeySource loads the Keyring from a http based Key-Value store, e.g. Vault KV or Consul KV (with `?raw`),
+// the `JsonPath` could be used to locate the keyring inside the response, i.e. `data.data` for Vault KV v2
+type KvKeySource struct {
+	Url      string
+	Token    string
+	JsonPath string
+	Client   *http.Client
+}
+
+// LoadKeyring implements the KeySource interface
+func (s *KvKeySource) LoadKeyring() (*Keyring, errors.Error) {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	req, err := http.NewRequest(http.MethodGet, s.Url, nil)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid kv url")
+	}
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to request the kv store")
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to read response from the kv store")
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code %d from the kv store", res.StatusCode))
+	}
+	return parseKeyring(data, s.JsonPath)
+}
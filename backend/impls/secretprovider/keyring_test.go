@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringValidate(t *testing.T) {
+	assert.Nil(t, (&Keyring{Current: "k1", Keys: map[string]string{"k1": "s"}}).Validate())
+	assert.NotNil(t, (&Keyring{Current: "", Keys: map[string]string{"k1": "s"}}).Validate())
+	assert.NotNil(t, (&Keyring{Current: "k2", Keys: map[string]string{"k1": "s"}}).Validate())
+	assert.NotNil(t, (&Keyring{Current: "k:1", Keys: map[string]string{"k:1": "s"}}).Validate())
+	assert.NotNil(t, (&Keyring{Current: "k1", Keys: map[string]string{"k1": ""}}).Validate())
+}
+
+func TestFileKeySource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"current": "k2", "keys": {"k1": "s1", "k2": "s2"}}`), 0600))
+	keyring, err := (&FileKeySource{Path: path}).LoadKeyring()
+	assert.Nil(t, err)
+	assert.Equal(t, "k2", keyring.Current)
+	assert.Equal(t, "s1", keyring.Keys["k1"])
+
+	_, err = (&FileKeySource{Path: filepath.Join(t.TempDir(), "missing.json")}).LoadKeyring()
+	assert.NotNil(t, err)
+}
+
+func TestKvKeySource(t *testing.T) {
+	// a local stand-in for Vault KV v2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"current": "k1", "keys": {"k1": "s1"}}}}`))
+	}))
+	defer server.Close()
+
+	keyring, err := (&KvKeySource{Url: server.URL, Token: "token", JsonPath: "data.data"}).LoadKeyring()
+	assert.Nil(t, err)
+	assert.Equal(t, "k1", keyring.Current)
+	assert.Equal(t, "s1", keyring.Keys["k1"])
+
+	_, err = (&KvKeySource{Url: server.URL, Token: "wrong", JsonPath: "data.data"}).LoadKeyring()
+	assert.NotNil(t, err)
+	_, err = (&KvKeySource{Url: server.URL, Token: "token", JsonPath: "data.missing"}).LoadKeyring()
+	assert.NotNil(t, err)
+}
+
+func TestNewSecretProviderWithoutLegacySecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"current": "k1", "keys": {"k1": "s1"}}`), 0600))
+	cfg := viper.New()
+	assert.True(t, RequiresLegacySecret(cfg))
+	cfg.Set(SecretProviderEnvStr, "file")
+	cfg.Set(SecretProviderFileEnvStr, path)
+	assert.False(t, RequiresLegacySecret(cfg))
+
+	p, err := NewSecretProvider(cfg)
+	assert.Nil(t, err)
+	cipherText, err := p.Encrypt("hello")
+	assert.Nil(t, err)
+	plainText, err := p.Decrypt(cipherText)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", plainText)
+	// values written in the legacy format can't be decrypted without the ENCRYPTION_SECRET
+	legacyCipherText, err := plugin.Encrypt(legacySecret, "hello")
+	assert.Nil(t, err)
+	_, err = p.Decrypt(legacyCipherText)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,219 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+const (
+	// VersionedPrefix marks values encrypted by a key from the Keyring: `dlk:v1:<keyId>:<base64(nonce+ciphertext)>`
+	VersionedPrefix = "dlk:v1:"
+	// EnvelopePrefix marks values encrypted by a random data key which is wrapped by a key from the Keyring:
+	// `dlk:e1:<keyId>:<base64(nonce+wrapped data key)>:<base64(nonce+ciphertext)>`
+	EnvelopePrefix = "dlk:e1:"
+)
+
+var _ plugin.SecretProvider = (*DefaultSecretProvider)(nil)
+
+// DefaultSecretProvider supports 3 formats of ciphertext:
+//  1. legacy: produced by plugin.Encrypt with the ENCRYPTION_SECRET, used when no Keyring was configured
+//  2. versioned: AES-GCM with the key id embedded, which makes key rotation possible
+//  3. envelope: like versioned, but every value is encrypted with its own random data key
+//
+// Values in any format can be decrypted as long as their key is still available, so they can be migrated lazily
+type DefaultSecretProvider struct {
+	legacySecret string
+	keyring      *Keyring
+	envelope     bool
+}
+
+// NewDefaultSecretProvider creates a DefaultSecretProvider, keyring could be nil which means the legacy format would be used
+func NewDefaultSecretProvider(legacySecret string, keyring *Keyring, envelope bool) (*DefaultSecretProvider, errors.Error) {
+	if keyring != nil {
+		if err := keyring.Validate(); err != nil {
+			return nil, err
+		}
+	} else if envelope {
+		return nil, errors.BadInput.New("envelope encryption requires a keyring")
+	}
+	return &DefaultSecretProvider{
+		legacySecret: legacySecret,
+		keyring:      keyring,
+		envelope:     envelope,
+	}, nil
+}
+
+// Encrypt implements the plugin.SecretProvider interface
+func (p *DefaultSecretProvider) Encrypt(plainText string) (string, errors.Error) {
+	if p.keyring == nil {
+		return plugin.Encrypt(p.legacySecret, plainText)
+	}
+	kek := deriveKey(p.keyring.Keys[p.keyring.Current])
+	if !p.envelope {
+		sealed, err := seal(kek, []byte(plainText))
+		if err != nil {
+			return "", err
+		}
+		return VersionedPrefix + p.keyring.Current + ":" + sealed, nil
+	}
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", errors.Convert(err)
+	}
+	wrappedDek, err := seal(kek, dek)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dek, []byte(plainText))
+	if err != nil {
+		return "", err
+	}
+	return EnvelopePrefix + p.keyring.Current + ":" + wrappedDek + ":" + sealed, nil
+}
+
+// Decrypt implements the plugin.SecretProvider interface
+func (p *DefaultSecretProvider) Decrypt(cipherText string) (string, errors.Error) {
+	switch {
+	case strings.HasPrefix(cipherText, VersionedPrefix):
+		parts := strings.SplitN(strings.TrimPrefix(cipherText, VersionedPrefix), ":", 2)
+		if len(parts) != 2 {
+			return "", errors.Default.New("malformed versioned ciphertext")
+		}
+		kek, err := p.getKey(parts[0])
+		if err != nil {
+			return "", err
+		}
+		plainText, err := open(kek, parts[1])
+		return string(plainText), err
+	case strings.HasPrefix(cipherText, EnvelopePrefix):
+		parts := strings.SplitN(strings.TrimPrefix(cipherText, EnvelopePrefix), ":", 3)
+		if len(parts) != 3 {
+			return "", errors.Default.New("malformed envelope ciphertext")
+		}
+		kek, err := p.getKey(parts[0])
+		if err != nil {
+			return "", err
+		}
+		dek, err := open(kek, parts[1])
+		if err != nil {
+			return "", err
+		}
+		plainText, err := open(dek, parts[2])
+		return string(plainText), err
+	default:
+		if p.legacySecret == "" {
+			return "", errors.Default.New(fmt.Sprintf("%s is required to decrypt values written in the legacy format", plugin.EncodeKeyEnvStr))
+		}
+		return plugin.Decrypt(p.legacySecret, cipherText)
+	}
+}
+
+// IsStale implements the plugin.SecretProvider interface
+func (p *DefaultSecretProvider) IsStale(cipherText string) bool {
+	keyId, envelope, versioned := parseHeader(cipherText)
+	if p.keyring == nil {
+		return versioned
+	}
+	return !versioned || envelope != p.envelope || keyId != p.keyring.Current
+}
+
+func (p *DefaultSecretProvider) getKey(keyId string) ([]byte, errors.Error) {
+	if p.keyring != nil {
+		if secret, ok := p.keyring.Keys[keyId]; ok {
+			return deriveKey(secret), nil
+		}
+	}
+	return nil, errors.Default.New(fmt.Sprintf("key %s does not exist in the keyring, was it removed before re-encryption?", keyId))
+}
+
+// parseHeader extracts the key id and the format from the cipherText, versioned would be false for legacy values
+func parseHeader(cipherText string) (keyId string, envelope bool, versioned bool) {
+	var rest string
+	switch {
+	case strings.HasPrefix(cipherText, VersionedPrefix):
+		rest = strings.TrimPrefix(cipherText, VersionedPrefix)
+	case strings.HasPrefix(cipherText, EnvelopePrefix):
+		rest = strings.TrimPrefix(cipherText, EnvelopePrefix)
+		envelope = true
+	default:
+		return "", false, false
+	}
+	keyId, _, _ = strings.Cut(rest, ":")
+	return keyId, envelope, true
+}
+
+// deriveKey turns a secret of arbitrary length into a 256 bits AES key, the same way as plugin.AesEncrypt does
+func deriveKey(secret string) []byte {
+	key := sha256.Sum256([]byte(secret))
+	return key[:]
+}
+
+// seal encrypts the data with AES-GCM and returns base64(nonce+ciphertext)
+func seal(key, data []byte) (string, errors.Error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Convert(err)
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, data, nil)), nil
+}
+
+// open reverses the seal
+func open(key []byte, sealed string) ([]byte, errors.Error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	aead, e := newAead(key)
+	if e != nil {
+		return nil, e
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.Default.New("ciphertext too short")
+	}
+	plainText, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to decrypt, the key might be wrong")
+	}
+	return plainText, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, errors.Error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return aead, nil
+}
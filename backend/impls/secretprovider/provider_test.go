@@ -0,0 +1,107 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+const legacySecret = "legacy secret"
+
+func TestLegacyProvider(t *testing.T) {
+	p, err := NewDefaultSecretProvider(legacySecret, nil, false)
+	assert.Nil(t, err)
+	cipherText, err := p.Encrypt("hello")
+	assert.Nil(t, err)
+	// must be compatible with the values written by older versions
+	plainText, err := plugin.Decrypt(legacySecret, cipherText)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", plainText)
+	assert.False(t, p.IsStale(cipherText))
+
+	_, err = NewDefaultSecretProvider(legacySecret, nil, true)
+	assert.NotNil(t, err)
+}
+
+func TestVersionedProviderRotation(t *testing.T) {
+	legacyCipherText, err := plugin.Encrypt(legacySecret, "legacy")
+	assert.Nil(t, err)
+
+	p1, err := NewDefaultSecretProvider(legacySecret, &Keyring{Current: "k1", Keys: map[string]string{"k1": "secret1"}}, false)
+	assert.Nil(t, err)
+	v1CipherText, err := p1.Encrypt("hello")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(v1CipherText, VersionedPrefix+"k1:"))
+	assert.False(t, p1.IsStale(v1CipherText))
+	assert.True(t, p1.IsStale(legacyCipherText))
+	plainText, err := p1.Decrypt(legacyCipherText)
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy", plainText)
+
+	// rotate to k2, values encrypted by k1 are still readable but stale
+	p2, err := NewDefaultSecretProvider(legacySecret, &Keyring{Current: "k2", Keys: map[string]string{"k1": "secret1", "k2": "secret2"}}, false)
+	assert.Nil(t, err)
+	assert.True(t, p2.IsStale(v1CipherText))
+	plainText, err = p2.Decrypt(v1CipherText)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", plainText)
+	v2CipherText, err := p2.Encrypt(plainText)
+	assert.Nil(t, err)
+	assert.False(t, p2.IsStale(v2CipherText))
+
+	// k1 removed after re-encryption
+	p3, err := NewDefaultSecretProvider(legacySecret, &Keyring{Current: "k2", Keys: map[string]string{"k2": "secret2"}}, false)
+	assert.Nil(t, err)
+	_, err = p3.Decrypt(v1CipherText)
+	assert.NotNil(t, err)
+	plainText, err = p3.Decrypt(v2CipherText)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", plainText)
+}
+
+func TestEnvelopeProvider(t *testing.T) {
+	keyring := &Keyring{Current: "k1", Keys: map[string]string{"k1": "secret1"}}
+	p, err := NewDefaultSecretProvider(legacySecret, keyring, true)
+	assert.Nil(t, err)
+	cipherText1, err := p.Encrypt("hello")
+	assert.Nil(t, err)
+	cipherText2, err := p.Encrypt("hello")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(cipherText1, EnvelopePrefix+"k1:"))
+	assert.NotEqual(t, cipherText1, cipherText2)
+	assert.False(t, p.IsStale(cipherText1))
+	plainText, err := p.Decrypt(cipherText1)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", plainText)
+
+	// switching mode makes values stale
+	versioned, err := NewDefaultSecretProvider(legacySecret, keyring, false)
+	assert.Nil(t, err)
+	assert.True(t, versioned.IsStale(cipherText1))
+	plainText, err = versioned.Decrypt(cipherText1)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", plainText)
+
+	// tampered value must be rejected
+	_, err = p.Decrypt(cipherText1[:len(cipherText1)-4] + "AAA=")
+	assert.NotNil(t, err)
+}
@@ -23,11 +23,12 @@ import (
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/customize/service"
 )
 
 const maxMemory = 32 << 20 // 32 MB
 
-// ImportIssue accepts a CSV file, parses and saves it to the database
+// ImportIssue accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload issues.csv file
 // @Description  Upload issues.csv file. 3 tables(boards, issues, board_issues) would be affected.
 // @Tags 		 plugins/customize
@@ -35,9 +36,10 @@ const maxMemory = 32 << 20 // 32 MB
 // @Param        boardId formData string true "the ID of the board"
 // @Param        boardName formData string true "the name of the board"
 // @Param        incremental formData bool false "whether to import incrementally"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/issues.csv [post]
@@ -60,22 +62,25 @@ func (h *Handlers) ImportIssue(input *plugin.ApiResourceInput) (*plugin.ApiResou
 	if boardName == "" {
 		return nil, errors.BadInput.New("empty boardName")
 	}
-	err = h.svc.SaveBoard(boardId, boardName)
-	if err != nil {
-		return nil, err
-	}
-	return nil, h.svc.ImportIssue(boardId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		err := svc.SaveBoard(boardId, boardName)
+		if err != nil {
+			return err
+		}
+		return svc.ImportIssue(boardId, file, incremental)
+	})
 }
 
-// ImportIssueCommit accepts a CSV file, parses and saves it to the database
+// ImportIssueCommit accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload issue_commits.csv file
 // @Description  Upload issue_commits.csv file
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        boardId formData string true "the ID of the board"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/issue_commits.csv [post]
@@ -90,19 +95,22 @@ func (h *Handlers) ImportIssueCommit(input *plugin.ApiResourceInput) (*plugin.Ap
 	if boardId == "" {
 		return nil, errors.Default.New("empty boardId")
 	}
-	return nil, h.svc.ImportIssueCommit(boardId, file)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportIssueCommit(boardId, file)
+	})
 }
 
-// ImportIssueRepoCommit accepts a CSV file, parses and saves it to the database
+// ImportIssueRepoCommit accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload issue_repo_commits.csv file
 // @Description  Upload issue_repo_commits.csv file
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        boardId formData string true "the ID of the board"
 // @Param        incremental formData bool false "whether to import incrementally"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/issue_repo_commits.csv [post]
@@ -121,19 +129,22 @@ func (h *Handlers) ImportIssueRepoCommit(input *plugin.ApiResourceInput) (*plugi
 	if input.Request.FormValue("incremental") == "true" {
 		incremental = true
 	}
-	return nil, h.svc.ImportIssueRepoCommit(boardId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportIssueRepoCommit(boardId, file, incremental)
+	})
 }
 
-// ImportSprint accepts a CSV file, parses and saves it to the database
+// ImportSprint accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload sprints.csv file
 // @Description  Upload sprints.csv file
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        boardId formData string true "the ID of the board"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Param        incremental formData string true "whether to save only new data"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/sprints.csv [post]
@@ -152,19 +163,22 @@ func (h *Handlers) ImportSprint(input *plugin.ApiResourceInput) (*plugin.ApiReso
 	if input.Request.FormValue("incremental") == "true" {
 		incremental = true
 	}
-	return nil, h.svc.ImportSprint(boardId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportSprint(boardId, file, incremental)
+	})
 }
 
-// ImportIssueChangelog accepts a CSV file, parses and saves it to the database
+// ImportIssueChangelog accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload issue_changelogs.csv file
 // @Description  Upload issue_changelogs.csv file
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        boardId formData string true "the ID of the board"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Param 		 incremental formData boolean false "Whether to incrementally update changelogs" default(false)
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/issue_changelogs.csv [post]
@@ -183,19 +197,22 @@ func (h *Handlers) ImportIssueChangelog(input *plugin.ApiResourceInput) (*plugin
 	if input.Request.FormValue("incremental") == "true" {
 		incremental = true
 	}
-	return nil, h.svc.ImportIssueChangelog(boardId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportIssueChangelog(boardId, file, incremental)
+	})
 }
 
-// ImportIssueWorklog accepts a CSV file, parses and saves it to the database
+// ImportIssueWorklog accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload issue_worklogs.csv file
 // @Description  Upload issue_worklogs.csv file
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        boardId formData string true "the ID of the board"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Param        incremental formData boolean false "Whether to do incremental sync (default false)"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/issue_worklogs.csv [post]
@@ -214,7 +231,22 @@ func (h *Handlers) ImportIssueWorklog(input *plugin.ApiResourceInput) (*plugin.A
 	if input.Request.FormValue("incremental") == "true" {
 		incremental = true
 	}
-	return nil, h.svc.ImportIssueWorklog(boardId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportIssueWorklog(boardId, file, incremental)
+	})
+}
+
+// runImport executes importFn, or validates the file without saving anything when `dryRun` is set and returns
+// the per-row error report
+func (h *Handlers) runImport(input *plugin.ApiResourceInput, importFn func(svc *service.Service) errors.Error) (*plugin.ApiResourceOutput, errors.Error) {
+	if input.Request.FormValue("dryRun") != "true" {
+		return nil, importFn(h.svc)
+	}
+	report, err := h.svc.DryRun(importFn)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: report}, nil
 }
 
 func (h *Handlers) extractFile(input *plugin.ApiResourceInput) (io.ReadCloser, errors.Error) {
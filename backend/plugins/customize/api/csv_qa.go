@@ -22,18 +22,20 @@ import (
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/customize/service"
 )
 
-// ImportQaApis accepts a CSV file, parses and saves it to the database
+// ImportQaApis accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload qa_apis.csv file
 // @Description  Upload qa_apis.csv file.
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        qaProjectId formData string true "the ID of the QA project"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Param        incremental formData bool false "incremental import"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/qa_apis.csv [post]
@@ -55,21 +57,24 @@ func (h *Handlers) ImportQaApis(input *plugin.ApiResourceInput) (*plugin.ApiReso
 		return nil, errors.BadInput.New("empty qaProjectId")
 	}
 
-	return nil, h.svc.ImportQaApis(qaProjectId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportQaApis(qaProjectId, file, incremental)
+	})
 
 }
 
-// ImportQaTestCases accepts a CSV file, parses and saves it to the database
+// ImportQaTestCases accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload qa_test_cases.csv file
 // @Description  Upload qa_test_cases.csv file.
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        qaProjectId formData string true "the ID of the QA project"
 // @Param        qaProjectName formData string true "the name of the QA project"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Param        incremental formData bool false "incremental update"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/qa_test_cases.csv [post]
@@ -94,19 +99,22 @@ func (h *Handlers) ImportQaTestCases(input *plugin.ApiResourceInput) (*plugin.Ap
 	if qaProjectName == "" {
 		return nil, errors.BadInput.New("empty qaProjectName")
 	}
-	return nil, h.svc.ImportQaTestCases(qaProjectId, qaProjectName, file, incremental) // records contains the CSV data
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportQaTestCases(qaProjectId, qaProjectName, file, incremental)
+	})
 }
 
-// ImportQaTestCaseExecutions accepts a CSV file, parses and saves it to the database
+// ImportQaTestCaseExecutions accepts a CSV, XLSX or NDJSON file, parses and saves it to the database
 // @Summary      Upload qa_test_case_executions.csv file
 // @Description  Upload qa_test_case_executions.csv file.
 // @Tags 		 plugins/customize
 // @Accept       multipart/form-data
 // @Param        qaProjectId formData string true "the ID of the QA project"
-// @Param        file formData file true "select file to upload"
+// @Param        file formData file true "select file to upload, CSV, XLSX and NDJSON are accepted"
+// @Param        dryRun formData bool false "validate every row without saving, a per-row error report is returned"
 // @Param        incremental formData bool false "incremental update"
 // @Produce      json
-// @Success      200
+// @Success      200  {object} service.ImportReport "only returned in dry-run mode"
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
 // @Router       /plugins/customize/csvfiles/qa_test_case_executions.csv [post]
@@ -128,5 +136,7 @@ func (h *Handlers) ImportQaTestCaseExecutions(input *plugin.ApiResourceInput) (*
 		return nil, errors.BadInput.New("empty qaProjectId")
 	}
 
-	return nil, h.svc.ImportQaTestCaseExecutions(qaProjectId, file, incremental)
+	return h.runImport(input, func(svc *service.Service) errors.Error {
+		return svc.ImportQaTestCaseExecutions(qaProjectId, file, incremental)
+	})
 }
@@ -40,6 +40,8 @@ type Field struct {
 	DisplayName string `json:"displayName" example:"department"`
 	DataType    string `json:"dataType" example:"varchar(255)"`
 	Description string `json:"description" example:"more details about the column"`
+	// Expression turns the field into a derived one, see the package `expression` for the syntax
+	Expression string `json:"expression" example:"CASE WHEN story_point > 8 THEN 'large' ELSE 'small' END"`
 }
 
 func (f *Field) toDBModel(table string) (*models.CustomizedField, errors.Error) {
@@ -59,6 +61,7 @@ func (f *Field) toDBModel(table string) (*models.CustomizedField, errors.Error)
 		DisplayName: f.DisplayName,
 		DataType:    t,
 		Description: f.Description,
+		Expression:  f.Expression,
 	}, nil
 }
 
@@ -69,6 +72,7 @@ func fromCustomizedField(cf models.CustomizedField) fieldResponse {
 			DisplayName: cf.DisplayName,
 			DataType:    cf.DataType.String(),
 			Description: cf.Description,
+			Expression:  cf.Expression,
 		},
 		IsCustomizedField: strings.HasPrefix(cf.ColumnName, "x_"),
 	}
@@ -105,7 +109,7 @@ func (h *Handlers) ListFields(input *plugin.ApiResourceInput) (*plugin.ApiResour
 
 // CreateFields create a customized field
 // @Summary create a customized field
-// @Description create a customized field
+// @Description create a customized field, the field is derived from other columns by the extractor if `expression` is set
 // @Tags plugins/customize
 // @Param table path string true "the table name"
 // @Param request body Field true "request body"
@@ -126,6 +130,9 @@ func (h *Handlers) CreateFields(input *plugin.ApiResourceInput) (*plugin.ApiReso
 	}
 	err = h.svc.CreateField(customizedField)
 	if err != nil {
+		if err.GetType() == errors.BadInput {
+			return &plugin.ApiResourceOutput{Status: http.StatusBadRequest}, err
+		}
 		return nil, errors.Default.Wrap(err, "CreateField error")
 	}
 	return &plugin.ApiResourceOutput{Body: fieldResponse{*fld, true}, Status: http.StatusOK}, nil
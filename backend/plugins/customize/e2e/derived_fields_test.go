@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/customize/impl"
+	"github.com/apache/incubator-devlake/plugins/customize/models"
+	"github.com/apache/incubator-devlake/plugins/customize/service"
+	"github.com/apache/incubator-devlake/plugins/customize/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerivedFieldsDataFlow(t *testing.T) {
+	var plugin impl.Customize
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "customize", plugin)
+
+	taskData := &tasks.TaskData{
+		Options: &tasks.Options{
+			TransformationRules: []tasks.MappingRules{{
+				Table:         "issues",
+				RawDataTable:  "_raw_jira_api_issues",
+				RawDataParams: `{"ConnectionId":1,"BoardId":8}`,
+				Mapping: map[string]string{
+					"x_float": "fields.customfield_10024",
+					"x_int":   "fields.customfield_10146",
+				},
+			}}}}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_jira_api_issues.csv", "_raw_jira_api_issues")
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/issues.csv", &ticket.Issue{})
+	dataflowTester.FlushTabler(&models.CustomizedField{})
+	svc := service.NewService(dataflowTester.Dal)
+	for _, cf := range []*models.CustomizedField{
+		{ColumnName: "x_float", DisplayName: "test column x_float", DataType: "float"},
+		{ColumnName: "x_int", DisplayName: "test column x_int", DataType: "bigint"},
+		{
+			ColumnName:  "x_size",
+			DisplayName: "size",
+			DataType:    "varchar(255)",
+			Expression:  `CASE WHEN x_float > 8 THEN 'large' WHEN x_float > 0 THEN 'small' ELSE 'none' END`,
+		},
+		{
+			ColumnName:  "x_cycle_days",
+			DisplayName: "cycle days",
+			DataType:    "bigint",
+			Expression:  `date_diff('day', created_date, resolution_date)`,
+		},
+		{
+			ColumnName:  "x_label",
+			DisplayName: "label",
+			DataType:    "varchar(255)",
+			Expression:  `x_size || '-' || coalesce(x_int, 0)`,
+		},
+	} {
+		cf.TbName = "issues"
+		err := svc.CreateField(cf)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// invalid expressions are rejected when the field is created
+	for _, expr := range []string{
+		`x_unknown + 1`,
+		`x_bad || 'a'`,
+		`lower(title`,
+	} {
+		err := svc.CreateField(&models.CustomizedField{
+			TbName:      "issues",
+			ColumnName:  "x_bad",
+			DisplayName: "bad",
+			DataType:    "varchar(255)",
+			Expression:  expr,
+		})
+		assert.NotNil(t, err, expr)
+	}
+	// fields referenced by derived fields can't be deleted
+	assert.NotNil(t, svc.DeleteField("issues", "x_size"))
+
+	// verify derived fields evaluation
+	dataflowTester.Subtask(tasks.ExtractCustomizedFieldsMeta, taskData)
+	dataflowTester.VerifyTable(
+		ticket.Issue{},
+		"./snapshot_tables/issues_with_derived_fields_board8.csv",
+		e2ehelper.ColumnWithRawData(
+			"id",
+			"x_float",
+			"x_int",
+			"x_size",
+			"x_cycle_days",
+			"x_label",
+		),
+	)
+}
@@ -0,0 +1,71 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"os"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/customize/impl"
+	"github.com/apache/incubator-devlake/plugins/customize/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportDryRunDataFlow(t *testing.T) {
+	var plugin impl.Customize
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "customize", plugin)
+
+	dataflowTester.FlushTabler(&ticket.Sprint{})
+	dataflowTester.FlushTabler(&ticket.BoardSprint{})
+	svc := service.NewService(dataflowTester.Dal)
+
+	sprintFile, err := os.Open("raw_tables/sprints_dry_run.ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sprintFile.Close()
+	report, err1 := svc.DryRun(func(svc *service.Service) errors.Error {
+		return svc.ImportSprint("csv-board", sprintFile, false)
+	})
+	if err1 != nil {
+		t.Fatal(err1)
+	}
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Failed)
+	if assert.Len(t, report.Errors, 2) {
+		assert.Equal(t, 2, report.Errors[0].Row)
+		assert.Contains(t, report.Errors[0].Error, "id")
+		assert.Equal(t, 3, report.Errors[1].Row)
+	}
+
+	// nothing should be written in dry-run mode
+	count, err1 := dataflowTester.Dal.Count(dal.From(&ticket.Sprint{}))
+	if err1 != nil {
+		t.Fatal(err1)
+	}
+	assert.Equal(t, int64(0), count)
+	count, err1 = dataflowTester.Dal.Count(dal.From(&ticket.BoardSprint{}))
+	if err1 != nil {
+		t.Fatal(err1)
+	}
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,261 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expression implements the small SQL-like language used by the customize plugin to
+// define derived fields, e.g.
+//
+//	CASE WHEN story_point > 8 THEN 'large' WHEN story_point > 3 THEN 'medium' ELSE 'small' END
+//
+// NULL propagates through operators and functions the same way it does in SQL,
+// and a NULL condition is treated as false.
+package expression
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// Expression is a compiled expression ready to be evaluated against rows
+type Expression struct {
+	source      string
+	root        node
+	identifiers []string
+}
+
+// Compile parses the source and checks function names, arities and literal regex patterns
+func Compile(source string) (*Expression, errors.Error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errors.BadInput.New("expression is empty")
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, identifiers: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, errors.BadInput.New(fmt.Sprintf("unexpected %s", p.peek()))
+	}
+	identifiers := make([]string, 0, len(p.identifiers))
+	for name := range p.identifiers {
+		identifiers = append(identifiers, name)
+	}
+	sort.Strings(identifiers)
+	return &Expression{source: source, root: root, identifiers: identifiers}, nil
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Identifiers returns the sorted column names referenced by the expression
+func (e *Expression) Identifiers() []string {
+	return e.identifiers
+}
+
+// Eval evaluates the expression against a row, the result is nil, bool, float64, string or time.Time
+func (e *Expression) Eval(row map[string]interface{}) (interface{}, errors.Error) {
+	return e.root.eval(row)
+}
+
+func (n *literalNode) eval(_ map[string]interface{}) (interface{}, errors.Error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(row map[string]interface{}) (interface{}, errors.Error) {
+	value, ok := row[n.name]
+	if !ok {
+		return nil, errors.Default.New(fmt.Sprintf("unknown column %s", n.name))
+	}
+	return normalize(value), nil
+}
+
+func (n *unaryNode) eval(row map[string]interface{}) (interface{}, errors.Error) {
+	v, err := n.operand.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	if n.op == "NOT" {
+		b, err := toBool(v)
+		if err != nil {
+			return nil, err
+		}
+		return !b, nil
+	}
+	f, err := toNumber(v)
+	if err != nil {
+		return nil, err
+	}
+	return -f, nil
+}
+
+func (n *isNullNode) eval(row map[string]interface{}) (interface{}, errors.Error) {
+	v, err := n.operand.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != n.negate, nil
+}
+
+func (n *caseNode) eval(row map[string]interface{}) (interface{}, errors.Error) {
+	for _, when := range n.whens {
+		cond, err := when.cond.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := isTrue(cond)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return when.result.eval(row)
+		}
+	}
+	if n.elseValue != nil {
+		return n.elseValue.eval(row)
+	}
+	return nil, nil
+}
+
+func (n *binaryNode) eval(row map[string]interface{}) (interface{}, errors.Error) {
+	if n.op == "AND" || n.op == "OR" {
+		return n.evalLogical(row)
+	}
+	left, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	switch n.op {
+	case "||":
+		return toString(left) + toString(right), nil
+	case "=", "!=", "<", "<=", ">", ">=":
+		c, err := compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "=":
+			return c == 0, nil
+		case "!=":
+			return c != 0, nil
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	}
+	l, err := toNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := toNumber(right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, nil
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, nil
+		}
+		return float64(int64(l) % int64(r)), nil
+	}
+}
+
+// evalLogical implements the three-valued AND/OR of SQL
+func (n *binaryNode) evalLogical(row map[string]interface{}) (interface{}, errors.Error) {
+	left, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if left != nil {
+		l, err := toBool(left)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "AND" && !l {
+			return false, nil
+		}
+		if n.op == "OR" && l {
+			return true, nil
+		}
+	}
+	right, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if right != nil {
+		r, err := toBool(right)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "AND" && !r {
+			return false, nil
+		}
+		if n.op == "OR" && r {
+			return true, nil
+		}
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return n.op == "AND", nil
+}
+
+func (n *callNode) eval(row map[string]interface{}) (interface{}, errors.Error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn.call(n, args)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to evaluate %s", n.name))
+	}
+	return v, nil
+}
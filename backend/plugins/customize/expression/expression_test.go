@@ -0,0 +1,140 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func eval(t *testing.T, source string, row map[string]interface{}) interface{} {
+	expr, err := Compile(source)
+	if !assert.Nil(t, err, source) {
+		return nil
+	}
+	v, err := expr.Eval(row)
+	assert.Nil(t, err, source)
+	return v
+}
+
+func TestEvalCase(t *testing.T) {
+	source := `CASE WHEN story_point > 8 THEN 'large' WHEN story_point > 3 THEN 'medium' ELSE 'small' END`
+	assert.Equal(t, "large", eval(t, source, map[string]interface{}{"story_point": int64(13)}))
+	assert.Equal(t, "medium", eval(t, source, map[string]interface{}{"story_point": []byte("5")}))
+	assert.Equal(t, "small", eval(t, source, map[string]interface{}{"story_point": 1.0}))
+	// NULL conditions are false
+	assert.Equal(t, "small", eval(t, source, map[string]interface{}{"story_point": nil}))
+	assert.Nil(t, eval(t, `case when x = 1 then 'one' end`, map[string]interface{}{"x": 2}))
+}
+
+func TestEvalOperators(t *testing.T) {
+	row := map[string]interface{}{"a": 7, "b": 2, "s": "x", "n": nil}
+	assert.Equal(t, 11.0, eval(t, "a + b * 2", row))
+	assert.Equal(t, 18.0, eval(t, "(a + b) * 2", row))
+	assert.Equal(t, 1.0, eval(t, "a % b", row))
+	assert.Equal(t, -5.0, eval(t, "-a + b", row))
+	assert.Nil(t, eval(t, "a / 0", row))
+	assert.Equal(t, "x7", eval(t, "s || a", row))
+	assert.Nil(t, eval(t, "n + 1", row))
+	assert.Equal(t, true, eval(t, "a <> b AND NOT a = b", row))
+	assert.Equal(t, true, eval(t, "n IS NULL AND s IS NOT NULL", row))
+	assert.Equal(t, true, eval(t, "n = 1 OR TRUE", row))
+	assert.Equal(t, false, eval(t, "n = 1 AND false", row))
+	assert.Nil(t, eval(t, "n = 1 AND true", row))
+	assert.Equal(t, "it's", eval(t, `'it''s'`, row))
+}
+
+func TestEvalFunctions(t *testing.T) {
+	row := map[string]interface{}{
+		"title":       "[FE] Fix login",
+		"created":     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		"resolved":    "2023-01-04 12:00:00",
+		"story_point": nil,
+	}
+	assert.Equal(t, "FE", eval(t, `regex_capture(title, '^\[(\w+)\]')`, row))
+	assert.Equal(t, "[FE]", eval(t, `regex_capture(title, '^\[(\w+)\]', 0)`, row))
+	assert.Nil(t, eval(t, `regex_capture(title, '^bug')`, row))
+	assert.Equal(t, true, eval(t, `regex_match(lower(title), 'login')`, row))
+	assert.Equal(t, 3.0, eval(t, `date_diff('day', created, resolved)`, row))
+	assert.Equal(t, 84.0, eval(t, `date_diff('hours', created, resolved)`, row))
+	assert.Equal(t, 0.0, eval(t, `coalesce(story_point, 0)`, row))
+	assert.Equal(t, "FE-x", eval(t, `concat(upper('fe'), '-', story_point, 'x')`, row))
+	assert.Equal(t, 3.14, eval(t, `round(3.14159, 2)`, row))
+	assert.Equal(t, 2.0, eval(t, `abs(-2)`, row))
+	assert.Equal(t, 3.0, eval(t, `length(trim('  abc '))`, row))
+	assert.Nil(t, eval(t, `upper(story_point)`, row))
+}
+
+func TestIdentifiers(t *testing.T) {
+	expr, err := Compile(`CASE WHEN Story_Point > 8 THEN lower(title) ELSE status END || title`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Story_Point", "status", "title"}, expr.Identifiers())
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"a +",
+		"(a",
+		"a b",
+		"'abc",
+		"a # b",
+		"CASE a END",
+		"CASE WHEN a THEN b",
+		"unknown_fn(a)",
+		"lower(a, b)",
+		"date_diff('day', a)",
+		"regex_match(a, '(')",
+		"a IS 1",
+	} {
+		_, err := Compile(source)
+		assert.NotNil(t, err, source)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	expr, err := Compile("a + 1")
+	assert.Nil(t, err)
+	_, err = expr.Eval(map[string]interface{}{"b": 1})
+	assert.NotNil(t, err)
+	_, err = expr.Eval(map[string]interface{}{"a": "abc"})
+	assert.NotNil(t, err)
+}
+
+func TestOrder(t *testing.T) {
+	compile := func(source string) *Expression {
+		expr, err := Compile(source)
+		assert.Nil(t, err)
+		return expr
+	}
+	order, err := Order(map[string]*Expression{
+		"x_c": compile("x_b || x_a"),
+		"x_b": compile("upper(x_a)"),
+		"x_a": compile("lower(title)"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x_a", "x_b", "x_c"}, order)
+
+	_, err = Order(map[string]*Expression{
+		"x_a": compile("x_b + 1"),
+		"x_b": compile("x_a + 1"),
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,190 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+type function struct {
+	minArgs int
+	// maxArgs is -1 for variadic functions
+	maxArgs int
+	// patternArg is the index of the regex argument, -1 if there is none
+	patternArg int
+	// nullable functions receive NULL arguments, others return NULL as soon as any argument is NULL
+	nullable bool
+	impl     func(call *callNode, args []interface{}) (interface{}, errors.Error)
+}
+
+func (f *function) call(call *callNode, args []interface{}) (interface{}, errors.Error) {
+	if !f.nullable {
+		for _, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+		}
+	}
+	return f.impl(call, args)
+}
+
+var functions map[string]*function
+
+func init() {
+	functions = map[string]*function{
+		"coalesce": {minArgs: 1, maxArgs: -1, patternArg: -1, nullable: true, impl: fnCoalesce},
+		"concat":   {minArgs: 1, maxArgs: -1, patternArg: -1, nullable: true, impl: fnConcat},
+		"lower": {minArgs: 1, maxArgs: 1, patternArg: -1, impl: func(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+			return strings.ToLower(toString(args[0])), nil
+		}},
+		"upper": {minArgs: 1, maxArgs: 1, patternArg: -1, impl: func(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+			return strings.ToUpper(toString(args[0])), nil
+		}},
+		"trim": {minArgs: 1, maxArgs: 1, patternArg: -1, impl: func(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+			return strings.TrimSpace(toString(args[0])), nil
+		}},
+		"length": {minArgs: 1, maxArgs: 1, patternArg: -1, impl: func(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+			return float64(utf8.RuneCountInString(toString(args[0]))), nil
+		}},
+		"abs": {minArgs: 1, maxArgs: 1, patternArg: -1, impl: func(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+			f, err := toNumber(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return math.Abs(f), nil
+		}},
+		"round":         {minArgs: 1, maxArgs: 2, patternArg: -1, impl: fnRound},
+		"regex_match":   {minArgs: 2, maxArgs: 2, patternArg: 1, impl: fnRegexMatch},
+		"regex_capture": {minArgs: 2, maxArgs: 3, patternArg: 1, impl: fnRegexCapture},
+		"date_diff":     {minArgs: 3, maxArgs: 3, patternArg: -1, impl: fnDateDiff},
+	}
+}
+
+func fnCoalesce(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnConcat(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+	var sb strings.Builder
+	for _, arg := range args {
+		if arg != nil {
+			sb.WriteString(toString(arg))
+		}
+	}
+	return sb.String(), nil
+}
+
+func fnRound(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+	f, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	digits := 0.0
+	if len(args) > 1 {
+		digits, err = toNumber(args[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	scale := math.Pow(10, math.Trunc(digits))
+	return math.Round(f*scale) / scale, nil
+}
+
+func pattern(call *callNode, v interface{}) (*regexp.Regexp, errors.Error) {
+	if call.re != nil {
+		return call.re, nil
+	}
+	re, err := regexp.Compile(toString(v))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "invalid pattern")
+	}
+	return re, nil
+}
+
+func fnRegexMatch(call *callNode, args []interface{}) (interface{}, errors.Error) {
+	re, err := pattern(call, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(toString(args[0])), nil
+}
+
+// fnRegexCapture returns the given group of the first match, NULL if nothing matches.
+// The group defaults to 1, or to the whole match when the pattern has no group
+func fnRegexCapture(call *callNode, args []interface{}) (interface{}, errors.Error) {
+	re, err := pattern(call, args[1])
+	if err != nil {
+		return nil, err
+	}
+	group := 0
+	if re.NumSubexp() > 0 {
+		group = 1
+	}
+	if len(args) > 2 {
+		g, err := toNumber(args[2])
+		if err != nil {
+			return nil, err
+		}
+		group = int(g)
+	}
+	if group < 0 || group > re.NumSubexp() {
+		return nil, errors.Default.New(fmt.Sprintf("pattern has no group %d", group))
+	}
+	matches := re.FindStringSubmatch(toString(args[0]))
+	if matches == nil {
+		return nil, nil
+	}
+	return matches[group], nil
+}
+
+var dateUnits = map[string]float64{
+	"second": 1,
+	"minute": 60,
+	"hour":   3600,
+	"day":    86400,
+	"week":   7 * 86400,
+}
+
+// fnDateDiff returns the number of whole units between start and end, negative if end is before start
+func fnDateDiff(_ *callNode, args []interface{}) (interface{}, errors.Error) {
+	unit := strings.TrimSuffix(strings.ToLower(toString(args[0])), "s")
+	seconds, ok := dateUnits[unit]
+	if !ok {
+		return nil, errors.Default.New(fmt.Sprintf("unknown unit %q", toString(args[0])))
+	}
+	start, err := toTime(args[1])
+	if err != nil {
+		return nil, err
+	}
+	end, err := toTime(args[2])
+	if err != nil {
+		return nil, err
+	}
+	return math.Trunc(end.Sub(start).Seconds() / seconds), nil
+}
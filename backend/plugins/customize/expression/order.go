@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// Order sorts the named expressions so that every one of them comes after the expressions it references,
+// it fails if the references form a cycle
+func Order(exprs map[string]*Expression) ([]string, errors.Error) {
+	names := make([]string, 0, len(exprs))
+	for name := range exprs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(exprs))
+	result := make([]string, 0, len(exprs))
+	var visit func(name string, path []string) errors.Error
+	visit = func(name string, path []string) errors.Error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.BadInput.New(fmt.Sprintf("circular reference: %v", append(path, name)))
+		}
+		state[name] = visiting
+		for _, dep := range exprs[name].Identifiers() {
+			if _, ok := exprs[dep]; ok {
+				if err := visit(dep, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		result = append(result, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,383 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// node is a compiled piece of an expression
+type node interface {
+	eval(row map[string]interface{}) (interface{}, errors.Error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+type identNode struct {
+	name string
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+type isNullNode struct {
+	operand node
+	negate  bool
+}
+
+type whenClause struct {
+	cond, result node
+}
+
+type caseNode struct {
+	whens     []whenClause
+	elseValue node
+}
+
+type callNode struct {
+	name string
+	fn   *function
+	args []node
+	// re is the precompiled pattern when the regex argument is a literal
+	re *regexp.Regexp
+}
+
+type parser struct {
+	tokens      []token
+	pos         int
+	identifiers map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokenKeyword && t.text == word
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(word string) errors.Error {
+	if !p.isKeyword(word) {
+		return errors.BadInput.New(fmt.Sprintf("expected %s but got %s", word, p.peek()))
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) expect(kind tokenKind, text string) errors.Error {
+	if p.peek().kind != kind {
+		return errors.BadInput.New(fmt.Sprintf("expected %q but got %s", text, p.peek()))
+	}
+	p.next()
+	return nil
+}
+
+// parseOr handles the lowest precedence level: expr OR expr
+func (p *parser) parseOr() (node, errors.Error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, errors.Error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, errors.Error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "NOT", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, errors.Error) {
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if p.isKeyword("IS") {
+		p.next()
+		negate := false
+		if p.isKeyword("NOT") {
+			p.next()
+			negate = true
+		}
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullNode{operand: left, negate: negate}, nil
+	}
+	if p.isOperator("=", "==", "!=", "<>", "<", "<=", ">", ">=") {
+		op := p.next().text
+		switch op {
+		case "==":
+			op = "="
+		case "<>":
+			op = "!="
+		}
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseConcat() (node, errors.Error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, errors.Error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (node, errors.Error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, errors.Error) {
+	if p.isOperator("-", "+") {
+		op := p.next().text
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return operand, nil
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, errors.Error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, errors.BadInput.New(fmt.Sprintf("invalid number %s", t))
+		}
+		return &literalNode{value: f}, nil
+	case tokenString:
+		return &literalNode{value: t.text}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenKeyword:
+		switch t.text {
+		case "TRUE":
+			return &literalNode{value: true}, nil
+		case "FALSE":
+			return &literalNode{value: false}, nil
+		case "NULL":
+			return &literalNode{value: nil}, nil
+		case "CASE":
+			return p.parseCase()
+		}
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(t)
+		}
+		p.identifiers[t.text] = true
+		return &identNode{name: t.text}, nil
+	}
+	return nil, errors.BadInput.New(fmt.Sprintf("unexpected %s", t))
+}
+
+func (p *parser) parseCase() (node, errors.Error) {
+	c := &caseNode{}
+	for p.isKeyword("WHEN") {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		result, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		c.whens = append(c.whens, whenClause{cond: cond, result: result})
+	}
+	if len(c.whens) == 0 {
+		return nil, errors.BadInput.New(fmt.Sprintf("expected WHEN but got %s", p.peek()))
+	}
+	if p.isKeyword("ELSE") {
+		p.next()
+		elseValue, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		c.elseValue = elseValue
+	}
+	if err := p.expectKeyword("END"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (p *parser) parseCall(name token) (node, errors.Error) {
+	fn, ok := functions[strings.ToLower(name.text)]
+	if !ok {
+		return nil, errors.BadInput.New(fmt.Sprintf("unknown function %s", name))
+	}
+	p.next() // (
+	call := &callNode{name: strings.ToLower(name.text), fn: fn}
+	if p.peek().kind != tokenRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expect(tokenRParen, ")"); err != nil {
+		return nil, err
+	}
+	if len(call.args) < fn.minArgs || (fn.maxArgs >= 0 && len(call.args) > fn.maxArgs) {
+		return nil, errors.BadInput.New(fmt.Sprintf("wrong number of arguments for %s: got %d", call.name, len(call.args)))
+	}
+	if fn.patternArg >= 0 {
+		if lit, ok := call.args[fn.patternArg].(*literalNode); ok {
+			pattern, ok := lit.value.(string)
+			if !ok {
+				return nil, errors.BadInput.New(fmt.Sprintf("pattern of %s must be a string", call.name))
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid pattern for %s", call.name))
+			}
+			call.re = re
+		}
+	}
+	return call, nil
+}
@@ -0,0 +1,185 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// normalize converts values read from the database into one of nil, bool, float64, string or time.Time
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil, bool, float64, string, time.Time:
+		return x
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return *x
+	case []byte:
+		return string(x)
+	case int:
+		return float64(x)
+	case int8:
+		return float64(x)
+	case int16:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case uint8:
+		return float64(x)
+	case uint16:
+		return float64(x)
+	case uint32:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case float32:
+		return float64(x)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
+
+// isTrue reports whether a condition holds, NULL is false
+func isTrue(v interface{}) (bool, errors.Error) {
+	if v == nil {
+		return false, nil
+	}
+	return toBool(v)
+}
+
+func toBool(v interface{}) (bool, errors.Error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case float64:
+		return x != 0, nil
+	case string:
+		b, err := strconv.ParseBool(x)
+		if err != nil {
+			return false, errors.Default.New(fmt.Sprintf("%q is not a boolean", x))
+		}
+		return b, nil
+	}
+	return false, errors.Default.New(fmt.Sprintf("%v is not a boolean", v))
+}
+
+func toNumber(v interface{}) (float64, errors.Error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case bool:
+		if x {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return 0, errors.Default.New(fmt.Sprintf("%q is not a number", x))
+		}
+		return f, nil
+	}
+	return 0, errors.Default.New(fmt.Sprintf("%v is not a number", v))
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		return x.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func toTime(v interface{}) (time.Time, errors.Error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case string:
+		if t, err := common.ConvertStringToTime(x); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse("2006-01-02", x); err == nil {
+			return t, nil
+		}
+		return time.Time{}, errors.Default.New(fmt.Sprintf("%q is not a date", x))
+	}
+	return time.Time{}, errors.Default.New(fmt.Sprintf("%v is not a date", v))
+}
+
+// compare orders two non-nil values, numbers and dates are compared by value and everything else as strings
+func compare(left, right interface{}) (int, errors.Error) {
+	switch l := left.(type) {
+	case float64:
+		r, err := toNumber(right)
+		if err != nil {
+			return 0, err
+		}
+		return compareFloat(l, r), nil
+	case time.Time:
+		r, err := toTime(right)
+		if err != nil {
+			return 0, err
+		}
+		return compareFloat(float64(l.UnixNano()), float64(r.UnixNano())), nil
+	case bool:
+		r, err := toBool(right)
+		if err != nil {
+			return 0, err
+		}
+		return compareFloat(boolToFloat(l), boolToFloat(r)), nil
+	}
+	switch right.(type) {
+	case float64, time.Time, bool:
+		c, err := compare(right, left)
+		return -c, err
+	}
+	return strings.Compare(toString(left), toString(right)), nil
+}
+
+func compareFloat(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -31,6 +31,8 @@ type CustomizedField struct {
 	DisplayName string         `gorm:"type:varchar(255)"`
 	DataType    dal.ColumnType `gorm:"type:varchar(255)"`
 	Description string
+	// Expression makes the field a derived one, computed from other columns by the extractor
+	Expression string `gorm:"type:text"`
 }
 
 func (t *CustomizedField) TableName() string {
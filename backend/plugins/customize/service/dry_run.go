@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+const dryRunSavepoint = "customize_dry_run_row"
+
+// ImportReport is the result of validating a file in dry-run mode
+type ImportReport struct {
+	Total  int               `json:"total"`
+	Failed int               `json:"failed"`
+	Errors []*ImportRowError `json:"errors"`
+}
+
+// ImportRowError is the reason a row of the file would be rejected, Row starts from 1 for the first record
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// DryRun runs importFn against a transaction which is always rolled back, so every row gets validated by the
+// exact same handlers used for importing without writing anything, failing rows are collected into the report
+func (s *Service) DryRun(importFn func(svc *Service) errors.Error) (*ImportReport, errors.Error) {
+	tx := s.dal.Begin()
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	report := &ImportReport{Errors: []*ImportRowError{}}
+	err := importFn(&Service{dal: tx, nameChecker: s.nameChecker, report: report})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// validateRecord handles the record within a savepoint, a failing row is rolled back alone and reported,
+// so it doesn't affect the following ones even on databases aborting the whole transaction on errors
+func (s *Service) validateRecord(row int, record map[string]interface{}, recordHandler func(map[string]interface{}) errors.Error) errors.Error {
+	if err := s.dal.Exec("SAVEPOINT " + dryRunSavepoint); err != nil {
+		return err
+	}
+	s.report.Total++
+	if err := recordHandler(record); err != nil {
+		s.report.Failed++
+		s.report.Errors = append(s.report.Errors, &ImportRowError{Row: row, Error: err.Messages().Format()})
+		return s.dal.Exec("ROLLBACK TO SAVEPOINT " + dryRunSavepoint)
+	}
+	return s.dal.Exec("RELEASE SAVEPOINT " + dryRunSavepoint)
+}
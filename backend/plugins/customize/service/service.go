@@ -32,6 +32,7 @@ import (
 	"github.com/apache/incubator-devlake/core/models/domainlayer/qa"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper"
+	"github.com/apache/incubator-devlake/plugins/customize/expression"
 	customizeModels "github.com/apache/incubator-devlake/plugins/customize/models"
 )
 
@@ -39,6 +40,8 @@ import (
 type Service struct {
 	dal         dal.Dal
 	nameChecker *regexp.Regexp
+	// report is set in dry-run mode, failing rows are recorded into it instead of aborting the import
+	report *ImportReport
 }
 
 func NewService(dal dal.Dal) *Service {
@@ -119,6 +122,12 @@ func (s *Service) CreateField(cf *customizeModels.CustomizedField) errors.Error
 	if exists {
 		return errors.BadInput.New(fmt.Sprintf("the column %s already exists", cf.ColumnName))
 	}
+	if cf.Expression != "" {
+		err = s.checkExpression(cf)
+		if err != nil {
+			return err
+		}
+	}
 	err = s.dal.Create(cf)
 	if err != nil {
 		return errors.Default.Wrap(err, "create customizedField")
@@ -139,6 +148,13 @@ func (s *Service) DeleteField(table, field string) errors.Error {
 	if !exists {
 		return nil
 	}
+	dependents, err := s.getDependentFields(table, field)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return errors.BadInput.New(fmt.Sprintf("the column %s is referenced by derived fields %s", field, strings.Join(dependents, ", ")))
+	}
 	err = s.dal.DropColumns(table, field)
 	if err != nil {
 		return errors.Default.Wrap(err, "DropColumn error")
@@ -146,6 +162,65 @@ func (s *Service) DeleteField(table, field string) errors.Error {
 	return s.dal.Delete(&customizeModels.CustomizedField{}, dal.Where("tb_name = ? AND column_name = ?", table, field))
 }
 
+// checkExpression makes sure the expression of a derived field compiles,
+// only references existing columns of the table and doesn't introduce a circular reference
+func (s *Service) checkExpression(cf *customizeModels.CustomizedField) errors.Error {
+	expr, err := expression.Compile(cf.Expression)
+	if err != nil {
+		return errors.BadInput.Wrap(err, "invalid expression")
+	}
+	fields, err := s.GetFields(cf.TbName)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool, len(fields))
+	derived := map[string]*expression.Expression{cf.ColumnName: expr}
+	for _, f := range fields {
+		columns[f.ColumnName] = true
+		if f.Expression != "" {
+			derived[f.ColumnName], err = expression.Compile(f.Expression)
+			if err != nil {
+				return errors.Default.Wrap(err, fmt.Sprintf("invalid expression of the existing field %s", f.ColumnName))
+			}
+		}
+	}
+	for _, name := range expr.Identifiers() {
+		if name == cf.ColumnName {
+			return errors.BadInput.New(fmt.Sprintf("the expression of %s references itself", cf.ColumnName))
+		}
+		if !columns[name] {
+			return errors.BadInput.New(fmt.Sprintf("the expression references unknown column %s", name))
+		}
+	}
+	_, err = expression.Order(derived)
+	return err
+}
+
+// getDependentFields returns the derived fields of the table whose expressions reference the field
+func (s *Service) getDependentFields(table, field string) ([]string, errors.Error) {
+	ff, err := s.getCustomizedFields(table)
+	if err != nil {
+		return nil, err
+	}
+	var dependents []string
+	for _, f := range ff {
+		if f.Expression == "" || f.ColumnName == field {
+			continue
+		}
+		expr, err := expression.Compile(f.Expression)
+		if err != nil {
+			continue
+		}
+		for _, name := range expr.Identifiers() {
+			if name == field {
+				dependents = append(dependents, f.ColumnName)
+				break
+			}
+		}
+	}
+	return dependents, nil
+}
+
 // getCustomizedFields returns all the customized fields definitions of the table
 func (s *Service) getCustomizedFields(table string) ([]customizeModels.CustomizedField, errors.Error) {
 	var result []customizeModels.CustomizedField
@@ -182,7 +257,7 @@ func (s *Service) ImportIssue(boardId string, file io.ReadCloser, incremental bo
 			return err
 		}
 	}
-	return s.importCSV(file, boardId, s.issueHandlerFactory(boardId, incremental))
+	return s.importRecords(file, boardId, s.issueHandlerFactory(boardId, incremental))
 }
 
 // SaveBoard make sure the board exists in table `boards`
@@ -205,7 +280,7 @@ func (s *Service) ImportIssueCommit(boardId string, file io.ReadCloser) errors.E
 	if err != nil {
 		return err
 	}
-	return s.importCSV(file, boardId, s.issueCommitHandler)
+	return s.importRecords(file, boardId, s.issueCommitHandler)
 }
 
 // ImportIssueRepoCommit imports data to the table `issue_repo_commits` and `issue_commits`
@@ -227,15 +302,15 @@ func (s *Service) ImportIssueRepoCommit(boardId string, file io.ReadCloser, incr
 			return err
 		}
 	}
-	return s.importCSV(file, boardId, s.issueRepoCommitHandler)
+	return s.importRecords(file, boardId, s.issueRepoCommitHandler)
 }
 
-// importCSV extract records from csv file, and save them to DB using recordHandler
+// importRecords extract records from csv, xlsx or ndjson file, and save them to DB using recordHandler
 // the rawDataParams is used to identify the data source,
 // the recordHandler is used to handle the record, it should return an error if the record is invalid
 // the `created_at` and `updated_at` will be set to the current time
-func (s *Service) importCSV(file io.ReadCloser, rawDataParams string, recordHandler func(map[string]interface{}) errors.Error) errors.Error {
-	iterator, err := pluginhelper.NewCsvFileIteratorFromFile(file)
+func (s *Service) importRecords(file io.ReadCloser, rawDataParams string, recordHandler func(map[string]interface{}) errors.Error) errors.Error {
+	iterator, err := pluginhelper.NewRecordIteratorFromFile(file)
 	if err != nil {
 		return err
 	}
@@ -250,13 +325,17 @@ func (s *Service) importCSV(file io.ReadCloser, rawDataParams string, recordHand
 			record := iterator.Fetch()
 			record["_raw_data_params"] = rawDataParams
 			for k, v := range record {
-				if v.(string) == "NULL" {
+				if v, ok := v.(string); ok && v == "NULL" {
 					record[k] = nil
 				}
 			}
 			record["created_at"] = now
 			record["updated_at"] = now
-			err = recordHandler(record)
+			if s.report != nil {
+				err = s.validateRecord(line, record, recordHandler)
+			} else {
+				err = recordHandler(record)
+			}
 			if err != nil {
 				return errors.BadInput.Wrap(err, fmt.Sprintf("error on processing the line:%d", line))
 			}
@@ -365,7 +444,7 @@ func (s *Service) issueHandlerFactory(boardId string, incremental bool) func(rec
 		// Handle creator and assignee accounts
 		rawDataParams, err := getStringField(record, "_raw_data_params", true)
 		if err != nil {
-			// This should ideally not happen as it's set in importCSV, but good to check
+			// This should ideally not happen as it's set in importRecords, but good to check
 			return err
 		}
 
@@ -447,7 +526,7 @@ func (s *Service) ImportQaApis(qaProjectId string, file io.ReadCloser, increment
 			return errors.Default.Wrap(err, fmt.Sprintf("failed to delete old qa_apis for qaProjectId %s", qaProjectId))
 		}
 	}
-	return s.importCSV(file, qaProjectId, s.qaApiHandler(qaProjectId))
+	return s.importRecords(file, qaProjectId, s.qaApiHandler(qaProjectId))
 }
 
 // qaApiHandler saves a record into the `qa_apis` table
@@ -500,7 +579,7 @@ func (s *Service) ImportQaTestCases(qaProjectId, qaProjectName string, file io.R
 	if err != nil {
 		return err
 	}
-	return s.importCSV(file, qaProjectId, s.qaTestCaseHandler(qaProjectId))
+	return s.importRecords(file, qaProjectId, s.qaTestCaseHandler(qaProjectId))
 }
 
 // qaTestCaseHandler saves a record into the `qa_test_cases` table
@@ -527,7 +606,7 @@ func (s *Service) ImportQaTestCaseExecutions(qaProjectId string, file io.ReadClo
 			return errors.Default.Wrap(err, fmt.Sprintf("failed to delete old qa_test_case_executions for qaProjectId %s", qaProjectId))
 		}
 	}
-	return s.importCSV(file, qaProjectId, s.qaTestCaseExecutionHandler(qaProjectId))
+	return s.importRecords(file, qaProjectId, s.qaTestCaseExecutionHandler(qaProjectId))
 }
 
 // qaTestCaseExecutionHandler saves a record into the `qa_test_case_executions` table
@@ -578,7 +657,7 @@ func (s *Service) ImportSprint(boardId string, file io.ReadCloser, incremental b
 			return err
 		}
 	}
-	return s.importCSV(file, boardId, s.sprintHandler(boardId))
+	return s.importRecords(file, boardId, s.sprintHandler(boardId))
 }
 
 // sprintHandler saves a record into the `sprints` table
@@ -613,7 +692,7 @@ func (s *Service) ImportIssueChangelog(boardId string, file io.ReadCloser, incre
 			return err
 		}
 	}
-	return s.importCSV(file, boardId, s.issueChangelogHandler)
+	return s.importRecords(file, boardId, s.issueChangelogHandler)
 }
 
 // issueChangelogHandler saves a record into the `issue_changelogs` table
@@ -675,7 +754,7 @@ func (s *Service) ImportIssueWorklog(boardId string, file io.ReadCloser, increme
 			return err
 		}
 	}
-	return s.importCSV(file, boardId, s.issueWorklogHandler)
+	return s.importRecords(file, boardId, s.issueWorklogHandler)
 }
 
 // issueWorklogHandler saves a record into the `issue_worklogs` table
@@ -46,12 +46,17 @@ func ExtractCustomizedFields(taskCtx plugin.SubTaskContext) errors.Error {
 		return nil
 	}
 	d := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
 	var err error
 	for _, rule := range data.Options.TransformationRules {
 		err = extractCustomizedFields(taskCtx.GetContext(), d, rule.Table, rule.RawDataTable, rule.RawDataParams, rule.Mapping)
 		if err != nil {
 			return errors.Default.Wrap(err, "error extracting customized fields")
 		}
+		err = deriveFields(taskCtx.GetContext(), d, logger, rule.Table, rule.RawDataTable, rule.RawDataParams)
+		if err != nil {
+			return errors.Default.Wrap(err, "error deriving customized fields")
+		}
 	}
 	return nil
 }
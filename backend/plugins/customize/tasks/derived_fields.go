@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/plugins/customize/expression"
+	"github.com/apache/incubator-devlake/plugins/customize/models"
+)
+
+// deriveFields evaluates the expressions of the derived fields of the table against the rows extracted from rawTable,
+// fields are evaluated in dependency order so that a derived field may reference another one
+func deriveFields(ctx context.Context, d dal.Dal, logger log.Logger, table, rawTable, rawDataParams string) errors.Error {
+	var fields []models.CustomizedField
+	err := d.All(&fields, dal.Where("tb_name = ? AND expression IS NOT NULL AND expression != ''", table))
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	exprs := make(map[string]*expression.Expression, len(fields))
+	for _, f := range fields {
+		exprs[f.ColumnName], err = expression.Compile(f.Expression)
+		if err != nil {
+			return errors.Default.Wrap(err, fmt.Sprintf("invalid expression of the field %s", f.ColumnName))
+		}
+	}
+	order, err := expression.Order(exprs)
+	if err != nil {
+		return err
+	}
+	pkFields, err := dal.GetPrimarykeyColumns(d, &models.Table{Name: table})
+	if err != nil {
+		return err
+	}
+	rows, err := d.Cursor(
+		dal.From(table),
+		dal.Where("_raw_data_table = ? AND _raw_data_params LIKE ?", rawTable, rawDataParams),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		select {
+		case <-ctx.Done():
+			return errors.Convert(ctx.Err())
+		default:
+		}
+		row := make(map[string]interface{})
+		err = d.Fetch(rows, &row)
+		if err != nil {
+			return err
+		}
+		pk := make(map[string]interface{}, len(pkFields))
+		for _, field := range pkFields {
+			pk[field.Name()] = row[field.Name()]
+		}
+		updates := make(map[string]interface{}, len(order))
+		for _, name := range order {
+			value, evalErr := exprs[name].Eval(row)
+			if evalErr != nil {
+				logger.Warn(evalErr, "failed to evaluate %s of %s %v", name, table, pk)
+				value = nil
+			}
+			// later fields may reference the value just derived
+			row[name] = value
+			updates[name] = value
+		}
+		query, params := mkUpdate(table, updates, pk)
+		err = d.Exec(query, params...)
+		if err != nil {
+			return errors.Default.Wrap(err, "Exec SQL error")
+		}
+	}
+	return nil
+}
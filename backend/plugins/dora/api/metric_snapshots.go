@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+// @Summary get DORA metric snapshots of a project
+// @Description GET /plugins/dora/metric-snapshots?projectName=xxx&periodType=month&definitionVersion=xxx&since=2024-01-01&until=2024-12-31
+// @Description snapshots of closed periods are never recalculated, filter by definitionVersion to get comparable values
+// @Tags plugins/dora
+// @Param projectName query string true "project name"
+// @Param periodType query string false "week or month"
+// @Param definitionVersion query string false "definition version"
+// @Param since query string false "periods starting at or after the date, i.e. 2024-01-01"
+// @Param until query string false "periods starting before the date, i.e. 2024-12-31"
+// @Success 200  {object} []crossdomain.DoraMetricSnapshot
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/dora/metric-snapshots [GET]
+func GetMetricSnapshots(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	projectName := input.Query.Get("projectName")
+	if projectName == "" {
+		return nil, errors.BadInput.New("projectName is required")
+	}
+	clauses := []dal.Clause{
+		dal.From(&crossdomain.DoraMetricSnapshot{}),
+		dal.Where("project_name = ?", projectName),
+	}
+	if periodType := input.Query.Get("periodType"); periodType != "" {
+		if periodType != crossdomain.DORA_SNAPSHOT_PERIOD_WEEK && periodType != crossdomain.DORA_SNAPSHOT_PERIOD_MONTH {
+			return nil, errors.BadInput.New("periodType must be week or month")
+		}
+		clauses = append(clauses, dal.Where("period_type = ?", periodType))
+	}
+	if definitionVersion := input.Query.Get("definitionVersion"); definitionVersion != "" {
+		clauses = append(clauses, dal.Where("definition_version = ?", definitionVersion))
+	}
+	for param, condition := range map[string]string{"since": "period_start >= ?", "until": "period_start < ?"} {
+		value := input.Query.Get(param)
+		if value == "" {
+			continue
+		}
+		date, err := parseDate(value)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid "+param)
+		}
+		clauses = append(clauses, dal.Where(condition, date))
+	}
+	clauses = append(clauses, dal.Orderby("period_type, period_start, definition_version"))
+
+	snapshots := make([]*crossdomain.DoraMetricSnapshot, 0)
+	err := basicRes.GetDal().All(&snapshots, clauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error getting dora_metric_snapshots")
+	}
+	return &plugin.ApiResourceOutput{Body: snapshots, Status: http.StatusOK}, nil
+}
+
+func parseDate(value string) (time.Time, errors.Error) {
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		date, err = time.Parse("2006-01-02", value)
+	}
+	return date, errors.Convert(err)
+}
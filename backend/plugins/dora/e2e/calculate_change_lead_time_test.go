@@ -33,12 +33,6 @@ func TestCalculateCLTimeDataFlow(t *testing.T) {
 	var plugin impl.Dora
 	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
 
-	taskData := &tasks.DoraTaskData{
-		Options: &tasks.DoraOptions{
-			ProjectName: "project1",
-		},
-	}
-
 	dataflowTester.FlushTabler(&code.PullRequest{})
 
 	// import raw data table
@@ -51,6 +45,16 @@ func TestCalculateCLTimeDataFlow(t *testing.T) {
 	dataflowTester.ImportCsvIntoTabler("./change_lead_time/pull_request_comments.csv", &code.PullRequestComment{})
 	dataflowTester.ImportCsvIntoTabler("./change_lead_time/pull_request_commits.csv", &code.PullRequestCommit{})
 
+	taskData, err := tasks.NewDoraTaskData(&tasks.DoraOptions{
+		ProjectName: "project1",
+		DoraDefinition: tasks.DoraDefinition{
+			HotfixBranchPattern: "^hotfix/",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
 	// verify converter
 	dataflowTester.FlushTabler(&crossdomain.ProjectPrMetric{})
 	dataflowTester.Subtask(tasks.CalculateChangeLeadTimeMeta, taskData)
@@ -33,15 +33,17 @@ func TestConnectIncidentToDeploymentDataFlow(t *testing.T) {
 	var plugin impl.Dora
 	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
 
-	taskData := &tasks.DoraTaskData{
-		Options: &tasks.DoraOptions{
-			ProjectName: "project1",
-		},
-	}
 	// import raw data table
 	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/prev_success_deployment_commit/cicd_deployment_commits_after.csv", &devops.CicdDeploymentCommit{})
 	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/raw_tables/project_mapping.csv", &crossdomain.ProjectMapping{})
 	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/raw_tables/incidents.csv", &ticket.Incident{})
+	dataflowTester.FlushTabler(&crossdomain.IncidentAttribution{})
+	taskData, err := tasks.NewDoraTaskData(&tasks.DoraOptions{
+		ProjectName: "project1",
+	})
+	if err != nil {
+		panic(err)
+	}
 
 	// verify converter
 	dataflowTester.FlushTabler(&crossdomain.ProjectIncidentDeploymentRelationship{})
@@ -51,3 +53,28 @@ func TestConnectIncidentToDeploymentDataFlow(t *testing.T) {
 		IgnoreTypes: []interface{}{common.NoPKModel{}},
 	})
 }
+
+func TestConnectIncidentToDeploymentWithAttributionsDataFlow(t *testing.T) {
+	var plugin impl.Dora
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "dora", plugin)
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/prev_success_deployment_commit/cicd_deployment_commits_after.csv", &devops.CicdDeploymentCommit{})
+	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/raw_tables/project_mapping.csv", &crossdomain.ProjectMapping{})
+	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/raw_tables/incidents.csv", &ticket.Incident{})
+	dataflowTester.ImportCsvIntoTabler("./connect_incident_to_deployment/raw_tables/incident_attributions.csv", &crossdomain.IncidentAttribution{})
+	taskData, err := tasks.NewDoraTaskData(&tasks.DoraOptions{
+		ProjectName: "project1",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// explicit deployments outside the project or unknown fall back to the time based attribution
+	dataflowTester.FlushTabler(&crossdomain.ProjectIncidentDeploymentRelationship{})
+	dataflowTester.Subtask(tasks.ConnectIncidentToDeploymentMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&crossdomain.ProjectIncidentDeploymentRelationship{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./connect_incident_to_deployment/snapshot_tables/project_incident_deployment_relationships_with_attributions.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
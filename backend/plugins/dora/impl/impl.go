@@ -20,10 +20,12 @@ package impl
 import (
 	"encoding/json"
 
+	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	coreModels "github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/dora/api"
 	"github.com/apache/incubator-devlake/plugins/dora/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/dora/tasks"
 )
@@ -35,11 +37,18 @@ var _ interface {
 	plugin.PluginModel
 	plugin.PluginMetric
 	plugin.PluginMigration
+	plugin.PluginInit
+	plugin.PluginApi
 	plugin.MetricPluginBlueprintV200
 } = (*Dora)(nil)
 
 type Dora struct{}
 
+func (p Dora) Init(basicRes context.BasicRes) errors.Error {
+	api.Init(basicRes)
+	return nil
+}
+
 func (p Dora) Description() string {
 	return "collect some Dora data"
 }
@@ -96,6 +105,7 @@ func (p Dora) SubTaskMetas() []plugin.SubTaskMeta {
 		tasks.CalculateChangeLeadTimeMeta,
 		tasks.IssuesToIncidentsMeta,
 		tasks.ConnectIncidentToDeploymentMeta,
+		tasks.SnapshotDoraMetricsMeta,
 	}
 }
 
@@ -104,9 +114,15 @@ func (p Dora) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]int
 	if err != nil {
 		return nil, err
 	}
-	return &tasks.DoraTaskData{
-		Options: op,
-	}, nil
+	return tasks.NewDoraTaskData(op)
+}
+
+func (p Dora) ApiResources() map[string]map[string]plugin.ApiResourceHandler {
+	return map[string]map[string]plugin.ApiResourceHandler{
+		"metric-snapshots": {
+			"GET": api.GetMetricSnapshots,
+		},
+	}
 }
 
 // RootPkgPath information lost when compiled as plugin(.so)
@@ -126,14 +142,27 @@ func (p Dora) MakeMetricPluginPipelinePlanV200(projectName string, options json.
 			return nil, errors.Default.WrapRaw(err)
 		}
 	}
+	// the definitions are passed to the dora tasks as is, refdiff doesn't care about them
+	doraOptions := map[string]interface{}{
+		"projectName": projectName,
+	}
+	definition, err := json.Marshal(struct {
+		tasks.DoraDefinition
+		ScopeDoraDefinitions map[string]tasks.DoraDefinition `json:"scopeDoraDefinitions,omitempty"`
+	}{op.DoraDefinition, op.ScopeDoraDefinitions})
+	if err != nil {
+		return nil, errors.Default.WrapRaw(err)
+	}
+	err = json.Unmarshal(definition, &doraOptions)
+	if err != nil {
+		return nil, errors.Default.WrapRaw(err)
+	}
 
 	plan := coreModels.PipelinePlan{
 		{
 			{
-				Plugin: "dora",
-				Options: map[string]interface{}{
-					"projectName": projectName,
-				},
+				Plugin:  "dora",
+				Options: doraOptions,
 				Subtasks: []string{
 					"generateDeployments",
 					"generateDeploymentCommits",
@@ -154,14 +183,13 @@ func (p Dora) MakeMetricPluginPipelinePlanV200(projectName string, options json.
 		},
 		{
 			{
-				Plugin: "dora",
-				Options: map[string]interface{}{
-					"projectName": projectName,
-				},
+				Plugin:  "dora",
+				Options: doraOptions,
 				Subtasks: []string{
 					"calculateChangeLeadTime",
 					tasks.IssuesToIncidentsMeta.Name,
 					"ConnectIncidentToDeployment",
+					tasks.SnapshotDoraMetricsMeta.Name,
 				},
 			},
 		},
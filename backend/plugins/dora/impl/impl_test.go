@@ -63,6 +63,7 @@ func TestMakeMetricPluginPipelinePlanV200(t *testing.T) {
 					"calculateChangeLeadTime",
 					tasks.IssuesToIncidentsMeta.Name,
 					"ConnectIncidentToDeployment",
+					tasks.SnapshotDoraMetricsMeta.Name,
 				},
 				Options: map[string]interface{}{"projectName": projectName},
 			},
@@ -70,3 +71,60 @@ func TestMakeMetricPluginPipelinePlanV200(t *testing.T) {
 	}
 	assert.Equal(t, doraOutputPlan, plan)
 }
+
+func TestMakeMetricPluginPipelinePlanV200WithDoraDefinition(t *testing.T) {
+	var dora Dora
+	const projectName = "TestMakePlanV200-project"
+	optionJson := []byte(`{"productionEnvironments":["PRODUCTION","STAGING"],"incidentAttribution":"fixDeployment","hotfixLabelPattern":"(?i)hotfix"}`)
+	plan, err := dora.MakeMetricPluginPipelinePlanV200(projectName, optionJson)
+	assert.Nil(t, err)
+	expectedOptions := map[string]interface{}{
+		"projectName":            projectName,
+		"productionEnvironments": []interface{}{"PRODUCTION", "STAGING"},
+		"incidentAttribution":    "fixDeployment",
+		"hotfixLabelPattern":     "(?i)hotfix",
+	}
+	assert.Equal(t, expectedOptions, plan[0][0].Options)
+	assert.Equal(t, map[string]interface{}{"projectName": projectName}, plan[1][0].Options)
+	assert.Equal(t, expectedOptions, plan[2][0].Options)
+
+	// options of the plan should be decoded back into the same definition
+	op, err := tasks.DecodeAndValidateTaskOptions(plan[2][0].Options)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"PRODUCTION", "STAGING"}, op.GetProductionEnvironments())
+	assert.Equal(t, tasks.INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT, op.GetIncidentAttribution())
+	definition, err := tasks.NewScopeDefinition("", op.DoraDefinition)
+	assert.Nil(t, err)
+	assert.True(t, definition.HotfixLabelRegex.MatchString("HotFix"))
+	assert.Nil(t, definition.HotfixBranchRegex)
+}
+
+func TestMakeMetricPluginPipelinePlanV200WithScopeDoraDefinitions(t *testing.T) {
+	var dora Dora
+	const projectName = "TestMakePlanV200-project"
+	optionJson := []byte(`{"hotfixLabelPattern":"(?i)hotfix","scopeDoraDefinitions":{"github:GithubRepo:1:1":{"productionEnvironments":["PRODUCTION","STAGING"]}}}`)
+	plan, err := dora.MakeMetricPluginPipelinePlanV200(projectName, optionJson)
+	assert.Nil(t, err)
+
+	// the overrides of scopes should be passed to the tasks alongside the definition of the project
+	op, err := tasks.DecodeAndValidateTaskOptions(plan[2][0].Options)
+	assert.Nil(t, err)
+	data, err := tasks.NewDoraTaskData(op)
+	assert.Nil(t, err)
+	definition := data.Definitions.Get("github:GithubRepo:1:1")
+	assert.Equal(t, "github:GithubRepo:1:1", definition.ScopeId)
+	assert.Equal(t, []string{"PRODUCTION", "STAGING"}, definition.GetProductionEnvironments())
+	assert.Nil(t, definition.HotfixLabelRegex)
+	definition = data.Definitions.Get("github:GithubRepo:1:2")
+	assert.Equal(t, "", definition.ScopeId)
+	assert.True(t, definition.HotfixLabelRegex.MatchString("HotFix"))
+}
+
+func TestDecodeAndValidateTaskOptionsWithInvalidDoraDefinition(t *testing.T) {
+	_, err := tasks.DecodeAndValidateTaskOptions(map[string]interface{}{"incidentAttribution": "nextDeployment"})
+	assert.NotNil(t, err)
+	_, err = tasks.DecodeAndValidateTaskOptions(map[string]interface{}{"incidentAttributionWindowHours": -1})
+	assert.NotNil(t, err)
+	_, err = tasks.NewScopeDefinition("", tasks.DoraDefinition{HotfixBranchPattern: "("})
+	assert.NotNil(t, err)
+}
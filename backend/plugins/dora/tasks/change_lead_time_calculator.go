@@ -51,10 +51,9 @@ func CalculateChangeLeadTime(taskCtx plugin.SubTaskContext) errors.Error {
 	if err != nil {
 		return errors.Default.Wrap(err, "error deleting previous project_pr_metrics")
 	}
-
 	// Get pull requests by repo project_name
 	var clauses = []dal.Clause{
-		dal.Select("pr.id, pr.pull_request_key, pr.author_id, pr.merge_commit_sha, pr.created_date, pr.merged_date"),
+		dal.Select("pr.id, pr.base_repo_id, pr.pull_request_key, pr.author_id, pr.merge_commit_sha, pr.head_ref, pr.created_date, pr.merged_date"),
 		dal.From("pull_requests pr"),
 		dal.Join(`LEFT JOIN project_mapping pm ON (pm.row_id = pr.base_repo_id)`),
 		dal.Where("pr.merged_date IS NOT NULL AND pm.project_name = ? AND pm.table = 'repos'", data.Options.ProjectName),
@@ -83,6 +82,15 @@ func CalculateChangeLeadTime(taskCtx plugin.SubTaskContext) errors.Error {
 			projectPrMetric := &crossdomain.ProjectPrMetric{}
 			projectPrMetric.Id = pr.Id
 			projectPrMetric.ProjectName = data.Options.ProjectName
+			// The definition of the project might be overridden for the base repo
+			definition := data.Definitions.Get(pr.BaseRepoId)
+			projectPrMetric.DoraDefinition = definition.String()
+
+			// Detect rework/hotfix by the head branch and labels
+			projectPrMetric.IsHotfix, err = isHotfix(pr, definition, db)
+			if err != nil {
+				return nil, err
+			}
 
 			// Get the first commit for the PR
 			firstCommit, err := getFirstCommit(pr.Id, db)
@@ -114,7 +122,7 @@ func CalculateChangeLeadTime(taskCtx plugin.SubTaskContext) errors.Error {
 			projectPrMetric.PrMergedDate = pr.MergedDate
 
 			// Get the deployment for the PR
-			deployment, err := getDeploymentCommit(pr.MergeCommitSha, data.Options.ProjectName, db)
+			deployment, err := getDeploymentCommit(pr.MergeCommitSha, data.Options.ProjectName, data.Definitions, db)
 			if err != nil {
 				return nil, err
 			}
@@ -206,9 +214,30 @@ func getFirstReview(prId string, prCreator string, db dal.Dal) (*code.PullReques
 	return review, nil
 }
 
-// getDeploymentCommit takes a merge commit SHA, a repository ID, a list of deployment pairs, and a database connection as input.
-// It returns the deployment pair related to the merge commit, or nil if not found.
-func getDeploymentCommit(mergeSha string, projectName string, db dal.Dal) (*devops.CicdDeploymentCommit, errors.Error) {
+// isHotfix tells whether the PR is a rework/hotfix by matching its head branch and labels with the patterns of the DoraDefinition
+func isHotfix(pr *code.PullRequest, definition *ScopeDefinition, db dal.Dal) (bool, errors.Error) {
+	if definition.HotfixBranchRegex != nil && definition.HotfixBranchRegex.MatchString(pr.HeadRef) {
+		return true, nil
+	}
+	if definition.HotfixLabelRegex == nil {
+		return false, nil
+	}
+	var labels []string
+	err := db.Pluck("label_name", &labels, dal.From(&code.PullRequestLabel{}), dal.Where("pull_request_id = ?", pr.Id))
+	if err != nil {
+		return false, err
+	}
+	for _, label := range labels {
+		if definition.HotfixLabelRegex.MatchString(label) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getDeploymentCommit takes a merge commit SHA, a project name, the definitions of the scopes, and a database connection as input.
+// It returns the first deployment commit containing the merge commit to a production environment of its cicd_scope, or nil if not found.
+func getDeploymentCommit(mergeSha string, projectName string, definitions *ScopeDefinitions, db dal.Dal) (*devops.CicdDeploymentCommit, errors.Error) {
 	deploymentCommits := make([]*devops.CicdDeploymentCommit, 0, 1)
 	// do not use `.First` method since gorm would append ORDER BY ID to the query which leads to a error
 	err := db.All(
@@ -219,7 +248,7 @@ func getDeploymentCommit(mergeSha string, projectName string, db dal.Dal) (*devo
 		dal.Join("LEFT JOIN project_mapping pm ON (pm.table = 'cicd_scopes' AND pm.row_id = dc.cicd_scope_id)"),
 		dal.Join("INNER JOIN commits_diffs cd ON (cd.new_commit_sha = dc.commit_sha AND cd.old_commit_sha = COALESCE (p.commit_sha, ''))"),
 		dal.Where("dc.prev_success_deployment_commit_id <> ''"),
-		dal.Where("dc.environment = 'PRODUCTION'"), // TODO: remove this when multi-environment is supported
+		definitions.ProductionClause("dc"),
 		dal.Where("pm.project_name = ? AND cd.commit_sha = ? AND dc.RESULT = ?", projectName, mergeSha, devops.RESULT_SUCCESS),
 		dal.Orderby("dc.started_date, dc.id ASC"),
 		dal.Limit(1),
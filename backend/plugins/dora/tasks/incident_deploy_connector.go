@@ -18,6 +18,7 @@ limitations under the License.
 package tasks
 
 import (
+	"math"
 	"reflect"
 	"time"
 
@@ -55,6 +56,10 @@ func ConnectIncidentToDeployment(taskCtx plugin.SubTaskContext) errors.Error {
 		return errors.Default.Wrap(err, "error deleting previous project_incident_deployment_relationships")
 	}
 	logger.Info("delete previous project_incident_deployment_relationships")
+	attributions, err := loadIncidentAttributions(db, data.Options.ProjectName)
+	if err != nil {
+		return err
+	}
 	// select all issues belongs to the board
 	clauses := []dal.Clause{
 		dal.From(`incidents i`),
@@ -99,35 +104,46 @@ func ConnectIncidentToDeployment(taskCtx plugin.SubTaskContext) errors.Error {
 				ProjectName: data.Options.ProjectName,
 			}
 			logger.Debug("get incident: %+v", incident.Id)
-			cicdDeploymentCommit := &devops.CicdDeploymentCommit{}
-			cicdDeploymentCommitClauses := []dal.Clause{
-				dal.Select("cicd_deployment_commits.cicd_deployment_id as id, cicd_deployment_commits.finished_date as finished_date"),
-				dal.From(cicdDeploymentCommit),
-				dal.Join("left join project_mapping pm on cicd_deployment_commits.cicd_scope_id = pm.row_id"),
-				dal.Where(
-					`cicd_deployment_commits.finished_date < ?
-					    and cicd_deployment_commits.result = ?
-						and cicd_deployment_commits.environment = ?
-						and pm.table = ?
-						and pm.project_name = ?`,
-					incident.CreatedDate, devops.RESULT_SUCCESS, devops.PRODUCTION, "cicd_scopes", data.Options.ProjectName,
-				),
-				dal.Orderby("finished_date DESC"),
-				dal.Limit(1),
+			if incident.CreatedDate == nil {
+				logger.Debug("created date is empty, incident will be ignored: %+v", incident.Id)
+				return nil, nil
 			}
-
-			scdc := &simpleCicdDeploymentCommit{}
-			err = db.All(scdc, cicdDeploymentCommitClauses...)
-			if err != nil {
-				if db.IsErrorNotFound(err) {
-					logger.Warn(err, "deployment commit not found")
-					return nil, nil
-				} else {
+			attribution := attributions[incident.Id]
+			if attribution != nil && attribution.CausedByDeploymentId != "" {
+				// explicit attribution wins as long as the deployment belongs to the project
+				count, err := db.Count(
+					dal.From(&devops.CicdDeploymentCommit{}),
+					dal.Join("left join project_mapping pm on cicd_deployment_commits.cicd_scope_id = pm.row_id"),
+					dal.Where(
+						"cicd_deployment_commits.cicd_deployment_id = ? and pm.table = ? and pm.project_name = ?",
+						attribution.CausedByDeploymentId, "cicd_scopes", data.Options.ProjectName,
+					),
+				)
+				if err != nil {
+					return nil, err
+				}
+				if count > 0 {
+					projectIssueMetric.DeploymentId = attribution.CausedByDeploymentId
+					return []interface{}{projectIssueMetric}, nil
+				}
+				logger.Warn(nil, "deployment %s attributed to incident %s is not found in the project, fall back to time based attribution", attribution.CausedByDeploymentId, incident.Id)
+			}
+			// cicd_scopes might attribute incidents by different definitions, the nearest deployment wins
+			var scdc *simpleCicdDeploymentCommit
+			for _, group := range data.Definitions.Groups() {
+				candidate, err := findAttributedDeployment(db, incident, attribution, group, data.Options.ProjectName)
+				if err != nil {
 					logger.Error(err, "get all deployment commits")
 					return nil, err
 				}
+				if candidate.Id == "" {
+					continue
+				}
+				if scdc == nil || deploymentDistance(incident, candidate) < deploymentDistance(incident, scdc) {
+					scdc = candidate
+				}
 			}
-			if scdc.Id != "" {
+			if scdc != nil {
 				projectIssueMetric.DeploymentId = scdc.Id
 				return []interface{}{projectIssueMetric}, nil
 			}
@@ -141,3 +157,95 @@ func ConnectIncidentToDeployment(taskCtx plugin.SubTaskContext) errors.Error {
 
 	return enricher.Execute()
 }
+
+// findAttributedDeployment returns the deployment attributed for the incident among the cicd_scopes of the group
+func findAttributedDeployment(
+	db dal.Dal,
+	incident *ticket.Incident,
+	attribution *crossdomain.IncidentAttribution,
+	group *ScopeGroup,
+	projectName string,
+) (*simpleCicdDeploymentCommit, errors.Error) {
+	cicdDeploymentCommitClauses := []dal.Clause{
+		dal.Select("cicd_deployment_commits.cicd_deployment_id as id, cicd_deployment_commits.finished_date as finished_date"),
+		dal.From(&devops.CicdDeploymentCommit{}),
+		dal.Join("left join project_mapping pm on cicd_deployment_commits.cicd_scope_id = pm.row_id"),
+		dal.Where(
+			`cicd_deployment_commits.result = ?
+				and cicd_deployment_commits.environment in ?
+				and pm.table = ?
+				and pm.project_name = ?`,
+			devops.RESULT_SUCCESS, group.Definition.GetProductionEnvironments(), "cicd_scopes", projectName,
+		),
+	}
+	cicdDeploymentCommitClauses = append(cicdDeploymentCommitClauses, group.Clauses("cicd_deployment_commits.cicd_scope_id")...)
+	if attribution != nil && attribution.CicdScopeId != "" {
+		cicdDeploymentCommitClauses = append(cicdDeploymentCommitClauses, dal.Where("cicd_deployment_commits.cicd_scope_id = ?", attribution.CicdScopeId))
+	}
+	cicdDeploymentCommitClauses = append(cicdDeploymentCommitClauses, incidentAttributionClauses(incident, &group.Definition.DoraDefinition)...)
+	cicdDeploymentCommitClauses = append(cicdDeploymentCommitClauses, dal.Limit(1))
+
+	scdc := &simpleCicdDeploymentCommit{}
+	err := db.All(scdc, cicdDeploymentCommitClauses...)
+	if err != nil && !db.IsErrorNotFound(err) {
+		return nil, err
+	}
+	return scdc, nil
+}
+
+// deploymentDistance returns how far the deployment finished from the creation of the incident
+func deploymentDistance(incident *ticket.Incident, scdc *simpleCicdDeploymentCommit) time.Duration {
+	if scdc.FinishedDate == nil {
+		return time.Duration(math.MaxInt64)
+	}
+	distance := scdc.FinishedDate.Sub(*incident.CreatedDate)
+	if distance < 0 {
+		return -distance
+	}
+	return distance
+}
+
+// incidentAttributionClauses returns clauses selecting the deployment to be attributed for the incident
+func incidentAttributionClauses(incident *ticket.Incident, definition *DoraDefinition) []dal.Clause {
+	createdDate := *incident.CreatedDate
+	window := time.Duration(definition.IncidentAttributionWindowHours) * time.Hour
+	if definition.GetIncidentAttribution() == INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT {
+		clauses := []dal.Clause{
+			dal.Where("cicd_deployment_commits.finished_date >= ?", createdDate),
+			dal.Orderby("finished_date ASC"),
+		}
+		if window > 0 {
+			clauses = append(clauses, dal.Where("cicd_deployment_commits.finished_date <= ?", createdDate.Add(window)))
+		}
+		return clauses
+	}
+	clauses := []dal.Clause{
+		dal.Where("cicd_deployment_commits.finished_date < ?", createdDate),
+		dal.Orderby("finished_date DESC"),
+	}
+	if window > 0 {
+		clauses = append(clauses, dal.Where("cicd_deployment_commits.finished_date >= ?", createdDate.Add(-window)))
+	}
+	return clauses
+}
+
+// loadIncidentAttributions returns the explicit attributions of the incidents in the project, keyed by incident id
+func loadIncidentAttributions(db dal.Dal, projectName string) (map[string]*crossdomain.IncidentAttribution, errors.Error) {
+	var attributions []*crossdomain.IncidentAttribution
+	err := db.All(
+		&attributions,
+		dal.Select("ia.*"),
+		dal.From("incident_attributions ia"),
+		dal.Join("inner join incidents i on i.id = ia.incident_id"),
+		dal.Join("inner join project_mapping pm on pm.row_id = i.scope_id and pm.table = i.table"),
+		dal.Where("pm.project_name = ?", projectName),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error loading incident attributions")
+	}
+	attributionMap := make(map[string]*crossdomain.IncidentAttribution, len(attributions))
+	for _, attribution := range attributions {
+		attributionMap[attribution.IncidentId] = attribution
+	}
+	return attributionMap, nil
+}
@@ -0,0 +1,282 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var SnapshotDoraMetricsMeta = plugin.SubTaskMeta{
+	Name:             "snapshotDoraMetrics",
+	EntryPoint:       SnapshotDoraMetrics,
+	EnabledByDefault: true,
+	Description:      "Materialize DORA metrics of the project by week and month into dora_metric_snapshots",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD, plugin.DOMAIN_TYPE_CODE, plugin.DOMAIN_TYPE_TICKET},
+}
+
+type snapshotDeployment struct {
+	Id           string
+	FinishedDate *time.Time
+}
+
+type snapshotPullRequest struct {
+	PrDeployedDate *time.Time
+	PrCycleTime    *int64
+}
+
+type snapshotIncident struct {
+	CreatedDate    *time.Time
+	ResolutionDate *time.Time
+}
+
+// doraSnapshotInput holds everything needed for calculating snapshots of a project
+type doraSnapshotInput struct {
+	deployments       []*snapshotDeployment
+	failedDeployments map[string]bool
+	pullRequests      []*snapshotPullRequest
+	incidents         []*snapshotIncident
+}
+
+// SnapshotDoraMetrics calculates DORA metrics of the project for each week and month. Snapshots of closed periods
+// are kept as is, only the open periods and the missing ones get (re)calculated
+func SnapshotDoraMetrics(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*DoraTaskData)
+	projectName := data.Options.ProjectName
+	definitions := data.Definitions
+	version := definitions.Version()
+
+	input, err := loadDoraSnapshotInput(db, projectName, definitions)
+	if err != nil {
+		return err
+	}
+	earliest := input.earliest()
+	if earliest == nil {
+		logger.Info("no data for snapshotting DORA metrics of project %s", projectName)
+		return nil
+	}
+
+	closed := make([]*crossdomain.DoraMetricSnapshot, 0)
+	err = db.All(
+		&closed,
+		dal.Where("project_name = ? AND definition_version = ? AND is_closed = ?", projectName, version, true),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "error loading closed dora_metric_snapshots")
+	}
+	isClosed := make(map[string]bool, len(closed))
+	for _, snapshot := range closed {
+		isClosed[snapshot.PeriodType+snapshot.PeriodStart.UTC().Format(time.RFC3339)] = true
+	}
+
+	now := time.Now().UTC()
+	for _, periodType := range []string{crossdomain.DORA_SNAPSHOT_PERIOD_WEEK, crossdomain.DORA_SNAPSHOT_PERIOD_MONTH} {
+		for _, start := range doraSnapshotPeriodStarts(periodType, *earliest, now) {
+			if isClosed[periodType+start.Format(time.RFC3339)] {
+				continue
+			}
+			snapshot := input.calculate(periodType, start, now)
+			snapshot.ProjectName = projectName
+			snapshot.DefinitionVersion = version
+			snapshot.DoraDefinition = definitions.String()
+			err = db.CreateOrUpdate(snapshot)
+			if err != nil {
+				return errors.Default.Wrap(err, "error saving dora_metric_snapshots")
+			}
+		}
+	}
+	return nil
+}
+
+func loadDoraSnapshotInput(db dal.Dal, projectName string, definitions *ScopeDefinitions) (*doraSnapshotInput, errors.Error) {
+	input := &doraSnapshotInput{failedDeployments: make(map[string]bool)}
+	err := db.All(
+		&input.deployments,
+		dal.Select("dc.cicd_deployment_id AS id, MAX(dc.finished_date) AS finished_date"),
+		dal.From("cicd_deployment_commits dc"),
+		dal.Join("LEFT JOIN project_mapping pm ON (pm.table = 'cicd_scopes' AND pm.row_id = dc.cicd_scope_id)"),
+		dal.Where(
+			"pm.project_name = ? AND dc.result = ? AND dc.finished_date IS NOT NULL",
+			projectName, devops.RESULT_SUCCESS,
+		),
+		definitions.ProductionClause("dc"),
+		dal.Groupby("dc.cicd_deployment_id"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error loading deployments")
+	}
+	var failedDeploymentIds []string
+	err = db.Pluck(
+		"deployment_id",
+		&failedDeploymentIds,
+		dal.From(&crossdomain.ProjectIncidentDeploymentRelationship{}),
+		dal.Where("project_name = ?", projectName),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error loading failed deployments")
+	}
+	for _, id := range failedDeploymentIds {
+		input.failedDeployments[id] = true
+	}
+	err = db.All(
+		&input.pullRequests,
+		dal.Select("pr_deployed_date, pr_cycle_time"),
+		dal.From(&crossdomain.ProjectPrMetric{}),
+		dal.Where("project_name = ? AND pr_deployed_date IS NOT NULL AND pr_cycle_time IS NOT NULL", projectName),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error loading project_pr_metrics")
+	}
+	err = db.All(
+		&input.incidents,
+		dal.Select("i.created_date, i.resolution_date"),
+		dal.From("incidents i"),
+		dal.Join("LEFT JOIN project_mapping pm ON (pm.row_id = i.scope_id AND pm.table = i.table)"),
+		dal.Where("pm.project_name = ? AND i.created_date IS NOT NULL AND i.resolution_date IS NOT NULL", projectName),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error loading incidents")
+	}
+	return input, nil
+}
+
+// earliest returns the earliest date of all inputs, nil if there is no input at all
+func (input *doraSnapshotInput) earliest() *time.Time {
+	var earliest *time.Time
+	check := func(t *time.Time) {
+		if t != nil && (earliest == nil || t.Before(*earliest)) {
+			earliest = t
+		}
+	}
+	for _, deployment := range input.deployments {
+		check(deployment.FinishedDate)
+	}
+	for _, pr := range input.pullRequests {
+		check(pr.PrDeployedDate)
+	}
+	for _, incident := range input.incidents {
+		check(incident.ResolutionDate)
+	}
+	return earliest
+}
+
+// calculate calculates the snapshot of the period starting at `start`
+func (input *doraSnapshotInput) calculate(periodType string, start, now time.Time) *crossdomain.DoraMetricSnapshot {
+	end := doraSnapshotPeriodEnd(periodType, start)
+	snapshot := &crossdomain.DoraMetricSnapshot{
+		PeriodType:   periodType,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		IsClosed:     !now.Before(end),
+		CalculatedAt: now,
+	}
+	within := func(t *time.Time) bool {
+		return t != nil && !t.Before(start) && t.Before(end)
+	}
+
+	deploymentDays := make(map[string]bool)
+	for _, deployment := range input.deployments {
+		if !within(deployment.FinishedDate) {
+			continue
+		}
+		snapshot.DeploymentCount++
+		deploymentDays[deployment.FinishedDate.UTC().Format("2006-01-02")] = true
+		if input.failedDeployments[deployment.Id] {
+			snapshot.FailedDeploymentCount++
+		}
+	}
+	snapshot.DeploymentDays = len(deploymentDays)
+	// the open period is measured by the days passed so far
+	elapsed := end
+	if now.Before(end) {
+		elapsed = now
+	}
+	days := elapsed.Sub(start).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+	snapshot.DeploymentFrequency = float64(snapshot.DeploymentCount) / days
+	if snapshot.DeploymentCount > 0 {
+		rate := float64(snapshot.FailedDeploymentCount) / float64(snapshot.DeploymentCount)
+		snapshot.ChangeFailureRate = &rate
+	}
+
+	var cycleTimes []int64
+	for _, pr := range input.pullRequests {
+		if within(pr.PrDeployedDate) && pr.PrCycleTime != nil {
+			cycleTimes = append(cycleTimes, *pr.PrCycleTime)
+		}
+	}
+	snapshot.LeadTimeMinutes = median(cycleTimes)
+
+	var restoreTimes []int64
+	for _, incident := range input.incidents {
+		if within(incident.ResolutionDate) && incident.CreatedDate != nil {
+			restoreTimes = append(restoreTimes, int64(incident.ResolutionDate.Sub(*incident.CreatedDate).Minutes()))
+		}
+	}
+	snapshot.IncidentCount = len(restoreTimes)
+	snapshot.MttrMinutes = median(restoreTimes)
+	return snapshot
+}
+
+// doraSnapshotPeriodStarts returns starts of all periods from the one containing `from` to the one containing `to` in UTC
+func doraSnapshotPeriodStarts(periodType string, from, to time.Time) []time.Time {
+	from = from.UTC()
+	var start time.Time
+	if periodType == crossdomain.DORA_SNAPSHOT_PERIOD_WEEK {
+		// weeks start on Monday
+		day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	} else {
+		start = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	var starts []time.Time
+	for ; !start.After(to); start = doraSnapshotPeriodEnd(periodType, start) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+func doraSnapshotPeriodEnd(periodType string, start time.Time) time.Time {
+	if periodType == crossdomain.DORA_SNAPSHOT_PERIOD_WEEK {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+func median(values []int64) *int64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	middle := len(values) / 2
+	result := values[middle]
+	if len(values)%2 == 0 {
+		result = (values[middle-1] + values[middle]) / 2
+	}
+	return &result
+}
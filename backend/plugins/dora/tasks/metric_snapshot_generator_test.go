@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoraSnapshotPeriodStarts(t *testing.T) {
+	from := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC) // Wednesday
+	to := time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)     // Monday
+
+	weeks := doraSnapshotPeriodStarts(crossdomain.DORA_SNAPSHOT_PERIOD_WEEK, from, to)
+	assert.Len(t, weeks, 5)
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), weeks[0])
+	assert.Equal(t, time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC), weeks[4])
+
+	months := doraSnapshotPeriodStarts(crossdomain.DORA_SNAPSHOT_PERIOD_MONTH, from, to)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}, months)
+}
+
+func TestDoraSnapshotCalculate(t *testing.T) {
+	date := func(day, hour int) *time.Time {
+		d := time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+		return &d
+	}
+	minutes := func(m int64) *int64 {
+		return &m
+	}
+	input := &doraSnapshotInput{
+		deployments: []*snapshotDeployment{
+			{Id: "d1", FinishedDate: date(8, 1)},
+			{Id: "d2", FinishedDate: date(8, 5)},
+			{Id: "d3", FinishedDate: date(10, 1)},
+			{Id: "d4", FinishedDate: date(10, 1)},
+			{Id: "d5", FinishedDate: date(15, 1)},
+		},
+		failedDeployments: map[string]bool{"d2": true, "d5": true},
+		pullRequests: []*snapshotPullRequest{
+			{PrDeployedDate: date(8, 1), PrCycleTime: minutes(100)},
+			{PrDeployedDate: date(9, 1), PrCycleTime: minutes(300)},
+			{PrDeployedDate: date(14, 23), PrCycleTime: minutes(200)},
+			{PrDeployedDate: date(15, 1), PrCycleTime: minutes(1000)},
+		},
+		incidents: []*snapshotIncident{
+			{CreatedDate: date(7, 0), ResolutionDate: date(8, 0)},
+			{CreatedDate: date(15, 0), ResolutionDate: date(15, 2)},
+		},
+	}
+	assert.Equal(t, date(8, 0), input.earliest())
+
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	week := input.calculate(crossdomain.DORA_SNAPSHOT_PERIOD_WEEK, *date(8, 0), now)
+	assert.True(t, week.IsClosed)
+	assert.Equal(t, *date(15, 0), week.PeriodEnd)
+	assert.Equal(t, 4, week.DeploymentCount)
+	assert.Equal(t, 2, week.DeploymentDays)
+	assert.InDelta(t, 4.0/7, week.DeploymentFrequency, 0.0001)
+	assert.Equal(t, 1, week.FailedDeploymentCount)
+	assert.InDelta(t, 0.25, *week.ChangeFailureRate, 0.0001)
+	assert.Equal(t, int64(200), *week.LeadTimeMinutes)
+	assert.Equal(t, 1, week.IncidentCount)
+	assert.Equal(t, int64(1440), *week.MttrMinutes)
+
+	// the open period is measured by the days passed so far
+	open := input.calculate(crossdomain.DORA_SNAPSHOT_PERIOD_WEEK, *date(15, 0), *date(17, 0))
+	assert.False(t, open.IsClosed)
+	assert.Equal(t, 1, open.DeploymentCount)
+	assert.InDelta(t, 0.5, open.DeploymentFrequency, 0.0001)
+	assert.Equal(t, int64(120), *open.MttrMinutes)
+
+	empty := input.calculate(crossdomain.DORA_SNAPSHOT_PERIOD_WEEK, *date(22, 0), now)
+	assert.Equal(t, 0, empty.DeploymentCount)
+	assert.Nil(t, empty.ChangeFailureRate)
+	assert.Nil(t, empty.LeadTimeMinutes)
+	assert.Nil(t, empty.MttrMinutes)
+}
@@ -0,0 +1,203 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// ScopeDefinition is the DoraDefinition of a scope with the hotfix patterns compiled
+type ScopeDefinition struct {
+	DoraDefinition
+	// ScopeId is the domain id of the repo or cicd_scope the definition is overridden for,
+	// empty for the definition of the project
+	ScopeId           string
+	HotfixBranchRegex *regexp.Regexp
+	HotfixLabelRegex  *regexp.Regexp
+}
+
+// NewScopeDefinition validates the definition and compiles its hotfix patterns
+func NewScopeDefinition(scopeId string, definition DoraDefinition) (*ScopeDefinition, errors.Error) {
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+	d := &ScopeDefinition{DoraDefinition: definition, ScopeId: scopeId}
+	var err error
+	if definition.HotfixBranchPattern != "" {
+		d.HotfixBranchRegex, err = regexp.Compile(definition.HotfixBranchPattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid hotfixBranchPattern")
+		}
+	}
+	if definition.HotfixLabelPattern != "" {
+		d.HotfixLabelRegex, err = regexp.Compile(definition.HotfixLabelPattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid hotfixLabelPattern")
+		}
+	}
+	return d, nil
+}
+
+// ScopeDefinitions holds the DoraDefinition of repos and cicd_scopes of the project which are overridden by the
+// options of the task, the others use the Default one of the project
+type ScopeDefinitions struct {
+	Default *ScopeDefinition
+	scopes  map[string]*ScopeDefinition
+}
+
+// NewScopeDefinitions creates the ScopeDefinitions from definitions of the scopes
+func NewScopeDefinitions(defaultDefinition *ScopeDefinition, scopes ...*ScopeDefinition) *ScopeDefinitions {
+	definitions := &ScopeDefinitions{Default: defaultDefinition, scopes: make(map[string]*ScopeDefinition)}
+	for _, scope := range scopes {
+		definitions.scopes[scope.ScopeId] = scope
+	}
+	return definitions
+}
+
+// Get returns the definition of the scope
+func (s *ScopeDefinitions) Get(scopeId string) *ScopeDefinition {
+	if definition, ok := s.scopes[scopeId]; ok {
+		return definition
+	}
+	return s.Default
+}
+
+// ScopeGroup is a group of cicd_scopes sharing the same definition
+type ScopeGroup struct {
+	Definition *ScopeDefinition
+	// ScopeIds are the cicd_scopes of the group, or the ones to be excluded if Others is true
+	ScopeIds []string
+	// Others is true for the group of cicd_scopes using the default definition
+	Others bool
+}
+
+// Clauses returns the conditions for the column of cicd_scope_id to match the group
+func (g *ScopeGroup) Clauses(column string) []dal.Clause {
+	if !g.Others {
+		return []dal.Clause{dal.Where(column+" IN ?", g.ScopeIds)}
+	}
+	if len(g.ScopeIds) == 0 {
+		return nil
+	}
+	return []dal.Clause{dal.Where(column+" NOT IN ?", g.ScopeIds)}
+}
+
+// Groups returns the overridden scopes grouped by their definitions, followed by the group of the others
+func (s *ScopeDefinitions) Groups() []*ScopeGroup {
+	groups := make([]*ScopeGroup, 0)
+	byDefinition := make(map[string]*ScopeGroup)
+	others := &ScopeGroup{Definition: s.Default, Others: true, ScopeIds: make([]string, 0)}
+	for _, scopeId := range s.scopeIds() {
+		definition := s.scopes[scopeId]
+		others.ScopeIds = append(others.ScopeIds, scopeId)
+		key := definition.String()
+		if group, ok := byDefinition[key]; ok {
+			group.ScopeIds = append(group.ScopeIds, scopeId)
+			continue
+		}
+		group := &ScopeGroup{Definition: definition, ScopeIds: []string{scopeId}}
+		byDefinition[key] = group
+		groups = append(groups, group)
+	}
+	return append(groups, others)
+}
+
+// ProductionClause returns the condition for deployment commits to be deployed to production environments of
+// their cicd_scopes, the table of deployment commits is referred as `alias`
+func (s *ScopeDefinitions) ProductionClause(alias string) dal.Clause {
+	conditions := make([]string, 0)
+	params := make([]interface{}, 0)
+	for _, group := range s.Groups() {
+		environments := group.Definition.GetProductionEnvironments()
+		switch {
+		case !group.Others:
+			conditions = append(conditions, fmt.Sprintf("(%s.cicd_scope_id IN ? AND %s.environment IN ?)", alias, alias))
+			params = append(params, group.ScopeIds, environments)
+		case len(group.ScopeIds) > 0:
+			conditions = append(conditions, fmt.Sprintf("(%s.cicd_scope_id NOT IN ? AND %s.environment IN ?)", alias, alias))
+			params = append(params, group.ScopeIds, environments)
+		default:
+			conditions = append(conditions, alias+".environment IN ?")
+			params = append(params, environments)
+		}
+	}
+	return dal.Where("("+strings.Join(conditions, " OR ")+")", params...)
+}
+
+// String returns the definitions as json, the overridden ones are keyed by their scope ids
+func (s *ScopeDefinitions) String() string {
+	if len(s.scopes) == 0 {
+		return s.Default.String()
+	}
+	scopes := make(map[string]json.RawMessage, len(s.scopes))
+	for scopeId, definition := range s.scopes {
+		scopes[scopeId] = json.RawMessage(definition.String())
+	}
+	bytes, err := json.Marshal(map[string]interface{}{
+		"default": json.RawMessage(s.Default.String()),
+		"scopes":  scopes,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// Version returns a short hash of the definitions, metrics calculated by different definitions are not comparable
+func (s *ScopeDefinitions) Version() string {
+	if len(s.scopes) == 0 {
+		return s.Default.Version()
+	}
+	sum := sha256.Sum256([]byte(s.String()))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func (s *ScopeDefinitions) scopeIds() []string {
+	scopeIds := make([]string, 0, len(s.scopes))
+	for scopeId := range s.scopes {
+		scopeIds = append(scopeIds, scopeId)
+	}
+	sort.Strings(scopeIds)
+	return scopeIds
+}
+
+// BuildScopeDefinitions creates the definitions of the project and the scopes overridden by the options
+func BuildScopeDefinitions(op *DoraOptions) (*ScopeDefinitions, errors.Error) {
+	defaultDefinition, err := NewScopeDefinition("", op.DoraDefinition)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make([]*ScopeDefinition, 0, len(op.ScopeDoraDefinitions))
+	for scopeId, scopeDefinition := range op.ScopeDoraDefinitions {
+		definition, err := NewScopeDefinition(scopeId, scopeDefinition)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid dora definition of %s", scopeId))
+		}
+		scopes = append(scopes, definition)
+	}
+	return NewScopeDefinitions(defaultDefinition, scopes...), nil
+}
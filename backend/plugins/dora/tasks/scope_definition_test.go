@@ -0,0 +1,81 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildScopeDefinitions(t *testing.T) {
+	op := &DoraOptions{
+		ProjectName:    "project1",
+		DoraDefinition: DoraDefinition{HotfixBranchPattern: "^hotfix/"},
+		ScopeDoraDefinitions: map[string]DoraDefinition{
+			"github:GithubRepo:1:1": {
+				ProductionEnvironments: []string{"PRODUCTION", "STAGING"},
+				IncidentAttribution:    INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT,
+				HotfixBranchPattern:    "^fix/",
+			},
+		},
+	}
+	definitions, err := BuildScopeDefinitions(op)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	// the definition of the project is overridden for repo1
+	definition := definitions.Get("github:GithubRepo:1:1")
+	assert.Equal(t, "github:GithubRepo:1:1", definition.ScopeId)
+	assert.Equal(t, []string{"PRODUCTION", "STAGING"}, definition.GetProductionEnvironments())
+	assert.Equal(t, INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT, definition.GetIncidentAttribution())
+	assert.True(t, definition.HotfixBranchRegex.MatchString("fix/login"))
+	// the others use the definition of the project
+	assert.Equal(t, definitions.Default, definitions.Get("github:GithubRepo:1:2"))
+	assert.Equal(t, "", definitions.Default.ScopeId)
+	assert.True(t, definitions.Default.HotfixBranchRegex.MatchString("hotfix/login"))
+
+	groups := definitions.Groups()
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, []string{"github:GithubRepo:1:1"}, groups[0].ScopeIds)
+		assert.False(t, groups[0].Others)
+		assert.Equal(t, definitions.Default, groups[1].Definition)
+		assert.True(t, groups[1].Others)
+	}
+	assert.NotEqual(t, definitions.Default.Version(), definitions.Version())
+	assert.Contains(t, definitions.String(), `"github:GithubRepo:1:1"`)
+
+	// projects without overrides are identified by the definition of the project as before
+	op.ScopeDoraDefinitions = nil
+	definitions, err = BuildScopeDefinitions(op)
+	assert.Nil(t, err)
+	assert.Equal(t, op.DoraDefinition.Version(), definitions.Version())
+	assert.Equal(t, op.DoraDefinition.String(), definitions.String())
+}
+
+func TestBuildScopeDefinitionsWithInvalidDefinition(t *testing.T) {
+	_, err := BuildScopeDefinitions(&DoraOptions{ScopeDoraDefinitions: map[string]DoraDefinition{
+		"github:GithubRepo:1:1": {IncidentAttribution: "nextDeployment"},
+	}})
+	assert.NotNil(t, err)
+	_, err = BuildScopeDefinitions(&DoraOptions{ScopeDoraDefinitions: map[string]DoraDefinition{
+		"github:GithubRepo:1:1": {HotfixLabelPattern: "("},
+	}})
+	assert.NotNil(t, err)
+}
@@ -18,24 +18,104 @@ limitations under the License.
 package tasks
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 )
 
+const (
+	// INCIDENT_ATTRIBUTION_PREVIOUS_DEPLOYMENT attributes an incident to the last deployment finished before it
+	INCIDENT_ATTRIBUTION_PREVIOUS_DEPLOYMENT = "previousDeployment"
+	// INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT attributes an incident to the first deployment finished after it,
+	// which is supposed to contain the fix
+	INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT = "fixDeployment"
+)
+
 type DoraApiParams struct {
 	ProjectName string
 }
 
 type DoraOptions struct {
-	Tasks       []string `json:"tasks,omitempty"`
-	Since       string
-	ProjectName string  `json:"projectName"`
-	ScopeId     *string `json:"scopeId,omitempty"`
+	Tasks          []string `json:"tasks,omitempty"`
+	Since          string
+	ProjectName    string  `json:"projectName"`
+	ScopeId        *string `json:"scopeId,omitempty"`
+	DoraDefinition `mapstructure:",squash"`
+	// ScopeDoraDefinitions override the DoraDefinition of the project for repos and cicd_scopes keyed by their domain ids
+	ScopeDoraDefinitions map[string]DoraDefinition `json:"scopeDoraDefinitions,omitempty" mapstructure:"scopeDoraDefinitions,omitempty"`
+}
+
+// DoraDefinition decides how the DORA metrics are calculated, the default values follow the DORA team's definitions.
+// It is recorded alongside each project_pr_metrics row
+type DoraDefinition struct {
+	// ProductionEnvironments are environments of deployments counting as production, PRODUCTION if empty
+	ProductionEnvironments []string `json:"productionEnvironments,omitempty"`
+	// IncidentAttribution is either previousDeployment (default) or fixDeployment
+	IncidentAttribution string `json:"incidentAttribution,omitempty"`
+	// IncidentAttributionWindowHours ignores deployments finished more than N hours away from the incident, 0 means unlimited
+	IncidentAttributionWindowHours int `json:"incidentAttributionWindowHours,omitempty"`
+	// HotfixBranchPattern is a regex matching head branches of pull requests doing rework/hotfix
+	HotfixBranchPattern string `json:"hotfixBranchPattern,omitempty"`
+	// HotfixLabelPattern is a regex matching labels of pull requests doing rework/hotfix
+	HotfixLabelPattern string `json:"hotfixLabelPattern,omitempty"`
+}
+
+// GetProductionEnvironments returns environments counting as production
+func (d *DoraDefinition) GetProductionEnvironments() []string {
+	if len(d.ProductionEnvironments) == 0 {
+		return []string{devops.PRODUCTION}
+	}
+	return d.ProductionEnvironments
+}
+
+// GetIncidentAttribution returns the strategy of attributing incidents to deployments
+func (d *DoraDefinition) GetIncidentAttribution() string {
+	if d.IncidentAttribution == "" {
+		return INCIDENT_ATTRIBUTION_PREVIOUS_DEPLOYMENT
+	}
+	return d.IncidentAttribution
+}
+
+// String returns the definition with default values filled in as json
+func (d DoraDefinition) String() string {
+	d.ProductionEnvironments = d.GetProductionEnvironments()
+	d.IncidentAttribution = d.GetIncidentAttribution()
+	bytes, err := json.Marshal(d)
+	if err != nil {
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// Version returns a short hash of the definition, metrics calculated by different definitions are not comparable
+func (d DoraDefinition) Version() string {
+	sum := sha256.Sum256([]byte(d.String()))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Validate checks the attribution strategy and window of the definition
+func (d *DoraDefinition) Validate() errors.Error {
+	switch d.GetIncidentAttribution() {
+	case INCIDENT_ATTRIBUTION_PREVIOUS_DEPLOYMENT, INCIDENT_ATTRIBUTION_FIX_DEPLOYMENT:
+	default:
+		return errors.BadInput.New(fmt.Sprintf("unknown incidentAttribution %s", d.IncidentAttribution))
+	}
+	if d.IncidentAttributionWindowHours < 0 {
+		return errors.BadInput.New("incidentAttributionWindowHours must not be negative")
+	}
+	return nil
 }
 
 type DoraTaskData struct {
 	Options                         *DoraOptions
 	DisableIssueToIncidentGenerator bool
+	// Definitions are the DoraDefinition of the project's repos and cicd_scopes, overridden by ScopeDoraDefinitions
+	Definitions *ScopeDefinitions
 }
 
 func DecodeAndValidateTaskOptions(options map[string]interface{}) (*DoraOptions, errors.Error) {
@@ -44,6 +124,18 @@ func DecodeAndValidateTaskOptions(options map[string]interface{}) (*DoraOptions,
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error decoding DORA task options")
 	}
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &op, nil
 }
+
+// NewDoraTaskData creates the task data with the definitions of the project and the scopes overridden by the options
+func NewDoraTaskData(op *DoraOptions) (*DoraTaskData, errors.Error) {
+	definitions, err := BuildScopeDefinitions(op)
+	if err != nil {
+		return nil, err
+	}
+	return &DoraTaskData{Options: op, Definitions: definitions}, nil
+}
@@ -90,6 +90,10 @@ func (p IssueTrace) SubTaskMetas() []plugin.SubTaskMeta {
 		tasks.ConvertIssueStatusHistoryMeta,
 		// issue_assignee_history
 		tasks.ConvertIssueAssigneeHistoryMeta,
+		// issue_status_times and issue_lifecycles
+		tasks.ConvertIssueLifecycleMeta,
+		// board_cumulative_flows
+		tasks.SnapshotBoardCumulativeFlowMeta,
 	}
 }
 
@@ -137,6 +141,7 @@ func (p IssueTrace) RootPkgPath() string {
 func (p IssueTrace) MigrationScripts() []plugin.MigrationScript {
 	return []plugin.MigrationScript{
 		&migrationscripts.NewIssueTable{},
+		&migrationscripts.AddIssueLifecycleTables{},
 	}
 }
 
@@ -148,6 +153,9 @@ func (p IssueTrace) GetTablesInfo() []dal.Tabler {
 	return []dal.Tabler{
 		&models.IssueAssigneeHistory{},
 		&models.IssueStatusHistory{},
+		&models.IssueStatusTime{},
+		&models.IssueLifecycle{},
+		&models.BoardCumulativeFlow{},
 	}
 }
 
@@ -165,12 +173,15 @@ func (p IssueTrace) MakeMetricPluginPipelinePlanV200(projectName string, options
 			{
 				Plugin: "issue_trace",
 				Options: map[string]interface{}{
-					"projectName": projectName,
-					"scopeIds":    op.ScopeIds,
+					"projectName":      projectName,
+					"scopeIds":         op.ScopeIds,
+					"boardFlowConfigs": op.BoardFlowConfigs,
 				},
 				Subtasks: []string{
 					"ConvertIssueStatusHistory",
 					"ConvertIssueAssigneeHistory",
+					"ConvertIssueLifecycle",
+					"SnapshotBoardCumulativeFlow",
 				},
 			},
 		},
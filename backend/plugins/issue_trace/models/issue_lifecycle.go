@@ -0,0 +1,79 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// IssueStatusTime is the total time an issue spent in an (original) status
+// handled by ConvertIssueLifecycle task
+type IssueStatusTime struct {
+	common.NoPKModel
+	IssueId        string `gorm:"primaryKey;type:varchar(255)"`
+	OriginalStatus string `gorm:"primaryKey;type:varchar(255)"`
+	Status         string `gorm:"type:varchar(100)"`
+	Minutes        int64
+	// EnterCount is how many times the issue entered the status
+	EnterCount int
+}
+
+func (IssueStatusTime) TableName() string {
+	return "issue_status_times"
+}
+
+// IssueLifecycle summarizes the flow of an issue on a board, active and waiting statuses are configurable per board
+// handled by ConvertIssueLifecycle task
+type IssueLifecycle struct {
+	common.NoPKModel
+	BoardId        string `gorm:"primaryKey;type:varchar(255)"`
+	IssueId        string `gorm:"primaryKey;type:varchar(255)"`
+	ActiveMinutes  int64
+	WaitingMinutes int64
+	// FlowEfficiency is ActiveMinutes / (ActiveMinutes + WaitingMinutes), nil if both are 0
+	FlowEfficiency *float64
+	// ReopenCount is how many times the issue moved out of DONE
+	ReopenCount int
+	// HandoffCount is how many times the assignee changed
+	HandoffCount  int
+	FirstActiveAt *time.Time
+	LastDoneAt    *time.Time
+	IsDone        bool
+}
+
+func (IssueLifecycle) TableName() string {
+	return "issue_lifecycles"
+}
+
+// BoardCumulativeFlow is the number of issues of a board in an (original) status at the end of a day (UTC),
+// statuses without any issue are omitted
+// handled by SnapshotBoardCumulativeFlow task
+type BoardCumulativeFlow struct {
+	common.NoPKModel
+	BoardId        string    `gorm:"primaryKey;type:varchar(255)"`
+	Date           time.Time `gorm:"primaryKey;type:date"`
+	OriginalStatus string    `gorm:"primaryKey;type:varchar(255)"`
+	Status         string    `gorm:"type:varchar(100)"`
+	IssueCount     int
+}
+
+func (BoardCumulativeFlow) TableName() string {
+	return "board_cumulative_flows"
+}
@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+type AddIssueLifecycleTables struct {
+}
+
+func (*AddIssueLifecycleTables) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&IssueStatusTime20261017{},
+		&IssueLifecycle20261017{},
+		&BoardCumulativeFlow20261017{},
+	)
+}
+
+func (*AddIssueLifecycleTables) Version() uint64 {
+	return 20261017000001
+}
+
+func (*AddIssueLifecycleTables) Name() string {
+	return "add issue_status_times, issue_lifecycles and board_cumulative_flows"
+}
+
+type IssueStatusTime20261017 struct {
+	archived.NoPKModel
+	IssueId        string `gorm:"primaryKey;type:varchar(255)"`
+	OriginalStatus string `gorm:"primaryKey;type:varchar(255)"`
+	Status         string `gorm:"type:varchar(100)"`
+	Minutes        int64
+	EnterCount     int
+}
+
+func (IssueStatusTime20261017) TableName() string {
+	return "issue_status_times"
+}
+
+type IssueLifecycle20261017 struct {
+	archived.NoPKModel
+	BoardId        string `gorm:"primaryKey;type:varchar(255)"`
+	IssueId        string `gorm:"primaryKey;type:varchar(255)"`
+	ActiveMinutes  int64
+	WaitingMinutes int64
+	FlowEfficiency *float64
+	ReopenCount    int
+	HandoffCount   int
+	FirstActiveAt  *time.Time
+	LastDoneAt     *time.Time
+	IsDone         bool
+}
+
+func (IssueLifecycle20261017) TableName() string {
+	return "issue_lifecycles"
+}
+
+type BoardCumulativeFlow20261017 struct {
+	archived.NoPKModel
+	BoardId        string    `gorm:"primaryKey;type:varchar(255)"`
+	Date           time.Time `gorm:"primaryKey;type:date"`
+	OriginalStatus string    `gorm:"primaryKey;type:varchar(255)"`
+	Status         string    `gorm:"type:varchar(100)"`
+	IssueCount     int
+}
+
+func (BoardCumulativeFlow20261017) TableName() string {
+	return "board_cumulative_flows"
+}
@@ -0,0 +1,317 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/issue_trace/models"
+	"github.com/apache/incubator-devlake/plugins/issue_trace/utils"
+)
+
+var ConvertIssueLifecycleMeta = plugin.SubTaskMeta{
+	Name:             "ConvertIssueLifecycle",
+	EntryPoint:       ConvertIssueLifecycle,
+	EnabledByDefault: true,
+	Description:      "Calculate time in status, flow efficiency, reopenings and hand-offs of issues from issue status and assignee history",
+}
+
+func ConvertIssueLifecycle(taskCtx plugin.SubTaskContext) errors.Error {
+	logger := taskCtx.GetLogger()
+	options := taskCtx.GetData().(*TaskData)
+	db := taskCtx.GetDal()
+
+	for _, boardId := range options.ScopeIds {
+		if ctxErr := utils.CheckCancel(taskCtx); ctxErr != nil {
+			return ctxErr
+		}
+		logger.Info("calculate issue lifecycles, board %s", boardId)
+		statuses, err := loadBoardStatusHistory(db, boardId)
+		if err != nil {
+			return err
+		}
+		var assignees []models.IssueAssigneeHistory
+		err = db.All(&assignees,
+			dal.Select("issue_assignee_history.*"),
+			dal.From("issue_assignee_history"),
+			dal.Join("INNER JOIN board_issues ON board_issues.issue_id = issue_assignee_history.issue_id"),
+			dal.Where("board_issues.board_id = ?", boardId),
+			dal.Orderby("issue_assignee_history.issue_id, issue_assignee_history.start_date"),
+		)
+		if err != nil {
+			return err
+		}
+		assigneesByIssue := make(map[string][]models.IssueAssigneeHistory)
+		for _, a := range assignees {
+			assigneesByIssue[a.IssueId] = append(assigneesByIssue[a.IssueId], a)
+		}
+
+		err = db.Delete(&models.IssueLifecycle{}, dal.Where("board_id = ?", boardId))
+		if err != nil {
+			return err
+		}
+		err = db.Delete(&models.IssueStatusTime{}, dal.Where("issue_id IN (SELECT issue_id FROM board_issues WHERE board_id = ?)", boardId))
+		if err != nil {
+			return err
+		}
+		divider := helper.NewBatchSaveDivider(taskCtx, utils.BATCH_SIZE, "", "")
+		lifecycleInserter, err := divider.ForType(reflect.TypeOf(&models.IssueLifecycle{}))
+		if err != nil {
+			return err
+		}
+		statusTimeInserter, err := divider.ForType(reflect.TypeOf(&models.IssueStatusTime{}))
+		if err != nil {
+			return err
+		}
+		flowConfig := options.Options.BoardFlowConfigs[boardId]
+		for start := 0; start < len(statuses); {
+			end := start + 1
+			for end < len(statuses) && statuses[end].IssueId == statuses[start].IssueId {
+				end++
+			}
+			issueId := statuses[start].IssueId
+			lifecycle, statusTimes := buildIssueLifecycle(boardId, statuses[start:end], assigneesByIssue[issueId], flowConfig)
+			err = lifecycleInserter.Add(lifecycle)
+			if err != nil {
+				return err
+			}
+			for _, statusTime := range statusTimes {
+				err = statusTimeInserter.Add(statusTime)
+				if err != nil {
+					return err
+				}
+			}
+			start = end
+		}
+		err = divider.Close()
+		if err != nil {
+			return err
+		}
+	}
+	logger.Info("issue lifecycles calculated successfully")
+	return nil
+}
+
+// loadBoardStatusHistory loads the status history of issues of the board ordered by issue and start date
+func loadBoardStatusHistory(db dal.Dal, boardId string) ([]models.IssueStatusHistory, errors.Error) {
+	var statuses []models.IssueStatusHistory
+	err := db.All(&statuses,
+		dal.Select("issue_status_history.*"),
+		dal.From("issue_status_history"),
+		dal.Join("INNER JOIN board_issues ON board_issues.issue_id = issue_status_history.issue_id"),
+		dal.Where("board_issues.board_id = ?", boardId),
+		dal.Orderby("issue_status_history.issue_id, issue_status_history.start_date"),
+	)
+	return statuses, err
+}
+
+// buildIssueLifecycle summarizes the status history (ordered by start date) and assignee history of an issue
+func buildIssueLifecycle(
+	boardId string,
+	statuses []models.IssueStatusHistory,
+	assignees []models.IssueAssigneeHistory,
+	flowConfig *BoardFlowConfig,
+) (*models.IssueLifecycle, []*models.IssueStatusTime) {
+	lifecycle := &models.IssueLifecycle{
+		BoardId: boardId,
+		IssueId: statuses[0].IssueId,
+	}
+	var statusTimes []*models.IssueStatusTime
+	statusTimeMap := make(map[string]*models.IssueStatusTime)
+	prevStatus := ""
+	for i := range statuses {
+		history := &statuses[i]
+		var minutes int64
+		if history.EndDate != nil && history.EndDate.After(history.StartDate) {
+			minutes = int64(history.EndDate.Sub(history.StartDate).Minutes())
+		}
+		statusTime, ok := statusTimeMap[history.OriginalStatus]
+		if !ok {
+			statusTime = &models.IssueStatusTime{
+				IssueId:        history.IssueId,
+				OriginalStatus: history.OriginalStatus,
+				Status:         history.Status,
+			}
+			statusTimeMap[history.OriginalStatus] = statusTime
+			statusTimes = append(statusTimes, statusTime)
+		}
+		statusTime.Minutes += minutes
+		statusTime.EnterCount++
+
+		if flowConfig.isActive(history) {
+			if lifecycle.FirstActiveAt == nil {
+				lifecycle.FirstActiveAt = &history.StartDate
+			}
+			lifecycle.ActiveMinutes += minutes
+		} else if flowConfig.isWaiting(history, lifecycle.FirstActiveAt != nil) {
+			lifecycle.WaitingMinutes += minutes
+		}
+
+		if history.Status == ticket.DONE && prevStatus != ticket.DONE {
+			lifecycle.LastDoneAt = &history.StartDate
+		}
+		if i > 0 && prevStatus == ticket.DONE && history.Status != ticket.DONE {
+			lifecycle.ReopenCount++
+		}
+		prevStatus = history.Status
+	}
+	lifecycle.IsDone = prevStatus == ticket.DONE
+	if total := lifecycle.ActiveMinutes + lifecycle.WaitingMinutes; total > 0 {
+		efficiency := float64(lifecycle.ActiveMinutes) / float64(total)
+		lifecycle.FlowEfficiency = &efficiency
+	}
+	for i := 1; i < len(assignees); i++ {
+		if assignees[i].Assignee != assignees[i-1].Assignee {
+			lifecycle.HandoffCount++
+		}
+	}
+	return lifecycle, statusTimes
+}
+
+func (c *BoardFlowConfig) isActive(history *models.IssueStatusHistory) bool {
+	if c != nil && len(c.ActiveStatuses) > 0 {
+		return utils.StringContains(c.ActiveStatuses, history.OriginalStatus)
+	}
+	return history.Status == ticket.IN_PROGRESS
+}
+
+func (c *BoardFlowConfig) isWaiting(history *models.IssueStatusHistory, becameActive bool) bool {
+	if c != nil && len(c.WaitingStatuses) > 0 {
+		return utils.StringContains(c.WaitingStatuses, history.OriginalStatus)
+	}
+	return becameActive && history.Status != ticket.DONE
+}
+
+var SnapshotBoardCumulativeFlowMeta = plugin.SubTaskMeta{
+	Name:             "SnapshotBoardCumulativeFlow",
+	EntryPoint:       SnapshotBoardCumulativeFlow,
+	EnabledByDefault: true,
+	Description:      "Snapshot daily number of issues by status of boards for cumulative flow diagrams",
+}
+
+func SnapshotBoardCumulativeFlow(taskCtx plugin.SubTaskContext) errors.Error {
+	logger := taskCtx.GetLogger()
+	options := taskCtx.GetData().(*TaskData)
+	db := taskCtx.GetDal()
+	now := time.Now()
+
+	for _, boardId := range options.ScopeIds {
+		if ctxErr := utils.CheckCancel(taskCtx); ctxErr != nil {
+			return ctxErr
+		}
+		logger.Info("snapshot cumulative flow, board %s", boardId)
+		statuses, err := loadBoardStatusHistory(db, boardId)
+		if err != nil {
+			return err
+		}
+		err = db.Delete(&models.BoardCumulativeFlow{}, dal.Where("board_id = ?", boardId))
+		if err != nil {
+			return err
+		}
+		divider := helper.NewBatchSaveDivider(taskCtx, utils.BATCH_SIZE, "", "")
+		inserter, err := divider.ForType(reflect.TypeOf(&models.BoardCumulativeFlow{}))
+		if err != nil {
+			return err
+		}
+		for _, flow := range buildCumulativeFlows(boardId, statuses, now) {
+			err = inserter.Add(flow)
+			if err != nil {
+				return err
+			}
+		}
+		err = divider.Close()
+		if err != nil {
+			return err
+		}
+	}
+	logger.Info("cumulative flows snapshot successfully")
+	return nil
+}
+
+// buildCumulativeFlows counts issues by status at the end of each day (UTC) from the first status change until
+// the day of now, current statuses are counted till now
+func buildCumulativeFlows(boardId string, statuses []models.IssueStatusHistory, now time.Time) []*models.BoardCumulativeFlow {
+	today := truncateToDay(now)
+	deltas := make(map[time.Time]map[string]int)
+	addDelta := func(day time.Time, originalStatus string, delta int) {
+		if deltas[day] == nil {
+			deltas[day] = make(map[string]int)
+		}
+		deltas[day][originalStatus] += delta
+	}
+	statusOf := make(map[string]string)
+	var firstDay *time.Time
+	for i := range statuses {
+		history := &statuses[i]
+		startDay := truncateToDay(history.StartDate)
+		// an issue is counted on the days whose end is within [StartDate, EndDate]
+		endDay := today.AddDate(0, 0, 1)
+		if !history.IsCurrentStatus && history.EndDate != nil {
+			endDay = truncateToDay(*history.EndDate)
+		}
+		if !endDay.After(startDay) {
+			continue
+		}
+		addDelta(startDay, history.OriginalStatus, 1)
+		addDelta(endDay, history.OriginalStatus, -1)
+		statusOf[history.OriginalStatus] = history.Status
+		if firstDay == nil || startDay.Before(*firstDay) {
+			firstDay = &startDay
+		}
+	}
+	var flows []*models.BoardCumulativeFlow
+	if firstDay == nil {
+		return flows
+	}
+	counts := make(map[string]int)
+	var originalStatuses []string
+	for day := *firstDay; !day.After(today); day = day.AddDate(0, 0, 1) {
+		for originalStatus, delta := range deltas[day] {
+			if _, ok := counts[originalStatus]; !ok {
+				originalStatuses = append(originalStatuses, originalStatus)
+				sort.Strings(originalStatuses)
+			}
+			counts[originalStatus] += delta
+		}
+		for _, originalStatus := range originalStatuses {
+			if counts[originalStatus] <= 0 {
+				continue
+			}
+			flows = append(flows, &models.BoardCumulativeFlow{
+				BoardId:        boardId,
+				Date:           day,
+				OriginalStatus: originalStatus,
+				Status:         statusOf[originalStatus],
+				IssueCount:     counts[originalStatus],
+			})
+		}
+	}
+	return flows
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,103 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/issue_trace/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func statusHistory(originalStatus, status string, start, end time.Time, isCurrent bool) models.IssueStatusHistory {
+	return models.IssueStatusHistory{
+		IssueId:         "issue1",
+		OriginalStatus:  originalStatus,
+		Status:          status,
+		StartDate:       start,
+		EndDate:         &end,
+		IsCurrentStatus: isCurrent,
+	}
+}
+
+func TestBuildIssueLifecycle(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	statuses := []models.IssueStatusHistory{
+		statusHistory("Open", ticket.TODO, day(1), day(2), false),
+		statusHistory("In Progress", ticket.IN_PROGRESS, day(2), day(4), false),
+		statusHistory("Review", ticket.IN_PROGRESS, day(4), day(5), false),
+		statusHistory("Done", ticket.DONE, day(5), day(6), false),
+		statusHistory("In Progress", ticket.IN_PROGRESS, day(6), day(7), false),
+		statusHistory("Done", ticket.DONE, day(7), day(9), true),
+	}
+	assignees := []models.IssueAssigneeHistory{
+		{IssueId: "issue1", Assignee: "alice", StartDate: day(1)},
+		{IssueId: "issue1", Assignee: "bob", StartDate: day(4)},
+		{IssueId: "issue1", Assignee: "bob", StartDate: day(6)},
+		{IssueId: "issue1", Assignee: "alice", StartDate: day(7)},
+	}
+
+	lifecycle, statusTimes := buildIssueLifecycle("board1", statuses, assignees, nil)
+	assert.Equal(t, int64(4*24*60), lifecycle.ActiveMinutes)
+	assert.Equal(t, int64(0), lifecycle.WaitingMinutes)
+	assert.Equal(t, 1.0, *lifecycle.FlowEfficiency)
+	assert.Equal(t, 1, lifecycle.ReopenCount)
+	assert.Equal(t, 2, lifecycle.HandoffCount)
+	assert.Equal(t, day(2), *lifecycle.FirstActiveAt)
+	assert.Equal(t, day(7), *lifecycle.LastDoneAt)
+	assert.True(t, lifecycle.IsDone)
+	assert.Len(t, statusTimes, 4)
+	assert.Equal(t, "In Progress", statusTimes[1].OriginalStatus)
+	assert.Equal(t, int64(3*24*60), statusTimes[1].Minutes)
+	assert.Equal(t, 2, statusTimes[1].EnterCount)
+
+	// review is waiting for the board
+	lifecycle, _ = buildIssueLifecycle("board1", statuses, assignees, &BoardFlowConfig{
+		ActiveStatuses:  []string{"In Progress"},
+		WaitingStatuses: []string{"Review"},
+	})
+	assert.Equal(t, int64(3*24*60), lifecycle.ActiveMinutes)
+	assert.Equal(t, int64(24*60), lifecycle.WaitingMinutes)
+	assert.Equal(t, 0.75, *lifecycle.FlowEfficiency)
+}
+
+func TestBuildCumulativeFlows(t *testing.T) {
+	at := func(d, h int) time.Time { return time.Date(2024, 1, d, h, 0, 0, 0, time.UTC) }
+	statuses := []models.IssueStatusHistory{
+		statusHistory("Open", ticket.TODO, at(1, 10), at(2, 9), false),
+		statusHistory("Done", ticket.DONE, at(2, 9), at(3, 12), true),
+		// less than a day, never seen at the end of a day
+		statusHistory("Open", ticket.TODO, at(2, 10), at(2, 11), false),
+		statusHistory("In Progress", ticket.IN_PROGRESS, at(2, 11), at(3, 12), true),
+	}
+	flows := buildCumulativeFlows("board1", statuses, at(3, 12))
+	var actual []string
+	for _, flow := range flows {
+		actual = append(actual, fmt.Sprintf("%s %s %s %d", flow.Date.Format("01-02"), flow.OriginalStatus, flow.Status, flow.IssueCount))
+	}
+	assert.Equal(t, []string{
+		"01-01 Open TODO 1",
+		"01-02 Done DONE 1",
+		"01-02 In Progress IN_PROGRESS 1",
+		"01-03 Done DONE 1",
+		"01-03 In Progress IN_PROGRESS 1",
+	}, actual)
+}
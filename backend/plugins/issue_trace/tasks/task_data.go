@@ -22,6 +22,15 @@ type Options struct {
 	Plugin      string   `json:"plugin"`   // jira
 	ScopeIds    []string `json:"scopeIds"` // 68
 	ProjectName string   `json:"projectName"`
+	// BoardFlowConfigs are the active and waiting statuses of boards for flow efficiency, keyed by board id
+	BoardFlowConfigs map[string]*BoardFlowConfig `json:"boardFlowConfigs"`
+}
+
+// BoardFlowConfig lists original statuses of a board, statuses with standard status IN_PROGRESS are active and
+// other statuses except DONE after the issue became active are waiting if not specified
+type BoardFlowConfig struct {
+	ActiveStatuses  []string `json:"activeStatuses"`
+	WaitingStatuses []string `json:"waitingStatuses"`
 }
 
 // TaskData converted parameter
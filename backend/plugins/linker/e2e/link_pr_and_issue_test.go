@@ -18,9 +18,9 @@ limitations under the License.
 package e2e
 
 import (
-	"regexp"
 	"testing"
 
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
@@ -34,28 +34,98 @@ func TestLinkPrToIssue(t *testing.T) {
 	dataflowTester := e2ehelper.NewDataFlowTester(t, "linker", plugin)
 
 	regexpStr := "#(\\d+)"
-	re, err := regexp.Compile(regexpStr)
+	taskData, err := tasks.NewLinkerTaskData(&tasks.LinkerOptions{
+		PrToIssueRegexp: regexpStr,
+		ProjectName:     "GitHub1",
+	})
 	if err != nil {
 		panic(err)
 	}
-	taskData := &tasks.LinkerTaskData{
-		Options: &tasks.LinkerOptions{
-			PrToIssueRegexp: regexpStr,
-			ProjectName:     "GitHub1",
+
+	importLinkerTables(dataflowTester)
+
+	dataflowTester.FlushTabler(&crossdomain.PullRequestIssue{})
+	dataflowTester.Subtask(tasks.LinkPrToIssueMeta, taskData)
+	verifyPullRequestIssues(dataflowTester, "./snapshot_tables/pull_request_issues.csv")
+}
+
+func TestLinkPrToIssueWithPatterns(t *testing.T) {
+	var plugin impl.Linker
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "linker", plugin)
+
+	taskData, err := tasks.NewLinkerTaskData(&tasks.LinkerOptions{
+		PrToIssuePatterns: []tasks.PrToIssuePattern{
+			{
+				Name:             "branch",
+				Regexp:           "(?i)gh-(\\d+)",
+				Sources:          []string{tasks.SOURCE_BRANCH},
+				IssueKeyTemplate: "$1",
+			},
+			{
+				Name:    "commit",
+				Regexp:  "#\\d+",
+				Sources: []string{tasks.SOURCE_COMMIT_MESSAGE},
+			},
 		},
-		PrToIssueRegexp: re,
+		ProjectName: "GitHub1",
+	})
+	if err != nil {
+		panic(err)
 	}
 
+	importLinkerTables(dataflowTester)
+
+	dataflowTester.FlushTabler(&crossdomain.PullRequestIssue{})
+	dataflowTester.Subtask(tasks.LinkPrToIssueMeta, taskData)
+	verifyPullRequestIssues(dataflowTester, "./snapshot_tables/pull_request_issues_patterns.csv")
+}
+
+func TestLinkPrToIssueIncrementally(t *testing.T) {
+	var plugin impl.Linker
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "linker", plugin)
+
+	taskData, err := tasks.NewLinkerTaskData(&tasks.LinkerOptions{
+		PrToIssueRegexp: "#(\\d+)",
+		ProjectName:     "GitHub1",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	importLinkerTables(dataflowTester)
+
+	// the first run links all pull requests of the project
+	dataflowTester.FlushTabler(&models.SubtaskState{})
+	dataflowTester.FlushTabler(&crossdomain.PullRequestIssue{})
+	dataflowTester.IncrementalSubtask(tasks.LinkPrToIssueMeta, taskData)
+	verifyPullRequestIssues(dataflowTester, "./snapshot_tables/pull_request_issues.csv")
+
+	// an issue mentioned by a pull request is added to the board after the first run, the pull request is not
+	// updated but should be linked to it by the second run
+	err = dataflowTester.Dal.Create(&ticket.BoardIssue{
+		BoardId: "github:GithubRepo:1:384111310",
+		IssueId: "github:GithubIssue:2:1237324696",
+	})
+	if err != nil {
+		panic(err)
+	}
+	dataflowTester.IncrementalSubtask(tasks.LinkPrToIssueMeta, taskData)
+	verifyPullRequestIssues(dataflowTester, "./snapshot_tables/pull_request_issues_incremental.csv")
+}
+
+func importLinkerTables(dataflowTester *e2ehelper.DataFlowTester) {
 	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/issues.csv", &ticket.Issue{})
 	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/pull_requests.csv", &code.PullRequest{})
+	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/pull_request_commits.csv", &code.PullRequestCommit{})
+	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/commits.csv", &code.Commit{})
 	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/project_mapping.csv", &crossdomain.ProjectMapping{})
 	dataflowTester.ImportCsvIntoTabler("./snapshot_tables/board_issues.csv", &ticket.BoardIssue{})
+}
 
-	dataflowTester.FlushTabler(&crossdomain.PullRequestIssue{})
-	dataflowTester.Subtask(tasks.LinkPrToIssueMeta, taskData)
+func verifyPullRequestIssues(dataflowTester *e2ehelper.DataFlowTester, csvRelPath string) {
 	dataflowTester.VerifyTable(
 		crossdomain.PullRequestIssue{},
-		"./snapshot_tables/pull_request_issues.csv",
+		csvRelPath,
 		[]string{
 			"pull_request_id",
 			"pull_request_key",
@@ -67,5 +137,4 @@ func TestLinkPrToIssue(t *testing.T) {
 			"_raw_data_remark",
 		},
 	)
-
 }
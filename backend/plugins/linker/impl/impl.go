@@ -19,7 +19,6 @@ package impl
 
 import (
 	"encoding/json"
-	"regexp"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
@@ -81,17 +80,7 @@ func (p Linker) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]i
 	if err != nil {
 		return nil, err
 	}
-	taskData := &tasks.LinkerTaskData{
-		Options: op,
-	}
-	if op.PrToIssueRegexp != "" {
-		re, err := regexp.Compile(op.PrToIssueRegexp)
-		if err != nil {
-			return taskData, errors.Convert(err)
-		}
-		taskData.PrToIssueRegexp = re
-	}
-	return taskData, nil
+	return tasks.NewLinkerTaskData(op)
 }
 
 // RootPkgPath information lost when compiled as plugin(.so)
@@ -114,8 +103,9 @@ func (p Linker) MakeMetricPluginPipelinePlanV200(projectName string, options jso
 			{
 				Plugin: "linker",
 				Options: map[string]interface{}{
-					"projectName":     projectName,
-					"prToIssueRegexp": op.PrToIssueRegexp,
+					"projectName":       projectName,
+					"prToIssueRegexp":   op.PrToIssueRegexp,
+					"prToIssuePatterns": op.PrToIssuePatterns,
 				},
 				Subtasks: []string{
 					"LinkPrToIssue",
@@ -14,17 +14,17 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
-
 package tasks
 
 import (
-	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
-	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
-	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
 	"strings"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 )
@@ -33,12 +33,16 @@ var LinkPrToIssueMeta = plugin.SubTaskMeta{
 	Name:             "LinkPrToIssue",
 	EntryPoint:       LinkPrToIssue,
 	EnabledByDefault: true,
-	Description:      "Try to link pull requests to issues, according to pull requests' title and description",
-	DependencyTables: []string{code.PullRequest{}.TableName(), ticket.Issue{}.TableName()},
+	Description:      "Try to link pull requests to issues, according to pull requests' title, description, branch and commit messages",
+	DependencyTables: []string{code.PullRequest{}.TableName(), ticket.Issue{}.TableName(), code.PullRequestCommit{}.TableName(), code.Commit{}.TableName()},
 	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE, plugin.DOMAIN_TYPE_TICKET, plugin.DOMAIN_TYPE_CROSS},
 	ProductTables:    []string{crossdomain.PullRequestIssue{}.TableName()},
 }
 
+type LinkerParams struct {
+	ProjectName string
+}
+
 func normalizeIssueKey(issueKey string) string {
 	issueKey = strings.ReplaceAll(issueKey, "#", "")
 	issueKey = strings.TrimSpace(issueKey)
@@ -51,28 +55,67 @@ func clearHistoryData(db dal.Dal, data *LinkerTaskData) errors.Error {
 		WHERE pull_request_id IN (
 			SELECT pr.id
 				FROM pull_requests pr
-					LEFT JOIN project_mapping pm
+					JOIN project_mapping pm
 					ON pm.table = 'repos'
 						AND pm.row_id = pr.base_repo_id
-						AND pm.project_name = ?
+				WHERE pm.project_name = ?
 	)
 `
 	return db.Exec(sql, data.Options.ProjectName)
 }
 
+// LinkPrToIssue links pull requests updated since the last run, and the ones which could be linked to issues
+// updated since then, to issues of the project. All pull requests of the project are relinked in full sync mode
+// or if the patterns changed
 func LinkPrToIssue(taskCtx plugin.SubTaskContext) errors.Error {
 	db := taskCtx.GetDal()
 	data := taskCtx.GetData().(*LinkerTaskData)
 
-	if err := clearHistoryData(db, data); err != nil {
+	stateManager, err := api.NewSubtaskStateManager(&api.SubtaskCommonArgs{
+		SubTaskContext: taskCtx,
+		Table:          code.PullRequest{}.TableName(),
+		Params:         LinkerParams{ProjectName: data.Options.ProjectName},
+		SubtaskConfig:  data.Options,
+	})
+	if err != nil {
+		return err
+	}
+	if !stateManager.IsIncremental() {
+		if err := clearHistoryData(db, data); err != nil {
+			return err
+		}
+	}
+
+	var projectIssueIds []string
+	if err := db.All(&projectIssueIds,
+		dal.From(ticket.BoardIssue{}),
+		dal.Select("board_issues.issue_id"),
+		dal.Join("LEFT JOIN project_mapping pm ON (pm.table = 'boards' AND pm.row_id = board_issues.board_id)"),
+		dal.Where("pm.project_name = ?", data.Options.ProjectName),
+	); err != nil {
 		return err
 	}
 
+	// pull requests not updated since the last run are relinked only if they mention keys of the new issues
+	since := stateManager.GetSince()
+	incremental := stateManager.IsIncremental() && since != nil
+	var newIssueKeys map[string]bool
+	if incremental {
+		newIssueKeys, err = loadNewIssueKeys(db, data.Options.ProjectName, *since)
+		if err != nil {
+			return err
+		}
+	}
+
 	var clauses = []dal.Clause{
+		dal.Select("pull_requests.*"),
 		dal.From(&code.PullRequest{}),
 		dal.Join("LEFT JOIN project_mapping pm ON (pm.table = 'repos' AND pm.row_id = pull_requests.base_repo_id)"),
 		dal.Where("pm.project_name = ?", data.Options.ProjectName),
 	}
+	if incremental && len(newIssueKeys) == 0 {
+		clauses = append(clauses, dal.Where("pull_requests.updated_at >= ?", since))
+	}
 	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return err
@@ -80,45 +123,48 @@ func LinkPrToIssue(taskCtx plugin.SubTaskContext) errors.Error {
 
 	defer cursor.Close()
 
-	var projectIssueIds []string
-	if err := db.All(&projectIssueIds,
-		dal.From(ticket.BoardIssue{}),
-		dal.Select("board_issues.issue_id"),
-		dal.Join("LEFT JOIN project_mapping pm ON (pm.table = 'boards' AND pm.row_id = board_issues.board_id)"),
-		dal.Where("pm.project_name = ?", data.Options.ProjectName),
-	); err != nil {
-		return err
-	}
-
 	enricher, err := api.NewDataEnricher(api.DataEnricherArgs[code.PullRequest]{
 		Ctx:   taskCtx,
 		Name:  code.PullRequest{}.TableName(),
 		Input: cursor,
 		Enrich: func(pullRequest *code.PullRequest) ([]interface{}, errors.Error) {
-
-			var issueKeys []string
-			for _, text := range []string{pullRequest.Title, pullRequest.Description} {
-				foundIssueKeys := data.PrToIssueRegexp.FindAllString(text, -1)
-				if len(foundIssueKeys) > 0 {
-					for _, issueKey := range foundIssueKeys {
-						issueKey = normalizeIssueKey(issueKey)
-						issueKeys = append(issueKeys, issueKey)
-					}
-					break
+			var commitMessages []string
+			if data.needsCommitMessages() {
+				err := db.Pluck("commits.message", &commitMessages,
+					dal.From(&code.PullRequestCommit{}),
+					dal.Join("JOIN commits ON commits.sha = pull_request_commits.commit_sha"),
+					dal.Where("pull_request_commits.pull_request_id = ?", pullRequest.Id),
+				)
+				if err != nil {
+					return nil, err
 				}
 			}
-			var issues []*ticket.Issue
-
-			var clauses = []dal.Clause{
-				dal.From(&ticket.Issue{}),
-				dal.Where("issues.id in ? AND issues.issue_key in ?", projectIssueIds, issueKeys),
+			if incremental {
+				if pullRequest.UpdatedAt.Before(*since) && !mentionsIssueKeys(data, pullRequest, commitMessages, newIssueKeys) {
+					return nil, nil
+				}
+				err := db.Delete(&crossdomain.PullRequestIssue{}, dal.Where("pull_request_id = ?", pullRequest.Id))
+				if err != nil {
+					return nil, err
+				}
 			}
 
-			if err := db.All(&issues, clauses...); err != nil {
-				return nil, err
-			}
-			if len(issues) == 0 {
-				return nil, nil
+			var issues []*ticket.Issue
+			for _, matcher := range data.matchers {
+				issueKeys := findIssueKeys(matcher, pullRequest, commitMessages)
+				if len(issueKeys) == 0 {
+					continue
+				}
+				var clauses = []dal.Clause{
+					dal.From(&ticket.Issue{}),
+					dal.Where("issues.id in ? AND issues.issue_key in ?", projectIssueIds, issueKeys),
+				}
+				if err := db.All(&issues, clauses...); err != nil {
+					return nil, err
+				}
+				if len(issues) > 0 {
+					break
+				}
 			}
 			var result []interface{}
 			for _, issue := range issues {
@@ -138,5 +184,67 @@ func LinkPrToIssue(taskCtx plugin.SubTaskContext) errors.Error {
 		return err
 	}
 
-	return enricher.Execute()
+	err = enricher.Execute()
+	if err != nil {
+		return err
+	}
+	return stateManager.Close()
+}
+
+// loadNewIssueKeys returns keys of the issues of the project which are updated or added to its boards since the
+// last run, in upper case
+func loadNewIssueKeys(db dal.Dal, projectName string, since time.Time) (map[string]bool, errors.Error) {
+	var issueKeys []string
+	err := db.Pluck("issues.issue_key", &issueKeys,
+		dal.From(&ticket.Issue{}),
+		dal.Join("JOIN board_issues ON board_issues.issue_id = issues.id"),
+		dal.Join("JOIN project_mapping pm ON (pm.table = 'boards' AND pm.row_id = board_issues.board_id)"),
+		dal.Where("pm.project_name = ? AND (issues.updated_at >= ? OR board_issues.updated_at >= ?)", projectName, since, since),
+	)
+	if err != nil {
+		return nil, err
+	}
+	newIssueKeys := make(map[string]bool, len(issueKeys))
+	for _, issueKey := range issueKeys {
+		newIssueKeys[strings.ToUpper(issueKey)] = true
+	}
+	return newIssueKeys, nil
+}
+
+// mentionsIssueKeys tells if any pattern finds any of the issue keys in the pull request, keys are compared in
+// upper case since databases like mysql compare them case-insensitively
+func mentionsIssueKeys(data *LinkerTaskData, pullRequest *code.PullRequest, commitMessages []string, issueKeys map[string]bool) bool {
+	for _, matcher := range data.matchers {
+		for _, issueKey := range findIssueKeys(matcher, pullRequest, commitMessages) {
+			if issueKeys[strings.ToUpper(issueKey)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findIssueKeys returns issue keys found in the first source of the pull request matching the pattern
+func findIssueKeys(matcher *prToIssueMatcher, pullRequest *code.PullRequest, commitMessages []string) []string {
+	for _, source := range matcher.Sources {
+		var texts []string
+		switch source {
+		case SOURCE_TITLE:
+			texts = []string{pullRequest.Title}
+		case SOURCE_DESCRIPTION:
+			texts = []string{pullRequest.Description}
+		case SOURCE_BRANCH:
+			texts = []string{pullRequest.HeadRef}
+		case SOURCE_COMMIT_MESSAGE:
+			texts = commitMessages
+		}
+		var issueKeys []string
+		for _, text := range texts {
+			issueKeys = append(issueKeys, matcher.issueKeys(text)...)
+		}
+		if len(issueKeys) > 0 {
+			return issueKeys
+		}
+	}
+	return nil
 }
@@ -14,23 +14,109 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
-
 package tasks
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/utils"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
-	"regexp"
 )
 
+const (
+	SOURCE_TITLE          = "title"
+	SOURCE_DESCRIPTION    = "description"
+	SOURCE_BRANCH         = "branch"
+	SOURCE_COMMIT_MESSAGE = "commitMessage"
+)
+
+var allSources = []string{SOURCE_TITLE, SOURCE_DESCRIPTION, SOURCE_BRANCH, SOURCE_COMMIT_MESSAGE}
+
 type LinkerOptions struct {
+	// PrToIssueRegexp is matched against title and description of pull requests, it is ignored if PrToIssuePatterns
+	// is not empty
 	PrToIssueRegexp string `json:"prToIssueRegexp"`
-	ProjectName     string `json:"projectName"`
+	// PrToIssuePatterns are tried in order, the first pattern linking the pull request to any issue wins
+	PrToIssuePatterns []PrToIssuePattern `json:"prToIssuePatterns"`
+	ProjectName       string             `json:"projectName"`
+}
+
+// PrToIssuePattern extracts issue keys from the Sources of pull requests, sources are scanned in order and the
+// first source with any match is used
+type PrToIssuePattern struct {
+	Name   string `json:"name"`
+	Regexp string `json:"regexp"`
+	// Sources could be title, description, branch and commitMessage, defaults to title and description
+	Sources []string `json:"sources"`
+	// IssueKeyTemplate normalizes matches to issue keys by expanding $1, ${name}, etc. with the submatches,
+	// e.g. "PROJ-$1" for "proj[-_](\d+)". The whole match with '#' removed is used if empty
+	IssueKeyTemplate string `json:"issueKeyTemplate"`
+	// UpperCase converts issue keys to upper case, e.g. keys found in branch names like "feature/proj-123"
+	UpperCase bool `json:"upperCase"`
+}
+
+type prToIssueMatcher struct {
+	*PrToIssuePattern
+	re *regexp.Regexp
+}
+
+// issueKeys returns the normalized issue keys found in text
+func (m *prToIssueMatcher) issueKeys(text string) []string {
+	var issueKeys []string
+	for _, submatches := range m.re.FindAllStringSubmatchIndex(text, -1) {
+		var issueKey string
+		if m.IssueKeyTemplate == "" {
+			issueKey = normalizeIssueKey(text[submatches[0]:submatches[1]])
+		} else {
+			issueKey = strings.TrimSpace(string(m.re.ExpandString(nil, m.IssueKeyTemplate, text, submatches)))
+		}
+		if m.UpperCase {
+			issueKey = strings.ToUpper(issueKey)
+		}
+		if issueKey != "" {
+			issueKeys = append(issueKeys, issueKey)
+		}
+	}
+	return issueKeys
 }
 
 type LinkerTaskData struct {
-	Options         *LinkerOptions
-	PrToIssueRegexp *regexp.Regexp
+	Options  *LinkerOptions
+	matchers []*prToIssueMatcher
+}
+
+// NewLinkerTaskData compiles the patterns of the options
+func NewLinkerTaskData(op *LinkerOptions) (*LinkerTaskData, errors.Error) {
+	patterns := op.PrToIssuePatterns
+	if len(patterns) == 0 && op.PrToIssueRegexp != "" {
+		patterns = []PrToIssuePattern{{Name: "default", Regexp: op.PrToIssueRegexp}}
+	}
+	taskData := &LinkerTaskData{Options: op}
+	for i := range patterns {
+		pattern := &patterns[i]
+		re, err := regexp.Compile(pattern.Regexp)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid regexp of pattern %s", pattern.Name))
+		}
+		if len(pattern.Sources) == 0 {
+			pattern.Sources = []string{SOURCE_TITLE, SOURCE_DESCRIPTION}
+		}
+		taskData.matchers = append(taskData.matchers, &prToIssueMatcher{PrToIssuePattern: pattern, re: re})
+	}
+	return taskData, nil
+}
+
+// needsCommitMessages tells if any pattern is matched against commit messages
+func (data *LinkerTaskData) needsCommitMessages() bool {
+	for _, m := range data.matchers {
+		if utils.StringsContains(m.Sources, SOURCE_COMMIT_MESSAGE) {
+			return true
+		}
+	}
+	return false
 }
 
 func DecodeAndValidateTaskOptions(options map[string]interface{}) (*LinkerOptions, errors.Error) {
@@ -39,5 +125,15 @@ func DecodeAndValidateTaskOptions(options map[string]interface{}) (*LinkerOption
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error decoding linker task options")
 	}
+	for _, pattern := range op.PrToIssuePatterns {
+		if pattern.Regexp == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("regexp of pattern %s is required", pattern.Name))
+		}
+		for _, source := range pattern.Sources {
+			if !utils.StringsContains(allSources, source) {
+				return nil, errors.BadInput.New(fmt.Sprintf("unknown source %s of pattern %s", source, pattern.Name))
+			}
+		}
+	}
 	return &op, nil
 }
@@ -0,0 +1,43 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrToIssueMatcher(t *testing.T) {
+	taskData, err := NewLinkerTaskData(&LinkerOptions{
+		PrToIssuePatterns: []PrToIssuePattern{
+			{Name: "branch", Regexp: `(?i)(proj)[-_](\d+)`, Sources: []string{SOURCE_BRANCH}, IssueKeyTemplate: "$1-$2", UpperCase: true},
+			{Name: "github", Regexp: `#\d+`},
+		},
+	})
+	assert.Nil(t, err)
+	assert.False(t, taskData.needsCommitMessages())
+
+	branch, github := taskData.matchers[0], taskData.matchers[1]
+	assert.Equal(t, []string{"PROJ-12", "PROJ-7"}, branch.issueKeys("feature/proj_12-and-Proj-7"))
+	assert.Equal(t, []string{"12", "34"}, github.issueKeys("fix #12 and #34"))
+	assert.Equal(t, []string{SOURCE_TITLE, SOURCE_DESCRIPTION}, github.Sources)
+
+	_, err = NewLinkerTaskData(&LinkerOptions{PrToIssueRegexp: "("})
+	assert.NotNil(t, err)
+}
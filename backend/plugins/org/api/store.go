@@ -18,11 +18,14 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
+
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
 	"reflect"
 )
 
@@ -34,6 +37,8 @@ type store interface {
 	findAllProjectMapping() ([]projectMapping, errors.Error)
 	deleteAll(i interface{}) errors.Error
 	save(items []interface{}) errors.Error
+	findUserAccountCandidates(status string, limit, offset int) ([]models.UserAccountCandidate, int64, errors.Error)
+	reviewUserAccountCandidates(reviews []candidateReview) errors.Error
 }
 
 type dbStore struct {
@@ -122,3 +127,74 @@ func (d *dbStore) save(items []interface{}) errors.Error {
 	d.driver.Close()
 	return nil
 }
+
+func (d *dbStore) findUserAccountCandidates(status string, limit, offset int) ([]models.UserAccountCandidate, int64, errors.Error) {
+	clauses := []dal.Clause{dal.From(&models.UserAccountCandidate{})}
+	if status != "" {
+		clauses = append(clauses, dal.Where("status = ?", status))
+	}
+	count, err := d.db.Count(clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clauses = append(clauses, dal.Orderby("account_id, score DESC"), dal.Limit(limit), dal.Offset(offset))
+	candidates := make([]models.UserAccountCandidate, 0)
+	err = d.db.All(&candidates, clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return candidates, count, nil
+}
+
+// reviewUserAccountCandidates links accepted candidates into user_accounts, and unlinks rejected ones accepted before.
+// Other pending candidates of an accepted account are dropped since an account belongs to one user only
+func (d *dbStore) reviewUserAccountCandidates(reviews []candidateReview) (err errors.Error) {
+	tx := d.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	for _, review := range reviews {
+		candidate := &models.UserAccountCandidate{}
+		err = tx.First(candidate, dal.Where("user_id = ? AND account_id = ?", review.UserId, review.AccountId))
+		if err != nil {
+			if tx.IsErrorNotFound(err) {
+				return errors.NotFound.New(fmt.Sprintf("candidate of user %s and account %s not found", review.UserId, review.AccountId))
+			}
+			return err
+		}
+		switch review.Status {
+		case models.CANDIDATE_ACCEPTED:
+			err = tx.CreateOrUpdate(&crossdomain.UserAccount{UserId: review.UserId, AccountId: review.AccountId})
+			if err != nil {
+				return err
+			}
+			err = tx.Delete(
+				&models.UserAccountCandidate{},
+				dal.Where("account_id = ? AND user_id != ? AND status = ?", review.AccountId, review.UserId, models.CANDIDATE_PENDING),
+			)
+		case models.CANDIDATE_REJECTED:
+			if candidate.Status == models.CANDIDATE_ACCEPTED {
+				err = tx.Delete(&crossdomain.UserAccount{}, dal.Where("user_id = ? AND account_id = ?", review.UserId, review.AccountId))
+			}
+		default:
+			return errors.BadInput.New(fmt.Sprintf("status must be %s or %s", models.CANDIDATE_ACCEPTED, models.CANDIDATE_REJECTED))
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.UpdateColumn(
+			&models.UserAccountCandidate{}, "status", review.Status,
+			dal.Where("user_id = ? AND account_id = ?", review.UserId, review.AccountId),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
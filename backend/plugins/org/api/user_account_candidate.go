@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
+)
+
+type candidateReview struct {
+	UserId    string `json:"userId" mapstructure:"userId"`
+	AccountId string `json:"accountId" mapstructure:"accountId"`
+	Status    string `json:"status" mapstructure:"status"`
+}
+
+type paginatedUserAccountCandidates struct {
+	Candidates []models.UserAccountCandidate `json:"candidates"`
+	Count      int64                         `json:"count"`
+}
+
+// GetUserAccountCandidates returns candidates suggested by the suggestUserAccounts subtask
+// @Summary      Get user/account candidates
+// @Description  get user/account candidates suggested by the identity matching rules, sorted by account and score
+// @Tags 		 plugins/org
+// @Param        status query string false "PENDING, ACCEPTED or REJECTED"
+// @Param        page query int false "page"
+// @Param        pageSize query int false "page size, default 50"
+// @Produce      json
+// @Success      200  {object} paginatedUserAccountCandidates
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/user-account-candidates [get]
+func (h *Handlers) GetUserAccountCandidates(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	candidates, count, err := h.store.findUserAccountCandidates(input.Query.Get("status"), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{
+		Body:   paginatedUserAccountCandidates{Candidates: candidates, Count: count},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// ReviewUserAccountCandidates accepts or rejects candidates
+// @Summary      Review user/account candidates
+// @Description  accept or reject user/account candidates, accepted ones land in user_accounts, rejecting an accepted one removes it from user_accounts
+// @Description  body: {"reviews": [{"userId": "1", "accountId": "github:GithubAccount:1:1", "status": "ACCEPTED"}]}
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/user-account-candidates [patch]
+func (h *Handlers) ReviewUserAccountCandidates(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	var reviews []candidateReview
+	err := helper.Decode(input.Body["reviews"], &reviews, nil)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid reviews")
+	}
+	if len(reviews) == 0 {
+		return nil, errors.BadInput.New("reviews is required")
+	}
+	err = h.store.reviewUserAccountCandidates(reviews)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
+}
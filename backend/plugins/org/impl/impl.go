@@ -25,6 +25,8 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/org/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
+	"github.com/apache/incubator-devlake/plugins/org/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/org/tasks"
 )
 
@@ -34,6 +36,7 @@ var _ interface {
 	plugin.PluginTask
 	plugin.PluginModel
 	plugin.ProjectMapper
+	plugin.PluginMigration
 } = (*Org)(nil)
 
 type Org struct {
@@ -46,7 +49,9 @@ func (p *Org) Init(basicRes context.BasicRes) errors.Error {
 }
 
 func (p Org) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.UserAccountCandidate{},
+	}
 }
 
 func (p Org) Description() string {
@@ -60,7 +65,10 @@ func (p Org) Name() string {
 func (p Org) SubTaskMetas() []plugin.SubTaskMeta {
 	return []plugin.SubTaskMeta{
 		tasks.ConnectUserAccountsExactMeta,
+		tasks.SuggestUserAccountsMeta,
 		tasks.SetProjectMappingMeta,
+		tasks.BuildTeamClosuresMeta,
+		tasks.TrackTeamMembershipsMeta,
 		tasks.SleepMeta,
 	}
 }
@@ -73,7 +81,10 @@ func (p Org) MapProject(projectName string, scopes []plugin.Scope) (coreModels.P
 	options := make(map[string]interface{})
 	options["projectMappings"] = []tasks.ProjectMapping{tasks.NewProjectMapping(projectName, scopes)}
 
-	subtasks, err := helper.MakePipelinePlanSubtasks([]plugin.SubTaskMeta{tasks.SetProjectMappingMeta}, []string{plugin.DOMAIN_TYPE_CROSS})
+	subtasks, err := helper.MakePipelinePlanSubtasks(
+		[]plugin.SubTaskMeta{tasks.SetProjectMappingMeta, tasks.BuildTeamClosuresMeta, tasks.TrackTeamMembershipsMeta},
+		[]string{plugin.DOMAIN_TYPE_CROSS},
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +109,10 @@ func (p Org) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]inte
 	return taskData, nil
 }
 
+func (p Org) MigrationScripts() []plugin.MigrationScript {
+	return migrationscripts.All()
+}
+
 func (p Org) RootPkgPath() string {
 	return "github.com/apache/incubator-devlake/plugins/org"
 }
@@ -121,5 +136,9 @@ func (p Org) ApiResources() map[string]map[string]plugin.ApiResourceHandler {
 			"GET": p.handlers.GetProjectMapping,
 			"PUT": p.handlers.CreateProjectMapping,
 		},
+		"user-account-candidates": {
+			"GET":   p.handlers.GetUserAccountCandidates,
+			"PATCH": p.handlers.ReviewUserAccountCandidates,
+		},
 	}
 }
@@ -0,0 +1,40 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archived
+
+import (
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+)
+
+type UserAccountCandidate struct {
+	UserId          string `gorm:"primaryKey;type:varchar(255)"`
+	AccountId       string `gorm:"primaryKey;type:varchar(255)"`
+	Score           float64
+	Reasons         string `gorm:"type:varchar(255)"`
+	Status          string `gorm:"type:varchar(20);index"`
+	UserName        string `gorm:"type:varchar(255)"`
+	UserEmail       string `gorm:"type:varchar(255)"`
+	AccountFullName string `gorm:"type:varchar(255)"`
+	AccountUserName string `gorm:"type:varchar(255)"`
+	AccountEmail    string `gorm:"type:varchar(255)"`
+	archived.NoPKModel
+}
+
+func (UserAccountCandidate) TableName() string {
+	return "_tool_org_user_account_candidates"
+}
@@ -0,0 +1,51 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+const (
+	CANDIDATE_PENDING  = "PENDING"
+	CANDIDATE_ACCEPTED = "ACCEPTED"
+	CANDIDATE_REJECTED = "REJECTED"
+)
+
+// UserAccountCandidate is a suggested link between a user and an account found by the identity matching rules,
+// it lands in user_accounts only after being accepted
+type UserAccountCandidate struct {
+	UserId    string `json:"userId" gorm:"primaryKey;type:varchar(255)"`
+	AccountId string `json:"accountId" gorm:"primaryKey;type:varchar(255)"`
+	// Score is between 0 and 1, the higher the more likely the account belongs to the user
+	Score float64 `json:"score"`
+	// Reasons are the matching rules hit by the candidate, separated by comma
+	Reasons string `json:"reasons" gorm:"type:varchar(255)"`
+	Status  string `json:"status" gorm:"type:varchar(20);index"`
+
+	UserName        string `json:"userName" gorm:"type:varchar(255)"`
+	UserEmail       string `json:"userEmail" gorm:"type:varchar(255)"`
+	AccountFullName string `json:"accountFullName" gorm:"type:varchar(255)"`
+	AccountUserName string `json:"accountUserName" gorm:"type:varchar(255)"`
+	AccountEmail    string `json:"accountEmail" gorm:"type:varchar(255)"`
+	common.NoPKModel
+}
+
+func (UserAccountCandidate) TableName() string {
+	return "_tool_org_user_account_candidates"
+}
@@ -0,0 +1,326 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	REASON_EMAIL            = "email"
+	REASON_EMAIL_LOCAL_PART = "emailLocalPart"
+	REASON_GITHUB_NOREPLY   = "githubNoreply"
+	REASON_NAME             = "name"
+	REASON_USER_NAME        = "userName"
+	REASON_INITIALS         = "initials"
+	REASON_FUZZY_NAME       = "fuzzyName"
+)
+
+// scores of the matching rules, the score of a candidate is the highest one among the rules it hits
+var identityMatchingScores = map[string]float64{
+	REASON_EMAIL:            1,
+	REASON_NAME:             0.9,
+	REASON_GITHUB_NOREPLY:   0.8,
+	REASON_USER_NAME:        0.7,
+	REASON_INITIALS:         0.6,
+	REASON_EMAIL_LOCAL_PART: 0.5,
+	REASON_FUZZY_NAME:       0.8, // scaled down by the edit distance
+}
+
+// IdentityMatchingRules configures how accounts are matched with users fuzzily
+type IdentityMatchingRules struct {
+	// EmailDomainAliases maps alias domains to the canonical one, i.e. {"corp.example.com": "example.com"}
+	EmailDomainAliases map[string]string `json:"emailDomainAliases"`
+	// NormalizeNames compares names case/accent/punctuation insensitively regardless of the order of words
+	NormalizeNames bool `json:"normalizeNames"`
+	// GithubNoreplyEmails extracts the login from emails like 12345+login@users.noreply.github.com
+	GithubNoreplyEmails bool `json:"githubNoreplyEmails"`
+	// LevenshteinThreshold is the max edit distance between normalized names, 0 disables fuzzy names
+	LevenshteinThreshold int `json:"levenshteinThreshold"`
+	// MinScore drops candidates scored below it
+	MinScore float64 `json:"minScore"`
+	// AutoAcceptScore links candidates scored at or above it into user_accounts directly, 0 disables it
+	AutoAcceptScore float64 `json:"autoAcceptScore"`
+}
+
+// DefaultIdentityMatchingRules is used when no rules were given
+func DefaultIdentityMatchingRules() *IdentityMatchingRules {
+	return &IdentityMatchingRules{
+		NormalizeNames:       true,
+		GithubNoreplyEmails:  true,
+		LevenshteinThreshold: 2,
+		MinScore:             0.5,
+	}
+}
+
+var githubNoreplyPattern = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// identity is the comparable form of a user or an account
+type identity struct {
+	email     string // canonical email
+	localPart string
+	login     string   // login extracted from the noreply email or the username
+	noreply   bool     // login was extracted from the noreply email
+	names     []string // normalized names
+	tokens    [][]string
+}
+
+func (rules *IdentityMatchingRules) canonicalEmail(email string) (string, string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return "", ""
+	}
+	local, domain := email[:at], email[at+1:]
+	// sub-addressing like john+jira@example.com
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if canonical, ok := rules.EmailDomainAliases[domain]; ok {
+		domain = strings.ToLower(canonical)
+	}
+	return local + "@" + domain, local
+}
+
+func (rules *IdentityMatchingRules) normalizeName(name string) string {
+	if !rules.NormalizeNames {
+		return strings.TrimSpace(name)
+	}
+	return strings.Join(nameTokens(name), " ")
+}
+
+// nameTokens lowercases the name, strips accents and punctuation, and sorts the words
+func nameTokens(name string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	tokens := strings.Fields(b.String())
+	sort.Strings(tokens)
+	return tokens
+}
+
+func (rules *IdentityMatchingRules) userIdentity(user *crossdomain.User) *identity {
+	id := &identity{}
+	id.email, id.localPart = rules.canonicalEmail(user.Email)
+	if name := rules.normalizeName(user.Name); name != "" {
+		id.names = append(id.names, name)
+		id.tokens = append(id.tokens, nameTokens(user.Name))
+	}
+	return id
+}
+
+func (rules *IdentityMatchingRules) accountIdentity(account *crossdomain.Account) *identity {
+	id := &identity{}
+	id.email, id.localPart = rules.canonicalEmail(account.Email)
+	if rules.GithubNoreplyEmails {
+		if m := githubNoreplyPattern.FindStringSubmatch(strings.ToLower(account.Email)); m != nil {
+			id.login = m[1]
+			id.noreply = true
+			// noreply emails tell nothing about the person but the login
+			id.email, id.localPart = "", ""
+		}
+	}
+	if id.login == "" {
+		id.login = strings.ToLower(account.UserName)
+	}
+	for _, name := range []string{account.FullName, account.UserName} {
+		if normalized := rules.normalizeName(name); normalized != "" {
+			id.names = append(id.names, normalized)
+			id.tokens = append(id.tokens, nameTokens(name))
+		}
+	}
+	return id
+}
+
+// blockingKeys returns the keys of the blocks the identity falls into, users are only scored against the
+// accounts sharing a block with them. Logins and email local parts share the `l:` keys, which covers the
+// email, email local part, login and initials rules, and the name tokens the `t:` keys, which covers equal
+// names and the fuzzy ones sharing a word, i.e. a typo in a single word name is missed
+func (rules *IdentityMatchingRules) blockingKeys(id *identity, isUser bool) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if id.localPart != "" {
+		add("l:" + id.localPart)
+	}
+	if id.login != "" {
+		add("l:" + id.login)
+	}
+	for _, tokens := range id.tokens {
+		for _, token := range tokens {
+			add("t:" + token)
+		}
+		// the logins matchInitials would accept for the user
+		if isUser && rules.NormalizeNames && len(tokens) > 1 {
+			for i, first := range tokens {
+				for j, last := range tokens {
+					if i != j {
+						add("l:" + firstLetter(first) + last)
+						add("l:" + first + firstLetter(last))
+					}
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// match returns the score and reasons of the account belonging to the user, 0 if they don't match at all
+func (rules *IdentityMatchingRules) match(user, account *identity) (float64, []string) {
+	var reasons []string
+	hit := func(reason string) {
+		for _, r := range reasons {
+			if r == reason {
+				return
+			}
+		}
+		reasons = append(reasons, reason)
+	}
+	if user.email != "" && user.email == account.email {
+		hit(REASON_EMAIL)
+	} else if user.localPart != "" && user.localPart == account.localPart {
+		hit(REASON_EMAIL_LOCAL_PART)
+	}
+	if account.login != "" && user.localPart == account.login {
+		if account.noreply {
+			hit(REASON_GITHUB_NOREPLY)
+		} else {
+			hit(REASON_USER_NAME)
+		}
+	}
+	fuzzyScore := 0.0
+	for _, userName := range user.names {
+		for j, accountName := range account.names {
+			if userName == accountName {
+				if j == 0 {
+					hit(REASON_NAME)
+				} else {
+					hit(REASON_USER_NAME)
+				}
+				continue
+			}
+			if rules.LevenshteinThreshold > 0 {
+				distance := levenshtein(userName, accountName)
+				if distance <= rules.LevenshteinThreshold {
+					hit(REASON_FUZZY_NAME)
+					longest := len([]rune(userName))
+					if l := len([]rune(accountName)); l > longest {
+						longest = l
+					}
+					score := identityMatchingScores[REASON_FUZZY_NAME] * (1 - float64(distance)/float64(longest))
+					if score > fuzzyScore {
+						fuzzyScore = score
+					}
+				}
+			}
+		}
+	}
+	if rules.NormalizeNames && account.login != "" {
+		for _, tokens := range user.tokens {
+			if matchInitials(tokens, account.login) {
+				hit(REASON_INITIALS)
+			}
+		}
+	}
+	score := 0.0
+	for _, reason := range reasons {
+		s := identityMatchingScores[reason]
+		if reason == REASON_FUZZY_NAME {
+			s = fuzzyScore
+		}
+		if s > score {
+			score = s
+		}
+	}
+	return score, reasons
+}
+
+// matchInitials checks logins like jdoe or johnd against the name tokens of John Doe
+func matchInitials(tokens []string, login string) bool {
+	if len(tokens) < 2 {
+		return false
+	}
+	for i, first := range tokens {
+		for j, last := range tokens {
+			if i == j {
+				continue
+			}
+			if login == firstLetter(first)+last || login == first+firstLetter(last) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// firstLetter returns the first character of the token, which may span several bytes
+func firstLetter(token string) string {
+	_, size := utf8.DecodeRuneInString(token)
+	return token[:size]
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
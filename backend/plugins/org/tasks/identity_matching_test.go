@@ -0,0 +1,115 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityMatching(t *testing.T) {
+	rules := DefaultIdentityMatchingRules()
+	rules.EmailDomainAliases = map[string]string{"corp.com": "example.com"}
+	user := rules.userIdentity(&crossdomain.User{Name: "John Doe", Email: "jdoe@example.com"})
+
+	tests := []struct {
+		name    string
+		account crossdomain.Account
+		score   float64
+		reasons []string
+	}{
+		{
+			name:    "email domain alias and sub-addressing",
+			account: crossdomain.Account{Email: "JDoe+jira@corp.com"},
+			score:   1,
+			reasons: []string{REASON_EMAIL},
+		},
+		{
+			name:    "normalized full name",
+			account: crossdomain.Account{FullName: "Doe, John"},
+			score:   0.9,
+			reasons: []string{REASON_NAME},
+		},
+		{
+			name:    "github noreply email",
+			account: crossdomain.Account{Email: "12345+jdoe@users.noreply.github.com", UserName: "whatever"},
+			score:   0.8,
+			reasons: []string{REASON_GITHUB_NOREPLY, REASON_INITIALS},
+		},
+		{
+			name:    "initials",
+			account: crossdomain.Account{UserName: "johnd"},
+			score:   0.6,
+			reasons: []string{REASON_INITIALS},
+		},
+		{
+			name:    "fuzzy name",
+			account: crossdomain.Account{FullName: "Jöhn Do"},
+			score:   0.8 * (1 - 1.0/8),
+			reasons: []string{REASON_FUZZY_NAME},
+		},
+		{
+			name:    "email local part only",
+			account: crossdomain.Account{Email: "jdoe@other.org"},
+			score:   0.5,
+			reasons: []string{REASON_EMAIL_LOCAL_PART},
+		},
+		{
+			name:    "nothing in common",
+			account: crossdomain.Account{FullName: "Jane Roe", UserName: "jroe", Email: "jane@example.com"},
+			score:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := rules.accountIdentity(&tt.account)
+			score, reasons := rules.match(user, account)
+			assert.InDelta(t, tt.score, score, 0.0001)
+			assert.ElementsMatch(t, tt.reasons, reasons)
+			// the account must share a block with the user whenever they match
+			blocks := make(map[string][]int)
+			for _, key := range rules.blockingKeys(user, true) {
+				blocks[key] = append(blocks[key], 0)
+			}
+			assert.Equal(t, score > 0, len(blockedUsers(blocks, rules.blockingKeys(account, false))) > 0)
+		})
+	}
+}
+
+func TestBlockedUsers(t *testing.T) {
+	blocks := map[string][]int{"l:jdoe": {3, 1}, "t:doe": {1, 2}, "t:john": {0}}
+	assert.Equal(t, []int{1, 2, 3}, blockedUsers(blocks, []string{"t:doe", "l:jdoe", "t:jane"}))
+	assert.Nil(t, blockedUsers(blocks, []string{"t:jane"}))
+}
+
+func TestMatchInitials(t *testing.T) {
+	tokens := nameTokens("Øyvind Berg")
+	assert.True(t, matchInitials(tokens, "øberg"))
+	assert.True(t, matchInitials(tokens, "øyvindb"))
+	assert.False(t, matchInitials(tokens, "\xc3berg"))
+	assert.True(t, matchInitials(nameTokens("Émile Zola"), "ezola"))
+	assert.False(t, matchInitials([]string{"john"}, "jjohn"))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("doe john", "doe john"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "jöhn"))
+}
@@ -23,6 +23,8 @@ type Options struct {
 	ConnectionId    uint64           `json:"connectionId"`
 	ProjectMappings []ProjectMapping `json:"projectMappings"`
 	SleepSeconds    uint64           `json:"sleepSeconds"`
+	// IdentityMatching configures suggestUserAccounts, DefaultIdentityMatchingRules is used if empty
+	IdentityMatching *IdentityMatchingRules `json:"identityMatching"`
 }
 
 // ProjectMapping represents the relations between project and scopes
@@ -0,0 +1,189 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var BuildTeamClosuresMeta = plugin.SubTaskMeta{
+	Name:             "buildTeamClosures",
+	EntryPoint:       BuildTeamClosures,
+	EnabledByDefault: true,
+	Description:      "materialize the team hierarchy into team_closures",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
+
+var TrackTeamMembershipsMeta = plugin.SubTaskMeta{
+	Name:             "trackTeamMemberships",
+	EntryPoint:       TrackTeamMemberships,
+	EnabledByDefault: true,
+	Description:      "track changes of team_users as team_memberships with effective dates",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
+
+// membershipEpoch is the EffectiveFrom of memberships of users seen for the first time, the date they joined
+// the team is unknown so events before the first sync are attributed to their current teams
+var membershipEpoch = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// BuildTeamClosures rebuilds team_closures from teams.parent_id
+func BuildTeamClosures(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	var teams []crossdomain.Team
+	err := db.All(&teams)
+	if err != nil {
+		return err
+	}
+	closures, err := buildTeamClosures(teams)
+	if err != nil {
+		return err
+	}
+	err = db.Delete(&crossdomain.TeamClosure{}, dal.Where("1 = 1"))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(closures); i += DefaultBatchSize {
+		end := i + DefaultBatchSize
+		if end > len(closures) {
+			end = len(closures)
+		}
+		err = db.Create(closures[i:end])
+		if err != nil {
+			return errors.Default.Wrap(err, "error saving team_closures")
+		}
+	}
+	taskCtx.GetLogger().Info("%d team closures built for %d teams", len(closures), len(teams))
+	return nil
+}
+
+// buildTeamClosures returns all ancestor/descendant pairs of the teams including the team itself, parents
+// referring to unknown teams are ignored and cycles are reported as errors
+func buildTeamClosures(teams []crossdomain.Team) ([]*crossdomain.TeamClosure, errors.Error) {
+	parents := make(map[string]string, len(teams))
+	for _, team := range teams {
+		parents[team.Id] = team.ParentId
+	}
+	var closures []*crossdomain.TeamClosure
+	for _, team := range teams {
+		visited := map[string]bool{team.Id: true}
+		ancestorId, depth := team.Id, 0
+		for {
+			closures = append(closures, &crossdomain.TeamClosure{
+				AncestorId:   ancestorId,
+				DescendantId: team.Id,
+				Depth:        depth,
+			})
+			parentId, ok := parents[ancestorId]
+			if !ok || parentId == "" {
+				break
+			}
+			if _, ok := parents[parentId]; !ok {
+				break
+			}
+			if visited[parentId] {
+				return nil, errors.BadInput.New("cycle detected in teams hierarchy at team " + parentId)
+			}
+			visited[parentId] = true
+			ancestorId = parentId
+			depth++
+		}
+	}
+	return closures, nil
+}
+
+// TrackTeamMemberships opens memberships for new team_users and closes memberships whose team_users are gone
+func TrackTeamMemberships(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	var teamUsers []crossdomain.TeamUser
+	err := db.All(&teamUsers)
+	if err != nil {
+		return err
+	}
+	var memberships []crossdomain.TeamMembership
+	err = db.All(&memberships)
+	if err != nil {
+		return err
+	}
+	toOpen, toClose := diffTeamMemberships(teamUsers, memberships, time.Now())
+	for _, membership := range toClose {
+		err = db.Update(membership)
+		if err != nil {
+			return errors.Default.Wrap(err, "error closing team_memberships")
+		}
+	}
+	if len(toOpen) > 0 {
+		err = db.Create(toOpen)
+		if err != nil {
+			return errors.Default.Wrap(err, "error opening team_memberships")
+		}
+	}
+	taskCtx.GetLogger().Info("%d team memberships opened, %d closed", len(toOpen), len(toClose))
+	return nil
+}
+
+// diffTeamMemberships compares current team_users with the recorded memberships, memberships of users
+// without any history start from membershipEpoch, later changes take effect at now
+func diffTeamMemberships(
+	teamUsers []crossdomain.TeamUser,
+	memberships []crossdomain.TeamMembership,
+	now time.Time,
+) (toOpen []*crossdomain.TeamMembership, toClose []*crossdomain.TeamMembership) {
+	type pair struct{ teamId, userId string }
+	current := make(map[pair]bool, len(teamUsers))
+	for _, tu := range teamUsers {
+		current[pair{tu.TeamId, tu.UserId}] = true
+	}
+	open := make(map[pair]bool)
+	knownUsers := make(map[string]bool)
+	for i := range memberships {
+		m := &memberships[i]
+		knownUsers[m.UserId] = true
+		if m.EffectiveTo != nil {
+			continue
+		}
+		p := pair{m.TeamId, m.UserId}
+		if current[p] {
+			open[p] = true
+			continue
+		}
+		m.EffectiveTo = &now
+		toClose = append(toClose, m)
+	}
+	for _, tu := range teamUsers {
+		p := pair{tu.TeamId, tu.UserId}
+		if open[p] {
+			continue
+		}
+		open[p] = true
+		effectiveFrom := now
+		if !knownUsers[tu.UserId] {
+			effectiveFrom = membershipEpoch
+		}
+		toOpen = append(toOpen, &crossdomain.TeamMembership{
+			TeamId:        tu.TeamId,
+			UserId:        tu.UserId,
+			EffectiveFrom: effectiveFrom,
+		})
+	}
+	return
+}
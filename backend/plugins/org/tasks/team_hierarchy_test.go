@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTeam(id, parentId string) crossdomain.Team {
+	return crossdomain.Team{DomainEntity: domainlayer.DomainEntity{Id: id}, ParentId: parentId}
+}
+
+func TestBuildTeamClosures(t *testing.T) {
+	closures, err := buildTeamClosures([]crossdomain.Team{
+		newTeam("1", "2"),
+		newTeam("2", "3"),
+		newTeam("3", ""),
+		newTeam("4", "unknown"),
+	})
+	assert.Nil(t, err)
+	var actual []crossdomain.TeamClosure
+	for _, c := range closures {
+		actual = append(actual, *c)
+	}
+	assert.Equal(t, []crossdomain.TeamClosure{
+		{AncestorId: "1", DescendantId: "1", Depth: 0},
+		{AncestorId: "2", DescendantId: "1", Depth: 1},
+		{AncestorId: "3", DescendantId: "1", Depth: 2},
+		{AncestorId: "2", DescendantId: "2", Depth: 0},
+		{AncestorId: "3", DescendantId: "2", Depth: 1},
+		{AncestorId: "3", DescendantId: "3", Depth: 0},
+		{AncestorId: "4", DescendantId: "4", Depth: 0},
+	}, actual)
+
+	_, err = buildTeamClosures([]crossdomain.Team{newTeam("1", "2"), newTeam("2", "1")})
+	assert.NotNil(t, err)
+}
+
+func TestDiffTeamMemberships(t *testing.T) {
+	lastSync := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	teamUsers := []crossdomain.TeamUser{
+		{TeamId: "1", UserId: "alice"},
+		{TeamId: "2", UserId: "bob"},
+		{TeamId: "1", UserId: "carol"},
+	}
+	memberships := []crossdomain.TeamMembership{
+		{TeamId: "1", UserId: "alice", EffectiveFrom: membershipEpoch},
+		{TeamId: "1", UserId: "bob", EffectiveFrom: membershipEpoch},
+		{TeamId: "2", UserId: "bob", EffectiveFrom: membershipEpoch, EffectiveTo: &lastSync},
+	}
+
+	toOpen, toClose := diffTeamMemberships(teamUsers, memberships, now)
+
+	assert.Len(t, toClose, 1)
+	assert.Equal(t, "1", toClose[0].TeamId)
+	assert.Equal(t, "bob", toClose[0].UserId)
+	assert.Equal(t, now, *toClose[0].EffectiveTo)
+
+	assert.Len(t, toOpen, 2)
+	// bob moved back to team 2
+	assert.Equal(t, crossdomain.TeamMembership{TeamId: "2", UserId: "bob", EffectiveFrom: now}, *toOpen[0])
+	// carol has no history, her membership is assumed to be effective since ever
+	assert.Equal(t, crossdomain.TeamMembership{TeamId: "1", UserId: "carol", EffectiveFrom: membershipEpoch}, *toOpen[1])
+}
@@ -0,0 +1,174 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
+)
+
+var SuggestUserAccountsMeta = plugin.SubTaskMeta{
+	Name:             "suggestUserAccounts",
+	EntryPoint:       SuggestUserAccounts,
+	EnabledByDefault: true,
+	Description:      "suggest users for accounts not associated yet by the identity matching rules",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+	Dependencies:     []*plugin.SubTaskMeta{&ConnectUserAccountsExactMeta},
+}
+
+// SuggestUserAccounts scores users for each account not associated yet, candidates are saved for reviewing
+// unless scored above the AutoAcceptScore. Reviewed candidates are kept, pending ones are regenerated
+func SuggestUserAccounts(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*TaskData)
+	rules := data.Options.IdentityMatching
+	if rules == nil {
+		rules = DefaultIdentityMatchingRules()
+	}
+
+	var users []crossdomain.User
+	err := db.All(&users)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	// accounts are only scored against the users sharing a block with them instead of all the users
+	userIdentities := make([]*identity, len(users))
+	blocks := make(map[string][]int)
+	for i := range users {
+		userIdentities[i] = rules.userIdentity(&users[i])
+		for _, key := range rules.blockingKeys(userIdentities[i], true) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+	var rejected []models.UserAccountCandidate
+	err = db.All(&rejected, dal.Where("status = ?", models.CANDIDATE_REJECTED))
+	if err != nil {
+		return err
+	}
+	isRejected := make(map[string]bool, len(rejected))
+	for _, candidate := range rejected {
+		isRejected[candidate.UserId+"\x00"+candidate.AccountId] = true
+	}
+	err = db.Delete(&models.UserAccountCandidate{}, dal.Where("status = ?", models.CANDIDATE_PENDING))
+	if err != nil {
+		return err
+	}
+
+	cursor, err := db.Cursor(
+		dal.From(&crossdomain.Account{}),
+		dal.Where("id NOT IN (SELECT account_id FROM user_accounts)"),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	divider := api.NewBatchSaveDivider(taskCtx, 500, "", "")
+	candidateBatch, err := divider.ForType(reflect.TypeOf(&models.UserAccountCandidate{}))
+	if err != nil {
+		return err
+	}
+	userAccountBatch, err := divider.ForType(reflect.TypeOf(&crossdomain.UserAccount{}))
+	if err != nil {
+		return err
+	}
+	suggested, accepted := 0, 0
+	for cursor.Next() {
+		account := &crossdomain.Account{}
+		err = db.Fetch(cursor, account)
+		if err != nil {
+			return err
+		}
+		accountIdentity := rules.accountIdentity(account)
+		var candidates []*models.UserAccountCandidate
+		for _, i := range blockedUsers(blocks, rules.blockingKeys(accountIdentity, false)) {
+			score, reasons := rules.match(userIdentities[i], accountIdentity)
+			if score == 0 || score < rules.MinScore || isRejected[users[i].Id+"\x00"+account.Id] {
+				continue
+			}
+			candidates = append(candidates, &models.UserAccountCandidate{
+				UserId:          users[i].Id,
+				AccountId:       account.Id,
+				Score:           score,
+				Reasons:         strings.Join(reasons, ","),
+				Status:          models.CANDIDATE_PENDING,
+				UserName:        users[i].Name,
+				UserEmail:       users[i].Email,
+				AccountFullName: account.FullName,
+				AccountUserName: account.UserName,
+				AccountEmail:    account.Email,
+			})
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+		// only the unambiguous best candidate is accepted automatically
+		best := candidates[0]
+		if rules.AutoAcceptScore > 0 && best.Score >= rules.AutoAcceptScore &&
+			(len(candidates) == 1 || candidates[1].Score < best.Score) {
+			best.Status = models.CANDIDATE_ACCEPTED
+			err = userAccountBatch.Add(&crossdomain.UserAccount{UserId: best.UserId, AccountId: best.AccountId})
+			if err != nil {
+				return err
+			}
+			err = candidateBatch.Add(best)
+			if err != nil {
+				return err
+			}
+			accepted++
+			continue
+		}
+		for _, candidate := range candidates {
+			err = candidateBatch.Add(candidate)
+			if err != nil {
+				return err
+			}
+			suggested++
+		}
+	}
+	logger.Info("suggested %d user/account candidates, accepted %d automatically", suggested, accepted)
+	return divider.Close()
+}
+
+// blockedUsers returns the indexes of the users in the blocks in ascending order, without duplicates
+func blockedUsers(blocks map[string][]int, keys []string) []int {
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, key := range keys {
+		for _, i := range blocks[key] {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
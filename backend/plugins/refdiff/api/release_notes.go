@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/refdiff/tasks"
+)
+
+// @Summary get release notes of a ref pair
+// @Description GET /plugins/refdiff/release-notes?repoId=xxx&newRef=refs/tags/v1.1.0&oldRef=refs/tags/v1.0.0&format=markdown&labels=feature,bug
+// @Description issues are grouped by type and pull requests by label, commits diff of the pair must have been calculated
+// @Tags plugins/refdiff
+// @Param repoId query string true "repo id"
+// @Param newRef query string true "new ref name"
+// @Param oldRef query string true "old ref name"
+// @Param format query string false "json (default) or markdown"
+// @Param labels query string false "comma separated labels deciding the order of pull request groups"
+// @Success 200  {object} models.ReleaseNotesContent
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/refdiff/release-notes [GET]
+func GetReleaseNotes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	repoId, newRef, oldRef := input.Query.Get("repoId"), input.Query.Get("newRef"), input.Query.Get("oldRef")
+	if repoId == "" || newRef == "" || oldRef == "" {
+		return nil, errors.BadInput.New("repoId, newRef and oldRef are required")
+	}
+	var labels []string
+	if l := input.Query.Get("labels"); l != "" {
+		labels = strings.Split(l, ",")
+	}
+	content, err := tasks.BuildReleaseNotes(basicRes.GetDal(), repoId, newRef, oldRef, labels)
+	if err != nil {
+		return nil, err
+	}
+	switch input.Query.Get("format") {
+	case "", "json":
+		return &plugin.ApiResourceOutput{Body: content, Status: http.StatusOK}, nil
+	case "markdown":
+		return &plugin.ApiResourceOutput{
+			Status: http.StatusOK,
+			File: &plugin.OutputFile{
+				ContentType: "text/markdown",
+				Data:        []byte(tasks.RenderReleaseNotesMarkdown(content)),
+			},
+		}, nil
+	default:
+		return nil, errors.BadInput.New("format must be json or markdown")
+	}
+}
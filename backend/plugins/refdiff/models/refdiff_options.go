@@ -36,4 +36,6 @@ type RefdiffOptions struct {
 
 	AllPairs    RefCommitPairs // Pairs and TagsPattern Pairs
 	ProjectName string
+
+	ReleaseNotesLabels []string // The order of labels to group pull requests by in release notes
 }
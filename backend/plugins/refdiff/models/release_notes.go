@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// ReleaseNotes stores release notes rendered by the generateReleaseNotes subtask for a ref pair
+type ReleaseNotes struct {
+	NewRefId     string `json:"newRefId" gorm:"primaryKey;type:varchar(255)"`
+	OldRefId     string `json:"oldRefId" gorm:"primaryKey;type:varchar(255)"`
+	RepoId       string `json:"repoId" gorm:"type:varchar(255);index"`
+	NewCommitSha string `json:"newCommitSha" gorm:"type:varchar(40)"`
+	OldCommitSha string `json:"oldCommitSha" gorm:"type:varchar(40)"`
+	Markdown     string `json:"markdown" gorm:"type:text"`
+	// Content is the ReleaseNotesContent in json
+	Content string `json:"content" gorm:"type:text"`
+	common.NoPKModel
+}
+
+func (ReleaseNotes) TableName() string {
+	return "_tool_refdiff_release_notes"
+}
+
+// ReleaseNotesContent is the structured release notes of a ref pair
+type ReleaseNotesContent struct {
+	RepoId            string                     `json:"repoId"`
+	NewRef            string                     `json:"newRef"`
+	OldRef            string                     `json:"oldRef"`
+	NewCommitSha      string                     `json:"newCommitSha"`
+	OldCommitSha      string                     `json:"oldCommitSha"`
+	CommitCount       int                        `json:"commitCount"`
+	IssueGroups       []*ReleaseNotesIssueGroup  `json:"issueGroups"`
+	PullRequestGroups []*ReleaseNotesPrGroup     `json:"pullRequestGroups"`
+	Contributors      []*ReleaseNotesContributor `json:"contributors"`
+}
+
+// ReleaseNotesIssueGroup groups issues by their standard type, e.g. REQUIREMENT, BUG
+type ReleaseNotesIssueGroup struct {
+	Type   string               `json:"type"`
+	Issues []*ReleaseNotesIssue `json:"issues"`
+}
+
+type ReleaseNotesIssue struct {
+	Id       string `json:"id"`
+	IssueKey string `json:"issueKey"`
+	Title    string `json:"title"`
+	Url      string `json:"url"`
+}
+
+// ReleaseNotesPrGroup groups pull requests by label, a pull request is put into the group of its first label
+type ReleaseNotesPrGroup struct {
+	Label        string                     `json:"label"`
+	PullRequests []*ReleaseNotesPullRequest `json:"pullRequests"`
+}
+
+type ReleaseNotesPullRequest struct {
+	Id             string   `json:"id"`
+	PullRequestKey int      `json:"pullRequestKey"`
+	Title          string   `json:"title"`
+	Url            string   `json:"url"`
+	AuthorName     string   `json:"authorName"`
+	Labels         []string `json:"labels"`
+}
+
+// ReleaseNotesContributor is an author of the pull requests or commits, details are taken from accounts if available
+type ReleaseNotesContributor struct {
+	AccountId        string `json:"accountId"`
+	Name             string `json:"name"`
+	UserName         string `json:"userName"`
+	Email            string `json:"email"`
+	AvatarUrl        string `json:"avatarUrl"`
+	PullRequestCount int    `json:"pullRequestCount"`
+	CommitCount      int    `json:"commitCount"`
+}
@@ -38,6 +38,7 @@ func main() {
 	tagsOrder := refdiffCmd.Flags().StringP("tags-order", "d", "", "tags order")
 
 	projectName := refdiffCmd.Flags().StringP("project-name", "P", "", "project name")
+	releaseNotesLabels := refdiffCmd.Flags().StringSliceP("release-notes-labels", "L", nil, "labels to group pull requests by in release notes")
 	timeAfter := refdiffCmd.Flags().StringP("time-after", "a", "", "collect data that are created after specified time, ie 2006-01-02T15:04:05Z")
 
 	// _ = refdiffCmd.MarkFlagRequired("repo-id")
@@ -54,12 +55,13 @@ func main() {
 		}
 
 		runner.DirectRun(cmd, args, PluginEntry, map[string]interface{}{
-			"repoId":      repoId,
-			"pairs":       pairs,
-			"tagsPattern": *tagsPattern,
-			"tagsLimit":   *tagsLimit,
-			"tagsOrder":   *tagsOrder,
-			"projectName": *projectName,
+			"repoId":             repoId,
+			"pairs":              pairs,
+			"tagsPattern":        *tagsPattern,
+			"tagsLimit":          *tagsLimit,
+			"tagsOrder":          *tagsOrder,
+			"projectName":        *projectName,
+			"releaseNotesLabels": *releaseNotesLabels,
 		}, *timeAfter)
 	}
 	runner.RunCmd(refdiffCmd)
@@ -0,0 +1,367 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/refdiff/models"
+)
+
+const (
+	RELEASE_NOTES_OTHER_ISSUES        = "OTHER"
+	RELEASE_NOTES_OTHER_PULL_REQUESTS = "other"
+)
+
+var GenerateReleaseNotesMeta = plugin.SubTaskMeta{
+	Name:             "generateReleaseNotes",
+	EntryPoint:       GenerateReleaseNotes,
+	EnabledByDefault: true,
+	Description:      "Render release notes of ref pairs from commits_diffs and refs_issues_diffs",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
+	DependencyTables: []string{code.CommitsDiff{}.TableName(), crossdomain.RefsIssuesDiffs{}.TableName()},
+	ProductTables:    []string{models.ReleaseNotes{}.TableName()},
+}
+
+// GenerateReleaseNotes saves release notes of all ref pairs into _tool_refdiff_release_notes
+func GenerateReleaseNotes(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*RefdiffTaskData)
+	db := taskCtx.GetDal()
+	if data.Options.ProjectName != "" {
+		return nil
+	}
+	taskCtx.SetProgress(0, len(data.Options.AllPairs))
+	for _, pair := range data.Options.AllPairs {
+		content, err := buildReleaseNotes(db, data.Options.RepoId, pair[2], pair[3], pair[0], pair[1], data.Options.ReleaseNotesLabels)
+		if err != nil {
+			return err
+		}
+		blob, err := errors.Convert01(json.Marshal(content))
+		if err != nil {
+			return err
+		}
+		err = db.CreateOrUpdate(&models.ReleaseNotes{
+			NewRefId:     fmt.Sprintf("%s:%s", data.Options.RepoId, pair[2]),
+			OldRefId:     fmt.Sprintf("%s:%s", data.Options.RepoId, pair[3]),
+			RepoId:       data.Options.RepoId,
+			NewCommitSha: pair[0],
+			OldCommitSha: pair[1],
+			Markdown:     RenderReleaseNotesMarkdown(content),
+			Content:      string(blob),
+		})
+		if err != nil {
+			return errors.Default.Wrap(err, "error saving release notes")
+		}
+		taskCtx.IncProgress(1)
+	}
+	return nil
+}
+
+// BuildReleaseNotes collects issues, pull requests and contributors between the refs of the repo, commits_diffs
+// of the pair must have been calculated, labels decide the order of pull request groups
+func BuildReleaseNotes(db dal.Dal, repoId, newRef, oldRef string, labels []string) (*models.ReleaseNotesContent, errors.Error) {
+	var shas [2]string
+	for i, refName := range []string{newRef, oldRef} {
+		ref := &code.Ref{}
+		err := db.First(ref, dal.Where("id = ?", fmt.Sprintf("%s:%s", repoId, refName)))
+		if err != nil {
+			if db.IsErrorNotFound(err) {
+				return nil, errors.NotFound.New(fmt.Sprintf("ref %s not found in repo %s", refName, repoId))
+			}
+			return nil, err
+		}
+		shas[i] = ref.CommitSha
+	}
+	count, err := db.Count(
+		dal.From(&models.FinishedCommitsDiff{}),
+		dal.Where("new_commit_sha = ? AND old_commit_sha = ?", shas[0], shas[1]),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.NotFound.New(fmt.Sprintf("commits diff between %s and %s is not calculated yet", newRef, oldRef))
+	}
+	return buildReleaseNotes(db, repoId, newRef, oldRef, shas[0], shas[1], labels)
+}
+
+func buildReleaseNotes(db dal.Dal, repoId, newRef, oldRef, newCommitSha, oldCommitSha string, labels []string) (*models.ReleaseNotesContent, errors.Error) {
+	content := &models.ReleaseNotesContent{
+		RepoId:       repoId,
+		NewRef:       newRef,
+		OldRef:       oldRef,
+		NewCommitSha: newCommitSha,
+		OldCommitSha: oldCommitSha,
+	}
+	diffShas := `SELECT commit_sha FROM commits_diffs WHERE new_commit_sha = ? AND old_commit_sha = ?`
+	var commits []code.Commit
+	err := db.All(&commits,
+		dal.Select("sha, author_name, author_email"),
+		dal.From(&code.Commit{}),
+		dal.Where("sha IN ("+diffShas+")", newCommitSha, oldCommitSha),
+	)
+	if err != nil {
+		return nil, err
+	}
+	content.CommitCount = len(commits)
+
+	// issues were linked to the pair by calculateIssuesDiff
+	var issues []struct {
+		Id       string
+		IssueKey string
+		Title    string
+		Url      string
+		Type     string
+	}
+	err = db.All(&issues,
+		dal.Select("issues.id, issues.issue_key, issues.title, issues.url, issues.type"),
+		dal.From("refs_issues_diffs"),
+		dal.Join("JOIN issues ON issues.id = refs_issues_diffs.issue_id"),
+		dal.Where(
+			"refs_issues_diffs.new_ref_id = ? AND refs_issues_diffs.old_ref_id = ?",
+			fmt.Sprintf("%s:%s", repoId, newRef), fmt.Sprintf("%s:%s", repoId, oldRef),
+		),
+		dal.Orderby("issues.issue_key"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	issueGroups := make(map[string]*models.ReleaseNotesIssueGroup)
+	for _, issue := range issues {
+		issueType := issue.Type
+		if issueType == "" {
+			issueType = RELEASE_NOTES_OTHER_ISSUES
+		}
+		group, ok := issueGroups[issueType]
+		if !ok {
+			group = &models.ReleaseNotesIssueGroup{Type: issueType}
+			issueGroups[issueType] = group
+			content.IssueGroups = append(content.IssueGroups, group)
+		}
+		group.Issues = append(group.Issues, &models.ReleaseNotesIssue{
+			Id:       issue.Id,
+			IssueKey: issue.IssueKey,
+			Title:    issue.Title,
+			Url:      issue.Url,
+		})
+	}
+	sort.SliceStable(content.IssueGroups, func(i, j int) bool {
+		return groupLess(content.IssueGroups[i].Type, content.IssueGroups[j].Type, nil, RELEASE_NOTES_OTHER_ISSUES)
+	})
+
+	// pull requests are found by merge commits or their commits like calculateIssuesDiff does
+	var pullRequests []code.PullRequest
+	err = db.All(&pullRequests,
+		dal.From(&code.PullRequest{}),
+		dal.Where(
+			"base_repo_id = ? AND (merge_commit_sha IN ("+diffShas+") OR id IN (SELECT pull_request_id FROM pull_request_commits WHERE commit_sha IN ("+diffShas+")))",
+			repoId, newCommitSha, oldCommitSha, newCommitSha, oldCommitSha,
+		),
+		dal.Orderby("pull_request_key"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	prIds := make([]string, 0, len(pullRequests))
+	authorIds := make([]string, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		prIds = append(prIds, pr.Id)
+		if pr.AuthorId != "" {
+			authorIds = append(authorIds, pr.AuthorId)
+		}
+	}
+	var prLabels []code.PullRequestLabel
+	err = db.All(&prLabels, dal.Where("pull_request_id IN ?", prIds), dal.Orderby("label_name"))
+	if err != nil {
+		return nil, err
+	}
+	labelsByPr := make(map[string][]string)
+	for _, l := range prLabels {
+		labelsByPr[l.PullRequestId] = append(labelsByPr[l.PullRequestId], l.LabelName)
+	}
+	prGroups := make(map[string]*models.ReleaseNotesPrGroup)
+	for _, pr := range pullRequests {
+		labelNames := labelsByPr[pr.Id]
+		label := RELEASE_NOTES_OTHER_PULL_REQUESTS
+		for _, l := range labelNames {
+			if label == RELEASE_NOTES_OTHER_PULL_REQUESTS || groupLess(l, label, labels, RELEASE_NOTES_OTHER_PULL_REQUESTS) {
+				label = l
+			}
+		}
+		group, ok := prGroups[label]
+		if !ok {
+			group = &models.ReleaseNotesPrGroup{Label: label}
+			prGroups[label] = group
+			content.PullRequestGroups = append(content.PullRequestGroups, group)
+		}
+		group.PullRequests = append(group.PullRequests, &models.ReleaseNotesPullRequest{
+			Id:             pr.Id,
+			PullRequestKey: pr.PullRequestKey,
+			Title:          pr.Title,
+			Url:            pr.Url,
+			AuthorName:     pr.AuthorName,
+			Labels:         labelNames,
+		})
+	}
+	sort.SliceStable(content.PullRequestGroups, func(i, j int) bool {
+		return groupLess(content.PullRequestGroups[i].Label, content.PullRequestGroups[j].Label, labels, RELEASE_NOTES_OTHER_PULL_REQUESTS)
+	})
+
+	// contributors are authors of pull requests and commits, commit authors are merged into accounts by email
+	var accounts []crossdomain.Account
+	err = db.All(&accounts, dal.Where("id IN ?", authorIds))
+	if err != nil {
+		return nil, err
+	}
+	contributors := make(map[string]*models.ReleaseNotesContributor)
+	accountsByEmail := make(map[string]*models.ReleaseNotesContributor)
+	for _, account := range accounts {
+		contributor := &models.ReleaseNotesContributor{
+			AccountId: account.Id,
+			Name:      account.FullName,
+			UserName:  account.UserName,
+			Email:     account.Email,
+			AvatarUrl: account.AvatarUrl,
+		}
+		contributors[account.Id] = contributor
+		if account.Email != "" {
+			accountsByEmail[strings.ToLower(account.Email)] = contributor
+		}
+	}
+	for _, pr := range pullRequests {
+		key := pr.AuthorId
+		if key == "" {
+			key = pr.AuthorName
+		}
+		contributor, ok := contributors[key]
+		if !ok {
+			contributor = &models.ReleaseNotesContributor{AccountId: pr.AuthorId, Name: pr.AuthorName}
+			contributors[key] = contributor
+		}
+		if contributor.Name == "" {
+			contributor.Name = pr.AuthorName
+		}
+		contributor.PullRequestCount++
+	}
+	for _, commit := range commits {
+		email := strings.ToLower(commit.AuthorEmail)
+		contributor, ok := accountsByEmail[email]
+		if !ok {
+			contributor, ok = contributors[email]
+		}
+		if !ok {
+			contributor = &models.ReleaseNotesContributor{Name: commit.AuthorName, Email: commit.AuthorEmail}
+			contributors[email] = contributor
+		}
+		contributor.CommitCount++
+	}
+	for _, contributor := range contributors {
+		content.Contributors = append(content.Contributors, contributor)
+	}
+	sort.Slice(content.Contributors, func(i, j int) bool {
+		a, b := content.Contributors[i], content.Contributors[j]
+		if a.PullRequestCount != b.PullRequestCount {
+			return a.PullRequestCount > b.PullRequestCount
+		}
+		if a.CommitCount != b.CommitCount {
+			return a.CommitCount > b.CommitCount
+		}
+		return a.Name < b.Name
+	})
+	return content, nil
+}
+
+// groupLess orders groups by their position in order, groups not in order come after alphabetically,
+// and the other group is always the last
+func groupLess(a, b string, order []string, other string) bool {
+	if a == other || b == other {
+		return b == other && a != other
+	}
+	ia, ib := indexOf(order, a), indexOf(order, b)
+	if ia != ib {
+		return ia < ib
+	}
+	return a < b
+}
+
+func indexOf(order []string, s string) int {
+	for i, item := range order {
+		if item == s {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// RenderReleaseNotesMarkdown renders the release notes as Markdown
+func RenderReleaseNotesMarkdown(content *models.ReleaseNotesContent) string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "# %s\n\n", content.NewRef)
+	fmt.Fprintf(sb, "Changes since %s, %d commits.\n", content.OldRef, content.CommitCount)
+	if len(content.IssueGroups) > 0 {
+		sb.WriteString("\n## Issues\n")
+		for _, group := range content.IssueGroups {
+			fmt.Fprintf(sb, "\n### %s\n\n", group.Type)
+			for _, issue := range group.Issues {
+				fmt.Fprintf(sb, "- %s %s\n", markdownLink(issue.IssueKey, issue.Url), issue.Title)
+			}
+		}
+	}
+	if len(content.PullRequestGroups) > 0 {
+		sb.WriteString("\n## Pull Requests\n")
+		for _, group := range content.PullRequestGroups {
+			fmt.Fprintf(sb, "\n### %s\n\n", group.Label)
+			for _, pr := range group.PullRequests {
+				fmt.Fprintf(sb, "- %s %s", markdownLink(fmt.Sprintf("#%d", pr.PullRequestKey), pr.Url), pr.Title)
+				if pr.AuthorName != "" {
+					fmt.Fprintf(sb, " by %s", pr.AuthorName)
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+	if len(content.Contributors) > 0 {
+		sb.WriteString("\n## Contributors\n\n")
+		for _, contributor := range content.Contributors {
+			name := contributor.Name
+			if name == "" {
+				name = contributor.UserName
+			}
+			if contributor.UserName != "" && contributor.UserName != name {
+				name = fmt.Sprintf("%s (@%s)", name, contributor.UserName)
+			}
+			fmt.Fprintf(sb, "- %s\n", name)
+		}
+	}
+	return sb.String()
+}
+
+func markdownLink(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
@@ -0,0 +1,73 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/refdiff/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupLess(t *testing.T) {
+	groups := []string{"other", "docs", "bug", "feature", "chore"}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groupLess(groups[i], groups[j], []string{"feature", "bug"}, RELEASE_NOTES_OTHER_PULL_REQUESTS)
+	})
+	assert.Equal(t, []string{"feature", "bug", "chore", "docs", "other"}, groups)
+}
+
+func TestRenderReleaseNotesMarkdown(t *testing.T) {
+	markdown := RenderReleaseNotesMarkdown(&models.ReleaseNotesContent{
+		NewRef:      "v1.1.0",
+		OldRef:      "v1.0.0",
+		CommitCount: 3,
+		IssueGroups: []*models.ReleaseNotesIssueGroup{
+			{Type: "BUG", Issues: []*models.ReleaseNotesIssue{{IssueKey: "12", Title: "crash on start", Url: "https://example.com/issues/12"}}},
+		},
+		PullRequestGroups: []*models.ReleaseNotesPrGroup{
+			{Label: "bug", PullRequests: []*models.ReleaseNotesPullRequest{{PullRequestKey: 34, Title: "fix crash", AuthorName: "alice"}}},
+		},
+		Contributors: []*models.ReleaseNotesContributor{
+			{Name: "Alice", UserName: "alice", PullRequestCount: 1, CommitCount: 2},
+			{Name: "bob@example.com", CommitCount: 1},
+		},
+	})
+	assert.Equal(t, `# v1.1.0
+
+Changes since v1.0.0, 3 commits.
+
+## Issues
+
+### BUG
+
+- [12](https://example.com/issues/12) crash on start
+
+## Pull Requests
+
+### bug
+
+- #34 fix crash by alice
+
+## Contributors
+
+- Alice (@alice)
+- bob@example.com
+`, markdown)
+}
@@ -0,0 +1,163 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/apache/incubator-devlake/server/api/shared"
+)
+
+type RestTestConnResponse struct {
+	shared.ApiBody
+}
+
+func testConnection(ctx context.Context, connection *models.RestConn) (*RestTestConnResponse, errors.Error) {
+	// validate
+	if vld != nil {
+		if err := vld.Struct(connection); err != nil {
+			return nil, errors.Default.Wrap(err, "error validating target")
+		}
+	}
+	// test connection
+	apiClient, err := api.NewApiClientFromConnection(ctx, basicRes, connection)
+	if err != nil {
+		return nil, err
+	}
+	res, err := apiClient.Get("", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the endpoint is not known at this point, so only authentication failures are treated as errors
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return nil, errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code: %d", res.StatusCode))
+	}
+	body := RestTestConnResponse{}
+	body.Success = true
+	body.Message = "success"
+	return &body, nil
+}
+
+// TestConnection test rest connection
+// @Summary test rest connection
+// @Description Test rest Connection
+// @Tags plugins/rest
+// @Param body body models.RestConnection true "json body"
+// @Success 200  {object} RestTestConnResponse "Success"
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/test [POST]
+func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.RestConn{}
+	err := api.Decode(input.Body, connection, vld)
+	if err != nil {
+		return nil, err
+	}
+	result, err := testConnection(context.TODO(), connection)
+	if err != nil {
+		return nil, plugin.WrapTestConnectionErrResp(basicRes, err)
+	}
+	return &plugin.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+}
+
+// TestExistingConnection test rest connection
+// @Summary test rest connection
+// @Description Test rest Connection
+// @Tags plugins/rest
+// @Param connectionId path int true "connection ID"
+// @Success 200  {object} RestTestConnResponse "Success"
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/connections/{connectionId}/test [POST]
+func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection, err := dsHelper.ConnApi.GetMergedConnection(input)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "find connection from db")
+	}
+	if err := api.DecodeMapStruct(input.Body, connection, false); err != nil {
+		return nil, err
+	}
+	result, err := testConnection(context.TODO(), &connection.RestConn)
+	if err != nil {
+		return nil, plugin.WrapTestConnectionErrResp(basicRes, err)
+	}
+	return &plugin.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+}
+
+// PostConnections @Summary create rest connection
+// @Description Create rest connection
+// @Tags plugins/rest
+// @Param body body models.RestConnection true "json body"
+// @Success 200  {object} models.RestConnection
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/connections [POST]
+func PostConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.Post(input)
+}
+
+// PatchConnection @Summary patch rest connection
+// @Description Patch rest connection
+// @Tags plugins/rest
+// @Param body body models.RestConnection true "json body"
+// @Success 200  {object} models.RestConnection
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/connections/{connectionId} [PATCH]
+func PatchConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.Patch(input)
+}
+
+// DeleteConnection @Summary delete a rest connection
+// @Description Delete a rest connection
+// @Tags plugins/rest
+// @Success 200  {object} models.RestConnection
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/connections/{connectionId} [DELETE]
+func DeleteConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.Delete(input)
+}
+
+// ListConnections @Summary get all rest connections
+// @Description Get all rest connections
+// @Tags plugins/rest
+// @Success 200  {object} []models.RestConnection
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/connections [GET]
+func ListConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.GetAll(input)
+}
+
+// GetConnection @Summary get rest connection detail
+// @Description Get rest connection detail
+// @Tags plugins/rest
+// @Success 200  {object} models.RestConnection
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/rest/connections/{connectionId} [GET]
+func GetConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.GetDetail(input)
+}
@@ -0,0 +1,186 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/apache/incubator-devlake/plugins/rest/tasks"
+	"gopkg.in/yaml.v3"
+)
+
+const maxMemory = 32 << 20 // 32 MB
+
+// PostScopeConfig create scope config for REST
+// @Summary create scope config for REST
+// @Description create scope config for REST
+// @Accept application/json
+// @Param connectionId path int true "connectionId"
+// @Param scopeConfig body models.RestScopeConfig true "scope config"
+// @Success 200  {object} models.RestScopeConfig
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Tags plugins/rest
+// @Router /plugins/rest/connections/{connectionId}/scope-configs [POST]
+func PostScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if err := validateEndpoints(input.Body); err != nil {
+		return nil, err
+	}
+	return dsHelper.ScopeConfigApi.Post(input)
+}
+
+// ImportScopeConfig create scope config for REST from a YAML or JSON file
+// @Summary create scope config for REST from a YAML or JSON file
+// @Description create scope config for REST from a YAML or JSON file, which has the same structure as the json body of POST scope-configs
+// @Tags plugins/rest
+// @Accept multipart/form-data
+// @Param connectionId path int true "connectionId"
+// @Param file formData file true "select file to upload"
+// @Success 200  {object} models.RestScopeConfig
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/rest/connections/{connectionId}/scope-configs/import [POST]
+func ImportScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if input.Request == nil {
+		return nil, errors.BadInput.New("file is required")
+	}
+	if input.Request.MultipartForm == nil {
+		if err := input.Request.ParseMultipartForm(maxMemory); err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	file, _, err := input.Request.FormFile("file")
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "file is required")
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	// YAML is a superset of JSON, so both of them are accepted
+	body := make(map[string]interface{})
+	if err := yaml.Unmarshal(content, &body); err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to parse the file")
+	}
+	input.Body = body
+	return PostScopeConfig(input)
+}
+
+// PatchScopeConfig update scope config for REST
+// @Summary update scope config for REST
+// @Description update scope config for REST
+// @Tags plugins/rest
+// @Accept application/json
+// @Param id path int true "id"
+// @Param connectionId path int true "connectionId"
+// @Param scopeConfig body models.RestScopeConfig true "scope config"
+// @Success 200  {object} models.RestScopeConfig
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/rest/connections/{connectionId}/scope-configs/{id} [PATCH]
+func PatchScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if err := validateEndpoints(input.Body); err != nil {
+		return nil, err
+	}
+	return dsHelper.ScopeConfigApi.Patch(input)
+}
+
+// GetScopeConfig return one scope config
+// @Summary return one scope config
+// @Description return one scope config
+// @Tags plugins/rest
+// @Param id path int true "id"
+// @Param connectionId path int true "connectionId"
+// @Success 200  {object} models.RestScopeConfig
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/rest/connections/{connectionId}/scope-configs/{id} [GET]
+func GetScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.GetDetail(input)
+}
+
+// GetScopeConfigList return all scope configs
+// @Summary return all scope configs
+// @Description return all scope configs
+// @Tags plugins/rest
+// @Param connectionId path int true "connectionId"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page size, default 1"
+// @Success 200  {object} []models.RestScopeConfig
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/rest/connections/{connectionId}/scope-configs [GET]
+func GetScopeConfigList(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.GetAll(input)
+}
+
+// GetProjectsByScopeConfig return projects details related by scope config
+// @Summary return all related projects
+// @Description return all related projects
+// @Tags plugins/rest
+// @Param scopeConfigId path int true "scopeConfigId"
+// @Success 200  {object} models.ProjectScopeOutput
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/rest/scope-config/{scopeConfigId}/projects [GET]
+func GetProjectsByScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.GetProjectsByScopeConfig(input)
+}
+
+// DeleteScopeConfig delete a scope config
+// @Summary delete a scope config
+// @Description delete a scope config
+// @Tags plugins/rest
+// @Param id path int true "id"
+// @Param connectionId path int true "connectionId"
+// @Success 200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/rest/connections/{connectionId}/scope-configs/{id} [DELETE]
+func DeleteScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.Delete(input)
+}
+
+func validateEndpoints(body map[string]interface{}) errors.Error {
+	if _, ok := body["endpoints"]; !ok {
+		return nil
+	}
+	scopeConfig := &models.RestScopeConfig{}
+	if err := api.DecodeMapStruct(body, scopeConfig, false); err != nil {
+		return errors.BadInput.Wrap(err, "invalid endpoints")
+	}
+	names := make(map[string]bool)
+	for i := range scopeConfig.Endpoints {
+		if names[scopeConfig.Endpoints[i].Name] {
+			return errors.BadInput.New("duplicate endpoint " + scopeConfig.Endpoints[i].Name)
+		}
+		names[scopeConfig.Endpoints[i].Name] = true
+		if err := vld.Struct(scopeConfig.Endpoints[i]); err != nil {
+			return errors.BadInput.Wrap(err, "invalid endpoints")
+		}
+		if err := tasks.ValidateEndpoint(&scopeConfig.Endpoints[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/impl"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/apache/incubator-devlake/plugins/rest/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+const totalRecords = 75
+
+// serverPage returns the records of the 1-based page, 30 per page whatever the page size asked for like GitHub
+func serverPage(page int) []map[string]int {
+	records := []map[string]int{}
+	for i := (page - 1) * 30; i < page*30 && i < totalRecords; i++ {
+		records = append(records, map[string]int{"id": i + 1})
+	}
+	return records
+}
+
+func TestCollectRecordsWithSmallerServerPages(t *testing.T) {
+	var plugin impl.Rest
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "rest", plugin)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		switch r.URL.Path {
+		case "/page", "/link":
+			if p := r.URL.Query().Get("page"); p != "" {
+				page, _ = strconv.Atoi(p)
+			}
+		case "/cursor":
+			if c := r.URL.Query().Get("cursor"); c != "" {
+				page, _ = strconv.Atoi(c)
+			}
+		}
+		hasNext := page*30 < totalRecords
+		if r.URL.Path == "/link" && hasNext {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/link?page=%d>; rel="next"`, server.URL, page+1))
+		}
+		body := map[string]interface{}{"items": serverPage(page)}
+		if r.URL.Path == "/cursor" && hasNext {
+			body["next"] = strconv.Itoa(page + 1)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	taskCtx := dataflowTester.SubtaskContext(nil).TaskContext()
+	apiClient, err := api.NewApiClient(taskCtx.GetContext(), server.URL, nil, 0, "", taskCtx)
+	assert.Nil(t, err)
+	asyncClient, err := api.CreateAsyncApiClient(taskCtx, apiClient, nil)
+	assert.Nil(t, err)
+	defer asyncClient.Release()
+
+	paginations := map[string]*models.PaginationDef{
+		"page":   {Type: models.PAGINATION_PAGE, PageParam: "page"},
+		"link":   {Type: models.PAGINATION_LINK},
+		"cursor": {Type: models.PAGINATION_CURSOR, PageParam: "cursor", SizeParam: "limit", CursorPath: "next"},
+	}
+	for name, pagination := range paginations {
+		t.Run(name, func(t *testing.T) {
+			taskData := &tasks.RestTaskData{
+				Options: &tasks.RestOptions{
+					ConnectionId: 1,
+					ScopeId:      "s1",
+					ScopeConfig: &models.RestScopeConfig{Endpoints: []models.EndpointDef{{
+						Name:       name,
+						Path:       "/" + name,
+						ResultPath: "items",
+						Pagination: pagination,
+						Target:     "ticket.Issue",
+						Mappings:   map[string]string{"id": "id"},
+					}}},
+				},
+				ApiClient: asyncClient,
+				Scope:     &models.RestScope{Id: "s1", Name: "s1"},
+			}
+			dataflowTester.FlushRawTable("_raw_" + tasks.RAW_RECORD_TABLE)
+			dataflowTester.Subtask(tasks.CollectRecordsMeta, taskData)
+			count, err := dataflowTester.Dal.Count(dal.From("_raw_" + tasks.RAW_RECORD_TABLE))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(totalRecords), count)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/apache/incubator-devlake/plugins/rest/models/migrationscripts"
+	"github.com/apache/incubator-devlake/plugins/rest/tasks"
+)
+
+// make sure interface is implemented
+var _ interface {
+	plugin.PluginMeta
+	plugin.PluginInit
+	plugin.PluginModel
+	plugin.PluginMigration
+	plugin.PluginTask
+	plugin.PluginApi
+	plugin.DataSourcePluginBlueprintV200
+	plugin.CloseablePluginTask
+	plugin.PluginSource
+} = (*Rest)(nil)
+
+// Rest collects records of REST APIs declared by scope configs into domain tables
+type Rest struct{}
+
+func (p Rest) Init(basicRes context.BasicRes) errors.Error {
+	api.Init(basicRes, p)
+	return nil
+}
+
+func (p Rest) Description() string {
+	return "collect data from REST APIs declared by endpoint definitions"
+}
+
+// RootPkgPath PkgPath information lost when compiled as plugin(.so)
+func (p Rest) RootPkgPath() string {
+	return "github.com/apache/incubator-devlake/plugins/rest"
+}
+
+func (p Rest) Name() string {
+	return "rest"
+}
+
+func (p Rest) Connection() dal.Tabler {
+	return &models.RestConnection{}
+}
+
+func (p Rest) Scope() plugin.ToolLayerScope {
+	return &models.RestScope{}
+}
+
+func (p Rest) ScopeConfig() dal.Tabler {
+	return &models.RestScopeConfig{}
+}
+
+func (p Rest) GetTablesInfo() []dal.Tabler {
+	return []dal.Tabler{
+		&models.RestConnection{},
+		&models.RestScope{},
+		&models.RestScopeConfig{},
+	}
+}
+
+func (p Rest) SubTaskMetas() []plugin.SubTaskMeta {
+	return []plugin.SubTaskMeta{
+		tasks.ConvertScopeMeta,
+		tasks.CollectRecordsMeta,
+		tasks.ExtractRecordsMeta,
+	}
+}
+
+func (p Rest) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]interface{}) (interface{}, errors.Error) {
+	op, err := tasks.DecodeAndValidateTaskOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	db := taskCtx.GetDal()
+	connectionHelper := helper.NewConnectionHelper(
+		taskCtx,
+		nil,
+		p.Name(),
+	)
+	connection := &models.RestConnection{}
+	err = connectionHelper.FirstById(connection, op.ConnectionId)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "unable to get REST connection by the given connection ID")
+	}
+	scope := &models.RestScope{}
+	err = db.First(scope, dal.Where("connection_id = ? AND id = ?", op.ConnectionId, op.ScopeId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("unable to find scope %s", op.ScopeId))
+	}
+	// fallback to the scope config of the scope
+	if op.ScopeConfig == nil {
+		if op.ScopeConfigId == 0 {
+			op.ScopeConfigId = scope.ScopeConfigId
+		}
+		if op.ScopeConfigId == 0 {
+			return nil, errors.BadInput.New(fmt.Sprintf("no scope config was assigned to scope %s", op.ScopeId))
+		}
+		scopeConfig := &models.RestScopeConfig{}
+		err = db.First(scopeConfig, dal.Where("id = ?", op.ScopeConfigId))
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "fail to load scopeConfig")
+		}
+		op.ScopeConfig = scopeConfig
+	}
+	for i := range op.ScopeConfig.Endpoints {
+		if err := tasks.ValidateEndpoint(&op.ScopeConfig.Endpoints[i]); err != nil {
+			return nil, err
+		}
+	}
+	apiClient, err := helper.NewApiClientFromConnection(taskCtx.GetContext(), taskCtx, connection)
+	if err != nil {
+		return nil, err
+	}
+	asyncClient, err := helper.CreateAsyncApiClient(taskCtx, apiClient, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &tasks.RestTaskData{
+		Options:   op,
+		ApiClient: asyncClient,
+		Scope:     scope,
+	}, nil
+}
+
+func (p Rest) MigrationScripts() []plugin.MigrationScript {
+	return migrationscripts.All()
+}
+
+func (p Rest) ApiResources() map[string]map[string]plugin.ApiResourceHandler {
+	return map[string]map[string]plugin.ApiResourceHandler{
+		"test": {
+			"POST": api.TestConnection,
+		},
+		"connections": {
+			"POST": api.PostConnections,
+			"GET":  api.ListConnections,
+		},
+		"connections/:connectionId": {
+			"GET":    api.GetConnection,
+			"PATCH":  api.PatchConnection,
+			"DELETE": api.DeleteConnection,
+		},
+		"connections/:connectionId/test": {
+			"POST": api.TestExistingConnection,
+		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopes,
+			"PUT": api.PutScopes,
+		},
+		"connections/:connectionId/scopes/:scopeId": {
+			"GET":    api.GetScope,
+			"PATCH":  api.PatchScope,
+			"DELETE": api.DeleteScope,
+		},
+		"connections/:connectionId/scopes/:scopeId/latest-sync-state": {
+			"GET": api.GetScopeLatestSyncState,
+		},
+		"connections/:connectionId/scope-configs": {
+			"POST": api.PostScopeConfig,
+			"GET":  api.GetScopeConfigList,
+		},
+		"connections/:connectionId/scope-configs/import": {
+			"POST": api.ImportScopeConfig,
+		},
+		"connections/:connectionId/scope-configs/:scopeConfigId": {
+			"PATCH":  api.PatchScopeConfig,
+			"GET":    api.GetScopeConfig,
+			"DELETE": api.DeleteScopeConfig,
+		},
+		"scope-config/:scopeConfigId/projects": {
+			"GET": api.GetProjectsByScopeConfig,
+		},
+	}
+}
+
+func (p Rest) MakeDataSourcePipelinePlanV200(
+	connectionId uint64,
+	scopes []*coreModels.BlueprintScope,
+) (coreModels.PipelinePlan, []plugin.Scope, errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(p.SubTaskMetas(), connectionId, scopes)
+}
+
+func (p Rest) Close(taskCtx plugin.TaskContext) errors.Error {
+	data, ok := taskCtx.GetData().(*tasks.RestTaskData)
+	if !ok {
+		return errors.Default.New(fmt.Sprintf("GetData failed when try to close %+v", taskCtx))
+	}
+	data.ApiClient.Release()
+	return nil
+}
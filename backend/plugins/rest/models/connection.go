@@ -0,0 +1,90 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/utils"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+// RestConn holds the essential information to connect to a REST API, AuthMethod is optional since internal
+// tools might not require authentication at all
+type RestConn struct {
+	helper.RestConnection `mapstructure:",squash"`
+	AuthMethod            string `mapstructure:"authMethod" json:"authMethod" validate:"omitempty,oneof=BasicAuth AccessToken"`
+	Username              string `mapstructure:"username" json:"username"`
+	Password              string `mapstructure:"password" json:"password" gorm:"serializer:encdec"`
+	Token                 string `mapstructure:"token" json:"token" gorm:"serializer:encdec"`
+	// TokenHeader is the header carrying the Token, it would be sent as `Authorization: Bearer <Token>` if empty
+	TokenHeader string `mapstructure:"tokenHeader" json:"tokenHeader" gorm:"type:varchar(255)"`
+}
+
+// SetupAuthentication implements the `IAuthentication` interface
+func (rc *RestConn) SetupAuthentication(req *http.Request) errors.Error {
+	switch rc.AuthMethod {
+	case plugin.AUTH_METHOD_BASIC:
+		req.SetBasicAuth(rc.Username, rc.Password)
+	case plugin.AUTH_METHOD_TOKEN:
+		if rc.TokenHeader == "" {
+			req.Header.Set("Authorization", "Bearer "+rc.Token)
+		} else {
+			req.Header.Set(rc.TokenHeader, rc.Token)
+		}
+	}
+	return nil
+}
+
+func (rc RestConn) Sanitize() RestConn {
+	rc.Password = ""
+	rc.Token = utils.SanitizeString(rc.Token)
+	return rc
+}
+
+// RestConnection holds RestConn plus ID/Name for database storage
+type RestConnection struct {
+	helper.BaseConnection `mapstructure:",squash"`
+	RestConn              `mapstructure:",squash"`
+}
+
+func (RestConnection) TableName() string {
+	return "_tool_rest_connections"
+}
+
+func (connection RestConnection) Sanitize() RestConnection {
+	connection.RestConn = connection.RestConn.Sanitize()
+	return connection
+}
+
+func (connection *RestConnection) MergeFromRequest(target *RestConnection, body map[string]interface{}) error {
+	token := target.Token
+	password := target.Password
+	if err := helper.DecodeMapStruct(body, target, true); err != nil {
+		return err
+	}
+	if target.Token == "" || target.Token == utils.SanitizeString(token) {
+		target.Token = token
+	}
+	if target.Password == "" {
+		target.Password = password
+	}
+	return nil
+}
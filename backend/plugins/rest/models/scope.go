@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.ToolLayerScope = (*RestScope)(nil)
+
+// RestScope is a set of records to be collected by the Endpoints of its scope config, Variables are
+// exposed to the templates of the Endpoints, i.e. `/projects/{{ .Variables.project }}/issues`
+type RestScope struct {
+	common.Scope `mapstructure:",squash"`
+	Id           string            `gorm:"primaryKey;type:varchar(255)" json:"id" mapstructure:"id" validate:"required"`
+	Name         string            `gorm:"type:varchar(255)" json:"name" mapstructure:"name" validate:"required"`
+	Variables    map[string]string `gorm:"type:json;serializer:json" json:"variables" mapstructure:"variables"`
+}
+
+func (RestScope) TableName() string {
+	return "_tool_rest_scopes"
+}
+
+// ScopeId implements plugin.ToolLayerScope.
+func (s RestScope) ScopeId() string {
+	return s.Id
+}
+
+// ScopeName implements plugin.ToolLayerScope.
+func (s RestScope) ScopeName() string {
+	return s.Name
+}
+
+// ScopeFullName implements plugin.ToolLayerScope.
+func (s RestScope) ScopeFullName() string {
+	return s.Name
+}
+
+// ScopeParams implements plugin.ToolLayerScope.
+func (s RestScope) ScopeParams() interface{} {
+	return &RestApiParams{
+		ConnectionId: s.ConnectionId,
+		ScopeId:      s.Id,
+	}
+}
+
+// RestApiParams identifies the raw records of an Endpoint of a scope
+type RestApiParams struct {
+	ConnectionId uint64
+	ScopeId      string
+	Endpoint     string `json:",omitempty"`
+}
@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+const (
+	PAGINATION_NONE   = "none"
+	PAGINATION_PAGE   = "page"
+	PAGINATION_OFFSET = "offset"
+	PAGINATION_CURSOR = "cursor"
+	PAGINATION_LINK   = "link"
+)
+
+// RestScopeConfig describes how the records of its scopes are collected and which domain tables they go to
+type RestScopeConfig struct {
+	common.ScopeConfig `mapstructure:",squash" json:",inline" gorm:"embedded"`
+	Endpoints          []EndpointDef `gorm:"type:json;serializer:json" json:"endpoints" mapstructure:"endpoints" validate:"dive"`
+}
+
+func (RestScopeConfig) TableName() string {
+	return "_tool_rest_scope_configs"
+}
+
+func (t *RestScopeConfig) SetConnectionId(c *RestScopeConfig, connectionId uint64) {
+	c.ConnectionId = connectionId
+	c.ScopeConfig.ConnectionId = connectionId
+}
+
+// EndpointDef declares an API endpoint and how its records are mapped to a domain table. Path and Query
+// values are go templates, with `.Scope`, `.Variables` (of the scope) available
+type EndpointDef struct {
+	// Name identifies the raw records of the endpoint, and is part of the ids of the domain entities
+	Name  string            `json:"name" mapstructure:"name" validate:"required"`
+	Path  string            `json:"path" mapstructure:"path" validate:"required" example:"/api/projects/{{ .Variables.project }}/tickets"`
+	Query map[string]string `json:"query" mapstructure:"query"`
+	// ResultPath is a JSON path of the records within the response, the response itself is used if empty
+	ResultPath  string          `json:"resultPath" mapstructure:"resultPath" example:"data.items"`
+	Pagination  *PaginationDef  `json:"pagination" mapstructure:"pagination"`
+	Incremental *IncrementalDef `json:"incremental" mapstructure:"incremental"`
+	// Target is the domain table the records go to, in the form of `<package>.<Type>`
+	Target string `json:"target" mapstructure:"target" validate:"required" example:"ticket.Issue"`
+	// Mappings maps fields of the Target to JSON paths of a record, `id` is required
+	Mappings map[string]string `json:"mappings" mapstructure:"mappings" validate:"required"`
+	// Defaults are used for fields of the Target not resolved by Mappings
+	Defaults map[string]interface{} `json:"defaults" mapstructure:"defaults"`
+}
+
+// PaginationDef declares how to iterate the pages of an endpoint
+type PaginationDef struct {
+	Type string `json:"type" mapstructure:"type" validate:"omitempty,oneof=none page offset cursor link"`
+	// PageParam is the query parameter of the page number(page), the offset(offset) or the cursor(cursor)
+	PageParam string `json:"pageParam" mapstructure:"pageParam"`
+	SizeParam string `json:"sizeParam" mapstructure:"sizeParam"`
+	PageSize  int    `json:"pageSize" mapstructure:"pageSize"`
+	// CursorPath is a JSON path of the next cursor within the response
+	CursorPath string `json:"cursorPath" mapstructure:"cursorPath"`
+}
+
+// IncrementalDef declares the query parameter to collect records updated after the previous collection
+type IncrementalDef struct {
+	Param string `json:"param" mapstructure:"param" validate:"required" example:"updated_since"`
+	// TimeFormat is a go time layout, RFC3339 is used if empty, `unix` and `unixMilli` are supported as well
+	TimeFormat string `json:"timeFormat" mapstructure:"timeFormat"`
+}
@@ -0,0 +1,209 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/tidwall/gjson"
+)
+
+const defaultPageSize = 100
+
+var _ plugin.SubTaskEntryPoint = CollectRecords
+
+var CollectRecordsMeta = plugin.SubTaskMeta{
+	Name:             "collectRecords",
+	EntryPoint:       CollectRecords,
+	EnabledByDefault: true,
+	Description:      "collect records of all endpoints declared by the scope config",
+	DomainTypes:      plugin.DOMAIN_TYPES,
+}
+
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// endpointTemplateData is exposed to the templates of an EndpointDef
+type endpointTemplateData struct {
+	Scope     *models.RestScope
+	Variables map[string]string
+}
+
+func CollectRecords(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*RestTaskData)
+	for i := range data.Options.ScopeConfig.Endpoints {
+		endpoint := &data.Options.ScopeConfig.Endpoints[i]
+		taskCtx.GetLogger().Info("collect records of endpoint %s", endpoint.Name)
+		if err := collectEndpoint(taskCtx, endpoint); err != nil {
+			return errors.Default.Wrap(err, "failed to collect endpoint "+endpoint.Name)
+		}
+	}
+	return nil
+}
+
+func collectEndpoint(taskCtx plugin.SubTaskContext, endpoint *models.EndpointDef) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, endpoint.Name)
+	tplData := &endpointTemplateData{Scope: data.Scope, Variables: data.Scope.Variables}
+	path, err := RenderTemplate(endpoint.Path, tplData)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	for key, value := range endpoint.Query {
+		rendered, err := RenderTemplate(value, tplData)
+		if err != nil {
+			return err
+		}
+		query.Set(key, rendered)
+	}
+	pagination := endpoint.Pagination
+	if pagination == nil {
+		pagination = &models.PaginationDef{Type: models.PAGINATION_NONE}
+	}
+	args := api.ApiCollectorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		ApiClient:          data.ApiClient,
+		UrlTemplate:        path,
+		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				return nil, errors.Convert(err)
+			}
+			return ParseRecords(body, endpoint.ResultPath)
+		},
+	}
+	pageSize := pagination.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	// the collector stops at the first page holding less records than args.PageSize, which only holds when
+	// the size is sent with the requests (or declared for offsets), otherwise pages are fetched until an empty one
+	switch pagination.Type {
+	case models.PAGINATION_PAGE:
+		args.PageSize = 1
+		if pagination.SizeParam != "" {
+			args.PageSize = pageSize
+		}
+	case models.PAGINATION_OFFSET:
+		args.PageSize = pageSize
+	case models.PAGINATION_CURSOR:
+		// the server may return less records than asked for, stop on an empty page or a missing cursor only
+		args.PageSize = 1
+		args.GetNextPageCustomData = func(_ *api.RequestData, res *http.Response) (interface{}, errors.Error) {
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				return nil, errors.Convert(err)
+			}
+			cursor := gjson.GetBytes(body, pagination.CursorPath).String()
+			if cursor == "" {
+				return nil, api.ErrFinishCollect
+			}
+			return cursor, nil
+		}
+	case models.PAGINATION_LINK:
+		// stop on an empty page or a missing next link only, the next link is a full url carrying all query parameters
+		args.PageSize = 1
+		args.UrlTemplate = "{{ if .CustomData }}{{ .CustomData }}{{ else }}" + path + "{{ end }}"
+		args.GetNextPageCustomData = func(_ *api.RequestData, res *http.Response) (interface{}, errors.Error) {
+			next := GetNextLink(res.Header.Get("Link"))
+			if next == "" {
+				return nil, api.ErrFinishCollect
+			}
+			return next, nil
+		}
+	}
+
+	var stateManager *api.CollectorStateManager
+	buildQuery := func(reqData *api.RequestData) (url.Values, errors.Error) {
+		if pagination.Type == models.PAGINATION_LINK && reqData.CustomData != nil {
+			return nil, nil
+		}
+		q := url.Values{}
+		for key, values := range query {
+			q[key] = values
+		}
+		if stateManager != nil && stateManager.GetSince() != nil {
+			q.Set(endpoint.Incremental.Param, FormatTime(*stateManager.GetSince(), endpoint.Incremental.TimeFormat))
+		}
+		if pagination.SizeParam != "" {
+			q.Set(pagination.SizeParam, strconv.Itoa(pageSize))
+		}
+		switch pagination.Type {
+		case models.PAGINATION_PAGE:
+			q.Set(pagination.PageParam, strconv.Itoa(reqData.Pager.Page))
+		case models.PAGINATION_OFFSET:
+			q.Set(pagination.PageParam, strconv.Itoa(reqData.Pager.Skip))
+		case models.PAGINATION_CURSOR:
+			if cursor, ok := reqData.CustomData.(string); ok && cursor != "" {
+				q.Set(pagination.PageParam, cursor)
+			}
+		}
+		return q, nil
+	}
+	args.Query = buildQuery
+
+	// endpoints without an incremental parameter are fully collected every time
+	if endpoint.Incremental == nil {
+		collector, err := api.NewApiCollector(args)
+		if err != nil {
+			return err
+		}
+		return collector.Execute()
+	}
+	collector, err := api.NewStatefulApiCollector(*rawDataSubTaskArgs)
+	if err != nil {
+		return err
+	}
+	stateManager = &collector.CollectorStateManager
+	err = collector.InitCollector(args)
+	if err != nil {
+		return err
+	}
+	return collector.Execute()
+}
+
+// GetNextLink returns the url of rel="next" within the Link header
+func GetNextLink(linkHeader string) string {
+	matches := linkNextPattern.FindStringSubmatch(linkHeader)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// FormatTime formats t by layout, `unix` and `unixMilli` for epoch seconds and milliseconds
+func FormatTime(t time.Time, layout string) string {
+	switch layout {
+	case "":
+		return t.UTC().Format(time.RFC3339)
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixMilli":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return t.UTC().Format(layout)
+}
@@ -0,0 +1,97 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ExtractRecords
+
+var ExtractRecordsMeta = plugin.SubTaskMeta{
+	Name:             "extractRecords",
+	EntryPoint:       ExtractRecords,
+	EnabledByDefault: true,
+	Description:      "map raw records of all endpoints to their target domain tables",
+	DomainTypes:      plugin.DOMAIN_TYPES,
+	Dependencies:     []*plugin.SubTaskMeta{&CollectRecordsMeta},
+}
+
+// scopeFields are filled with the id of the scope if they were not mapped
+var scopeFields = []string{"CicdScopeId", "BoardId", "RepoId", "BaseRepoId", "HeadRepoId"}
+
+func ExtractRecords(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*RestTaskData)
+	for i := range data.Options.ScopeConfig.Endpoints {
+		endpoint := &data.Options.ScopeConfig.Endpoints[i]
+		if err := extractEndpoint(taskCtx, endpoint); err != nil {
+			return errors.Default.Wrap(err, "failed to extract endpoint "+endpoint.Name)
+		}
+	}
+	return nil
+}
+
+func extractEndpoint(taskCtx plugin.SubTaskContext, endpoint *models.EndpointDef) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, endpoint.Name)
+	targetType, err := GetTargetType(endpoint.Target)
+	if err != nil {
+		return err
+	}
+	scopeDomainId := GenerateScopeDomainId(data.Options.ConnectionId, data.Options.ScopeId)
+	extractor, err := api.NewApiExtractor(api.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *api.RawData) ([]interface{}, errors.Error) {
+			entity, recordId, err := MapRecord(row.Data, endpoint, targetType)
+			if err != nil {
+				return nil, err
+			}
+			id := GenerateDomainId(endpoint.Name, data.Options.ConnectionId, recordId)
+			value := reflect.ValueOf(entity).Elem()
+			value.FieldByName("Id").SetString(id)
+			for _, name := range scopeFields {
+				field := value.FieldByName(name)
+				if field.IsValid() && field.Kind() == reflect.String && field.String() == "" {
+					field.SetString(scopeDomainId)
+				}
+			}
+			results := []interface{}{entity}
+			switch e := entity.(type) {
+			case *ticket.Issue:
+				results = append(results, &ticket.BoardIssue{BoardId: scopeDomainId, IssueId: e.Id})
+			case *ticket.Sprint:
+				results = append(results, &ticket.BoardSprint{BoardId: scopeDomainId, SprintId: e.Id})
+			case *ticket.Incident:
+				if e.ScopeId == "" {
+					e.ScopeId = scopeDomainId
+					e.Table = "boards"
+				}
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return extractor.Execute()
+}
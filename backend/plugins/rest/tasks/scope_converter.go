@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertScope
+
+var ConvertScopeMeta = plugin.SubTaskMeta{
+	Name:             "convertScope",
+	EntryPoint:       ConvertScope,
+	EnabledByDefault: true,
+	Description:      "convert the scope into boards, cicd_scopes or repos depending on targets of the endpoints",
+	DomainTypes:      plugin.DOMAIN_TYPES,
+}
+
+// MakeScopeDomainEntities returns a board, a cicd_scope or a repo for each domain type targeted by the endpoints
+func MakeScopeDomainEntities(scope *models.RestScope, scopeConfig *models.RestScopeConfig) []plugin.Scope {
+	id := GenerateScopeDomainId(scope.ConnectionId, scope.Id)
+	domainTypes := make(map[string]bool)
+	for _, endpoint := range scopeConfig.Endpoints {
+		domainTypes[GetTargetDomainType(endpoint.Target)] = true
+	}
+	var scopes []plugin.Scope
+	if domainTypes[plugin.DOMAIN_TYPE_TICKET] {
+		scopes = append(scopes, ticket.NewBoard(id, scope.Name))
+	}
+	if domainTypes[plugin.DOMAIN_TYPE_CICD] {
+		scopes = append(scopes, devops.NewCicdScope(id, scope.Name))
+	}
+	if domainTypes[plugin.DOMAIN_TYPE_CODE] {
+		scopes = append(scopes, code.NewRepo(id, scope.Name))
+	}
+	return scopes
+}
+
+func ConvertScope(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, "")
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.RestScope{}),
+		dal.Where("connection_id = ? AND id = ?", data.Options.ConnectionId, data.Options.ScopeId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		InputRowType:       reflect.TypeOf(models.RestScope{}),
+		Input:              cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			scope := inputRow.(*models.RestScope)
+			var results []interface{}
+			for _, entity := range MakeScopeDomainEntities(scope, data.Options.ScopeConfig) {
+				results = append(results, entity)
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return converter.Execute()
+}
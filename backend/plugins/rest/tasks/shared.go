@@ -0,0 +1,223 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/domaininfo"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/tidwall/gjson"
+)
+
+const RAW_RECORD_TABLE = "rest_api_records"
+
+var scopeIdGen *didgen.DomainIdGenerator
+var targetTypes map[string]reflect.Type
+
+func getScopeIdGen() *didgen.DomainIdGenerator {
+	if scopeIdGen == nil {
+		scopeIdGen = didgen.NewDomainIdGenerator(&models.RestScope{})
+	}
+	return scopeIdGen
+}
+
+// GenerateScopeDomainId returns the id of the board/cicd_scope/repo converted from the scope
+func GenerateScopeDomainId(connectionId uint64, scopeId string) string {
+	return getScopeIdGen().Generate(connectionId, scopeId)
+}
+
+// GenerateDomainId returns the id of the domain entity of a record collected by the endpoint
+func GenerateDomainId(endpoint string, connectionId uint64, recordId string) string {
+	return fmt.Sprintf("rest:%s:%d:%s", endpoint, connectionId, recordId)
+}
+
+// GetTargetType resolves the `<package>.<Type>` target to a domain table with a string `Id`
+func GetTargetType(target string) (reflect.Type, errors.Error) {
+	if targetTypes == nil {
+		targetTypes = make(map[string]reflect.Type)
+		for _, table := range domaininfo.GetDomainTablesInfo() {
+			t := reflect.TypeOf(table).Elem()
+			if id, ok := t.FieldByName("Id"); ok && id.Type.Kind() == reflect.String {
+				targetTypes[t.String()] = t
+			}
+		}
+	}
+	t, ok := targetTypes[target]
+	if !ok {
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported target %s", target))
+	}
+	return t, nil
+}
+
+// GetTargetDomainType returns the domain type of the target, i.e. TICKET for ticket.Issue
+func GetTargetDomainType(target string) string {
+	switch strings.SplitN(target, ".", 2)[0] {
+	case "ticket":
+		return plugin.DOMAIN_TYPE_TICKET
+	case "devops":
+		return plugin.DOMAIN_TYPE_CICD
+	case "code":
+		return plugin.DOMAIN_TYPE_CODE
+	case "codequality":
+		return plugin.DOMAIN_TYPE_CODE_QUALITY
+	}
+	return plugin.DOMAIN_TYPE_CROSS
+}
+
+// ValidateEndpoint makes sure the endpoint could be collected and mapped to its target
+func ValidateEndpoint(endpoint *models.EndpointDef) errors.Error {
+	targetType, err := GetTargetType(endpoint.Target)
+	if err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid endpoint %s", endpoint.Name))
+	}
+	if _, ok := endpoint.Mappings["id"]; !ok {
+		return errors.BadInput.New(fmt.Sprintf("mapping of id is required for endpoint %s", endpoint.Name))
+	}
+	for field := range endpoint.Mappings {
+		if !hasField(targetType, field) {
+			return errors.BadInput.New(fmt.Sprintf("%s has no field %s, endpoint %s", endpoint.Target, field, endpoint.Name))
+		}
+	}
+	for field := range endpoint.Defaults {
+		if !hasField(targetType, field) {
+			return errors.BadInput.New(fmt.Sprintf("%s has no field %s, endpoint %s", endpoint.Target, field, endpoint.Name))
+		}
+	}
+	if _, err := template.New(endpoint.Name).Parse(endpoint.Path); err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid path of endpoint %s", endpoint.Name))
+	}
+	if endpoint.Pagination != nil {
+		switch endpoint.Pagination.Type {
+		case models.PAGINATION_PAGE, models.PAGINATION_OFFSET:
+			if endpoint.Pagination.PageParam == "" {
+				return errors.BadInput.New(fmt.Sprintf("pageParam is required for endpoint %s", endpoint.Name))
+			}
+			// offsets are computed from the page size, which must match the one of the server if it is not sent
+			if endpoint.Pagination.Type == models.PAGINATION_OFFSET && endpoint.Pagination.SizeParam == "" && endpoint.Pagination.PageSize <= 0 {
+				return errors.BadInput.New(fmt.Sprintf("sizeParam or pageSize is required for endpoint %s", endpoint.Name))
+			}
+		case models.PAGINATION_CURSOR:
+			if endpoint.Pagination.PageParam == "" || endpoint.Pagination.CursorPath == "" {
+				return errors.BadInput.New(fmt.Sprintf("pageParam and cursorPath are required for endpoint %s", endpoint.Name))
+			}
+		}
+	}
+	return nil
+}
+
+func hasField(t reflect.Type, name string) bool {
+	_, ok := t.FieldByNameFunc(func(n string) bool {
+		return strings.EqualFold(n, name)
+	})
+	return ok
+}
+
+// RenderTemplate renders the text as a go template with data
+func RenderTemplate(text string, data interface{}) (string, errors.Error) {
+	tpl, err := template.New("").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", errors.BadInput.Wrap(err, fmt.Sprintf("invalid template %s", text))
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", errors.BadInput.Wrap(err, fmt.Sprintf("failed to render template %s", text))
+	}
+	return buf.String(), nil
+}
+
+// ParseRecords returns records located by the resultPath within the body, a single object is treated
+// as one record
+func ParseRecords(body []byte, resultPath string) ([]json.RawMessage, errors.Error) {
+	var result gjson.Result
+	if resultPath == "" {
+		if !gjson.ValidBytes(body) {
+			return nil, errors.Default.New("response is not a valid json")
+		}
+		result = gjson.ParseBytes(body)
+	} else {
+		result = gjson.GetBytes(body, resultPath)
+	}
+	if result.IsArray() {
+		items := result.Array()
+		records := make([]json.RawMessage, 0, len(items))
+		for _, item := range items {
+			records = append(records, json.RawMessage(item.Raw))
+		}
+		return records, nil
+	}
+	if result.IsObject() {
+		return []json.RawMessage{json.RawMessage(result.Raw)}, nil
+	}
+	return nil, nil
+}
+
+// MapRecord maps the record to a new entity of targetType by Mappings and Defaults of the endpoint,
+// the id of the record is returned as well
+func MapRecord(record []byte, endpoint *models.EndpointDef, targetType reflect.Type) (interface{}, string, errors.Error) {
+	values := make(map[string]interface{}, len(endpoint.Mappings)+len(endpoint.Defaults))
+	for field, value := range endpoint.Defaults {
+		values[field] = value
+	}
+	var recordId string
+	for field, path := range endpoint.Mappings {
+		result := gjson.GetBytes(record, path)
+		if !result.Exists() || result.Type == gjson.Null {
+			continue
+		}
+		if strings.EqualFold(field, "id") {
+			recordId = result.String()
+			continue
+		}
+		if result.IsObject() || result.IsArray() {
+			values[field] = result.Raw
+		} else {
+			values[field] = result.Value()
+		}
+	}
+	if recordId == "" {
+		return nil, "", errors.BadInput.New(fmt.Sprintf("id not found by %s", endpoint.Mappings["id"]))
+	}
+	entity := reflect.New(targetType).Interface()
+	if err := api.DecodeMapStruct(values, entity, true); err != nil {
+		return nil, "", errors.BadInput.Wrap(err, fmt.Sprintf("failed to map record %s of endpoint %s", recordId, endpoint.Name))
+	}
+	return entity, recordId, nil
+}
+
+func CreateRawDataSubTaskArgs(taskCtx plugin.SubTaskContext, endpoint string) (*api.RawDataSubTaskArgs, *RestTaskData) {
+	data := taskCtx.GetData().(*RestTaskData)
+	return &api.RawDataSubTaskArgs{
+		Ctx: taskCtx,
+		Params: models.RestApiParams{
+			ConnectionId: data.Options.ConnectionId,
+			ScopeId:      data.Options.ScopeId,
+			Endpoint:     endpoint,
+		},
+		Table: RAW_RECORD_TABLE,
+	}, data
+}
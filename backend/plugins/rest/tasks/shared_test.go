@@ -0,0 +1,155 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecords(t *testing.T) {
+	records, err := ParseRecords([]byte(`{"data":{"items":[{"id":1},{"id":2}]}}`), "data.items")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.JSONEq(t, `{"id":2}`, string(records[1]))
+
+	records, err = ParseRecords([]byte(`[{"id":1}]`), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+
+	records, err = ParseRecords([]byte(`{"id":1}`), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+
+	records, err = ParseRecords([]byte(`{"data":[]}`), "items")
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	_, err = ParseRecords([]byte(`not json`), "")
+	assert.NotNil(t, err)
+}
+
+func TestMapRecord(t *testing.T) {
+	endpoint := &models.EndpointDef{
+		Name:   "tickets",
+		Target: "ticket.Issue",
+		Mappings: map[string]string{
+			"id":          "key",
+			"title":       "fields.summary",
+			"storyPoint":  "fields.points",
+			"createdDate": "fields.created",
+			"component":   "fields.components",
+			"priority":    "fields.missing",
+		},
+		Defaults: map[string]interface{}{
+			"type":     ticket.REQUIREMENT,
+			"priority": "LOW",
+		},
+	}
+	assert.Nil(t, ValidateEndpoint(endpoint))
+	targetType, err := GetTargetType(endpoint.Target)
+	assert.Nil(t, err)
+	entity, recordId, err := MapRecord([]byte(`{
+		"key": 42,
+		"fields": {
+			"summary": "Fix login",
+			"points": 3,
+			"created": "2026-10-01T08:00:00Z",
+			"components": ["api", "web"],
+			"missing": null
+		}
+	}`), endpoint, targetType)
+	assert.Nil(t, err)
+	assert.Equal(t, "42", recordId)
+	issue := entity.(*ticket.Issue)
+	assert.Equal(t, "Fix login", issue.Title)
+	assert.Equal(t, 3.0, *issue.StoryPoint)
+	assert.Equal(t, time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC), issue.CreatedDate.UTC())
+	assert.Equal(t, `["api", "web"]`, issue.Component)
+	assert.Equal(t, ticket.REQUIREMENT, issue.Type)
+	assert.Equal(t, "LOW", issue.Priority)
+
+	_, _, err = MapRecord([]byte(`{"fields":{}}`), endpoint, targetType)
+	assert.NotNil(t, err)
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	endpoint := models.EndpointDef{
+		Name:     "deployments",
+		Path:     "/deployments",
+		Target:   "devops.CICDDeployment",
+		Mappings: map[string]string{"id": "id", "environment": "env"},
+	}
+	assert.Nil(t, ValidateEndpoint(&endpoint))
+	targetType, _ := GetTargetType(endpoint.Target)
+	assert.Equal(t, reflect.TypeOf(devops.CICDDeployment{}), targetType)
+
+	unknownTarget := endpoint
+	unknownTarget.Target = "devops.Unknown"
+	assert.NotNil(t, ValidateEndpoint(&unknownTarget))
+
+	noId := endpoint
+	noId.Mappings = map[string]string{"environment": "env"}
+	assert.NotNil(t, ValidateEndpoint(&noId))
+
+	unknownField := endpoint
+	unknownField.Mappings = map[string]string{"id": "id", "foo": "bar"}
+	assert.NotNil(t, ValidateEndpoint(&unknownField))
+
+	noCursorPath := endpoint
+	noCursorPath.Pagination = &models.PaginationDef{Type: models.PAGINATION_CURSOR, PageParam: "cursor"}
+	assert.NotNil(t, ValidateEndpoint(&noCursorPath))
+
+	noPageSize := endpoint
+	noPageSize.Pagination = &models.PaginationDef{Type: models.PAGINATION_OFFSET, PageParam: "offset"}
+	assert.NotNil(t, ValidateEndpoint(&noPageSize))
+	noPageSize.Pagination = &models.PaginationDef{Type: models.PAGINATION_OFFSET, PageParam: "offset", PageSize: 30}
+	assert.Nil(t, ValidateEndpoint(&noPageSize))
+}
+
+func TestRenderTemplate(t *testing.T) {
+	scope := &models.RestScope{Id: "p1", Variables: map[string]string{"project": "DEVLAKE"}}
+	path, err := RenderTemplate("/projects/{{ .Variables.project }}/{{ .Scope.Id }}/issues", &endpointTemplateData{
+		Scope:     scope,
+		Variables: scope.Variables,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "/projects/DEVLAKE/p1/issues", path)
+}
+
+func TestGetNextLink(t *testing.T) {
+	assert.Equal(t, "https://example.com/items?page=3", GetNextLink(
+		`<https://example.com/items?page=1>; rel="prev", <https://example.com/items?page=3>; rel="next", <https://example.com/items?page=9>; rel="last"`,
+	))
+	assert.Equal(t, "", GetNextLink(`<https://example.com/items?page=1>; rel="prev"`))
+	assert.Equal(t, "", GetNextLink(""))
+}
+
+func TestFormatTime(t *testing.T) {
+	tm := time.Date(2026, 10, 17, 1, 2, 3, 0, time.UTC)
+	assert.Equal(t, "2026-10-17T01:02:03Z", FormatTime(tm, ""))
+	assert.Equal(t, "1792198923", FormatTime(tm, "unix"))
+	assert.Equal(t, "1792198923000", FormatTime(tm, "unixMilli"))
+	assert.Equal(t, "2026-10-17", FormatTime(tm, "2006-01-02"))
+}
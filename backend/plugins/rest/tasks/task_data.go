@@ -0,0 +1,51 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/apache/incubator-devlake/core/errors"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/rest/models"
+)
+
+type RestOptions struct {
+	ConnectionId  uint64                  `json:"connectionId" mapstructure:"connectionId"`
+	ScopeId       string                  `json:"scopeId" mapstructure:"scopeId"`
+	ScopeConfigId uint64                  `json:"scopeConfigId,omitempty" mapstructure:"scopeConfigId,omitempty"`
+	ScopeConfig   *models.RestScopeConfig `json:"scopeConfig,omitempty" mapstructure:"scopeConfig,omitempty"`
+}
+
+type RestTaskData struct {
+	Options   *RestOptions
+	ApiClient *helper.ApiAsyncClient
+	Scope     *models.RestScope
+}
+
+func DecodeAndValidateTaskOptions(options map[string]interface{}) (*RestOptions, errors.Error) {
+	var op RestOptions
+	if err := helper.Decode(options, &op, nil); err != nil {
+		return nil, err
+	}
+	if op.ConnectionId == 0 {
+		return nil, errors.BadInput.New("connectionId is invalid")
+	}
+	if op.ScopeId == "" {
+		return nil, errors.BadInput.New("scopeId is required")
+	}
+	return &op, nil
+}
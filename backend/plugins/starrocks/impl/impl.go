@@ -22,6 +22,8 @@ import (
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/starrocks/models"
+	"github.com/apache/incubator-devlake/plugins/starrocks/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/starrocks/tasks"
 )
 
@@ -32,6 +34,7 @@ var _ interface {
 	plugin.PluginMeta
 	plugin.PluginTask
 	plugin.PluginModel
+	plugin.PluginMigration
 } = (*StarRocks)(nil)
 
 func (s StarRocks) SubTaskMetas() []plugin.SubTaskMeta {
@@ -53,7 +56,13 @@ func (s StarRocks) PrepareTaskData(taskCtx plugin.TaskContext, options map[strin
 }
 
 func (s StarRocks) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.ExportState{},
+	}
+}
+
+func (s StarRocks) MigrationScripts() []plugin.MigrationScript {
+	return migrationscripts.All()
 }
 
 func (s StarRocks) Description() string {
@@ -0,0 +1,39 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// ExportState records the progress of the incremental export of a table to a sink
+type ExportState struct {
+	// Sink identifies the destination, e.g. `clickhouse:http://localhost:8123/lake`
+	Sink          string `gorm:"primaryKey;type:varchar(255)"`
+	ExportedTable string `gorm:"primaryKey;type:varchar(255)"`
+	// Watermark is the greatest value of the update column exported so far
+	Watermark  *time.Time
+	ExportedAt time.Time
+	common.NoPKModel
+}
+
+func (ExportState) TableName() string {
+	return "_tool_starrocks_export_states"
+}
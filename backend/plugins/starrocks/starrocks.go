@@ -50,6 +50,13 @@ func main() {
 	_ = cmd.MarkFlagRequired("batch_size")
 	extra := cmd.Flags().StringP("extra", "e", "", "StarRocks create table sql extra")
 	orderBy := cmd.Flags().StringP("order_by", "o", "", "Source tables order by, default is primary key")
+	sinkType := cmd.Flags().String("sink_type", "", "Sink type: starrocks (default), clickhouse or parquet")
+	incremental := cmd.Flags().Bool("incremental", false, "Export only rows updated since the last export and propagate deletes")
+	clickhouseUrl := cmd.Flags().String("clickhouse_url", "", "ClickHouse HTTP url, ie http://localhost:8123")
+	clickhouseUser := cmd.Flags().String("clickhouse_user", "", "ClickHouse user")
+	clickhousePassword := cmd.Flags().String("clickhouse_password", "", "ClickHouse password")
+	clickhouseDatabase := cmd.Flags().String("clickhouse_database", "", "ClickHouse database")
+	parquetDir := cmd.Flags().String("parquet_dir", "", "Directory to write parquet files to")
 	timeAfter := cmd.Flags().StringP("time_after", "a", "", "collect data that are created after specified time, ie 2006-01-02T15:04:05Z")
 	cmd.Run = func(cmd *cobra.Command, args []string) {
 		runner.DirectRun(cmd, args, PluginEntry, map[string]interface{}{
@@ -67,6 +74,17 @@ func main() {
 			"batch_size":    batchSize,
 			"extra":         extra,
 			"order_by":      orderBy,
+			"sink_type":     sinkType,
+			"incremental":   incremental,
+			"clickhouse": map[string]interface{}{
+				"url":      clickhouseUrl,
+				"user":     clickhouseUser,
+				"password": clickhousePassword,
+				"database": clickhouseDatabase,
+			},
+			"parquet": map[string]interface{}{
+				"dir": parquetDir,
+			},
 		}, *timeAfter)
 	}
 	runner.RunCmd(cmd)
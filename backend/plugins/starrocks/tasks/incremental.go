@@ -0,0 +1,418 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/starrocks/models"
+	"github.com/apache/incubator-devlake/plugins/starrocks/utils"
+)
+
+const defaultSinkBatchSize = 10000
+
+// exportToSink exports the tables through the Sink abstraction, it is used for all the sinks other
+// than StarRocks and for StarRocks in incremental mode
+func exportToSink(c plugin.SubTaskContext, db dal.Dal, tables []string) errors.Error {
+	config := c.GetData().(*StarRocksConfig)
+	if config.Incremental && config.UpdateColumn == "" {
+		c.GetLogger().Warn(nil, "update_column is not set, all rows will be exported on every run")
+	}
+	sink, err := newSink(c, config)
+	if err != nil {
+		return err
+	}
+	defer sink.Close()
+	for _, table := range tables {
+		select {
+		case <-c.GetContext().Done():
+			return errors.Convert(c.GetContext().Err())
+		default:
+		}
+		err = exportTableToSink(c, db, sink, table)
+		if err != nil {
+			return errors.Default.Wrap(err, fmt.Sprintf("export %s to %s", table, sink.Name()))
+		}
+	}
+	return nil
+}
+
+func exportTableToSink(c plugin.SubTaskContext, db dal.Dal, sink Sink, table string) errors.Error {
+	logger := c.GetLogger()
+	config := c.GetData().(*StarRocksConfig)
+	quote, err := identifierQuote(db)
+	if err != nil {
+		return err
+	}
+	columns, err := getSinkColumns(db, config, table)
+	if err != nil {
+		return err
+	}
+	pks := primaryKeys(columns)
+	columnNames := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		columnNames[column.Name] = true
+	}
+
+	state := &models.ExportState{Sink: sink.Name(), ExportedTable: table}
+	stateFound := false
+	if config.Incremental {
+		err = c.GetDal().First(state, dal.Where("sink = ? AND exported_table = ?", state.Sink, table))
+		if err != nil && !c.GetDal().IsErrorNotFound(err) {
+			return err
+		}
+		stateFound = err == nil
+	}
+	existing, e := sink.Columns(table)
+	if e != nil {
+		return errors.Convert(e)
+	}
+
+	// the table is recreated unless it was exported incrementally before and the primary keys didn't change
+	recreate := !stateFound || existing == nil
+	reload := false
+	if !recreate {
+		var added []SinkColumn
+		var dropped []string
+		for _, column := range columns {
+			if !slices.Contains(existing, column.Name) {
+				added = append(added, column)
+				recreate = recreate || column.PrimaryKey
+			}
+		}
+		for _, name := range existing {
+			if !columnNames[name] {
+				dropped = append(dropped, name)
+			}
+		}
+		if !recreate && (len(added) > 0 || len(dropped) > 0) {
+			logger.Info("schema of %s changed, adding %d and dropping %d columns", table, len(added), len(dropped))
+			e = sink.AlterTable(table, columns, added, dropped)
+			if e != nil {
+				return errors.Convert(e)
+			}
+			// rows exported before don't have the values of the new columns
+			reload = len(added) > 0
+		}
+	}
+	if recreate {
+		e = sink.CreateTable(table, columns)
+		if e != nil {
+			return errors.Convert(e)
+		}
+	}
+
+	where := ""
+	if tableConfig, ok := config.TableConfigs[table]; ok {
+		where = tableConfig.Where
+	}
+	clauses := []dal.Clause{dal.From(table), dal.Where(where)}
+	var watermark *time.Time
+	if config.UpdateColumn != "" && columnNames[config.UpdateColumn] {
+		// the watermark is taken before copying so that the rows updated meanwhile will be exported next time
+		watermark, err = maxTime(db, quote, table, config.UpdateColumn)
+		if err != nil {
+			return err
+		}
+		if !recreate && !reload && state.Watermark != nil {
+			clauses = append(clauses, dal.Where(fmt.Sprintf("%s%s%s >= ?", quote, config.UpdateColumn, quote), *state.Watermark))
+		}
+	}
+	count, err := copyRowsToSink(c, db, sink, table, columns, quote, clauses)
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	if !recreate {
+		deleted, err = propagateDeletes(db, sink, quote, table, pks, where, config.BatchSize)
+		if err != nil {
+			return err
+		}
+	}
+	e = sink.Flush(table)
+	if e != nil {
+		return errors.Convert(e)
+	}
+	logger.Info("export %s to %s success, %d rows upserted, %d rows deleted", table, sink.Name(), count, deleted)
+
+	if !config.Incremental {
+		return nil
+	}
+	if watermark != nil {
+		state.Watermark = watermark
+	}
+	state.ExportedAt = time.Now()
+	return c.GetDal().CreateOrUpdate(state)
+}
+
+// getSinkColumns returns the columns to be exported, the first column is used as the primary key if the table has none
+func getSinkColumns(db dal.Dal, config *StarRocksConfig, table string) ([]SinkColumn, errors.Error) {
+	columnMetas, err := db.GetColumns(&Table{name: table}, nil)
+	if err != nil {
+		return nil, err
+	}
+	tableConfig, hasTableConfig := config.TableConfigs[table]
+	var columns []SinkColumn
+	hasPrimaryKey := false
+	for _, cm := range columnMetas {
+		name := cm.Name()
+		if hasTableConfig {
+			if len(tableConfig.ExcludedColumns) > 0 && slices.Contains(tableConfig.ExcludedColumns, name) {
+				continue
+			}
+			if len(tableConfig.IncludedColumns) > 0 && !slices.Contains(tableConfig.IncludedColumns, name) {
+				continue
+			}
+		}
+		columnType, ok := cm.ColumnType()
+		if !ok {
+			return nil, errors.Default.New(fmt.Sprintf("Get [%s] ColumeType Failed", name))
+		}
+		isPrimaryKey, ok := cm.PrimaryKey()
+		column := SinkColumn{Name: name, Type: columnType, PrimaryKey: isPrimaryKey && ok}
+		hasPrimaryKey = hasPrimaryKey || column.PrimaryKey
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, errors.Default.New(fmt.Sprintf("no column of %s to export", table))
+	}
+	if !hasPrimaryKey {
+		columns[0].PrimaryKey = true
+	}
+	return columns, nil
+}
+
+func identifierQuote(db dal.Dal) (string, errors.Error) {
+	switch db.Dialect() {
+	case "postgres":
+		return "\"", nil
+	case "mysql":
+		return "`", nil
+	case "sqlite":
+		return "\"", nil
+	}
+	return "", errors.NotFound.New(fmt.Sprintf("unsupported dialect %s", db.Dialect()))
+}
+
+func quoteIdentifiers(quote string, names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote + name + quote
+	}
+	return quoted
+}
+
+func maxTime(db dal.Dal, quote, table, column string) (*time.Time, errors.Error) {
+	rows, err := db.Cursor(dal.Select(fmt.Sprintf("MAX(%s%s%s)", quote, column, quote)), dal.From(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var max sql.NullTime
+	if rows.Next() {
+		if e := rows.Scan(&max); e != nil {
+			return nil, errors.Convert(e)
+		}
+	}
+	if !max.Valid {
+		return nil, nil
+	}
+	return &max.Time, nil
+}
+
+// copyRowsToSink upserts the rows matching the clauses into the sink in batches
+func copyRowsToSink(c plugin.SubTaskContext, db dal.Dal, sink Sink, table string, columns []SinkColumn, quote string, clauses []dal.Clause) (int, errors.Error) {
+	config := c.GetData().(*StarRocksConfig)
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSinkBatchSize
+	}
+	names := make([]string, len(columns))
+	columnMap := make(map[string]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+		columnMap[column.Name] = utils.GetStarRocksDataType(column.Type)
+	}
+	rows, err := db.Cursor(append([]dal.Clause{dal.Select(strings.Join(quoteIdentifiers(quote, names), ", "))}, clauses...)...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	cols, e := rows.Columns()
+	if e != nil {
+		return 0, errors.Convert(e)
+	}
+	count := 0
+	var batch []map[string]interface{}
+	for rows.Next() {
+		select {
+		case <-c.GetContext().Done():
+			return count, errors.Convert(c.GetContext().Err())
+		default:
+		}
+		row, e := scanRow(rows, cols, columnMap)
+		if e != nil {
+			return count, errors.Convert(e)
+		}
+		for k, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[k] = string(b)
+			}
+		}
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if e = sink.Upsert(table, columns, batch); e != nil {
+				return count, errors.Convert(e)
+			}
+			count += len(batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		if e = sink.Upsert(table, columns, batch); e != nil {
+			return count, errors.Convert(e)
+		}
+		count += len(batch)
+	}
+	return count, nil
+}
+
+// propagateDeletes deletes the rows that no longer exist in the source from the sink, the keys of the sink
+// are read page by page in primary key order and only the keys of the current page are looked up in the
+// source, so neither side is loaded into memory as a whole
+func propagateDeletes(db dal.Dal, sink Sink, quote, table string, pks []string, where string, batchSize int) (int, errors.Error) {
+	if batchSize <= 0 {
+		batchSize = defaultSinkBatchSize
+	}
+	deleted := 0
+	var after []interface{}
+	for {
+		sinkKeys, err := sink.KeysAfter(table, pks, after, batchSize)
+		if err != nil {
+			return deleted, errors.Convert(err)
+		}
+		if len(sinkKeys) == 0 {
+			return deleted, nil
+		}
+		condition, params := keysCondition(quote, pks, sinkKeys)
+		clauses := []dal.Clause{dal.From(table), dal.Where(fmt.Sprintf("(%s)", condition), params...)}
+		if where != "" {
+			clauses = append(clauses, dal.Where(fmt.Sprintf("(%s)", where)))
+		}
+		sourceKeys, err := selectKeys(db, quote, pks, clauses...)
+		if err != nil {
+			return deleted, errors.Convert(err)
+		}
+		existing := make(map[string]bool, len(sourceKeys))
+		for _, key := range sourceKeys {
+			existing[encodeKey(key)] = true
+		}
+		var missing [][]interface{}
+		for _, key := range sinkKeys {
+			if !existing[encodeKey(key)] {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			err = sink.Delete(table, pks, missing)
+			if err != nil {
+				return deleted, errors.Convert(err)
+			}
+			deleted += len(missing)
+		}
+		if len(sinkKeys) < batchSize {
+			return deleted, nil
+		}
+		after = sinkKeys[len(sinkKeys)-1]
+	}
+}
+
+// selectKeys reads the primary keys of the rows matching the clauses, the values are normalized by normalizeKeyValue
+func selectKeys(db dal.Dal, quote string, pks []string, clauses ...dal.Clause) ([][]interface{}, error) {
+	rows, err := db.Cursor(append([]dal.Clause{dal.Select(strings.Join(quoteIdentifiers(quote, pks), ", "))}, clauses...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(pks))
+		pointers := make([]interface{}, len(pks))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = normalizeKeyValue(v)
+		}
+		keys = append(keys, values)
+	}
+	return keys, rows.Err()
+}
+
+// keysAfterCondition builds the where condition matching the primary keys greater than after in
+// primary key order, e.g. `(a > ?) OR (a = ? AND b > ?)`
+func keysAfterCondition(quote string, pks []string, after []interface{}) (string, []interface{}) {
+	var params []interface{}
+	conditions := make([]string, len(pks))
+	for i := range pks {
+		parts := make([]string, i+1)
+		for j := 0; j < i; j++ {
+			parts[j] = fmt.Sprintf("%s%s%s = ?", quote, pks[j], quote)
+			params = append(params, after[j])
+		}
+		parts[i] = fmt.Sprintf("%s%s%s > ?", quote, pks[i], quote)
+		params = append(params, after[i])
+		conditions[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+	return strings.Join(conditions, " OR "), params
+}
+
+// keysCondition builds the where condition matching the primary keys
+func keysCondition(quote string, pks []string, keys [][]interface{}) (string, []interface{}) {
+	var params []interface{}
+	if len(pks) == 1 {
+		placeholders := make([]string, len(keys))
+		for i, key := range keys {
+			placeholders[i] = "?"
+			params = append(params, key[0])
+		}
+		return fmt.Sprintf("%s%s%s IN (%s)", quote, pks[0], quote, strings.Join(placeholders, ", ")), params
+	}
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		parts := make([]string, len(pks))
+		for j, pk := range pks {
+			parts[j] = fmt.Sprintf("%s%s%s = ?", quote, pk, quote)
+			params = append(params, key[j])
+		}
+		conditions[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+	return strings.Join(conditions, " OR "), params
+}
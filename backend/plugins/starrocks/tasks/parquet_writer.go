@@ -0,0 +1,466 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A minimal parquet writer: every column is OPTIONAL, PLAIN encoded and uncompressed,
+// each call of WriteRowGroup produces one row group made of a single data page per column.
+
+const parquetMagic = "PAR1"
+
+// parquet physical types
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// parquet converted types
+const (
+	parquetUtf8            = 0
+	parquetTimestampMillis = 9
+)
+
+// parquet encodings
+const (
+	parquetPlain = 0
+	parquetRle   = 3
+)
+
+// thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+type parquetColumn struct {
+	name          string
+	physicalType  int32
+	convertedType int32 // -1 if none
+}
+
+// parquetColumnOf maps the data type in the source database to a parquet column
+func parquetColumnOf(column SinkColumn) parquetColumn {
+	dataType := strings.ToLower(column.Type)
+	pc := parquetColumn{name: column.Name, physicalType: parquetByteArray, convertedType: parquetUtf8}
+	switch {
+	case hasAnyPrefix(dataType, "datetime", "timestamp", "date"):
+		pc.physicalType, pc.convertedType = parquetInt64, parquetTimestampMillis
+	case strings.HasSuffix(dataType, "[]"):
+		// arrays are written as json strings
+	case dataType == "tinyint(1)" || dataType == "boolean":
+		pc.physicalType, pc.convertedType = parquetBoolean, -1
+	case hasAnyPrefix(dataType, "bigint", "int", "smallint", "tinyint", "serial", "bigserial", "smallserial"):
+		pc.physicalType, pc.convertedType = parquetInt64, -1
+	case hasAnyPrefix(dataType, "real", "float", "double", "numeric", "decimal"):
+		pc.physicalType, pc.convertedType = parquetDouble, -1
+	}
+	return pc
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type parquetColumnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+type parquetRowGroup struct {
+	chunks   []parquetColumnChunk
+	numRows  int64
+	byteSize int64
+}
+
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []parquetColumn
+	rowGroups []parquetRowGroup
+}
+
+func newParquetWriter(w io.Writer, columns []SinkColumn) (*parquetWriter, error) {
+	pw := &parquetWriter{w: w}
+	for _, column := range columns {
+		pw.columns = append(pw.columns, parquetColumnOf(column))
+	}
+	return pw, pw.write([]byte(parquetMagic))
+}
+
+func (pw *parquetWriter) write(b []byte) error {
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	return err
+}
+
+// WriteRowGroup writes the rows as a row group, missing values are written as null
+func (pw *parquetWriter) WriteRowGroup(rows []map[string]interface{}) error {
+	rg := parquetRowGroup{numRows: int64(len(rows))}
+	for _, column := range pw.columns {
+		levels := make([]byte, len(rows))
+		var values bytes.Buffer
+		var bits []bool
+		for i, row := range rows {
+			v, err := parquetValue(column, row[column.name])
+			if err != nil {
+				return fmt.Errorf("column %s: %w", column.name, err)
+			}
+			if v == nil {
+				continue
+			}
+			levels[i] = 1
+			switch x := v.(type) {
+			case bool:
+				bits = append(bits, x)
+			case int64:
+				_ = binary.Write(&values, binary.LittleEndian, x)
+			case float64:
+				_ = binary.Write(&values, binary.LittleEndian, math.Float64bits(x))
+			case string:
+				_ = binary.Write(&values, binary.LittleEndian, uint32(len(x)))
+				values.WriteString(x)
+			}
+		}
+		if column.physicalType == parquetBoolean {
+			values.Write(packBits(bits))
+		}
+		encodedLevels := rleEncodeLevels(levels)
+		var page bytes.Buffer
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(encodedLevels)))
+		page.Write(encodedLevels)
+		page.Write(values.Bytes())
+
+		var header thriftWriter
+		header.beginStruct()
+		header.fieldI32(1, 0) // DATA_PAGE
+		header.fieldI32(2, int32(page.Len()))
+		header.fieldI32(3, int32(page.Len()))
+		header.fieldStruct(5, func() {
+			header.fieldI32(1, int32(len(rows)))
+			header.fieldI32(2, parquetPlain)
+			header.fieldI32(3, parquetRle)
+			header.fieldI32(4, parquetRle)
+		})
+		header.endStruct()
+
+		chunk := parquetColumnChunk{offset: pw.offset, numValues: int64(len(rows))}
+		if err := pw.write(header.Bytes()); err != nil {
+			return err
+		}
+		if err := pw.write(page.Bytes()); err != nil {
+			return err
+		}
+		chunk.size = pw.offset - chunk.offset
+		rg.byteSize += chunk.size
+		rg.chunks = append(rg.chunks, chunk)
+	}
+	pw.rowGroups = append(pw.rowGroups, rg)
+	return nil
+}
+
+// Close writes the footer, it doesn't close the underlying writer
+func (pw *parquetWriter) Close() error {
+	var numRows int64
+	for _, rg := range pw.rowGroups {
+		numRows += rg.numRows
+	}
+	var meta thriftWriter
+	meta.beginStruct()
+	meta.fieldI32(1, 1)
+	meta.fieldList(2, thriftStruct, len(pw.columns)+1, func(i int) {
+		meta.beginStruct()
+		if i == 0 {
+			meta.fieldString(4, "schema")
+			meta.fieldI32(5, int32(len(pw.columns)))
+		} else {
+			column := pw.columns[i-1]
+			meta.fieldI32(1, column.physicalType)
+			meta.fieldI32(3, 1) // OPTIONAL
+			meta.fieldString(4, column.name)
+			if column.convertedType >= 0 {
+				meta.fieldI32(6, column.convertedType)
+			}
+		}
+		meta.endStruct()
+	})
+	meta.fieldI64(3, numRows)
+	meta.fieldList(4, thriftStruct, len(pw.rowGroups), func(i int) {
+		rg := pw.rowGroups[i]
+		meta.beginStruct()
+		meta.fieldList(1, thriftStruct, len(rg.chunks), func(j int) {
+			chunk := rg.chunks[j]
+			column := pw.columns[j]
+			meta.beginStruct()
+			meta.fieldI64(2, chunk.offset)
+			meta.fieldStruct(3, func() {
+				meta.fieldI32(1, column.physicalType)
+				meta.fieldList(2, thriftI32, 2, func(k int) {
+					meta.writeVarint(zigzag32([]int32{parquetPlain, parquetRle}[k]))
+				})
+				meta.fieldList(3, thriftBinary, 1, func(int) {
+					meta.writeBinary(column.name)
+				})
+				meta.fieldI32(4, 0) // UNCOMPRESSED
+				meta.fieldI64(5, chunk.numValues)
+				meta.fieldI64(6, chunk.size)
+				meta.fieldI64(7, chunk.size)
+				meta.fieldI64(9, chunk.offset)
+			})
+			meta.endStruct()
+		})
+		meta.fieldI64(2, rg.byteSize)
+		meta.fieldI64(3, rg.numRows)
+		meta.endStruct()
+	})
+	meta.fieldString(6, "apache devlake starrocks plugin")
+	meta.endStruct()
+
+	footer := meta.Bytes()
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+	if err := binary.Write(pw.w, binary.LittleEndian, uint32(len(footer))); err != nil {
+		return err
+	}
+	return pw.write([]byte(parquetMagic))
+}
+
+// parquetValue converts a value to the go type of the physical type of the column, nil stands for null
+func parquetValue(column parquetColumn, v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case *interface{}:
+		return parquetValue(column, *x)
+	case []byte:
+		v = string(x)
+	case *[]string:
+		if x == nil {
+			return nil, nil
+		}
+		v = "[" + strings.Join(quoteAll(*x), ",") + "]"
+	}
+	switch column.physicalType {
+	case parquetBoolean:
+		switch x := v.(type) {
+		case bool:
+			return x, nil
+		case int64:
+			return x != 0, nil
+		case string:
+			return strconv.ParseBool(x)
+		}
+	case parquetInt64:
+		if column.convertedType == parquetTimestampMillis {
+			switch x := v.(type) {
+			case time.Time:
+				return x.UnixMilli(), nil
+			case string:
+				t, err := time.Parse(time.RFC3339Nano, x)
+				if err != nil {
+					t, err = time.Parse("2006-01-02 15:04:05", x)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return t.UnixMilli(), nil
+			}
+		} else {
+			switch x := v.(type) {
+			case int64:
+				return x, nil
+			case int32:
+				return int64(x), nil
+			case int:
+				return int64(x), nil
+			case uint64:
+				return int64(x), nil
+			case bool:
+				if x {
+					return int64(1), nil
+				}
+				return int64(0), nil
+			case float64:
+				return int64(x), nil
+			case string:
+				return strconv.ParseInt(x, 10, 64)
+			}
+		}
+	case parquetDouble:
+		switch x := v.(type) {
+		case float64:
+			return x, nil
+		case float32:
+			return float64(x), nil
+		case int64:
+			return float64(x), nil
+		case string:
+			return strconv.ParseFloat(x, 64)
+		}
+	default:
+		switch x := v.(type) {
+		case string:
+			return x, nil
+		case time.Time:
+			return x.Format(time.RFC3339Nano), nil
+		default:
+			return fmt.Sprintf("%v", x), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected value %v of type %T", v, v)
+}
+
+func quoteAll(ss []string) []string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = strconv.Quote(s)
+	}
+	return quoted
+}
+
+// rleEncodeLevels encodes definition levels of bit width 1 with the RLE/bit-packing hybrid, using RLE runs only
+func rleEncodeLevels(levels []byte) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		writeUvarint(&buf, uint64(j-i)<<1)
+		buf.WriteByte(levels[i])
+		i = j
+	}
+	return buf.Bytes()
+}
+
+// packBits packs booleans LSB first as required by the PLAIN encoding
+func packBits(bits []bool) []byte {
+	packed := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	buf.Write(b[:n])
+}
+
+func zigzag32(v int32) uint64 {
+	return uint64(uint32((v << 1) ^ (v >> 31)))
+}
+
+func zigzag64(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// thriftWriter encodes structs with the thrift compact protocol
+type thriftWriter struct {
+	bytes.Buffer
+	lastFieldIds []int16
+}
+
+func (t *thriftWriter) beginStruct() {
+	t.lastFieldIds = append(t.lastFieldIds, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.WriteByte(0)
+	t.lastFieldIds = t.lastFieldIds[:len(t.lastFieldIds)-1]
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := &t.lastFieldIds[len(t.lastFieldIds)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.WriteByte(fieldType)
+		t.writeVarint(zigzag32(int32(id)))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) writeVarint(v uint64) {
+	writeUvarint(&t.Buffer, v)
+}
+
+func (t *thriftWriter) writeBinary(s string) {
+	t.writeVarint(uint64(len(s)))
+	t.WriteString(s)
+}
+
+func (t *thriftWriter) fieldI32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.writeVarint(zigzag32(v))
+}
+
+func (t *thriftWriter) fieldI64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.writeVarint(zigzag64(v))
+}
+
+func (t *thriftWriter) fieldString(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.writeBinary(s)
+}
+
+func (t *thriftWriter) fieldStruct(id int16, body func()) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+	body()
+	t.endStruct()
+}
+
+// fieldList writes a list field, elem is called to write each element, struct elements must begin and end themselves
+func (t *thriftWriter) fieldList(id int16, elemType byte, size int, elem func(i int)) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.WriteByte(0xf0 | elemType)
+		t.writeVarint(uint64(size))
+	}
+	for i := 0; i < size; i++ {
+		elem(i)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+const (
+	SINK_STARROCKS  = "starrocks"
+	SINK_CLICKHOUSE = "clickhouse"
+	SINK_PARQUET    = "parquet"
+)
+
+// SinkColumn describes an exported column, Type is the data type in the source database
+type SinkColumn struct {
+	Name       string
+	Type       string
+	PrimaryKey bool
+}
+
+// Sink is a destination the tables are exported to
+type Sink interface {
+	// Name identifies the destination, the export states are recorded per sink
+	Name() string
+	// Columns returns the column names of the table in the sink, nil if the table doesn't exist
+	Columns(table string) ([]string, error)
+	// CreateTable drops the table if it exists and creates it with the columns
+	CreateTable(table string, columns []SinkColumn) error
+	// AlterTable adds and drops columns so that the table follows the source schema
+	AlterTable(table string, columns []SinkColumn, added []SinkColumn, dropped []string) error
+	// KeysAfter returns up to limit primary keys of the table following after in the order of the sink,
+	// starting from the first key if after is nil
+	KeysAfter(table string, pks []string, after []interface{}, limit int) ([][]interface{}, error)
+	// Upsert inserts the rows, replacing the existing ones with the same primary key
+	Upsert(table string, columns []SinkColumn, rows []map[string]interface{}) error
+	// Delete removes the rows with the primary keys
+	Delete(table string, pks []string, keys [][]interface{}) error
+	// Flush is called once a table is fully exported
+	Flush(table string) error
+	Close() error
+}
+
+func newSink(c plugin.SubTaskContext, config *StarRocksConfig) (Sink, errors.Error) {
+	switch config.SinkType {
+	case "", SINK_STARROCKS:
+		return newStarRocksSink(c, config)
+	case SINK_CLICKHOUSE:
+		return newClickHouseSink(c, config)
+	case SINK_PARQUET:
+		return newParquetSink(c, config)
+	}
+	return nil, errors.BadInput.New(fmt.Sprintf("unsupported sink type %s", config.SinkType))
+}
+
+// normalizeKeyValue converts a primary key value to a string so that the keys read from
+// the source and from the sink could be compared
+func normalizeKeyValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case *interface{}:
+		return normalizeKeyValue(*x)
+	case time.Time:
+		return x.UTC().Format("2006-01-02 15:04:05")
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// encodeKey encodes the values of a primary key into a string
+func encodeKey(values []interface{}) string {
+	normalized := make([]string, len(values))
+	for i, v := range values {
+		normalized[i] = normalizeKeyValue(v)
+	}
+	b, _ := json.Marshal(normalized)
+	return string(b)
+}
+
+func primaryKeys(columns []SinkColumn) []string {
+	var pks []string
+	for _, column := range columns {
+		if column.PrimaryKey {
+			pks = append(pks, column.Name)
+		}
+	}
+	return pks
+}
@@ -0,0 +1,246 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/starrocks/utils"
+)
+
+// clickhouseSink talks to the HTTP interface of ClickHouse, tables are created with the ReplacingMergeTree
+// engine ordered by the primary keys so that inserting a row replaces the existing one
+type clickhouseSink struct {
+	config *ClickHouseConfig
+	extra  map[string]string
+	client *http.Client
+}
+
+var _ Sink = (*clickhouseSink)(nil)
+
+func newClickHouseSink(_ plugin.SubTaskContext, config *StarRocksConfig) (Sink, errors.Error) {
+	if config.ClickHouse.Url == "" {
+		return nil, errors.BadInput.New("clickhouse.url is required")
+	}
+	return &clickhouseSink{
+		config: &config.ClickHouse,
+		extra:  config.Extra,
+		client: &http.Client{Timeout: 10 * time.Minute},
+	}, nil
+}
+
+func (s *clickhouseSink) Name() string {
+	return fmt.Sprintf("%s:%s/%s", SINK_CLICKHOUSE, s.config.Url, s.config.Database)
+}
+
+// exec runs the query, body is sent as the data of INSERT queries
+func (s *clickhouseSink) exec(query string, body io.Reader) ([]byte, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	if s.config.Database != "" {
+		params.Set("database", s.config.Database)
+	}
+	params.Set("date_time_input_format", "best_effort")
+	// wait for ALTER ... DELETE mutations to finish
+	params.Set("mutations_sync", "1")
+	if body == nil {
+		body = http.NoBody
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(s.config.Url, "/")+"/?"+params.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.User != "" {
+		req.Header.Set("X-ClickHouse-User", s.config.User)
+		req.Header.Set("X-ClickHouse-Key", s.config.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Default.New(fmt.Sprintf("clickhouse responded %d: %s", resp.StatusCode, strings.TrimSpace(string(b))))
+	}
+	return b, nil
+}
+
+func quoteClickHouseString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func clickhouseColumnType(column SinkColumn) string {
+	dataType := utils.GetClickHouseDataType(column.Type)
+	// key columns and arrays can't be nullable
+	if column.PrimaryKey || strings.HasPrefix(dataType, "Array") {
+		return dataType
+	}
+	return fmt.Sprintf("Nullable(%s)", dataType)
+}
+
+func (s *clickhouseSink) Columns(table string) ([]string, error) {
+	b, err := s.exec(fmt.Sprintf(
+		"SELECT name FROM system.columns WHERE database = currentDatabase() AND table = %s ORDER BY position FORMAT JSONEachRow",
+		quoteClickHouseString(table),
+	), nil)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var column struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &column); err != nil {
+			return nil, err
+		}
+		names = append(names, column.Name)
+	}
+	return names, scanner.Err()
+}
+
+func (s *clickhouseSink) CreateTable(table string, columns []SinkColumn) error {
+	var defs, pks []string
+	for _, column := range columns {
+		defs = append(defs, fmt.Sprintf("`%s` %s", column.Name, clickhouseColumnType(column)))
+		if column.PrimaryKey {
+			pks = append(pks, fmt.Sprintf("`%s`", column.Name))
+		}
+	}
+	extra := fmt.Sprintf("ENGINE = ReplacingMergeTree ORDER BY (%s)", strings.Join(pks, ", "))
+	if v, ok := s.extra[table]; ok {
+		extra = v
+	}
+	_, err := s.exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`", table), nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.exec(fmt.Sprintf("CREATE TABLE `%s` ( %s ) %s", table, strings.Join(defs, ", "), extra), nil)
+	return err
+}
+
+func (s *clickhouseSink) AlterTable(table string, _ []SinkColumn, added []SinkColumn, dropped []string) error {
+	var clauses []string
+	for _, column := range added {
+		clauses = append(clauses, fmt.Sprintf("ADD COLUMN IF NOT EXISTS `%s` %s", column.Name, clickhouseColumnType(column)))
+	}
+	for _, name := range dropped {
+		clauses = append(clauses, fmt.Sprintf("DROP COLUMN IF EXISTS `%s`", name))
+	}
+	if len(clauses) == 0 {
+		return nil
+	}
+	_, err := s.exec(fmt.Sprintf("ALTER TABLE `%s` %s", table, strings.Join(clauses, ", ")), nil)
+	return err
+}
+
+func (s *clickhouseSink) KeysAfter(table string, pks []string, after []interface{}, limit int) ([][]interface{}, error) {
+	quoted := strings.Join(quoteIdentifiers("`", pks), ", ")
+	where := ""
+	if after != nil {
+		condition, params := keysAfterCondition("`", pks, after)
+		// the values are inlined as the http interface doesn't take positional parameters
+		for _, param := range params {
+			condition = strings.Replace(condition, "?", quoteClickHouseString(normalizeKeyValue(param)), 1)
+		}
+		where = fmt.Sprintf(" WHERE %s", condition)
+	}
+	b, err := s.exec(fmt.Sprintf(
+		"SELECT %s FROM `%s` FINAL%s ORDER BY %s LIMIT %d FORMAT JSONCompactEachRow",
+		quoted, table, where, quoted, limit,
+	), nil)
+	if err != nil {
+		return nil, err
+	}
+	var keys [][]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	for decoder.More() {
+		var values []interface{}
+		if err := decoder.Decode(&values); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if n, ok := v.(json.Number); ok {
+				values[i] = n.String()
+			}
+		}
+		keys = append(keys, values)
+	}
+	return keys, nil
+}
+
+func (s *clickhouseSink) Upsert(table string, _ []SinkColumn, rows []map[string]interface{}) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		for k, v := range row {
+			if t, ok := v.(time.Time); ok {
+				row[k] = t.UTC().Format("2006-01-02 15:04:05.000")
+			}
+		}
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	_, err := s.exec(fmt.Sprintf("INSERT INTO `%s` FORMAT JSONEachRow", table), &body)
+	return err
+}
+
+func (s *clickhouseSink) Delete(table string, pks []string, keys [][]interface{}) error {
+	quoted := make([]string, len(pks))
+	for i, pk := range pks {
+		quoted[i] = fmt.Sprintf("`%s`", pk)
+	}
+	tuples := make([]string, len(keys))
+	for i, key := range keys {
+		values := make([]string, len(key))
+		for j, v := range key {
+			values[j] = quoteClickHouseString(normalizeKeyValue(v))
+		}
+		tuples[i] = fmt.Sprintf("(%s)", strings.Join(values, ", "))
+	}
+	_, err := s.exec(fmt.Sprintf(
+		"ALTER TABLE `%s` DELETE WHERE (%s) IN (%s)",
+		table, strings.Join(quoted, ", "), strings.Join(tuples, ", "),
+	), nil)
+	return err
+}
+
+func (s *clickhouseSink) Flush(_ string) error {
+	return nil
+}
+
+func (s *clickhouseSink) Close() error {
+	return nil
+}
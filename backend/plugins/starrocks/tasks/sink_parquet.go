@@ -0,0 +1,260 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+const (
+	parquetSchemaFile = "_schema.json"
+	parquetKeysFile   = "_keys.json"
+)
+
+// parquetSink writes every table into a folder of parquet files as a change log:
+// upserted rows go to `<timestamp>.parquet` files and deleted keys go to `<timestamp>.deletes.parquet` files,
+// readers should keep the latest version of every primary key. The current schema and keys are kept
+// in `_schema.json` and `_keys.json` next to the data files.
+type parquetSink struct {
+	dir  string
+	keys map[string]map[string][]interface{}
+	// sorted holds the encoded keys of the tables in order for KeysAfter, it is dropped on Upsert,
+	// the keys removed by Delete are skipped instead
+	sorted map[string][]string
+}
+
+var _ Sink = (*parquetSink)(nil)
+
+func newParquetSink(_ plugin.SubTaskContext, config *StarRocksConfig) (Sink, errors.Error) {
+	if config.Parquet.Dir == "" {
+		return nil, errors.BadInput.New("parquet.dir is required")
+	}
+	err := os.MkdirAll(config.Parquet.Dir, 0o755)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return &parquetSink{
+		dir:    config.Parquet.Dir,
+		keys:   make(map[string]map[string][]interface{}),
+		sorted: make(map[string][]string),
+	}, nil
+}
+
+func (s *parquetSink) Name() string {
+	return fmt.Sprintf("%s:%s", SINK_PARQUET, s.dir)
+}
+
+func (s *parquetSink) path(table string, file string) string {
+	return filepath.Join(s.dir, table, file)
+}
+
+func (s *parquetSink) readJson(table, file string, v interface{}) (bool, error) {
+	b, err := os.ReadFile(s.path(table, file))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+func (s *parquetSink) writeJson(table, file string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(table, file+".tmp")
+	err = os.WriteFile(tmp, b, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(table, file))
+}
+
+func (s *parquetSink) Columns(table string) ([]string, error) {
+	var columns []SinkColumn
+	exists, err := s.readJson(table, parquetSchemaFile, &columns)
+	if err != nil || !exists {
+		return nil, err
+	}
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names, nil
+}
+
+func (s *parquetSink) CreateTable(table string, columns []SinkColumn) error {
+	err := os.RemoveAll(filepath.Join(s.dir, table))
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Join(s.dir, table), 0o755)
+	if err != nil {
+		return err
+	}
+	s.keys[table] = make(map[string][]interface{})
+	delete(s.sorted, table)
+	return s.writeJson(table, parquetSchemaFile, columns)
+}
+
+// AlterTable only records the new schema, files written from now on follow it
+func (s *parquetSink) AlterTable(table string, columns []SinkColumn, _ []SinkColumn, _ []string) error {
+	return s.writeJson(table, parquetSchemaFile, columns)
+}
+
+// tableKeys returns the keys of the table indexed by encodeKey, they are read from the disk on the first call
+func (s *parquetSink) tableKeys(table string) (map[string][]interface{}, error) {
+	if keys, ok := s.keys[table]; ok {
+		return keys, nil
+	}
+	var list [][]interface{}
+	_, err := s.readJson(table, parquetKeysFile, &list)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string][]interface{}, len(list))
+	for _, key := range list {
+		keys[encodeKey(key)] = key
+	}
+	s.keys[table] = keys
+	return keys, nil
+}
+
+// KeysAfter pages over the keys in the order of their encodings
+func (s *parquetSink) KeysAfter(table string, _ []string, after []interface{}, limit int) ([][]interface{}, error) {
+	keys, err := s.tableKeys(table)
+	if err != nil {
+		return nil, err
+	}
+	sorted, ok := s.sorted[table]
+	if !ok {
+		sorted = make([]string, 0, len(keys))
+		for encoded := range keys {
+			sorted = append(sorted, encoded)
+		}
+		sort.Strings(sorted)
+		s.sorted[table] = sorted
+	}
+	start := 0
+	if after != nil {
+		encoded := encodeKey(after)
+		start = sort.SearchStrings(sorted, encoded)
+		if start < len(sorted) && sorted[start] == encoded {
+			start++
+		}
+	}
+	var page [][]interface{}
+	for _, encoded := range sorted[start:] {
+		if len(page) == limit {
+			break
+		}
+		if key, ok := keys[encoded]; ok {
+			page = append(page, key)
+		}
+	}
+	return page, nil
+}
+
+func (s *parquetSink) writeFile(table, suffix string, columns []SinkColumn, rows []map[string]interface{}) error {
+	f, err := os.Create(s.path(table, fmt.Sprintf("%d%s", time.Now().UnixNano(), suffix)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	pw, err := newParquetWriter(f, columns)
+	if err != nil {
+		return err
+	}
+	err = pw.WriteRowGroup(rows)
+	if err != nil {
+		return err
+	}
+	err = pw.Close()
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (s *parquetSink) Upsert(table string, columns []SinkColumn, rows []map[string]interface{}) error {
+	keys, err := s.tableKeys(table)
+	if err != nil {
+		return err
+	}
+	delete(s.sorted, table)
+	err = s.writeFile(table, ".parquet", columns, rows)
+	if err != nil {
+		return err
+	}
+	pks := primaryKeys(columns)
+	for _, row := range rows {
+		key := make([]interface{}, len(pks))
+		for i, pk := range pks {
+			key[i] = normalizeKeyValue(row[pk])
+		}
+		keys[encodeKey(key)] = key
+	}
+	return nil
+}
+
+func (s *parquetSink) Delete(table string, pks []string, deleted [][]interface{}) error {
+	keys, err := s.tableKeys(table)
+	if err != nil {
+		return err
+	}
+	columns := make([]SinkColumn, len(pks))
+	rows := make([]map[string]interface{}, len(deleted))
+	for i, pk := range pks {
+		columns[i] = SinkColumn{Name: pk, Type: "varchar", PrimaryKey: true}
+	}
+	for i, key := range deleted {
+		rows[i] = make(map[string]interface{}, len(pks))
+		for j, pk := range pks {
+			rows[i][pk] = normalizeKeyValue(key[j])
+		}
+		delete(keys, encodeKey(key))
+	}
+	return s.writeFile(table, ".deletes.parquet", columns, rows)
+}
+
+// Flush persists the keys of the table
+func (s *parquetSink) Flush(table string) error {
+	keys, ok := s.keys[table]
+	if !ok {
+		return nil
+	}
+	list := make([][]interface{}, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key)
+	}
+	return s.writeJson(table, parquetKeysFile, list)
+}
+
+func (s *parquetSink) Close() error {
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/impls/dalgorm"
+	"github.com/apache/incubator-devlake/plugins/starrocks/utils"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// starrocksSink keeps the tables as primary key tables, so that stream loads replace the existing rows
+type starrocksSink struct {
+	config *StarRocksConfig
+	db     dal.Dal
+	gormDb *gorm.DB
+}
+
+var _ Sink = (*starrocksSink)(nil)
+
+func newStarRocksSink(_ plugin.SubTaskContext, config *StarRocksConfig) (Sink, errors.Error) {
+	sr, err := gorm.Open(mysql.Open(fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.User, config.Password, config.Host, config.Port, config.Database)))
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	return &starrocksSink{config: config, db: dalgorm.NewDalgorm(sr), gormDb: sr}, nil
+}
+
+func (s *starrocksSink) Name() string {
+	return fmt.Sprintf("%s:%s:%d/%s", SINK_STARROCKS, s.config.Host, s.config.Port, s.config.Database)
+}
+
+func (s *starrocksSink) Columns(table string) ([]string, error) {
+	if !s.db.HasTable(table) {
+		return nil, nil
+	}
+	columnMetas, err := s.db.GetColumns(&Table{name: table}, nil)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(columnMetas))
+	for _, cm := range columnMetas {
+		names = append(names, cm.Name())
+	}
+	return names, nil
+}
+
+// starrocksColumnType returns the data type of the column, key columns of primary key tables can't be string
+func starrocksColumnType(column SinkColumn) string {
+	dataType := utils.GetStarRocksDataType(column.Type)
+	if column.PrimaryKey && dataType == "string" {
+		return "varchar(255)"
+	}
+	return dataType
+}
+
+func (s *starrocksSink) CreateTable(table string, columns []SinkColumn) error {
+	var keyDefs, valueDefs, pks []string
+	for _, column := range columns {
+		if column.PrimaryKey {
+			// key columns must come first in primary key tables
+			keyDefs = append(keyDefs, fmt.Sprintf("`%s` %s NOT NULL", column.Name, starrocksColumnType(column)))
+			pks = append(pks, fmt.Sprintf("`%s`", column.Name))
+		} else {
+			valueDefs = append(valueDefs, fmt.Sprintf("`%s` %s", column.Name, starrocksColumnType(column)))
+		}
+	}
+	replicationNum := os.Getenv("STARROCKS_REPLICAS_NUM")
+	if replicationNum == "" {
+		replicationNum = "1"
+	}
+	keys := strings.Join(pks, ", ")
+	extra := fmt.Sprintf(`engine=olap primary key(%s) distributed by hash(%s) properties("replication_num" = "%s")`, keys, keys, replicationNum)
+	if v, ok := s.config.Extra[table]; ok {
+		extra = v
+	}
+	err := s.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`", table))
+	if err != nil {
+		return err
+	}
+	return s.db.Exec(fmt.Sprintf("CREATE TABLE `%s` ( %s ) %s", table, strings.Join(append(keyDefs, valueDefs...), ", "), extra))
+}
+
+func (s *starrocksSink) AlterTable(table string, _ []SinkColumn, added []SinkColumn, dropped []string) error {
+	// StarRocks runs one schema change per table at a time, so all the changes go into one statement
+	var clauses []string
+	if len(added) > 0 {
+		var defs []string
+		for _, column := range added {
+			defs = append(defs, fmt.Sprintf("`%s` %s", column.Name, starrocksColumnType(column)))
+		}
+		clauses = append(clauses, fmt.Sprintf("ADD COLUMN (%s)", strings.Join(defs, ", ")))
+	}
+	for _, name := range dropped {
+		clauses = append(clauses, fmt.Sprintf("DROP COLUMN `%s`", name))
+	}
+	if len(clauses) == 0 {
+		return nil
+	}
+	return s.db.Exec(fmt.Sprintf("ALTER TABLE `%s` %s", table, strings.Join(clauses, ", ")))
+}
+
+func (s *starrocksSink) KeysAfter(table string, pks []string, after []interface{}, limit int) ([][]interface{}, error) {
+	clauses := []dal.Clause{
+		dal.From(table),
+		dal.Orderby(strings.Join(quoteIdentifiers("`", pks), ", ")),
+		dal.Limit(limit),
+	}
+	if after != nil {
+		where, params := keysAfterCondition("`", pks, after)
+		clauses = append(clauses, dal.Where(where, params...))
+	}
+	return selectKeys(s.db, "`", pks, clauses...)
+}
+
+func (s *starrocksSink) Upsert(table string, _ []SinkColumn, rows []map[string]interface{}) error {
+	jsonData, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	statusCode, b, err := streamLoad(s.config, table, jsonData)
+	if err != nil {
+		return err
+	}
+	var result map[string]interface{}
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return err
+	}
+	if result["Status"] != "Success" {
+		return errors.Default.New(fmt.Sprintf("load %s failed with status %d: %s", table, statusCode, string(b)))
+	}
+	return nil
+}
+
+func (s *starrocksSink) Delete(table string, pks []string, keys [][]interface{}) error {
+	where, params := keysCondition("`", pks, keys)
+	return s.db.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE %s", table, where), params...)
+}
+
+func (s *starrocksSink) Flush(_ string) error {
+	return nil
+}
+
+func (s *starrocksSink) Close() error {
+	sqlDB, err := s.gormDb.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,435 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/impls/dalgorm"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// thriftReader decodes the thrift compact protocol into generic values:
+// structs become map[int16]interface{} and lists become []interface{}
+type thriftReader struct {
+	r *bytes.Reader
+}
+
+func (t *thriftReader) varint() uint64 {
+	v, _ := binary.ReadUvarint(t.r)
+	return v
+}
+
+func (t *thriftReader) value(fieldType byte) interface{} {
+	switch fieldType {
+	case thriftI32, thriftI64:
+		v := t.varint()
+		return int64(v>>1) ^ -int64(v&1)
+	case thriftBinary:
+		b := make([]byte, t.varint())
+		_, _ = io.ReadFull(t.r, b)
+		return string(b)
+	case thriftList:
+		header, _ := t.r.ReadByte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(t.varint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = t.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		fields := make(map[int16]interface{})
+		var id int16
+		for {
+			header, _ := t.r.ReadByte()
+			if header == 0 {
+				return fields
+			}
+			if delta := header >> 4; delta != 0 {
+				id += int16(delta)
+			} else {
+				v := t.varint()
+				id = int16(v>>1) ^ -int16(v&1)
+			}
+			fields[id] = t.value(header & 0x0f)
+		}
+	}
+	panic("unexpected thrift type")
+}
+
+func TestParquetWriter(t *testing.T) {
+	columns := []SinkColumn{
+		{Name: "id", Type: "varchar(255)", PrimaryKey: true},
+		{Name: "count", Type: "bigint"},
+		{Name: "ratio", Type: "double precision"},
+		{Name: "done", Type: "boolean"},
+		{Name: "created_at", Type: "datetime(3)"},
+	}
+	var buf bytes.Buffer
+	pw, err := newParquetWriter(&buf, columns)
+	assert.Nil(t, err)
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Nil(t, pw.WriteRowGroup([]map[string]interface{}{
+		{"id": []byte("a"), "count": int64(1), "ratio": 0.5, "done": true, "created_at": created},
+		{"id": "b", "count": nil, "ratio": "1.5", "done": false},
+	}))
+	assert.Nil(t, pw.WriteRowGroup([]map[string]interface{}{
+		{"id": "c", "count": "3"},
+	}))
+	assert.Nil(t, pw.Close())
+
+	b := buf.Bytes()
+	assert.Equal(t, parquetMagic, string(b[:4]))
+	assert.Equal(t, parquetMagic, string(b[len(b)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	footer := b[len(b)-8-footerLen : len(b)-8]
+	meta := (&thriftReader{r: bytes.NewReader(footer)}).value(thriftStruct).(map[int16]interface{})
+
+	assert.Equal(t, int64(3), meta[3])
+	schema := meta[2].([]interface{})
+	assert.Len(t, schema, 6)
+	assert.Equal(t, int64(5), schema[0].(map[int16]interface{})[5])
+	assert.Equal(t, "created_at", schema[5].(map[int16]interface{})[4])
+	assert.Equal(t, int64(parquetTimestampMillis), schema[5].(map[int16]interface{})[6])
+	rowGroups := meta[4].([]interface{})
+	assert.Len(t, rowGroups, 2)
+
+	// the first page of `id` holds the levels [1, 1] and the values "a" and "b"
+	chunk := rowGroups[0].(map[int16]interface{})[1].([]interface{})[0].(map[int16]interface{})
+	columnMeta := chunk[3].(map[int16]interface{})
+	assert.Equal(t, []interface{}{"id"}, columnMeta[3])
+	assert.Equal(t, int64(2), columnMeta[5])
+	offset := columnMeta[9].(int64)
+	size := columnMeta[7].(int64)
+	reader := bytes.NewReader(b[offset : offset+size])
+	header := (&thriftReader{r: reader}).value(thriftStruct).(map[int16]interface{})
+	page := make([]byte, header[2].(int64))
+	_, _ = io.ReadFull(reader, page)
+	assert.Equal(t, []byte{2, 0, 0, 0, 4, 1, 1, 0, 0, 0, 'a', 1, 0, 0, 0, 'b'}, page)
+}
+
+// readParquet decodes the file by the parquet format spec into rows, it supports what the spec allows
+// for flat OPTIONAL columns with PLAIN values, not only what the writer produces, so it doesn't share the
+// writer's assumptions: the definition levels may mix RLE and bit-packed runs and pages may be split.
+func readParquet(t *testing.T, b []byte) []map[string]interface{} {
+	footerLen := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	meta := (&thriftReader{r: bytes.NewReader(b[len(b)-8-footerLen : len(b)-8])}).value(thriftStruct).(map[int16]interface{})
+	schema := meta[2].([]interface{})[1:]
+	var rows []map[string]interface{}
+	for _, rg := range meta[4].([]interface{}) {
+		numRows := int(rg.(map[int16]interface{})[3].(int64))
+		groupRows := make([]map[string]interface{}, numRows)
+		for i := range groupRows {
+			groupRows[i] = make(map[string]interface{})
+		}
+		for c, chunk := range rg.(map[int16]interface{})[1].([]interface{}) {
+			element := schema[c].(map[int16]interface{})
+			name := element[4].(string)
+			columnMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			// codec 0 is UNCOMPRESSED
+			if !assert.Equal(t, int64(0), columnMeta[4]) {
+				return nil
+			}
+			reader := bytes.NewReader(b[columnMeta[9].(int64) : columnMeta[9].(int64)+columnMeta[7].(int64)])
+			row := 0
+			for reader.Len() > 0 {
+				header := (&thriftReader{r: reader}).value(thriftStruct).(map[int16]interface{})
+				page := make([]byte, header[3].(int64))
+				_, _ = io.ReadFull(reader, page)
+				dataHeader := header[5].(map[int16]interface{})
+				numValues := int(dataHeader[1].(int64))
+				pageReader := bytes.NewReader(page)
+				var levelsLen uint32
+				_ = binary.Read(pageReader, binary.LittleEndian, &levelsLen)
+				levels := make([]byte, levelsLen)
+				_, _ = io.ReadFull(pageReader, levels)
+				definitions := decodeLevels(levels, numValues)
+				var bits []byte
+				bitIndex := 0
+				for _, defined := range definitions {
+					if defined == 0 {
+						groupRows[row][name] = nil
+						row++
+						continue
+					}
+					var v interface{}
+					switch element[1].(int64) {
+					case 0: // BOOLEAN, bit-packed LSB first
+						if bits == nil {
+							bits, _ = io.ReadAll(pageReader)
+						}
+						v = bits[bitIndex/8]&(1<<(bitIndex%8)) != 0
+						bitIndex++
+					case 2: // INT64
+						var x int64
+						_ = binary.Read(pageReader, binary.LittleEndian, &x)
+						v = x
+						// converted type 9 is TIMESTAMP_MILLIS
+						if element[6] == int64(9) {
+							v = time.UnixMilli(x).UTC()
+						}
+					case 5: // DOUBLE
+						var x float64
+						_ = binary.Read(pageReader, binary.LittleEndian, &x)
+						v = x
+					case 6: // BYTE_ARRAY
+						var n uint32
+						_ = binary.Read(pageReader, binary.LittleEndian, &n)
+						x := make([]byte, n)
+						_, _ = io.ReadFull(pageReader, x)
+						v = string(x)
+					}
+					groupRows[row][name] = v
+					row++
+				}
+			}
+			assert.Equal(t, numRows, row, name)
+		}
+		rows = append(rows, groupRows...)
+	}
+	return rows
+}
+
+// decodeLevels decodes the RLE/bit-packed hybrid encoding of levels with bit width 1
+func decodeLevels(b []byte, count int) []byte {
+	r := bytes.NewReader(b)
+	var levels []byte
+	for len(levels) < count {
+		header, err := binary.ReadUvarint(r)
+		if err != nil {
+			break
+		}
+		if header&1 == 1 {
+			// bit-packed run of (header >> 1) groups of 8 values
+			for i := 0; i < int(header>>1); i++ {
+				packed, _ := r.ReadByte()
+				for j := 0; j < 8; j++ {
+					levels = append(levels, packed>>j&1)
+				}
+			}
+		} else {
+			// rle run, the value takes one byte for bit width 1
+			value, _ := r.ReadByte()
+			for i := 0; i < int(header>>1); i++ {
+				levels = append(levels, value)
+			}
+		}
+	}
+	return levels[:count]
+}
+
+func TestParquetWriterRoundTrip(t *testing.T) {
+	columns := []SinkColumn{
+		{Name: "id", Type: "varchar(255)", PrimaryKey: true},
+		{Name: "count", Type: "bigint"},
+		{Name: "ratio", Type: "double precision"},
+		{Name: "done", Type: "boolean"},
+		{Name: "created_at", Type: "datetime(3)"},
+	}
+	var buf bytes.Buffer
+	pw, err := newParquetWriter(&buf, columns)
+	assert.Nil(t, err)
+	created := time.Date(2023, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	var many []map[string]interface{}
+	for i := 0; i < 20; i++ {
+		row := map[string]interface{}{"id": strconv.Itoa(i), "done": i%3 == 0}
+		if i%2 == 0 {
+			row["count"] = int64(i)
+		}
+		many = append(many, row)
+	}
+	assert.Nil(t, pw.WriteRowGroup([]map[string]interface{}{
+		{"id": "a", "count": int64(1), "ratio": 0.5, "done": true, "created_at": created},
+		{"id": "b", "ratio": 1.5, "done": false},
+	}))
+	assert.Nil(t, pw.WriteRowGroup([]map[string]interface{}{
+		{"id": "ç", "count": int64(-3)},
+	}))
+	assert.Nil(t, pw.WriteRowGroup(many))
+	assert.Nil(t, pw.Close())
+
+	expected := []map[string]interface{}{
+		{"id": "a", "count": int64(1), "ratio": 0.5, "done": true, "created_at": created},
+		{"id": "b", "count": nil, "ratio": 1.5, "done": false, "created_at": nil},
+		{"id": "ç", "count": int64(-3), "ratio": nil, "done": nil, "created_at": nil},
+	}
+	for _, row := range many {
+		expected = append(expected, map[string]interface{}{
+			"id": row["id"], "count": row["count"], "ratio": nil, "done": row["done"], "created_at": nil,
+		})
+	}
+	assert.Equal(t, expected, readParquet(t, buf.Bytes()))
+}
+
+func TestKeysCondition(t *testing.T) {
+	where, params := keysCondition("`", []string{"id"}, [][]interface{}{{"1"}, {"2"}})
+	assert.Equal(t, "`id` IN (?, ?)", where)
+	assert.Equal(t, []interface{}{"1", "2"}, params)
+
+	where, params = keysCondition(`"`, []string{"a", "b"}, [][]interface{}{{"1", "x"}, {"2", "y"}})
+	assert.Equal(t, `("a" = ? AND "b" = ?) OR ("a" = ? AND "b" = ?)`, where)
+	assert.Equal(t, []interface{}{"1", "x", "2", "y"}, params)
+}
+
+func TestKeysAfterCondition(t *testing.T) {
+	where, params := keysAfterCondition("`", []string{"id"}, []interface{}{"1"})
+	assert.Equal(t, "(`id` > ?)", where)
+	assert.Equal(t, []interface{}{"1"}, params)
+
+	where, params = keysAfterCondition(`"`, []string{"a", "b", "c"}, []interface{}{"1", "x", "y"})
+	assert.Equal(t, `("a" > ?) OR ("a" = ? AND "b" > ?) OR ("a" = ? AND "b" = ? AND "c" > ?)`, where)
+	assert.Equal(t, []interface{}{"1", "1", "x", "1", "x", "y"}, params)
+}
+
+func TestPropagateDeletes(t *testing.T) {
+	gormDb, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	assert.Nil(t, err)
+	db := dalgorm.NewDalgorm(gormDb)
+	assert.Nil(t, db.Exec(`CREATE TABLE "issues" ("id" varchar(255), "type" varchar(255), PRIMARY KEY ("id"))`))
+	assert.Nil(t, db.Exec(`INSERT INTO "issues" VALUES ('1', 'BUG'), ('3', 'BUG'), ('4', 'BUG'), ('6', 'TASK')`))
+	quote, e := identifierQuote(db)
+	assert.Nil(t, e)
+
+	sink, e := newParquetSink(nil, &StarRocksConfig{Parquet: ParquetConfig{Dir: t.TempDir()}})
+	assert.Nil(t, e)
+	columns := []SinkColumn{{Name: "id", Type: "varchar(255)", PrimaryKey: true}}
+	assert.Nil(t, sink.CreateTable("issues", columns))
+	var rows []map[string]interface{}
+	for i := 1; i <= 7; i++ {
+		rows = append(rows, map[string]interface{}{"id": strconv.Itoa(i)})
+	}
+	assert.Nil(t, sink.Upsert("issues", columns, rows))
+
+	// the keys are compared 2 by 2, and the rows out of the condition are deleted as well
+	deleted, e := propagateDeletes(db, sink, quote, "issues", []string{"id"}, "type = 'BUG'", 2)
+	assert.Nil(t, e)
+	assert.Equal(t, 4, deleted)
+	keys, err := sink.KeysAfter("issues", []string{"id"}, nil, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{{"1"}, {"3"}, {"4"}}, keys)
+}
+
+func TestEncodeKey(t *testing.T) {
+	assert.Equal(t, encodeKey([]interface{}{int64(12), []byte("a")}), encodeKey([]interface{}{"12", "a"}))
+	assert.Equal(t, `["1.5","2023-01-02 03:04:05"]`, encodeKey([]interface{}{1.5, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)}))
+}
+
+func TestClickHouseSink(t *testing.T) {
+	var queries, bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, query)
+		bodies = append(bodies, string(body))
+		assert.Equal(t, "lake", r.URL.Query().Get("database"))
+		assert.Equal(t, "user", r.Header.Get("X-ClickHouse-User"))
+		switch {
+		case strings.HasPrefix(query, "SELECT name FROM system.columns"):
+			_, _ = w.Write([]byte("{\"name\":\"id\"}\n{\"name\":\"title\"}\n"))
+		case strings.HasPrefix(query, "SELECT `id`"):
+			_, _ = w.Write([]byte("[12]\n[13]\n"))
+		case strings.HasPrefix(query, "DROP"):
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Code: 497. Not enough privileges"))
+		}
+	}))
+	defer server.Close()
+
+	sink, err := newClickHouseSink(nil, &StarRocksConfig{ClickHouse: ClickHouseConfig{Url: server.URL, User: "user", Database: "lake"}})
+	assert.Nil(t, err)
+	columns := []SinkColumn{
+		{Name: "id", Type: "bigint", PrimaryKey: true},
+		{Name: "title", Type: "varchar(255)"},
+		{Name: "labels", Type: "text[]"},
+	}
+
+	names, e := sink.Columns("issues")
+	assert.Nil(t, e)
+	assert.Equal(t, []string{"id", "title"}, names)
+
+	e = sink.AlterTable("issues", columns, columns[2:], []string{"old"})
+	assert.Nil(t, e)
+	assert.Equal(t, "ALTER TABLE `issues` ADD COLUMN IF NOT EXISTS `labels` Array(String), DROP COLUMN IF EXISTS `old`", queries[len(queries)-1])
+
+	keys, e := sink.KeysAfter("issues", []string{"id"}, nil, 2)
+	assert.Nil(t, e)
+	assert.Equal(t, [][]interface{}{{"12"}, {"13"}}, keys)
+	assert.Equal(t, "SELECT `id` FROM `issues` FINAL ORDER BY `id` LIMIT 2 FORMAT JSONCompactEachRow", queries[len(queries)-1])
+
+	_, e = sink.KeysAfter("issues", []string{"id", "title"}, []interface{}{"13", "it's"}, 2)
+	assert.Nil(t, e)
+	assert.Equal(t,
+		"SELECT `id`, `title` FROM `issues` FINAL WHERE (`id` > '13') OR (`id` = '13' AND `title` > 'it\\'s') "+
+			"ORDER BY `id`, `title` LIMIT 2 FORMAT JSONCompactEachRow",
+		queries[len(queries)-1],
+	)
+
+	e = sink.Upsert("issues", columns, []map[string]interface{}{{"id": int64(12), "title": "it's"}})
+	assert.Nil(t, e)
+	assert.Equal(t, "INSERT INTO `issues` FORMAT JSONEachRow", queries[len(queries)-1])
+	assert.Equal(t, "{\"id\":12,\"title\":\"it's\"}\n", bodies[len(bodies)-1])
+
+	e = sink.Delete("issues", []string{"id"}, [][]interface{}{{"13"}, {"it's"}})
+	assert.Nil(t, e)
+	assert.Equal(t, "ALTER TABLE `issues` DELETE WHERE (`id`) IN (('13'), ('it\\'s'))", queries[len(queries)-1])
+
+	e = sink.CreateTable("issues", columns)
+	assert.NotNil(t, e)
+	assert.Contains(t, e.Error(), "Not enough privileges")
+}
+
+func TestParquetSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := newParquetSink(nil, &StarRocksConfig{Parquet: ParquetConfig{Dir: dir}})
+	assert.Nil(t, err)
+	columns := []SinkColumn{{Name: "id", Type: "bigint", PrimaryKey: true}, {Name: "title", Type: "varchar(255)"}}
+
+	names, e := sink.Columns("issues")
+	assert.Nil(t, e)
+	assert.Nil(t, names)
+	assert.Nil(t, sink.CreateTable("issues", columns))
+	assert.Nil(t, sink.Upsert("issues", columns, []map[string]interface{}{{"id": int64(1)}, {"id": int64(2)}}))
+	assert.Nil(t, sink.Delete("issues", []string{"id"}, [][]interface{}{{"1"}}))
+	assert.Nil(t, sink.Flush("issues"))
+
+	// a new sink reads the schema and keys back from the disk
+	sink, err = newParquetSink(nil, &StarRocksConfig{Parquet: ParquetConfig{Dir: dir}})
+	assert.Nil(t, err)
+	names, e = sink.Columns("issues")
+	assert.Nil(t, e)
+	assert.Equal(t, []string{"id", "title"}, names)
+	keys, e := sink.KeysAfter("issues", []string{"id"}, nil, 10)
+	assert.Nil(t, e)
+	assert.Equal(t, [][]interface{}{{"2"}}, keys)
+	files, _ := filepath.Glob(filepath.Join(dir, "issues", "*.parquet"))
+	assert.Len(t, files, 2)
+}
@@ -23,6 +23,19 @@ type TableConfig struct {
 	Where           string   `mapstructure:"where"`
 }
 
+// ClickHouseConfig is used when the sink_type is clickhouse, data is loaded through the HTTP interface
+type ClickHouseConfig struct {
+	Url      string
+	User     string
+	Password string
+	Database string
+}
+
+// ParquetConfig is used when the sink_type is parquet, every table is written into a folder under Dir
+type ParquetConfig struct {
+	Dir string
+}
+
 type StarRocksConfig struct {
 	SourceType   string `mapstructure:"source_type"`
 	SourceDsn    string `mapstructure:"source_dsn"`
@@ -40,4 +53,11 @@ type StarRocksConfig struct {
 	OrderBy      map[string]string      `mapstructure:"order_by"`
 	DomainLayer  string                 `mapstructure:"domain_layer"`
 	Extra        map[string]string
+	// SinkType is one of starrocks (default), clickhouse and parquet
+	SinkType string `mapstructure:"sink_type"`
+	// Incremental exports only the rows updated since the last export according to UpdateColumn,
+	// propagates deletes and alters the target tables when the source schema changes
+	Incremental bool
+	ClickHouse  ClickHouseConfig `mapstructure:"clickhouse"`
+	Parquet     ParquetConfig
 }
@@ -87,6 +87,10 @@ func ExportData(c plugin.SubTaskContext) errors.Error {
 	if err != nil {
 		return errors.Convert(err)
 	}
+	// other sinks and the incremental mode go through the Sink abstraction
+	if config.Incremental || (config.SinkType != "" && config.SinkType != SINK_STARROCKS) {
+		return exportToSink(c, db, starrocksTables)
+	}
 	// 3. copy devlake data to starrocks
 	sr, err := gorm.Open(mysql.Open(fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.User, config.Password, config.Host, config.Port, config.Database)))
 	if err != nil {
@@ -312,26 +316,10 @@ func copyDataToDst(dc *DataConfigParams, columnMap map[string]string, orderBy st
 			return c.GetContext().Err()
 		default:
 		}
-		row := make(map[string]interface{})
-		columns := make([]interface{}, len(cols))
-		columnPointers := make([]interface{}, len(cols))
-		for i := range columns {
-			dataType := columnMap[cols[i]]
-			if strings.HasPrefix(dataType, "array") {
-				var arr []string
-				columns[i] = &arr
-				columnPointers[i] = pq.Array(&arr)
-			} else {
-				columnPointers[i] = &columns[i]
-			}
-		}
-		err = rows.Scan(columnPointers...)
+		row, err := scanRow(rows, cols, columnMap)
 		if err != nil {
 			return err
 		}
-		for i, colName := range cols {
-			row[colName] = columns[i]
-		}
 		data = append(data, row)
 		batchCount += 1
 		if batchCount == config.BatchSize {
@@ -377,11 +365,64 @@ func copyDataToDst(dc *DataConfigParams, columnMap map[string]string, orderBy st
 	return nil
 }
 
+// scanRow reads the current row, columnMap maps the column names to the StarRocks data types
+func scanRow(rows dal.Rows, cols []string, columnMap map[string]string) (map[string]interface{}, error) {
+	row := make(map[string]interface{})
+	columns := make([]interface{}, len(cols))
+	columnPointers := make([]interface{}, len(cols))
+	for i := range columns {
+		dataType := columnMap[cols[i]]
+		if strings.HasPrefix(dataType, "array") {
+			var arr []string
+			columns[i] = &arr
+			columnPointers[i] = pq.Array(&arr)
+		} else {
+			columnPointers[i] = &columns[i]
+		}
+	}
+	err := rows.Scan(columnPointers...)
+	if err != nil {
+		return nil, err
+	}
+	for i, colName := range cols {
+		row[colName] = columns[i]
+	}
+	return row, nil
+}
+
 // put batch size data to database
 func putBatchData(c plugin.SubTaskContext, starrocksTmpTable, table string, data []map[string]interface{}, config *StarRocksConfig, offset int) error {
 	logger := c.GetLogger()
 	// insert data to tmp table
-	loadURL := fmt.Sprintf("http://%s:%d/api/%s/%s/_stream_load", config.BeHost, config.BePort, config.Database, starrocksTmpTable)
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	statusCode, b, err := streamLoad(config, starrocksTmpTable, jsonData)
+	if err != nil {
+		return err
+	}
+
+	var result map[string]interface{}
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		logger.Error(nil, "[%s]: %s", statusCode, string(b))
+	}
+	if result["Status"] != "Success" {
+		logger.Error(nil, "load %s failed: %s", table, string(b))
+	} else {
+		logger.Debug("load %s success: %s, limit: %d, offset: %d", table, b, config.BatchSize, offset)
+	}
+	return nil
+}
+
+// streamLoad puts the json array into the table through the stream load api of the BE,
+// it returns the status code and body of the first response
+func streamLoad(config *StarRocksConfig, table string, jsonData []byte) (int, []byte, error) {
+	loadURL := fmt.Sprintf("http://%s:%d/api/%s/%s/_stream_load", config.BeHost, config.BePort, config.Database, table)
 	headers := map[string]string{
 		"format":            "json",
 		"strip_outer_array": "true",
@@ -389,10 +430,6 @@ func putBatchData(c plugin.SubTaskContext, starrocksTmpTable, table string, data
 		"ignore_json_size":  "true",
 		"Connection":        "close",
 	}
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
 	client := http.Client{
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
@@ -400,7 +437,7 @@ func putBatchData(c plugin.SubTaskContext, starrocksTmpTable, table string, data
 	}
 	req, err := http.NewRequest(http.MethodPut, loadURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 	req.SetBasicAuth(config.User, config.Password)
 	for k, v := range headers {
@@ -408,7 +445,7 @@ func putBatchData(c plugin.SubTaskContext, starrocksTmpTable, table string, data
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 	defer resp.Body.Close()
 	var b []byte
@@ -417,11 +454,11 @@ func putBatchData(c plugin.SubTaskContext, starrocksTmpTable, table string, data
 		var location *url.URL
 		location, err = resp.Location()
 		if err != nil {
-			return err
+			return 0, nil, err
 		}
 		req, err = http.NewRequest(http.MethodPut, location.String(), bytes.NewBuffer(jsonData))
 		if err != nil {
-			return err
+			return 0, nil, err
 		}
 		req.SetBasicAuth(config.User, config.Password)
 		for k, v := range headers {
@@ -429,34 +466,20 @@ func putBatchData(c plugin.SubTaskContext, starrocksTmpTable, table string, data
 		}
 		respRetry, err := client.Do(req)
 		if err != nil {
-			return err
+			return 0, nil, err
 		}
 		defer respRetry.Body.Close()
 		b, err = io.ReadAll(respRetry.Body)
 		if err != nil {
-			return err
+			return 0, nil, err
 		}
 	} else {
 		b, err = io.ReadAll(resp.Body)
 		if err != nil {
-			return err
+			return 0, nil, err
 		}
 	}
-
-	var result map[string]interface{}
-	err = json.Unmarshal(b, &result)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		logger.Error(nil, "[%s]: %s", resp.StatusCode, string(b))
-	}
-	if result["Status"] != "Success" {
-		logger.Error(nil, "load %s failed: %s", table, string(b))
-	} else {
-		logger.Debug("load %s success: %s, limit: %d, offset: %d", table, b, config.BatchSize, offset)
-	}
-	return nil
+	return resp.StatusCode, b, nil
 }
 
 // get db instance
@@ -518,5 +541,5 @@ var ExportDataTaskMeta = plugin.SubTaskMeta{
 	Name:             "ExportData",
 	EntryPoint:       ExportData,
 	EnabledByDefault: true,
-	Description:      "Load data to StarRocks",
+	Description:      "Load data to StarRocks, ClickHouse or parquet files",
 }
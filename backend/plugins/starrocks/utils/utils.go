@@ -57,6 +57,8 @@ func GetTablesByDomainLayer(domainLayer string) []string {
 		return []string{
 			"accounts",
 			"board_repos",
+			"dora_metric_snapshots",
+			"incident_attributions",
 			"issue_commits",
 			"issue_repo_commits",
 			"project_incident_deployment_relationships",
@@ -64,6 +66,8 @@ func GetTablesByDomainLayer(domainLayer string) []string {
 			"project_pr_metrics",
 			"pull_request_issues",
 			"refs_issues_diffs",
+			"team_closures",
+			"team_memberships",
 			"team_users",
 			"teams",
 			"user_accounts",
@@ -149,3 +153,29 @@ func GetStarRocksDataType(dataType string) string {
 	}
 	return starrocksDatatype
 }
+
+// GetClickHouseDataType analysis and return the data type of ClickHouse
+func GetClickHouseDataType(dataType string) string {
+	dataType = strings.ToLower(dataType)
+	clickhouseDatatype := "String"
+	if hasPrefixes(dataType, "datetime", "timestamp") {
+		clickhouseDatatype = "DateTime64(3)"
+	} else if stringIn(dataType, "date") {
+		clickhouseDatatype = "Date"
+	} else if strings.HasPrefix(dataType, "bigint") || stringIn(dataType, "bigserial") {
+		clickhouseDatatype = "Int64"
+	} else if stringIn(dataType, "int", "integer", "serial") {
+		clickhouseDatatype = "Int32"
+	} else if stringIn(dataType, "tinyint(1)", "boolean") {
+		clickhouseDatatype = "Bool"
+	} else if stringIn(dataType, "smallint", "smallserial") {
+		clickhouseDatatype = "Int16"
+	} else if stringIn(dataType, "real", "float") {
+		clickhouseDatatype = "Float32"
+	} else if stringIn(dataType, "numeric", "double precision", "double", "decimal") {
+		clickhouseDatatype = "Float64"
+	} else if strings.HasSuffix(dataType, "[]") {
+		clickhouseDatatype = fmt.Sprintf("Array(%s)", GetClickHouseDataType(strings.Split(dataType, "[]")[0]))
+	}
+	return clickhouseDatatype
+}
@@ -94,7 +94,7 @@ func PatchConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 	if err != nil {
 		return nil, err
 	}
-	return &plugin.ApiResourceOutput{Body: connection}, nil
+	return &plugin.ApiResourceOutput{Body: connection.Sanitize()}, nil
 }
 
 // PatchConnectionByName
@@ -112,7 +112,7 @@ func PatchConnectionByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceO
 	if err != nil {
 		return nil, err
 	}
-	return &plugin.ApiResourceOutput{Body: connection}, nil
+	return &plugin.ApiResourceOutput{Body: connection.Sanitize()}, nil
 }
 
 // DeleteConnection
@@ -164,6 +164,14 @@ func deleteConnection(e error, connectionId uint64) (*plugin.ApiResourceOutput,
 		logger.Error(err, "delete connection: %d", connectionId)
 		return nil, err
 	}
+	err = tx.Delete(&models.WebhookIdempotencyKey{}, dal.Where("connection_id = ?", connectionId))
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			logger.Error(err, "transaction Rollback")
+		}
+		logger.Error(err, "delete idempotency keys of connection: %d", connectionId)
+		return nil, err
+	}
 	extra := fmt.Sprintf("connectionId:%d", connectionId)
 	err = apiKeyHelper.DeleteForPlugin(tx, pluginName, extra)
 	if err != nil {
@@ -188,6 +196,9 @@ type WebhookConnectionResponse struct {
 	PostPipelineTaskEndpoint       string             `json:"postPipelineTaskEndpoint"`
 	PostPipelineDeployTaskEndpoint string             `json:"postPipelineDeployTaskEndpoint"`
 	ClosePipelineEndpoint          string             `json:"closePipelineEndpoint"`
+	PostIssuesBulkEndpoint         string             `json:"postIssuesBulkEndpoint"`
+	PostPullRequestsBulkEndpoint   string             `json:"postPullRequestsBulkEndpoint"`
+	PostDeploymentsBulkEndpoint    string             `json:"postDeploymentsBulkEndpoint"`
 	ApiKey                         *coreModels.ApiKey `json:"apiKey,omitempty"`
 }
 
@@ -254,10 +265,13 @@ func getConnection(err errors.Error, connection *models.WebhookConnection) (*plu
 }
 
 func formatConnection(connection *models.WebhookConnection, withApiKeyInfo bool) (*WebhookConnectionResponse, errors.Error) {
-	response := &WebhookConnectionResponse{WebhookConnection: *connection}
+	response := &WebhookConnectionResponse{WebhookConnection: connection.Sanitize()}
 	response.PostIssuesEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/issues`, connection.ID)
 	response.CloseIssuesEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/issue/:issueKey/close`, connection.ID)
 	response.PostPullRequestsEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/pull_requests`, connection.ID)
+	response.PostIssuesBulkEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/issues/bulk`, connection.ID)
+	response.PostPullRequestsBulkEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/pull_requests/bulk`, connection.ID)
+	response.PostDeploymentsBulkEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/deployments/bulk`, connection.ID)
 	response.PostPipelineTaskEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/cicd_tasks`, connection.ID)
 	response.PostPipelineDeployTaskEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/deployments`, connection.ID)
 	response.ClosePipelineEndpoint = fmt.Sprintf(`/rest/plugins/webhook/connections/%d/cicd_pipeline/:pipelineName/finish`, connection.ID)
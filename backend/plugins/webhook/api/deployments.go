@@ -20,16 +20,12 @@ package api
 import (
 	"crypto/md5"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/log"
 
-	"github.com/apache/incubator-devlake/helpers/dbhelper"
-	"github.com/go-playground/validator/v10"
-
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/domainlayer"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
@@ -109,31 +105,66 @@ func PostDeploymentsByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceO
 	return postDeployments(input, connection, err)
 }
 
+// PostDeploymentsBulk
+// @Summary create deployments in bulk by webhook
+// @Description Create multiple deployments by webhook, example: {"items":[{"idempotencyKey":"ci-run-1","id":"deploy1",...}]}<br/>
+// @Description Every item is saved in its own transaction, the response reports whether each item was ACCEPTED, REJECTED or a DUPLICATE of an earlier delivery with the same idempotencyKey.
+// @Tags plugins/webhook
+// @Param body body WebhookBulkReq true "json body"
+// @Success 200  {object} WebhookBulkResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/:connectionId/deployments/bulk [POST]
+func PostDeploymentsBulk(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	return postBulk(input, connection, ENTITY_DEPLOYMENT, ingestDeployment)
+}
+
+// PostDeploymentsBulkByName
+// @Summary create deployments in bulk by webhook name
+// @Description Create multiple deployments by webhook name, example: {"items":[{"idempotencyKey":"ci-run-1","id":"deploy1",...}]}<br/>
+// @Description Every item is saved in its own transaction, the response reports whether each item was ACCEPTED, REJECTED or a DUPLICATE of an earlier delivery with the same idempotencyKey.
+// @Tags plugins/webhook
+// @Param body body WebhookBulkReq true "json body"
+// @Success 200  {object} WebhookBulkResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/by-name/:connectionName/deployments/bulk [POST]
+func PostDeploymentsBulkByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.FirstByName(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	return postBulk(input, connection, ENTITY_DEPLOYMENT, ingestDeployment)
+}
+
 func postDeployments(input *plugin.ApiResourceInput, connection *models.WebhookConnection, err errors.Error) (*plugin.ApiResourceOutput, errors.Error) {
 	if err != nil {
 		return nil, err
 	}
-	// get request
+	return postSingle(input, connection, ENTITY_DEPLOYMENT, ingestDeployment)
+}
+
+func ingestDeployment(connection *models.WebhookConnection, item map[string]interface{}, tx dal.Transaction) (string, errors.Error) {
 	request := &WebhookDeploymentReq{}
-	err = api.DecodeMapStruct(input.Body, request, true)
+	err := api.DecodeMapStruct(item, request, true)
 	if err != nil {
-		return &plugin.ApiResourceOutput{Body: err.Error(), Status: http.StatusBadRequest}, nil
+		return "", errors.BadInput.Wrap(err, `input json error`)
 	}
-	// validate
-	vld = validator.New()
-	err = errors.Convert(vld.Struct(request))
-	if err != nil {
-		return nil, errors.BadInput.Wrap(vld.Struct(request), `input json error`)
+	if e := vld.Struct(request); e != nil {
+		return "", errors.BadInput.Wrap(e, `input json error`)
 	}
-	txHelper := dbhelper.NewTxHelper(basicRes, &err)
-	defer txHelper.End()
-	tx := txHelper.Begin()
 	if err := CreateDeploymentAndDeploymentCommits(connection, request, tx, logger); err != nil {
-		logger.Error(err, "create deployments")
-		return nil, err
+		return "", err
 	}
-
-	return &plugin.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
+	return request.Id, nil
 }
 
 func CreateDeploymentAndDeploymentCommits(connection *models.WebhookConnection, request *WebhookDeploymentReq, tx dal.Transaction, logger log.Logger) errors.Error {
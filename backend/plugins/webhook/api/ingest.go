@@ -0,0 +1,175 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+)
+
+const maxBulkItems = 1000
+
+const (
+	ENTITY_DEPLOYMENT   = "deployment"
+	ENTITY_PULL_REQUEST = "pull_request"
+	ENTITY_ISSUE        = "issue"
+)
+
+const (
+	INGEST_ACCEPTED  = "ACCEPTED"
+	INGEST_DUPLICATE = "DUPLICATE"
+	INGEST_REJECTED  = "REJECTED"
+)
+
+type WebhookBulkReq struct {
+	// Items are the same objects accepted by the single record endpoint, each item may carry an
+	// `idempotencyKey` so retried deliveries are acknowledged without being saved again
+	Items []map[string]interface{} `json:"items"`
+}
+
+type WebhookBulkItemResult struct {
+	Index          int    `json:"index"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	Id             string `json:"id,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+type WebhookBulkResponse struct {
+	Accepted  int                      `json:"accepted"`
+	Duplicate int                      `json:"duplicate"`
+	Rejected  int                      `json:"rejected"`
+	Results   []*WebhookBulkItemResult `json:"results"`
+}
+
+// ingestFunc decodes and validates a single item, saves it with the transaction and returns the id of the saved record
+type ingestFunc func(connection *models.WebhookConnection, item map[string]interface{}, tx dal.Transaction) (string, errors.Error)
+
+// postSingle saves the request body as a single item, an idempotency key may be passed with the Idempotency-Key header
+func postSingle(input *plugin.ApiResourceInput, connection *models.WebhookConnection, entityType string, ingest ingestFunc) (*plugin.ApiResourceOutput, errors.Error) {
+	err := verifySignature(connection, input.Header, input.RawBody, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	_, status, err := ingestOnce(connection, entityType, input.Header.Get(IDEMPOTENCY_KEY_HEADER), input.Body, ingest)
+	if err != nil && status != INGEST_DUPLICATE {
+		logger.Error(err, "create %s", entityType)
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
+}
+
+// postBulk saves every item in its own transaction so a bad item doesn't fail the whole batch,
+// the outcome of each item is reported in the order they were sent
+func postBulk(input *plugin.ApiResourceInput, connection *models.WebhookConnection, entityType string, ingest ingestFunc) (*plugin.ApiResourceOutput, errors.Error) {
+	err := verifySignature(connection, input.Header, input.RawBody, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	items, ok := input.Body["items"].([]interface{})
+	if !ok {
+		return nil, errors.BadInput.New("items is required and must be an array")
+	}
+	if len(items) > maxBulkItems {
+		return nil, errors.BadInput.New(fmt.Sprintf("too many items, at most %d items are allowed per request", maxBulkItems))
+	}
+	response := &WebhookBulkResponse{Results: make([]*WebhookBulkItemResult, len(items))}
+	for i, rawItem := range items {
+		result := &WebhookBulkItemResult{Index: i}
+		response.Results[i] = result
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			result.Status = INGEST_REJECTED
+			result.Error = "item must be an object"
+			response.Rejected++
+			continue
+		}
+		if key, ok := item["idempotencyKey"]; ok {
+			result.IdempotencyKey = fmt.Sprintf("%v", key)
+		}
+		result.Id, result.Status, err = ingestOnce(connection, entityType, result.IdempotencyKey, item, ingest)
+		switch result.Status {
+		case INGEST_ACCEPTED:
+			response.Accepted++
+		case INGEST_DUPLICATE:
+			response.Duplicate++
+		default:
+			result.Status = INGEST_REJECTED
+			result.Error = err.Messages().Format()
+			response.Rejected++
+		}
+	}
+	return &plugin.ApiResourceOutput{Body: response, Status: http.StatusOK}, nil
+}
+
+// ingestOnce saves the item along with its idempotency key in one transaction, items whose key
+// was seen before are reported as duplicates and left untouched
+func ingestOnce(
+	connection *models.WebhookConnection,
+	entityType string,
+	idempotencyKey string,
+	item map[string]interface{},
+	ingest ingestFunc,
+) (recordId string, status string, err errors.Error) {
+	db := basicRes.GetDal()
+	if idempotencyKey != "" {
+		existing := &models.WebhookIdempotencyKey{}
+		err = db.First(existing, dal.Where(
+			"connection_id = ? AND entity_type = ? AND idempotency_key = ?",
+			connection.ID, entityType, idempotencyKey,
+		))
+		if err == nil {
+			return existing.RecordId, INGEST_DUPLICATE, nil
+		}
+		if !db.IsErrorNotFound(err) {
+			return "", "", err
+		}
+		err = nil
+	}
+	txHelper := dbhelper.NewTxHelper(basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+	recordId, err = ingest(connection, item, tx)
+	if err != nil {
+		return "", "", err
+	}
+	if idempotencyKey != "" {
+		err = tx.Create(&models.WebhookIdempotencyKey{
+			ConnectionId:   connection.ID,
+			EntityType:     entityType,
+			IdempotencyKey: idempotencyKey,
+			RecordId:       recordId,
+			CreatedAt:      time.Now(),
+		})
+		if err != nil {
+			// a concurrent delivery with the same key won the race, keep err so the transaction gets rolled back
+			if tx.IsDuplicationError(err) {
+				return recordId, INGEST_DUPLICATE, err
+			}
+			return "", "", err
+		}
+	}
+	return recordId, INGEST_ACCEPTED, nil
+}
@@ -27,12 +27,11 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/webhook/models"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type WebhookIssueRequest struct {
@@ -60,11 +59,15 @@ type WebhookIssueRequest struct {
 	AssigneeName            string     `mapstructure:"assigneeName"`
 	Severity                string     `mapstructure:"severity"`
 	Component               string     `mapstructure:"component"`
+	// CicdScopeId and CausedByDeploymentId attribute an incident to a service and the deployment causing it,
+	// dora falls back to attribute incidents by time when they are absent
+	CicdScopeId          string `mapstructure:"cicdScopeId"`
+	CausedByDeploymentId string `mapstructure:"causedByDeploymentId"`
 	//IconURL               string
 	//DeploymentId          string
 }
 
-func saveIncidentRelatedRecordsFromIssue(db dal.Transaction, logger log.Logger, issueBoarId string, issue *ticket.Issue) error {
+func saveIncidentRelatedRecordsFromIssue(db dal.Transaction, logger log.Logger, issueBoarId string, issue *ticket.Issue, request *WebhookIssueRequest) error {
 	incident, err := issue.ToIncident(issueBoarId)
 	if err != nil {
 		return err
@@ -79,12 +82,25 @@ func saveIncidentRelatedRecordsFromIssue(db dal.Transaction, logger log.Logger,
 	if err := db.CreateOrUpdate(assignee); err != nil {
 		return err
 	}
+	if request.CicdScopeId == "" && request.CausedByDeploymentId == "" {
+		// the incident might be attributed by a previous request
+		return db.Delete(&crossdomain.IncidentAttribution{}, dal.Where("incident_id = ?", incident.Id))
+	}
+	attribution := &crossdomain.IncidentAttribution{
+		IncidentId:           incident.Id,
+		CicdScopeId:          request.CicdScopeId,
+		CausedByDeploymentId: request.CausedByDeploymentId,
+	}
+	if err := db.CreateOrUpdate(attribution); err != nil {
+		return err
+	}
 	return nil
 }
 
 // PostIssue
 // @Summary receive a record as defined and save it
-// @Description receive a record as follow and save it, example: {"url":"","issue_key":"DLK-1234","title":"a feature from DLK","description":"","epic_key":"","type":"BUG","status":"TODO","original_status":"created","story_point":0,"resolution_date":null,"created_date":"2020-01-01T12:00:00+00:00","updated_date":null,"lead_time_minutes":0,"parent_issue_key":"DLK-1200","priority":"","original_estimate_minutes":0,"time_spent_minutes":0,"time_remaining_minutes":0,"creator_id":"user1131","creator_name":"Nick name 1","assignee_id":"user1132","assignee_name":"Nick name 2","severity":"","component":""}
+// @Description receive a record as follow and save it, example: {"url":"","issue_key":"DLK-1234","title":"a feature from DLK","description":"","epic_key":"","type":"BUG","status":"TODO","original_status":"created","story_point":0,"resolution_date":null,"created_date":"2020-01-01T12:00:00+00:00","updated_date":null,"lead_time_minutes":0,"parent_issue_key":"DLK-1200","priority":"","original_estimate_minutes":0,"time_spent_minutes":0,"time_remaining_minutes":0,"creator_id":"user1131","creator_name":"Nick name 1","assignee_id":"user1132","assignee_name":"Nick name 2","severity":"","component":""}<br/>
+// @Description incidents may carry "cicdScopeId" and "causedByDeploymentId" to attribute them to a service and deployment explicitly
 // @Tags plugins/webhook
 // @Param body body WebhookIssueRequest true "json body"
 // @Success 200  {string} noResponse ""
@@ -99,7 +115,8 @@ func PostIssue(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, error
 
 // PostIssueByName
 // @Summary receive a record as defined and save it
-// @Description receive a record as follow and save it, example: {"url":"","issue_key":"DLK-1234","title":"a feature from DLK","description":"","epic_key":"","type":"BUG","status":"TODO","original_status":"created","story_point":0,"resolution_date":null,"created_date":"2020-01-01T12:00:00+00:00","updated_date":null,"lead_time_minutes":0,"parent_issue_key":"DLK-1200","priority":"","original_estimate_minutes":0,"time_spent_minutes":0,"time_remaining_minutes":0,"creator_id":"user1131","creator_name":"Nick name 1","assignee_id":"user1132","assignee_name":"Nick name 2","severity":"","component":""}
+// @Description receive a record as follow and save it, example: {"url":"","issue_key":"DLK-1234","title":"a feature from DLK","description":"","epic_key":"","type":"BUG","status":"TODO","original_status":"created","story_point":0,"resolution_date":null,"created_date":"2020-01-01T12:00:00+00:00","updated_date":null,"lead_time_minutes":0,"parent_issue_key":"DLK-1200","priority":"","original_estimate_minutes":0,"time_spent_minutes":0,"time_remaining_minutes":0,"creator_id":"user1131","creator_name":"Nick name 1","assignee_id":"user1132","assignee_name":"Nick name 2","severity":"","component":""}<br/>
+// @Description incidents may carry "cicdScopeId" and "causedByDeploymentId" to attribute them to a service and deployment explicitly
 // @Tags plugins/webhook
 // @Param body body WebhookIssueRequest true "json body"
 // @Success 200  {string} noResponse ""
@@ -112,25 +129,70 @@ func PostIssueByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 	return postIssue(input, err, connection)
 }
 
+// PostIssuesBulk
+// @Summary receive records in bulk and save them
+// @Description receive multiple issues and save them, example: {"items":[{"idempotencyKey":"DLK-1234-created","issueKey":"DLK-1234",...}]}<br/>
+// @Description Every item is saved in its own transaction, the response reports whether each item was ACCEPTED, REJECTED or a DUPLICATE of an earlier delivery with the same idempotencyKey.
+// @Tags plugins/webhook
+// @Param body body WebhookBulkReq true "json body"
+// @Success 200  {object} WebhookBulkResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/:connectionId/issues/bulk [POST]
+func PostIssuesBulk(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	return postBulk(input, connection, ENTITY_ISSUE, ingestIssue)
+}
+
+// PostIssuesBulkByName
+// @Summary receive records in bulk and save them
+// @Description receive multiple issues and save them, example: {"items":[{"idempotencyKey":"DLK-1234-created","issueKey":"DLK-1234",...}]}<br/>
+// @Description Every item is saved in its own transaction, the response reports whether each item was ACCEPTED, REJECTED or a DUPLICATE of an earlier delivery with the same idempotencyKey.
+// @Tags plugins/webhook
+// @Param body body WebhookBulkReq true "json body"
+// @Success 200  {object} WebhookBulkResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/by-name/:connectionName/issues/bulk [POST]
+func PostIssuesBulkByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.FirstByName(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	return postBulk(input, connection, ENTITY_ISSUE, ingestIssue)
+}
+
 func postIssue(input *plugin.ApiResourceInput, err errors.Error, connection *models.WebhookConnection) (*plugin.ApiResourceOutput, errors.Error) {
 	if err != nil {
 		return nil, err
 	}
-	// get request
+	return postSingle(input, connection, ENTITY_ISSUE, ingestIssue)
+}
+
+func ingestIssue(connection *models.WebhookConnection, item map[string]interface{}, tx dal.Transaction) (string, errors.Error) {
 	request := &WebhookIssueRequest{}
-	err = helper.DecodeMapStruct(input.Body, request, true)
+	err := helper.DecodeMapStruct(item, request, true)
 	if err != nil {
-		return &plugin.ApiResourceOutput{Body: err.Error(), Status: http.StatusBadRequest}, nil
+		return "", errors.BadInput.Wrap(err, `input json error`)
+	}
+	if e := vld.Struct(request); e != nil {
+		return "", errors.BadInput.Wrap(e, `input json error`)
 	}
-	// validate
-	vld = validator.New()
-	err = errors.Convert(vld.Struct(request))
+	domainIssue, err := CreateIssue(connection, request, tx, logger)
 	if err != nil {
-		return &plugin.ApiResourceOutput{Body: err.Error(), Status: http.StatusBadRequest}, nil
+		return "", err
 	}
-	txHelper := dbhelper.NewTxHelper(basicRes, &err)
-	defer txHelper.End()
-	tx := txHelper.Begin()
+	return domainIssue.Id, nil
+}
+
+func CreateIssue(connection *models.WebhookConnection, request *WebhookIssueRequest, tx dal.Transaction, logger log.Logger) (*ticket.Issue, errors.Error) {
 	domainIssue := &ticket.Issue{
 		DomainEntity: domainlayer.DomainEntity{
 			Id: fmt.Sprintf("%s:%d:%s", "webhook", connection.ID, request.IssueKey),
@@ -211,13 +273,13 @@ func postIssue(input *plugin.ApiResourceInput, err errors.Error, connection *mod
 		return nil, err
 	}
 	if domainIssue.IsIncident() {
-		if err := saveIncidentRelatedRecordsFromIssue(tx, logger, domainBoardId, domainIssue); err != nil {
+		if err := saveIncidentRelatedRecordsFromIssue(tx, logger, domainBoardId, domainIssue, request); err != nil {
 			logger.Error(err, "failed to save incident related records")
 			return nil, errors.Convert(err)
 		}
 	}
 
-	return &plugin.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
+	return domainIssue, nil
 }
 
 // CloseIssue
@@ -252,6 +314,10 @@ func closeIssue(input *plugin.ApiResourceInput, err errors.Error, connection *mo
 	if err != nil {
 		return nil, err
 	}
+	err = verifySignature(connection, input.Header, input.RawBody, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
 	txHelper := dbhelper.NewTxHelper(basicRes, &err)
 	defer txHelper.End()
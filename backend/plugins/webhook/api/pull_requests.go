@@ -19,15 +19,11 @@ package api
 
 import (
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/log"
 
-	"github.com/apache/incubator-devlake/helpers/dbhelper"
-	"github.com/go-playground/validator/v10"
-
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/domainlayer"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
@@ -104,31 +100,70 @@ func PostPullRequestsByName(input *plugin.ApiResourceInput) (*plugin.ApiResource
 	return postPullRequests(input, connection, err)
 }
 
+// PostPullRequestsBulk
+// @Summary create pull requests in bulk by webhook
+// @Description Create multiple pull requests by webhook, example: {"items":[{"idempotencyKey":"pr1-opened","id":"pr1",...}]}<br/>
+// @Description Every item is saved in its own transaction, the response reports whether each item was ACCEPTED, REJECTED or a DUPLICATE of an earlier delivery with the same idempotencyKey.
+// @Tags plugins/webhook
+// @Param body body WebhookBulkReq true "json body"
+// @Success 200  {object} WebhookBulkResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/:connectionId/pull_requests/bulk [POST]
+func PostPullRequestsBulk(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.First(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	return postBulk(input, connection, ENTITY_PULL_REQUEST, ingestPullRequest)
+}
+
+// PostPullRequestsBulkByName
+// @Summary create pull requests in bulk by webhook name
+// @Description Create multiple pull requests by webhook name, example: {"items":[{"idempotencyKey":"pr1-opened","id":"pr1",...}]}<br/>
+// @Description Every item is saved in its own transaction, the response reports whether each item was ACCEPTED, REJECTED or a DUPLICATE of an earlier delivery with the same idempotencyKey.
+// @Tags plugins/webhook
+// @Param body body WebhookBulkReq true "json body"
+// @Success 200  {object} WebhookBulkResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/webhook/connections/by-name/:connectionName/pull_requests/bulk [POST]
+func PostPullRequestsBulkByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.WebhookConnection{}
+	err := connectionHelper.FirstByName(connection, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	return postBulk(input, connection, ENTITY_PULL_REQUEST, ingestPullRequest)
+}
+
 func postPullRequests(input *plugin.ApiResourceInput, connection *models.WebhookConnection, err errors.Error) (*plugin.ApiResourceOutput, errors.Error) {
 	if err != nil {
 		return nil, err
 	}
-	// get request
+	return postSingle(input, connection, ENTITY_PULL_REQUEST, ingestPullRequest)
+}
+
+func ingestPullRequest(connection *models.WebhookConnection, item map[string]interface{}, tx dal.Transaction) (string, errors.Error) {
 	request := &WebhookPullRequestReq{}
-	err = api.DecodeMapStruct(input.Body, request, true)
+	err := api.DecodeMapStruct(item, request, true)
 	if err != nil {
-		return &plugin.ApiResourceOutput{Body: err.Error(), Status: http.StatusBadRequest}, nil
+		return "", errors.BadInput.Wrap(err, `input json error`)
 	}
-	// validate
-	vld = validator.New()
-	err = errors.Convert(vld.Struct(request))
-	if err != nil {
-		return nil, errors.BadInput.Wrap(vld.Struct(request), `input json error`)
+	if e := vld.Struct(request); e != nil {
+		return "", errors.BadInput.Wrap(e, `input json error`)
 	}
-	txHelper := dbhelper.NewTxHelper(basicRes, &err)
-	defer txHelper.End()
-	tx := txHelper.Begin()
 	if err := CreatePullRequest(connection, request, tx, logger); err != nil {
-		logger.Error(err, "create pull requests")
-		return nil, err
+		return "", err
 	}
+	return generatePullRequestId(connection.ID, request.PullRequestKey), nil
+}
 
-	return &plugin.ApiResourceOutput{Body: nil, Status: http.StatusOK}, nil
+func generatePullRequestId(connectionId uint64, pullRequestKey int) string {
+	return fmt.Sprintf("%s:%d:%d", "webhook", connectionId, pullRequestKey)
 }
 
 func CreatePullRequest(connection *models.WebhookConnection, request *WebhookPullRequestReq, tx dal.Transaction, logger log.Logger) errors.Error {
@@ -139,7 +174,7 @@ func CreatePullRequest(connection *models.WebhookConnection, request *WebhookPul
 	// create a pull_request record
 	pullRequest := &code.PullRequest{
 		DomainEntity: domainlayer.DomainEntity{
-			Id: fmt.Sprintf("%s:%d:%d", "webhook", connection.ID, request.PullRequestKey),
+			Id: generatePullRequestId(connection.ID, request.PullRequestKey),
 		},
 		BaseRepoId:     fmt.Sprintf("%s:%d", "webhook", connection.ID),
 		HeadRepoId:     request.HeadRepoId,
@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+)
+
+const (
+	SIGNATURE_HEADER       = "X-Devlake-Signature"
+	SIGNATURE_TS_HEADER    = "X-Devlake-Timestamp"
+	IDEMPOTENCY_KEY_HEADER = "Idempotency-Key"
+
+	signaturePrefix    = "sha256="
+	signatureTolerance = 5 * time.Minute
+)
+
+// SignPayload computes the value expected in the X-Devlake-Signature header: the hex encoded
+// HMAC-SHA256 of `<timestamp>.<body>` keyed by the signing secret of the connection
+func SignPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature rejects requests whose signature doesn't match the body, or whose timestamp is
+// too far away from now to prevent replaying. Connections without signing secret accept any request.
+func verifySignature(connection *models.WebhookConnection, header http.Header, body []byte, now time.Time) errors.Error {
+	if connection.SigningSecret == "" {
+		return nil
+	}
+	timestamp := header.Get(SIGNATURE_TS_HEADER)
+	signature := header.Get(SIGNATURE_HEADER)
+	if timestamp == "" || signature == "" {
+		return errors.Unauthorized.New("request signature is required for this connection")
+	}
+	unix, e := strconv.ParseInt(timestamp, 10, 64)
+	if e != nil {
+		return errors.Unauthorized.New("invalid request signature timestamp")
+	}
+	skew := now.Sub(time.Unix(unix, 0))
+	if skew > signatureTolerance || skew < -signatureTolerance {
+		return errors.Unauthorized.New("request signature timestamp is out of tolerance")
+	}
+	expected := SignPayload(connection.SigningSecret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.Unauthorized.New("request signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/webhook/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"items":[{"id":"deploy1"}]}`)
+	connection := &models.WebhookConnection{SigningSecret: "s3cret"}
+	signedHeader := func(secret string, ts time.Time, payload []byte) http.Header {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		header := http.Header{}
+		header.Set(SIGNATURE_TS_HEADER, timestamp)
+		header.Set(SIGNATURE_HEADER, SignPayload(secret, timestamp, payload))
+		return header
+	}
+
+	t.Run("connection without secret accepts unsigned requests", func(t *testing.T) {
+		assert.Nil(t, verifySignature(&models.WebhookConnection{}, http.Header{}, body, now))
+	})
+	t.Run("valid signature", func(t *testing.T) {
+		assert.Nil(t, verifySignature(connection, signedHeader("s3cret", now.Add(-time.Minute), body), body, now))
+	})
+	t.Run("missing signature", func(t *testing.T) {
+		err := verifySignature(connection, http.Header{}, body, now)
+		assert.Equal(t, errors.Unauthorized, err.GetType())
+	})
+	t.Run("wrong secret", func(t *testing.T) {
+		err := verifySignature(connection, signedHeader("other", now, body), body, now)
+		assert.Equal(t, errors.Unauthorized, err.GetType())
+	})
+	t.Run("tampered body", func(t *testing.T) {
+		err := verifySignature(connection, signedHeader("s3cret", now, body), []byte(`{"items":[]}`), now)
+		assert.Equal(t, errors.Unauthorized, err.GetType())
+	})
+	t.Run("stale timestamp", func(t *testing.T) {
+		err := verifySignature(connection, signedHeader("s3cret", now.Add(-10*time.Minute), body), body, now)
+		assert.Equal(t, errors.Unauthorized, err.GetType())
+	})
+}
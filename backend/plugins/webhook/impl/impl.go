@@ -57,6 +57,7 @@ func (p Webhook) Init(basicRes context.BasicRes) errors.Error {
 func (p Webhook) GetTablesInfo() []dal.Tabler {
 	return []dal.Tabler{
 		&models.WebhookConnection{},
+		&models.WebhookIdempotencyKey{},
 	}
 }
 
@@ -90,24 +91,42 @@ func (p Webhook) ApiResources() map[string]map[string]plugin.ApiResourceHandler
 		"connections/:connectionId/deployments": {
 			"POST": api.PostDeployments,
 		},
+		"connections/:connectionId/deployments/bulk": {
+			"POST": api.PostDeploymentsBulk,
+		},
 		"connections/:connectionId/pull_requests": {
 			"POST": api.PostPullRequests,
 		},
+		"connections/:connectionId/pull_requests/bulk": {
+			"POST": api.PostPullRequestsBulk,
+		},
 		"connections/:connectionId/issues": {
 			"POST": api.PostIssue,
 		},
+		"connections/:connectionId/issues/bulk": {
+			"POST": api.PostIssuesBulk,
+		},
 		"connections/:connectionId/issue/:issueKey/close": {
 			"POST": api.CloseIssue,
 		},
 		":connectionId/deployments": {
 			"POST": api.PostDeployments,
 		},
+		":connectionId/deployments/bulk": {
+			"POST": api.PostDeploymentsBulk,
+		},
 		":connectionId/pull_requests": {
 			"POST": api.PostPullRequests,
 		},
+		":connectionId/pull_requests/bulk": {
+			"POST": api.PostPullRequestsBulk,
+		},
 		":connectionId/issues": {
 			"POST": api.PostIssue,
 		},
+		":connectionId/issues/bulk": {
+			"POST": api.PostIssuesBulk,
+		},
 		":connectionId/issue/:issueKey/close": {
 			"POST": api.CloseIssue,
 		},
@@ -119,12 +138,21 @@ func (p Webhook) ApiResources() map[string]map[string]plugin.ApiResourceHandler
 		"connections/by-name/:connectionName/deployments": {
 			"POST": api.PostDeploymentsByName,
 		},
+		"connections/by-name/:connectionName/deployments/bulk": {
+			"POST": api.PostDeploymentsBulkByName,
+		},
 		"connections/by-name/:connectionName/pull_requests": {
 			"POST": api.PostPullRequestsByName,
 		},
+		"connections/by-name/:connectionName/pull_requests/bulk": {
+			"POST": api.PostPullRequestsBulkByName,
+		},
 		"connections/by-name/:connectionName/issues": {
 			"POST": api.PostIssueByName,
 		},
+		"connections/by-name/:connectionName/issues/bulk": {
+			"POST": api.PostIssuesBulkByName,
+		},
 		"connections/by-name/:connectionName/issue/:issueKey/close": {
 			"POST": api.CloseIssueByName,
 		},
@@ -18,13 +18,21 @@ limitations under the License.
 package models
 
 import (
+	"github.com/apache/incubator-devlake/core/utils"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 )
 
 type WebhookConnection struct {
 	helper.BaseConnection `mapstructure:",squash"`
+	// SigningSecret enables HMAC verification of incoming requests when set
+	SigningSecret string `mapstructure:"signingSecret" json:"signingSecret" gorm:"serializer:encdec"`
 }
 
 func (WebhookConnection) TableName() string {
 	return "_tool_webhook_connections"
 }
+
+func (connection WebhookConnection) Sanitize() WebhookConnection {
+	connection.SigningSecret = utils.SanitizeString(connection.SigningSecret)
+	return connection
+}
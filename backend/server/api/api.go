@@ -18,12 +18,17 @@ limitations under the License.
 package api
 
 import (
+	gocontext "context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	// "github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -36,6 +41,7 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/impls/logruslog"
 	_ "github.com/apache/incubator-devlake/server/api/docs"
+	"github.com/apache/incubator-devlake/server/api/metrics"
 	"github.com/apache/incubator-devlake/server/api/ping"
 	"github.com/apache/incubator-devlake/server/api/shared"
 	"github.com/apache/incubator-devlake/server/api/version"
@@ -102,6 +108,7 @@ func CreateApiServer() *gin.Engine {
 	router.GET("/ping", ping.Get)
 	router.GET("/ready", ping.Ready)
 	router.GET("/health", ping.Health)
+	router.GET("/metrics", metrics.Get)
 	router.GET("/version", version.Get)
 
 	// Api keys
@@ -172,10 +179,25 @@ func RunApiServer(router *gin.Engine) {
 	}
 
 	// Start the server
-	err = router.Run(fmt.Sprintf(":%d", portNum))
-	if err != nil {
-		panic(err)
+	server := &http.Server{Addr: fmt.Sprintf(":%d", portNum), Handler: router}
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	// Stop accepting requests and release resources of services on SIGINT/SIGTERM
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	<-sigc
+	basicRes.GetLogger().Info("shutting down the api server")
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		basicRes.GetLogger().Error(err, "failed to shut down the api server gracefully")
 	}
+	services.Shutdown()
 }
 
 func registerExtraOpenApiSpecs(router *gin.Engine) {
@@ -198,6 +198,48 @@ func Trigger(c *gin.Context) {
 	shared.ApiOutputSuccess(c, pipeline, http.StatusOK)
 }
 
+// @Summary plan blueprint
+// @Description generate the plan of a blueprint without running it, with subtasks to be executed, estimated api calls
+// @Description and the difference from the previous pipeline, only dry run is supported for now
+// @Tags framework/blueprints
+// @Accept application/json
+// @Param blueprintId path string true "blueprintId"
+// @Param dryRun query bool true "dryRun"
+// @Param skipCollectors body models.TriggerSyncPolicy false "json"
+// @Param fullSync body models.TriggerSyncPolicy false "json"
+// @Success 200 {object} services.BlueprintPlanPreview
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /blueprints/{blueprintId}/plan [Post]
+func Plan(c *gin.Context) {
+	blueprintId := c.Param("blueprintId")
+	id, err := strconv.ParseUint(blueprintId, 10, 64)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad blueprintID format supplied"))
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+	if !dryRun {
+		shared.ApiOutputError(c, errors.BadInput.New("only dryRun=true is supported, use /blueprints/{blueprintId}/trigger to run the blueprint"))
+		return
+	}
+
+	triggerSyncPolicy := &models.TriggerSyncPolicy{}
+	if c.Request.Body != nil && c.Request.ContentLength != 0 {
+		err = c.ShouldBindJSON(triggerSyncPolicy)
+		if err != nil {
+			shared.ApiOutputError(c, errors.BadInput.Wrap(err, "error binding request body"))
+			return
+		}
+	}
+	preview, err := services.DryRunBlueprint(id, triggerSyncPolicy)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error planning blueprint"))
+		return
+	}
+	shared.ApiOutputSuccess(c, preview, http.StatusOK)
+}
+
 // @Summary get pipelines by blueprint id
 // @Description get pipelines by blueprint id
 // @Tags framework/blueprints
@@ -0,0 +1,37 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var handler = promhttp.HandlerFor(metricshelper.Registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+
+// @Summary Metrics
+// @Description expose metrics of the pipelines, tasks, api requests and database in the Prometheus/OpenMetrics format
+// @Tags framework/metrics
+// @Produce plain
+// @Success 200
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /metrics [get]
+func Get(c *gin.Context) {
+	handler.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,201 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PaginatedNotificationRules struct {
+	NotificationRules []*models.NotificationRule `json:"notificationRules"`
+	Count             int64                      `json:"count"`
+}
+
+type PaginatedNotifications struct {
+	Notifications []*models.Notification `json:"notifications"`
+	Count         int64                  `json:"count"`
+}
+
+// @Summary Get list of notification rules
+// @Description GET /notification-rules?projectName=xxx&blueprintId=1&page=1&pageSize=10
+// @Tags framework/notifications
+// @Param projectName query string false "projectName"
+// @Param blueprintId query int false "blueprintId"
+// @Param page query int false "page"
+// @Param pageSize query int false "pageSize"
+// @Success 200  {object} PaginatedNotificationRules
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-rules [get]
+func GetNotificationRules(c *gin.Context) {
+	var query services.NotificationRuleQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	rules, count, err := services.GetNotificationRules(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting notification rules"))
+		return
+	}
+	shared.ApiOutputSuccess(c, PaginatedNotificationRules{NotificationRules: rules, Count: count}, http.StatusOK)
+}
+
+// @Summary Create a notification rule
+// @Description Create a notification rule, channelConfig depends on the channelType:
+// @Description slack: {"webhookUrl": "https://hooks.slack.com/services/xxx"}
+// @Description email: {"host": "smtp.example.com", "port": 587, "username": "", "password": "", "from": "devlake@example.com", "to": ["team@example.com"], "subject": "..."}
+// @Description http: {"url": "https://example.com/hook", "method": "POST", "contentType": "application/json", "headers": {"Authorization": "..."}}
+// @Description template is a go text/template, check services.NotificationTemplateData for available fields
+// @Tags framework/notifications
+// @Accept application/json
+// @Param rule body models.NotificationRule true "json"
+// @Success 200  {object} models.NotificationRule
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-rules [post]
+func PostNotificationRule(c *gin.Context) {
+	rule := &models.NotificationRule{}
+	err := c.ShouldBind(rule)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	rule, err = services.CreateNotificationRule(rule)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error creating notification rule"))
+		return
+	}
+	shared.ApiOutputSuccess(c, rule, http.StatusCreated)
+}
+
+// @Summary Get a notification rule
+// @Description Get a notification rule, secrets in channelConfig are masked
+// @Tags framework/notifications
+// @Param ruleId path int true "rule ID"
+// @Success 200  {object} models.NotificationRule
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-rules/{ruleId} [get]
+func GetNotificationRule(c *gin.Context) {
+	id, err := parseRuleId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	rule, err := services.GetNotificationRule(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting notification rule"))
+		return
+	}
+	shared.ApiOutputSuccess(c, rule, http.StatusOK)
+}
+
+// @Summary Patch a notification rule
+// @Description Patch a notification rule, masked secrets in channelConfig are left unchanged
+// @Tags framework/notifications
+// @Accept application/json
+// @Param ruleId path int true "rule ID"
+// @Param rule body models.NotificationRule true "json"
+// @Success 200  {object} models.NotificationRule
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-rules/{ruleId} [patch]
+func PatchNotificationRule(c *gin.Context) {
+	id, err := parseRuleId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	var body map[string]interface{}
+	if err := c.ShouldBind(&body); err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	rule, err := services.PatchNotificationRule(id, body)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error patching notification rule"))
+		return
+	}
+	shared.ApiOutputSuccess(c, rule, http.StatusOK)
+}
+
+// @Summary Delete a notification rule
+// @Description Delete a notification rule, the delivery history is kept
+// @Tags framework/notifications
+// @Param ruleId path int true "rule ID"
+// @Success 200
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-rules/{ruleId} [delete]
+func DeleteNotificationRule(c *gin.Context) {
+	id, err := parseRuleId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	err = services.DeleteNotificationRule(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting notification rule"))
+		return
+	}
+	shared.ApiOutputSuccess(c, nil, http.StatusOK)
+}
+
+// @Summary Get delivery history of notifications
+// @Description GET /notifications?ruleId=1&pipelineId=1&status=FAILED&page=1&pageSize=10
+// @Tags framework/notifications
+// @Param ruleId query int false "ruleId"
+// @Param pipelineId query int false "pipelineId"
+// @Param status query string false "PENDING, DELIVERED or FAILED"
+// @Param page query int false "page"
+// @Param pageSize query int false "pageSize"
+// @Success 200  {object} PaginatedNotifications
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notifications [get]
+func GetNotifications(c *gin.Context) {
+	var query services.NotificationQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	notifications, count, err := services.GetNotifications(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting notifications"))
+		return
+	}
+	shared.ApiOutputSuccess(c, PaginatedNotifications{Notifications: notifications, Count: count}, http.StatusOK)
+}
+
+func parseRuleId(c *gin.Context) (uint64, errors.Error) {
+	id, err := strconv.ParseUint(c.Param("ruleId"), 10, 64)
+	if err != nil {
+		return 0, errors.BadInput.Wrap(err, "bad ruleId format supplied")
+	}
+	return id, nil
+}
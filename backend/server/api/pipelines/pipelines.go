@@ -22,10 +22,12 @@ import (
 	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/server/api/shared"
 	"github.com/apache/incubator-devlake/server/services"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -168,6 +170,61 @@ func DownloadLogs(c *gin.Context) {
 	c.FileAttachment(archive, filepath.Base(archive))
 }
 
+// @Summary Stream events of a pipeline
+// @Description GET /pipelines/:pipelineId/events
+// @Description Server-Sent Events stream: a `snapshot` event with the pipeline and its tasks first, followed by
+// @Description `pipelineStatus`, `taskStatus`, `subtaskStarted`, `subtaskFinished` and `progress` events as they happen.
+// @Description The stream ends once the pipeline is finished.
+// @Tags framework/pipelines
+// @Produce text/event-stream
+// @Param pipelineId path int true "pipeline ID"
+// @Success 200  {object} services.PipelineEvent
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /pipelines/{pipelineId}/events [get]
+func GetEvents(c *gin.Context) {
+	pipelineId := c.Param("pipelineId")
+	id, err := strconv.ParseUint(pipelineId, 10, 64)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad pipelineID format supplied"))
+		return
+	}
+	// subscribe before taking the snapshot, so no event would be missed in between
+	events, unsubscribe := services.SubscribePipelineEvents(id)
+	defer unsubscribe()
+	pipeline, err := services.GetPipeline(id, true)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting pipeline"))
+		return
+	}
+	tasks, err := services.GetTasksWithLastStatus(id, true, nil)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting tasks"))
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("snapshot", gin.H{"pipeline": pipeline, "tasks": tasks})
+	c.Writer.Flush()
+	if services.IsFinishedStatus(pipeline.Status) {
+		return
+	}
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+			return !event.IsFinal()
+		case now := <-heartbeat.C:
+			c.SSEvent("heartbeat", now)
+			return true
+		}
+	})
+}
+
 // RerunPipeline rerun all failed tasks of the specified pipeline
 // @Summary rerun tasks
 // @Tags framework/pipelines
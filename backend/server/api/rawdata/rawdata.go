@@ -0,0 +1,168 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rawdata
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get list of raw data retention policies
+// @Description GET /raw-data-retention-policies
+// @Tags framework/raw-data
+// @Success 200  {object} []models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies [get]
+func GetRetentionPolicies(c *gin.Context) {
+	policies, err := services.GetRawDataRetentionPolicies()
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting raw data retention policies"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policies, http.StatusOK)
+}
+
+// @Summary Create a raw data retention policy
+// @Description Create a raw data retention policy, table could be a raw table name or a glob pattern like `_raw_github_*`
+// @Description keepCollections keeps records of the last N full collections of each `_raw_data_params` and the incremental ones after them, keepDays keeps records
+// @Description collected within the last N days, skipPersistence hands over collected records to the extractor in memory
+// @Tags framework/raw-data
+// @Accept application/json
+// @Param policy body models.RawDataRetentionPolicy true "json"
+// @Success 200  {object} models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies [post]
+func PostRetentionPolicy(c *gin.Context) {
+	policy := &models.RawDataRetentionPolicy{}
+	err := c.ShouldBind(policy)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	policy, err = services.CreateRawDataRetentionPolicy(policy)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error creating raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policy, http.StatusCreated)
+}
+
+// @Summary Get a raw data retention policy
+// @Description Get a raw data retention policy
+// @Tags framework/raw-data
+// @Param policyId path int true "policy ID"
+// @Success 200  {object} models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId} [get]
+func GetRetentionPolicy(c *gin.Context) {
+	id, err := parsePolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	policy, err := services.GetRawDataRetentionPolicy(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policy, http.StatusOK)
+}
+
+// @Summary Patch a raw data retention policy
+// @Description Patch a raw data retention policy
+// @Tags framework/raw-data
+// @Accept application/json
+// @Param policyId path int true "policy ID"
+// @Param policy body models.RawDataRetentionPolicy true "json"
+// @Success 200  {object} models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId} [patch]
+func PatchRetentionPolicy(c *gin.Context) {
+	id, err := parsePolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	var body map[string]interface{}
+	if err := c.ShouldBind(&body); err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	policy, err := services.PatchRawDataRetentionPolicy(id, body)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error patching raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policy, http.StatusOK)
+}
+
+// @Summary Delete a raw data retention policy
+// @Description Delete a raw data retention policy, records pruned already are not restored
+// @Tags framework/raw-data
+// @Param policyId path int true "policy ID"
+// @Success 200
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId} [delete]
+func DeleteRetentionPolicy(c *gin.Context) {
+	id, err := parsePolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	err = services.DeleteRawDataRetentionPolicy(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, nil, http.StatusOK)
+}
+
+// @Summary Prune raw tables
+// @Description Prune raw tables by the enabled retention policies right away instead of waiting for the scheduled job
+// @Tags framework/raw-data
+// @Success 200  {object} []services.RawDataPruneResult
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data/prune [post]
+func PostPrune(c *gin.Context) {
+	results, err := services.PruneRawData()
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error pruning raw data"))
+		return
+	}
+	shared.ApiOutputSuccess(c, results, http.StatusOK)
+}
+
+func parsePolicyId(c *gin.Context) (uint64, errors.Error) {
+	id, err := strconv.ParseUint(c.Param("policyId"), 10, 64)
+	if err != nil {
+		return 0, errors.BadInput.Wrap(err, "bad policyId format supplied")
+	}
+	return id, nil
+}
@@ -31,15 +31,18 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/server/api/blueprints"
 	"github.com/apache/incubator-devlake/server/api/domainlayer"
+	"github.com/apache/incubator-devlake/server/api/notifications"
 	"github.com/apache/incubator-devlake/server/api/pipelines"
 	"github.com/apache/incubator-devlake/server/api/plugininfo"
 	"github.com/apache/incubator-devlake/server/api/project"
 	"github.com/apache/incubator-devlake/server/api/push"
+	"github.com/apache/incubator-devlake/server/api/rawdata"
 	"github.com/apache/incubator-devlake/server/api/shared"
 	"github.com/apache/incubator-devlake/server/api/task"
 	"github.com/apache/incubator-devlake/server/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
@@ -51,6 +54,7 @@ func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	r.GET("/pipelines/:pipelineId/subtasks", task.GetSubtaskByPipeline)
 	r.POST("/pipelines/:pipelineId/rerun", pipelines.PostRerun)
 	r.GET("/pipelines/:pipelineId/logging.tar.gz", pipelines.DownloadLogs)
+	r.GET("/pipelines/:pipelineId/events", pipelines.GetEvents)
 
 	r.GET("/blueprints", blueprints.Index)
 	r.POST("/blueprints", blueprints.Post)
@@ -58,6 +62,7 @@ func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	r.DELETE("/blueprints/:blueprintId", blueprints.Delete)
 	r.GET("/blueprints/:blueprintId", blueprints.Get)
 	r.POST("/blueprints/:blueprintId/trigger", blueprints.Trigger)
+	r.POST("/blueprints/:blueprintId/plan", blueprints.Plan)
 	r.GET("/blueprints/:blueprintId/pipelines", blueprints.GetBlueprintPipelines)
 
 	r.POST("/tasks/:taskId/rerun", task.PostRerun)
@@ -86,6 +91,22 @@ func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	r.PUT("/api-keys/:apiKeyId", apikeys.PutApiKey)
 	r.DELETE("/api-keys/:apiKeyId", apikeys.DeleteApiKey)
 
+	// notification rules api
+	r.GET("/notification-rules", notifications.GetNotificationRules)
+	r.POST("/notification-rules", notifications.PostNotificationRule)
+	r.GET("/notification-rules/:ruleId", notifications.GetNotificationRule)
+	r.PATCH("/notification-rules/:ruleId", notifications.PatchNotificationRule)
+	r.DELETE("/notification-rules/:ruleId", notifications.DeleteNotificationRule)
+	r.GET("/notifications", notifications.GetNotifications)
+
+	// raw data retention api
+	r.GET("/raw-data-retention-policies", rawdata.GetRetentionPolicies)
+	r.POST("/raw-data-retention-policies", rawdata.PostRetentionPolicy)
+	r.GET("/raw-data-retention-policies/:policyId", rawdata.GetRetentionPolicy)
+	r.PATCH("/raw-data-retention-policies/:policyId", rawdata.PatchRetentionPolicy)
+	r.DELETE("/raw-data-retention-policies/:policyId", rawdata.DeleteRetentionPolicy)
+	r.POST("/raw-data/prune", rawdata.PostPrune)
+
 	// mount all api resources for all plugins
 	resources, err := services.GetPluginsApiResources()
 	if err != nil {
@@ -127,15 +148,19 @@ func handlePluginCall(basicRes context.BasicRes, pluginName string, handler plug
 		} else {
 			input.User = user
 		}
+		input.Header = c.Request.Header
 		if c.Request.Body != nil {
 			if strings.HasPrefix(c.Request.Header.Get("Content-Type"), "multipart/form-data;") {
 				input.Request = c.Request
 			} else {
-				shouldBindJSONErr := c.ShouldBindJSON(&input.Body)
+				shouldBindJSONErr := c.ShouldBindBodyWith(&input.Body, binding.JSON)
 				if shouldBindJSONErr != nil && shouldBindJSONErr.Error() != "EOF" {
 					shared.ApiOutputError(c, shouldBindJSONErr)
 					return
 				}
+				if rawBody, ok := c.Get(gin.BodyBytesKey); ok {
+					input.RawBody, _ = rawBody.([]byte)
+				}
 			}
 		}
 		output, err := handler(input)
@@ -74,6 +74,7 @@ func GetTaskByPipeline(c *gin.Context) {
 
 // GetSubtasksByPipeline return most recent subtasks
 // @Summary Get subtasks, only the most recent subtasks will be returned
+// @Description Each subtask carries the metrics of its last run: duration, rows read/written and http requests/retries/429s
 // @Tags framework/tasks
 // @Accept application/json
 // @Param pipelineId path int true "pipelineId"
@@ -19,16 +19,48 @@ package main
 
 import (
 	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
 	_ "github.com/apache/incubator-devlake/core/version"
+	"github.com/apache/incubator-devlake/impls/secretprovider"
 	"github.com/apache/incubator-devlake/server/api"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/spf13/cobra"
 )
 
 func main() {
 	v := config.GetConfig()
+	// file/kv providers only need the ENCRYPTION_SECRET to decrypt values written in the legacy format
 	encryptionSecret := v.GetString(plugin.EncodeKeyEnvStr)
-	if encryptionSecret == "" {
+	if encryptionSecret == "" && secretprovider.RequiresLegacySecret(v) {
 		panic("ENCRYPTION_SECRET must be set in environment variable or .env file")
 	}
-	api.CreateAndRunApiServer()
+	rootCmd := &cobra.Command{
+		Use:   "lake",
+		Short: "Run the DevLake api server",
+		Run: func(cmd *cobra.Command, args []string) {
+			api.CreateAndRunApiServer()
+		},
+	}
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "reencrypt-secrets",
+		Short: "Re-encrypt connections, pipeline/blueprint plans, task options and notification rules with the newest key",
+		Run: func(cmd *cobra.Command, args []string) {
+			services.InitForMaintenance()
+			defer services.Shutdown()
+			result := errors.Must1(services.ReencryptSecrets())
+			for table, count := range result {
+				cmd.Printf("%s: %d value(s) re-encrypted\n", table, count)
+			}
+		},
+	})
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "worker",
+		Short: "Run a worker executing tasks dispatched by the api server with TASK_EXECUTOR=distributed",
+		Run: func(cmd *cobra.Command, args []string) {
+			services.InitForWorker()
+			services.RunWorker()
+		},
+	})
+	errors.Must(rootCmd.Execute())
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/services"
 
@@ -85,6 +86,9 @@ func GetBlueprints(query *BlueprintQuery, shouldSanitize bool) ([]*models.Bluepr
 	if err != nil {
 		return nil, 0, err
 	}
+	for _, bp := range blueprints {
+		fillBlueprintNextRuns(bp)
+	}
 	if shouldSanitize {
 		for idx, bp := range blueprints {
 			if err := SanitizeBlueprint(bp); err != nil {
@@ -106,6 +110,7 @@ func GetBlueprint(blueprintId uint64, shouldSanitize bool) (*models.Blueprint, e
 		}
 		return nil, errors.Internal.Wrap(err, "error getting the blueprint from database")
 	}
+	fillBlueprintNextRuns(blueprint)
 	if shouldSanitize {
 		if err := SanitizeBlueprint(blueprint); err != nil {
 			return nil, errors.Convert(err)
@@ -159,15 +164,21 @@ func validateBlueprintAndMakePlan(blueprint *models.Blueprint) errors.Error {
 		blueprint.IsManual = true
 	}
 	if !blueprint.IsManual {
-		_, err = cron.ParseStandard(blueprint.CronConfig)
+		schedule, err := NewBlueprintSchedule(blueprint)
 		if err != nil {
-			return errors.Default.Wrap(err, "invalid cronConfig")
+			return err
+		}
+		if schedule.Next(time.Now()).IsZero() {
+			return errors.BadInput.New("the blueprint would never run with the cronConfig and blackoutWindows")
 		}
 	}
 	if blueprint.Mode == models.BLUEPRINT_MODE_ADVANCED {
 		if len(blueprint.Plan) == 0 {
 			return errors.BadInput.New("invalid plan")
 		}
+		if _, err := blueprint.Plan.Dependencies(); err != nil {
+			return errors.BadInput.Wrap(err, "invalid plan")
+		}
 	} else if blueprint.Mode == models.BLUEPRINT_MODE_NORMAL {
 		var e errors.Error
 		blueprint.Plan, e = MakePlanForBlueprint(blueprint, &blueprint.SyncPolicy)
@@ -194,6 +205,7 @@ func saveBlueprint(blueprint *models.Blueprint) (*models.Blueprint, errors.Error
 	if err != nil {
 		return nil, err
 	}
+	fillBlueprintNextRuns(blueprint)
 	// done
 	return blueprint, nil
 }
@@ -298,10 +310,11 @@ func reloadBlueprint(blueprint *models.Blueprint) errors.Error {
 		logger.Info("removed blueprint %d from cronjobs, cron id: %v", blueprint.ID, cronId)
 	}
 	if blueprint.Enable && !blueprint.IsManual {
-		if cronId, err := cronManager.AddJob(blueprint.CronConfig, &BlueprintJob{blueprint}); err != nil {
+		if schedule, err := NewBlueprintSchedule(blueprint); err != nil {
 			blueprintLog.Error(err, failToCreateCronJob)
 			return errors.Default.Wrap(err, "created cron job failed")
 		} else {
+			cronId := cronManager.Schedule(schedule, &BlueprintJob{blueprint})
 			bpCronIdMap[blueprint.ID] = cronId
 			logger.Info("added blueprint %d to cronjobs, cron id: %v, cron config: %s", blueprint.ID, cronId, blueprint.CronConfig)
 		}
@@ -309,17 +322,23 @@ func reloadBlueprint(blueprint *models.Blueprint) errors.Error {
 	return nil
 }
 
+// getBlueprintPlan returns the generated plan for NORMAL mode blueprints, or the user defined one for ADVANCED mode
+func getBlueprintPlan(blueprint *models.Blueprint, syncPolicy *models.SyncPolicy) (models.PipelinePlan, errors.Error) {
+	if blueprint.Mode != models.BLUEPRINT_MODE_NORMAL {
+		return blueprint.Plan, nil
+	}
+	plan, err := MakePlanForBlueprint(blueprint, syncPolicy)
+	if err != nil {
+		blueprintLog.Error(err, fmt.Sprintf("failed to MakePlanForBlueprint on blueprint:[%d][%s]", blueprint.ID, blueprint.Name))
+		return nil, err
+	}
+	return plan, nil
+}
+
 func createPipelineByBlueprint(blueprint *models.Blueprint, syncPolicy *models.SyncPolicy) (*models.Pipeline, errors.Error) {
-	var plan models.PipelinePlan
-	var err errors.Error
-	if blueprint.Mode == models.BLUEPRINT_MODE_NORMAL {
-		plan, err = MakePlanForBlueprint(blueprint, syncPolicy)
-		if err != nil {
-			blueprintLog.Error(err, fmt.Sprintf("failed to MakePlanForBlueprint on blueprint:[%d][%s]", blueprint.ID, blueprint.Name))
-			return nil, err
-		}
-	} else {
-		plan = blueprint.Plan
+	plan, err := getBlueprintPlan(blueprint, syncPolicy)
+	if err != nil {
+		return nil, err
 	}
 
 	newPipeline := models.NewPipeline{}
@@ -384,6 +403,9 @@ func MakePlanForBlueprint(blueprint *models.Blueprint, syncPolicy *models.SyncPo
 // ParallelizePipelinePlans merges multiple pipelines into one unified plan
 // by assuming they can be executed in parallel
 func ParallelizePipelinePlans(plans ...models.PipelinePlan) models.PipelinePlan {
+	if isAnyDagPlan(plans) {
+		return mergeDagPlans(false, plans...)
+	}
 	merged := make(models.PipelinePlan, 0)
 	// iterate all pipelineTasks and try to merge them into `merged`
 	for _, plan := range plans {
@@ -402,6 +424,9 @@ func ParallelizePipelinePlans(plans ...models.PipelinePlan) models.PipelinePlan
 // SequentializePipelinePlans merges multiple pipelines into one unified plan
 // by assuming they must be executed in sequential order
 func SequentializePipelinePlans(plans ...models.PipelinePlan) models.PipelinePlan {
+	if isAnyDagPlan(plans) {
+		return mergeDagPlans(true, plans...)
+	}
 	merged := make(models.PipelinePlan, 0)
 	// iterate all pipelineTasks and try to merge them into `merged`
 	for _, plan := range plans {
@@ -410,6 +435,59 @@ func SequentializePipelinePlans(plans ...models.PipelinePlan) models.PipelinePla
 	return merged
 }
 
+func isAnyDagPlan(plans []models.PipelinePlan) bool {
+	for _, plan := range plans {
+		if plan.IsDag() {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeDagPlans converts all plans to DAG and merges them into a single stage, ids of tasks would be
+// prefixed with the index of the plan if they collided with previous plans. Tasks without dependencies
+// of a plan depend on the last tasks of the previous plan if `sequential` is true
+func mergeDagPlans(sequential bool, plans ...models.PipelinePlan) models.PipelinePlan {
+	merged := make(models.PipelineStage, 0)
+	taken := make(map[string]bool)
+	var lastIds []string
+	for index, plan := range plans {
+		if plan.IsEmpty() {
+			continue
+		}
+		dag := plan.ToDag()[0]
+		prefix := ""
+		for _, task := range dag {
+			if taken[task.Id] {
+				prefix = fmt.Sprintf("%d.", index+1)
+				break
+			}
+		}
+		dependedOn := make(map[string]bool)
+		for _, task := range dag {
+			task.Id = prefix + task.Id
+			for i := range task.DependsOn {
+				task.DependsOn[i] = prefix + task.DependsOn[i]
+				dependedOn[task.DependsOn[i]] = true
+			}
+		}
+		for _, task := range dag {
+			if sequential && len(task.DependsOn) == 0 {
+				task.DependsOn = append(task.DependsOn, lastIds...)
+			}
+			taken[task.Id] = true
+		}
+		lastIds = lastIds[:0:0]
+		for _, task := range dag {
+			if !dependedOn[task.Id] {
+				lastIds = append(lastIds, task.Id)
+			}
+		}
+		merged = append(merged, dag...)
+	}
+	return models.PipelinePlan{merged}
+}
+
 // TriggerBlueprint triggers blueprint immediately
 func TriggerBlueprint(id uint64, triggerSyncPolicy *models.TriggerSyncPolicy, shouldSanitize bool) (*models.Pipeline, errors.Error) {
 	// load record from db
@@ -0,0 +1,358 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/runner"
+)
+
+// ESTIMATED_PAGE_SIZE is the assumed number of records returned by a single api call
+const ESTIMATED_PAGE_SIZE = 100
+
+// CollectorEstimate estimates the api calls of a collector by the records it collected previously
+type CollectorEstimate struct {
+	RawDataTable  string     `json:"rawDataTable"`
+	RawDataParams string     `json:"rawDataParams"`
+	Incremental   bool       `json:"incremental"`
+	Since         *time.Time `json:"since"`
+	Records       int64      `json:"records"`
+	ApiCalls      int64      `json:"apiCalls"`
+}
+
+// PlannedTask describes what a task of the plan would do
+type PlannedTask struct {
+	Stage             int                    `json:"stage"`
+	Plugin            string                 `json:"plugin"`
+	Options           map[string]interface{} `json:"options"`
+	Subtasks          []string               `json:"subtasks"`
+	Collectors        []*CollectorEstimate   `json:"collectors"`
+	EstimatedApiCalls int64                  `json:"estimatedApiCalls"`
+	Message           string                 `json:"message,omitempty"`
+}
+
+// PlannedTaskChange describes how a task changed since the previous pipeline
+type PlannedTaskChange struct {
+	Plugin          string                 `json:"plugin"`
+	Options         map[string]interface{} `json:"options"`
+	PreviousOptions map[string]interface{} `json:"previousOptions,omitempty"`
+	AddedSubtasks   []string               `json:"addedSubtasks"`
+	RemovedSubtasks []string               `json:"removedSubtasks"`
+}
+
+// PlanDiff compares a plan with the one of the previous pipeline, tasks are matched by the plugin
+// and scalar options like connectionId and the scope id
+type PlanDiff struct {
+	PreviousPipelineId uint64               `json:"previousPipelineId"`
+	AddedTasks         []*PlannedTask       `json:"addedTasks"`
+	RemovedTasks       []*PlannedTask       `json:"removedTasks"`
+	ChangedTasks       []*PlannedTaskChange `json:"changedTasks"`
+}
+
+// BlueprintPlanPreview is the outcome of dry running a blueprint
+type BlueprintPlanPreview struct {
+	Plan              models.PipelinePlan `json:"plan"`
+	Tasks             []*PlannedTask      `json:"tasks"`
+	EstimatedApiCalls int64               `json:"estimatedApiCalls"`
+	// Diff is nil if the blueprint has never been triggered
+	Diff *PlanDiff `json:"diff"`
+}
+
+// DryRunBlueprint generates the plan of the blueprint without executing it, and reports the subtasks to be run,
+// the estimated api calls and the difference from the plan of the previous pipeline
+func DryRunBlueprint(id uint64, triggerSyncPolicy *models.TriggerSyncPolicy) (*BlueprintPlanPreview, errors.Error) {
+	blueprint, err := GetBlueprint(id, false)
+	if err != nil {
+		return nil, err
+	}
+	syncPolicy := &models.SyncPolicy{TriggerSyncPolicy: *triggerSyncPolicy}
+	plan, err := getBlueprintPlan(blueprint, syncPolicy)
+	if err != nil {
+		return nil, err
+	}
+	preview := &BlueprintPlanPreview{
+		Plan:  plan,
+		Tasks: planTasks(plan, syncPolicy),
+	}
+	for _, task := range preview.Tasks {
+		task.Collectors, err = estimateApiCalls(task, syncPolicy)
+		if err != nil {
+			return nil, err
+		}
+		for _, collector := range task.Collectors {
+			task.EstimatedApiCalls += collector.ApiCalls
+		}
+		preview.EstimatedApiCalls += task.EstimatedApiCalls
+	}
+
+	previous := &models.Pipeline{}
+	err = db.First(previous, dal.Where("blueprint_id = ?", id), dal.Orderby("id DESC"))
+	if err != nil && !db.IsErrorNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		preview.Diff = diffPlannedTasks(planTasks(previous.Plan, syncPolicy), preview.Tasks)
+		preview.Diff.PreviousPipelineId = previous.ID
+		if err := SanitizePipeline(previous); err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	// options of the tasks refer to the ones of the plan, they would be sanitized as well
+	if err := SanitizePipeline(&models.Pipeline{Plan: plan}); err != nil {
+		return nil, errors.Convert(err)
+	}
+	return preview, nil
+}
+
+func planTasks(plan models.PipelinePlan, syncPolicy *models.SyncPolicy) []*PlannedTask {
+	tasks := make([]*PlannedTask, 0)
+	for stageIdx, stage := range plan {
+		for _, task := range stage {
+			plannedTask := &PlannedTask{
+				Stage:      stageIdx + 1,
+				Plugin:     task.Plugin,
+				Options:    task.Options,
+				Subtasks:   make([]string, 0),
+				Collectors: make([]*CollectorEstimate, 0),
+			}
+			subtasks, err := getPlannedSubtasks(task, syncPolicy)
+			if err != nil {
+				plannedTask.Message = err.Error()
+			} else {
+				plannedTask.Subtasks = subtasks
+			}
+			tasks = append(tasks, plannedTask)
+		}
+	}
+	return tasks
+}
+
+// getPlannedSubtasks returns the subtasks to be executed by the task in order
+func getPlannedSubtasks(task *models.PipelineTask, syncPolicy *models.SyncPolicy) ([]string, errors.Error) {
+	pluginMeta, err := plugin.GetPlugin(task.Plugin)
+	if err != nil {
+		return nil, err
+	}
+	pluginTask, ok := pluginMeta.(plugin.PluginTask)
+	if !ok {
+		return nil, errors.Default.New(fmt.Sprintf("plugin %s doesn't support PluginTask interface", task.Plugin))
+	}
+	subtaskMetas := pluginTask.SubTaskMetas()
+	subtasksFlag, err := runner.GetSubtasksFlag(subtaskMetas, task.Subtasks, syncPolicy)
+	if err != nil {
+		return nil, err
+	}
+	subtasks := make([]string, 0)
+	for _, subtaskMeta := range subtaskMetas {
+		if subtasksFlag[subtaskMeta.Name] {
+			subtasks = append(subtasks, subtaskMeta.Name)
+		}
+	}
+	return subtasks, nil
+}
+
+// estimateApiCalls estimates the api calls of the task by the records collected previously with the same params,
+// only the records collected by the latest run are counted if the collector would run incrementally
+func estimateApiCalls(task *PlannedTask, syncPolicy *models.SyncPolicy) ([]*CollectorEstimate, errors.Error) {
+	collectors := make([]*CollectorEstimate, 0)
+	hasCollector := false
+	for _, subtask := range task.Subtasks {
+		if strings.Contains(strings.ToLower(subtask), "collect") {
+			hasCollector = true
+		}
+	}
+	if !hasCollector {
+		return collectors, nil
+	}
+	prefix := fmt.Sprintf("_raw_%s_", task.Plugin)
+	states := make([]*models.CollectorLatestState, 0)
+	err := db.All(&states, dal.Where("raw_data_table LIKE ?", prefix+"%"), dal.Orderby("raw_data_table"))
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		if !isRawTableOfPlugin(state.RawDataTable, task.Plugin) || !matchRawDataParams(state.RawDataParams, task.Options) {
+			continue
+		}
+		collector := &CollectorEstimate{
+			RawDataTable:  state.RawDataTable,
+			RawDataParams: state.RawDataParams,
+			Incremental:   !syncPolicy.FullSync && state.LatestSuccessStart != nil,
+		}
+		clauses := []dal.Clause{
+			dal.From(state.RawDataTable),
+			dal.Where("params = ?", state.RawDataParams),
+		}
+		if collector.Incremental {
+			// the latest run started at LatestSuccessStart
+			collector.Since = state.LatestSuccessStart
+			clauses = append(clauses, dal.Where("created_at >= ?", state.LatestSuccessStart))
+		}
+		if db.HasTable(state.RawDataTable) {
+			collector.Records, err = db.Count(clauses...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// at least one call is needed to find out there is nothing new
+		collector.ApiCalls = (collector.Records + ESTIMATED_PAGE_SIZE - 1) / ESTIMATED_PAGE_SIZE
+		if collector.ApiCalls == 0 {
+			collector.ApiCalls = 1
+		}
+		collectors = append(collectors, collector)
+	}
+	return collectors, nil
+}
+
+// isRawTableOfPlugin checks if the raw table belongs to the plugin rather than another plugin
+// sharing the same prefix, i.e. `_raw_github_graphql_jobs` belongs to github_graphql instead of github
+func isRawTableOfPlugin(rawTable, pluginName string) bool {
+	prefix := fmt.Sprintf("_raw_%s_", pluginName)
+	if !strings.HasPrefix(rawTable, prefix) {
+		return false
+	}
+	for otherPlugin := range plugin.AllPlugins() {
+		if len(otherPlugin) > len(pluginName) && strings.HasPrefix(rawTable, fmt.Sprintf("_raw_%s_", otherPlugin)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchRawDataParams checks if all raw data params, i.e. `{"ConnectionId":1,"Name":"apache/incubator-devlake"}`,
+// are found in the task options, keys are compared case-insensitively
+func matchRawDataParams(rawDataParams string, options map[string]interface{}) bool {
+	params := make(map[string]interface{})
+	decoder := json.NewDecoder(strings.NewReader(rawDataParams))
+	decoder.UseNumber()
+	if decoder.Decode(&params) != nil || len(params) == 0 {
+		return false
+	}
+	normalizedOptions := make(map[string]string, len(options))
+	for key, value := range options {
+		normalizedOptions[strings.ToLower(key)] = formatOptionValue(value)
+	}
+	for key, value := range params {
+		optionValue, ok := normalizedOptions[strings.ToLower(key)]
+		if !ok || optionValue != formatOptionValue(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatOptionValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	}
+	bytes, _ := json.Marshal(value)
+	return string(bytes)
+}
+
+// plannedTaskKey identifies the task by its plugin and scalar options
+func plannedTaskKey(task *PlannedTask) string {
+	scalars := make(map[string]interface{})
+	for key, value := range task.Options {
+		if value != nil {
+			switch reflect.TypeOf(value).Kind() {
+			case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr:
+				continue
+			}
+		}
+		scalars[key] = value
+	}
+	bytes, _ := json.Marshal(scalars)
+	return task.Plugin + string(bytes)
+}
+
+func diffPlannedTasks(previousTasks, tasks []*PlannedTask) *PlanDiff {
+	diff := &PlanDiff{
+		AddedTasks:   make([]*PlannedTask, 0),
+		RemovedTasks: make([]*PlannedTask, 0),
+		ChangedTasks: make([]*PlannedTaskChange, 0),
+	}
+	previousTasksByKey := make(map[string][]*PlannedTask)
+	for _, previousTask := range previousTasks {
+		key := plannedTaskKey(previousTask)
+		previousTasksByKey[key] = append(previousTasksByKey[key], previousTask)
+	}
+	matched := make(map[*PlannedTask]bool)
+	for _, task := range tasks {
+		key := plannedTaskKey(task)
+		candidates := previousTasksByKey[key]
+		if len(candidates) == 0 {
+			diff.AddedTasks = append(diff.AddedTasks, task)
+			continue
+		}
+		previousTask := candidates[0]
+		previousTasksByKey[key] = candidates[1:]
+		matched[previousTask] = true
+		change := &PlannedTaskChange{
+			Plugin:          task.Plugin,
+			Options:         task.Options,
+			AddedSubtasks:   subtractSubtasks(task.Subtasks, previousTask.Subtasks),
+			RemovedSubtasks: subtractSubtasks(previousTask.Subtasks, task.Subtasks),
+		}
+		previousOptions, _ := json.Marshal(previousTask.Options)
+		options, _ := json.Marshal(task.Options)
+		if string(previousOptions) != string(options) {
+			change.PreviousOptions = previousTask.Options
+		}
+		if change.PreviousOptions != nil || len(change.AddedSubtasks) > 0 || len(change.RemovedSubtasks) > 0 {
+			diff.ChangedTasks = append(diff.ChangedTasks, change)
+		}
+	}
+	for _, previousTask := range previousTasks {
+		if !matched[previousTask] {
+			diff.RemovedTasks = append(diff.RemovedTasks, previousTask)
+		}
+	}
+	return diff
+}
+
+func subtractSubtasks(subtasks, others []string) []string {
+	result := make([]string, 0)
+	for _, subtask := range subtasks {
+		found := false
+		for _, other := range others {
+			if subtask == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, subtask)
+		}
+	}
+	return result
+}
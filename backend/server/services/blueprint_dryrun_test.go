@@ -0,0 +1,100 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRawDataParams(t *testing.T) {
+	options := map[string]interface{}{
+		"connectionId": float64(1),
+		"githubId":     float64(12345678),
+		"name":         "apache/incubator-devlake",
+		"fullName":     "apache/incubator-devlake",
+	}
+	assert.True(t, matchRawDataParams(`{"ConnectionId":1,"Name":"apache/incubator-devlake"}`, options))
+	assert.True(t, matchRawDataParams(`{"ConnectionId":1,"GithubId":12345678}`, options))
+	assert.False(t, matchRawDataParams(`{"ConnectionId":2,"Name":"apache/incubator-devlake"}`, options))
+	assert.False(t, matchRawDataParams(`{"ConnectionId":1,"BoardId":8}`, options))
+	assert.False(t, matchRawDataParams(`{}`, options))
+	assert.False(t, matchRawDataParams(`not json`, options))
+	assert.True(t, matchRawDataParams(`{"ConnectionId":1,"BoardId":8}`, map[string]interface{}{
+		"connectionId": 1,
+		"boardId":      uint64(8),
+	}))
+}
+
+func TestDiffPlannedTasks(t *testing.T) {
+	previousTasks := []*PlannedTask{
+		{
+			Plugin:   "jira",
+			Options:  map[string]interface{}{"connectionId": float64(1), "boardId": float64(8)},
+			Subtasks: []string{"collectIssues", "extractIssues", "collectSprints"},
+		},
+		{
+			Plugin:   "gitlab",
+			Options:  map[string]interface{}{"connectionId": float64(1), "projectId": float64(2)},
+			Subtasks: []string{"collectApiIssues"},
+		},
+		{
+			Plugin:   "dora",
+			Options:  map[string]interface{}{"projectName": "p", "tasks": []interface{}{"a"}},
+			Subtasks: []string{"calculateChangeLeadTime"},
+		},
+	}
+	tasks := []*PlannedTask{
+		{
+			Plugin:   "jira",
+			Options:  map[string]interface{}{"connectionId": 1, "boardId": 8},
+			Subtasks: []string{"collectIssues", "extractIssues", "collectIssueChangelogs"},
+		},
+		{
+			Plugin:   "github",
+			Options:  map[string]interface{}{"connectionId": 1, "githubId": 3},
+			Subtasks: []string{"collectApiIssues"},
+		},
+		{
+			Plugin:   "dora",
+			Options:  map[string]interface{}{"projectName": "p", "tasks": []interface{}{"a", "b"}},
+			Subtasks: []string{"calculateChangeLeadTime"},
+		},
+	}
+	diff := diffPlannedTasks(previousTasks, tasks)
+	assert.Equal(t, []*PlannedTask{tasks[1]}, diff.AddedTasks)
+	assert.Equal(t, []*PlannedTask{previousTasks[1]}, diff.RemovedTasks)
+	assert.Equal(t, []*PlannedTaskChange{
+		{
+			Plugin:          "jira",
+			Options:         tasks[0].Options,
+			AddedSubtasks:   []string{"collectIssueChangelogs"},
+			RemovedSubtasks: []string{"collectSprints"},
+		},
+		{
+			Plugin:          "dora",
+			Options:         tasks[2].Options,
+			PreviousOptions: previousTasks[2].Options,
+			AddedSubtasks:   []string{},
+			RemovedSubtasks: []string{},
+		},
+	}, diff.ChangedTasks)
+
+	assert.Empty(t, diffPlannedTasks(tasks, tasks).ChangedTasks)
+}
@@ -0,0 +1,214 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/robfig/cron/v3"
+)
+
+// BLUEPRINT_NEXT_RUNS is the number of upcoming runs in the output of blueprint apis
+const BLUEPRINT_NEXT_RUNS = 5
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+type blackoutWindow struct {
+	days  map[time.Weekday]bool
+	start time.Duration
+	end   time.Duration
+}
+
+// BlueprintSchedule wraps the cron schedule of a blueprint with time zone, jitter and blackout windows
+type BlueprintSchedule struct {
+	cron     cron.Schedule
+	location *time.Location
+	jitter   time.Duration
+	windows  []*blackoutWindow
+}
+
+var _ cron.Schedule = (*BlueprintSchedule)(nil)
+
+// NewBlueprintSchedule creates the schedule of the blueprint
+func NewBlueprintSchedule(blueprint *models.Blueprint) (*BlueprintSchedule, errors.Error) {
+	location := time.Local
+	if blueprint.TimeZone != "" {
+		var err error
+		location, err = time.LoadLocation(blueprint.TimeZone)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid timeZone %s", blueprint.TimeZone))
+		}
+	}
+	schedule, err := cron.ParseStandard(blueprint.CronConfig)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid cronConfig")
+	}
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+	s := &BlueprintSchedule{
+		cron:     schedule,
+		location: location,
+	}
+	if blueprint.JitterSeconds > 0 {
+		// derive the jitter from the id, so it stays the same across restarts
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(fmt.Sprintf("%d", blueprint.ID)))
+		s.jitter = time.Duration(h.Sum32()%uint32(blueprint.JitterSeconds+1)) * time.Second
+	}
+	for _, w := range blueprint.BlackoutWindows {
+		window, err := parseBlackoutWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		s.windows = append(s.windows, window)
+	}
+	return s, nil
+}
+
+func parseBlackoutWindow(w models.BlackoutWindow) (*blackoutWindow, errors.Error) {
+	window := &blackoutWindow{days: make(map[time.Weekday]bool)}
+	for _, day := range w.Days {
+		weekday, ok := weekdays[day]
+		if !ok {
+			return nil, errors.BadInput.New(fmt.Sprintf("invalid day %s of blackout window", day))
+		}
+		window.days[weekday] = true
+	}
+	if len(window.days) == 0 {
+		for _, weekday := range weekdays {
+			window.days[weekday] = true
+		}
+	}
+	var err errors.Error
+	if window.start, err = parseTimeOfDay(w.Start); err != nil {
+		return nil, err
+	}
+	if window.end, err = parseTimeOfDay(w.End); err != nil {
+		return nil, err
+	}
+	if window.end <= window.start {
+		window.end += 24 * time.Hour
+	}
+	return window, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, errors.Error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.BadInput.Wrap(err, fmt.Sprintf("invalid time %s of blackout window, HH:MM is expected", s))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// blackoutEnd returns the end of the blackout window containing t, or zero time if t is not blacked out
+func (s *BlueprintSchedule) blackoutEnd(t time.Time) time.Time {
+	t = t.In(s.location)
+	for _, w := range s.windows {
+		// the window might start on the previous day if it spans midnight
+		for _, offset := range []int{0, -1} {
+			y, m, d := t.AddDate(0, 0, offset).Date()
+			if !w.days[time.Date(y, m, d, 0, 0, 0, 0, s.location).Weekday()] {
+				continue
+			}
+			// times of day are taken as wall clock, days of DST transitions are not 24 hours long
+			start := s.timeOfDay(y, m, d, w.start)
+			end := s.timeOfDay(y, m, d, w.end)
+			if !t.Before(start) && t.Before(end) {
+				return end
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// timeOfDay returns the time of the day in the location of the schedule, tod might exceed 24 hours for the
+// end of windows spanning midnight
+func (s *BlueprintSchedule) timeOfDay(y int, m time.Month, d int, tod time.Duration) time.Time {
+	return time.Date(y, m, d, int(tod/time.Hour), int(tod%time.Hour/time.Minute), 0, 0, s.location)
+}
+
+// deferRun moves t to the end of blackout windows, zero time would be returned if windows never end
+func (s *BlueprintSchedule) deferRun(t time.Time) time.Time {
+	// windows are at most 24 hours long, so a week of adjacent windows means they never end
+	for i := 0; i <= 7*len(s.windows); i++ {
+		end := s.blackoutEnd(t)
+		if end.IsZero() {
+			return t
+		}
+		t = end
+	}
+	return time.Time{}
+}
+
+// Next returns the next time the blueprint should be triggered after t
+func (s *BlueprintSchedule) Next(t time.Time) time.Time {
+	// runs before t might be deferred after t by blackout windows, so look back for them
+	lookback := time.Duration(0)
+	if len(s.windows) > 0 {
+		lookback = 7 * 24 * time.Hour
+	}
+	for run := s.cron.Next(t.Add(-s.jitter - lookback)); !run.IsZero(); run = s.cron.Next(run) {
+		next := s.deferRun(run.Add(s.jitter))
+		if next.IsZero() {
+			return next
+		}
+		if next.After(t) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// NextRuns returns the upcoming n runs after t
+func (s *BlueprintSchedule) NextRuns(t time.Time, n int) []time.Time {
+	runs := make([]time.Time, 0, n)
+	for len(runs) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		runs = append(runs, t.In(s.location))
+	}
+	return runs
+}
+
+// fillBlueprintNextRuns fills the upcoming runs of blueprints scheduled by cron
+func fillBlueprintNextRuns(blueprint *models.Blueprint) {
+	if !blueprint.Enable || blueprint.IsManual {
+		return
+	}
+	schedule, err := NewBlueprintSchedule(blueprint)
+	if err != nil {
+		blueprintLog.Warn(err, "failed to compute next runs of blueprint %d", blueprint.ID)
+		return
+	}
+	blueprint.NextRuns = schedule.NextRuns(time.Now(), BLUEPRINT_NEXT_RUNS)
+}
@@ -0,0 +1,121 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintScheduleTimeZone(t *testing.T) {
+	schedule, err := NewBlueprintSchedule(&models.Blueprint{CronConfig: "0 2 * * *", TimeZone: "Asia/Shanghai"})
+	assert.Nil(t, err)
+	next := schedule.Next(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC), next.UTC())
+
+	_, err = NewBlueprintSchedule(&models.Blueprint{CronConfig: "0 2 * * *", TimeZone: "Mars/Olympus"})
+	assert.NotNil(t, err)
+}
+
+func TestBlueprintScheduleBlackoutWindows(t *testing.T) {
+	schedule, err := NewBlueprintSchedule(&models.Blueprint{
+		CronConfig: "0 * * * *",
+		TimeZone:   "UTC",
+		BlackoutWindows: []models.BlackoutWindow{
+			{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "09:00", End: "18:00"},
+		},
+	})
+	assert.Nil(t, err)
+	monday := func(hour, min int) time.Time {
+		return time.Date(2026, 10, 19, hour, min, 0, 0, time.UTC)
+	}
+	// runs during business hours are deferred and merged to the end of the window
+	assert.Equal(t, []time.Time{monday(18, 0), monday(19, 0), monday(20, 0)}, schedule.NextRuns(monday(8, 30), 3))
+	assert.Equal(t, monday(18, 0), schedule.Next(monday(12, 0)))
+	assert.Equal(t, monday(19, 0), schedule.Next(monday(18, 0)))
+	// weekends are not blacked out
+	saturday := time.Date(2026, 10, 17, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, saturday.Add(30*time.Minute), schedule.Next(saturday))
+
+	// windows spanning midnight
+	schedule, err = NewBlueprintSchedule(&models.Blueprint{
+		CronConfig:      "0 23 * * *",
+		TimeZone:        "UTC",
+		BlackoutWindows: []models.BlackoutWindow{{Start: "22:00", End: "06:00"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2026, 10, 20, 6, 0, 0, 0, time.UTC), schedule.Next(monday(12, 0)))
+
+	// windows never end
+	schedule, err = NewBlueprintSchedule(&models.Blueprint{
+		CronConfig:      "0 0 * * *",
+		BlackoutWindows: []models.BlackoutWindow{{Start: "00:00", End: "00:00"}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, schedule.Next(monday(12, 0)).IsZero())
+
+	// invalid windows
+	_, err = NewBlueprintSchedule(&models.Blueprint{
+		CronConfig:      "0 0 * * *",
+		BlackoutWindows: []models.BlackoutWindow{{Start: "9am", End: "18:00"}},
+	})
+	assert.NotNil(t, err)
+	_, err = NewBlueprintSchedule(&models.Blueprint{
+		CronConfig:      "0 0 * * *",
+		BlackoutWindows: []models.BlackoutWindow{{Days: []string{"Monday"}, Start: "09:00", End: "18:00"}},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestBlueprintScheduleBlackoutWindowsOnDstTransitions(t *testing.T) {
+	schedule, err := NewBlueprintSchedule(&models.Blueprint{
+		CronConfig:      "30 1 * * *",
+		TimeZone:        "Europe/Berlin",
+		BlackoutWindows: []models.BlackoutWindow{{Start: "01:00", End: "06:00"}},
+	})
+	assert.Nil(t, err)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	// the clock jumps from 02:00 to 03:00 on 2026-03-29, the window still ends at 06:00 of the wall clock
+	next := schedule.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2026, 3, 29, 6, 0, 0, 0, berlin), next)
+	assert.Equal(t, time.Date(2026, 3, 29, 4, 0, 0, 0, time.UTC), next.UTC())
+	// the clock falls back from 03:00 to 02:00 on 2026-10-25
+	next = schedule.Next(time.Date(2026, 10, 24, 12, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2026, 10, 25, 6, 0, 0, 0, berlin), next)
+	assert.Equal(t, time.Date(2026, 10, 25, 5, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestBlueprintScheduleJitter(t *testing.T) {
+	blueprint := &models.Blueprint{CronConfig: "0 0 * * *", TimeZone: "UTC", JitterSeconds: 1800}
+	blueprint.ID = 42
+	schedule, err := NewBlueprintSchedule(blueprint)
+	assert.Nil(t, err)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	next := schedule.Next(now)
+	midnight := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	assert.False(t, next.Before(midnight))
+	assert.False(t, next.After(midnight.Add(30*time.Minute)))
+	// deterministic
+	again, _ := NewBlueprintSchedule(blueprint)
+	assert.Equal(t, next, again.Next(now))
+	// the jittered run is not skipped if it was not passed yet
+	assert.Equal(t, next, schedule.Next(midnight))
+}
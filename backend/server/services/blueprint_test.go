@@ -93,6 +93,54 @@ func TestParallelizePipelineTasks(t *testing.T) {
 	)
 }
 
+func TestMergeDagPipelinePlans(t *testing.T) {
+	plan1 := coreModels.PipelinePlan{
+		{
+			{Plugin: "jira", Id: "a"},
+			{Plugin: "dora", Id: "b", DependsOn: []string{"a"}},
+		},
+	}
+	plan2 := coreModels.PipelinePlan{
+		{
+			{Plugin: "gitlab", Id: "a"},
+		},
+		{
+			{Plugin: "refdiff"},
+		},
+	}
+
+	assert.Equal(
+		t,
+		coreModels.PipelinePlan{
+			{
+				{Plugin: "jira", Id: "a", DependsOn: []string{}},
+				{Plugin: "dora", Id: "b", DependsOn: []string{"a"}},
+				{Plugin: "gitlab", Id: "2.a", DependsOn: []string{}},
+				{Plugin: "refdiff", Id: "2.2-1", DependsOn: []string{"2.a"}},
+			},
+		},
+		ParallelizePipelinePlans(plan1, plan2),
+	)
+	assert.Equal(
+		t,
+		coreModels.PipelinePlan{
+			{
+				{Plugin: "gitlab", Id: "a", DependsOn: []string{}},
+				{Plugin: "refdiff", Id: "2-1", DependsOn: []string{"a"}},
+				{Plugin: "jira", Id: "3.a", DependsOn: []string{"2-1"}},
+				{Plugin: "dora", Id: "3.b", DependsOn: []string{"3.a"}},
+			},
+		},
+		SequentializePipelinePlans(plan2, nil, plan1),
+	)
+	// merged plans are still valid
+	_, err := SequentializePipelinePlans(plan1, plan2, plan1).Dependencies()
+	assert.Nil(t, err)
+	// the original plans are untouched
+	assert.Equal(t, "a", plan2[0][0].Id)
+	assert.Nil(t, plan2[1][0].DependsOn)
+}
+
 func TestRemoveCollectorTasks(t *testing.T) {
 	plan1 := coreModels.PipelinePlan{
 		{
@@ -30,6 +30,7 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/runner"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/services"
+	"github.com/apache/incubator-devlake/helpers/tracinghelper"
 	"github.com/go-playground/validator/v10"
 	"github.com/robfig/cron/v3"
 )
@@ -44,6 +45,7 @@ var migrator plugin.Migrator
 var cronManager *cron.Cron
 var vld *validator.Validate
 var serviceStatus string
+var shutdownTracing func()
 
 const (
 	SERVICE_STATUS_INIT         = "initializing"
@@ -64,6 +66,12 @@ func InitResources() {
 	cfg = basicRes.GetConfigReader()
 	logger = basicRes.GetLogger()
 	db = basicRes.GetDal()
+	registerDbStatsCollector()
+	// spans are exported in batches, the pending ones get flushed by Shutdown
+	shutdownTracing, err = tracinghelper.Init(cfg)
+	if err != nil {
+		panic(err)
+	}
 	bpManager = services.NewBlueprintManager(db)
 	// initialize db migrator
 	migrator, err = runner.InitMigrator(basicRes)
@@ -74,6 +82,15 @@ func InitResources() {
 	migrator.Register(migrationscripts.All(), "Framework")
 }
 
+// Shutdown releases resources of the services module before the process exits, i.e. flushes the pending spans
+// and closes the tracing file
+func Shutdown() {
+	if shutdownTracing != nil {
+		shutdownTracing()
+		shutdownTracing = nil
+	}
+}
+
 // GetBasicRes returns the context.BasicRes instance used by services module
 func GetBasicRes() context.BasicRes {
 	return basicRes
@@ -125,6 +142,26 @@ func Init() {
 	registerPluginsMigrationScripts()
 }
 
+// InitForMaintenance initializes the services module for maintenance commands like `reencrypt-secrets`,
+// the database would be locked as well, so the server must be stopped beforehand
+func InitForMaintenance() {
+	InitResources()
+	lockDatabase()
+}
+
+// InitForWorker initializes the services module for worker processes, which share the database with the
+// api server, so neither the database is locked nor the migration scripts are executed
+func InitForWorker() {
+	InitResources()
+	errors.Must(runner.LoadPlugins(basicRes))
+	logger.Info("all plugins have been loaded")
+	registerPluginsMigrationScripts()
+	if migrator.HasPendingScripts() {
+		panic(errors.Default.New("the database has pending migration scripts, please start the api server first"))
+	}
+	plugin.InitPlugins(basicRes)
+}
+
 func InjectCustomService(customPipelineNotifier PipelineNotificationService, customProjectService ProjectService) errors.Error {
 	if customPipelineNotifier != nil {
 		customPipelineNotificationService = customPipelineNotifier
@@ -43,6 +43,13 @@ func lockDatabase() {
 		Version:  version.Version,
 	}
 	errors.Must(db.Create(lockingHistory))
+	if db.Dialect() == "sqlite" {
+		// sqlite is an embedded database serving a single instance, besides, a never released transaction
+		// would block writing from all other connections
+		lockingHistory.Succeeded = true
+		errors.Must(db.Update(lockingHistory))
+		return
+	}
 	// 2. obtain the lock: using a never released transaction
 	// This prevents multiple devlake instances from sharing the same database by locking the migration history table
 	// However, it would not work if any older devlake instances were already using the database.
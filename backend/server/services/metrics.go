@@ -0,0 +1,74 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
+	"github.com/apache/incubator-devlake/impls/dalgorm"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var pipelineQueueDepth = metricshelper.NewGauge(
+	"pipeline_queue_depth",
+	"Number of pipelines waiting to be executed.",
+)
+
+var runningTasksGauge = metricshelper.NewGaugeVec(
+	"running_tasks",
+	"Number of tasks being executed by this instance.",
+	"plugin",
+)
+
+var finishedPipelinesCounter = metricshelper.NewCounterVec(
+	"pipelines_finished_total",
+	"Number of finished pipelines, labelled by the blueprint, the project and the final status.",
+	"blueprint", "project", "status",
+)
+
+// registerDbStatsCollector exposes the stats of the database connection pool
+func registerDbStatsCollector() {
+	gormDal, ok := db.(*dalgorm.Dalgorm)
+	if !ok {
+		return
+	}
+	sqlDB, err := gormDal.SqlDB()
+	if err != nil {
+		logger.Warn(err, "unable to collect database stats")
+		return
+	}
+	if err := metricshelper.Registry.Register(collectors.NewDBStatsCollector(sqlDB, "devlake")); err != nil {
+		logger.Warn(err, "unable to register database stats collector")
+	}
+}
+
+// recordPipelineFinished counts the finished pipeline by its blueprint and project
+func recordPipelineFinished(pipeline *models.Pipeline) {
+	blueprintName, projectName := "", ""
+	if pipeline.BlueprintId != 0 {
+		blueprint := &models.Blueprint{}
+		err := db.First(blueprint, dal.Where("id = ?", pipeline.BlueprintId))
+		if err != nil {
+			globalPipelineLog.Warn(err, "unable to get blueprint #%d of pipeline #%d", pipeline.BlueprintId, pipeline.ID)
+		} else {
+			blueprintName, projectName = blueprint.Name, blueprint.ProjectName
+		}
+	}
+	finishedPipelinesCounter.WithLabelValues(blueprintName, projectName, pipeline.Status).Inc()
+}
@@ -0,0 +1,437 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+const defaultNotificationMaxRetries = 3
+
+// notificationRetryDelay is the delay before the first retry, it would be doubled for every retry after
+var notificationRetryDelay = 2 * time.Second
+
+const notificationMaxRetryDelay = time.Minute
+
+// NotificationRuleQuery used to query notification rules
+type NotificationRuleQuery struct {
+	Pagination
+	ProjectName string `form:"projectName"`
+	BlueprintId uint64 `form:"blueprintId"`
+}
+
+// NotificationQuery used to query the delivery history of notifications
+type NotificationQuery struct {
+	Pagination
+	RuleId     uint64 `form:"ruleId"`
+	PipelineId uint64 `form:"pipelineId"`
+	Status     string `form:"status"`
+}
+
+// GetNotificationRules returns a paginated list of notification rules based on `query`
+func GetNotificationRules(query *NotificationRuleQuery) ([]*models.NotificationRule, int64, errors.Error) {
+	clauses := []dal.Clause{dal.From(&models.NotificationRule{})}
+	if query.ProjectName != "" {
+		clauses = append(clauses, dal.Where("project_name = ?", query.ProjectName))
+	}
+	if query.BlueprintId != 0 {
+		clauses = append(clauses, dal.Where("blueprint_id = ?", query.BlueprintId))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of notification rules")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id DESC"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	rules := make([]*models.NotificationRule, 0)
+	err = db.All(&rules, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB notification rules")
+	}
+	for _, rule := range rules {
+		maskNotificationRule(rule)
+	}
+	return rules, count, nil
+}
+
+// GetNotificationRule returns the notification rule with secrets in channel config masked
+func GetNotificationRule(id uint64) (*models.NotificationRule, errors.Error) {
+	rule, err := getNotificationRule(id)
+	if err != nil {
+		return nil, err
+	}
+	maskNotificationRule(rule)
+	return rule, nil
+}
+
+func getNotificationRule(id uint64) (*models.NotificationRule, errors.Error) {
+	rule := &models.NotificationRule{}
+	err := db.First(rule, dal.Where("id = ?", id))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("notification rule(id: %d) not found", id))
+		}
+		return nil, errors.Internal.Wrap(err, "error getting the notification rule from database")
+	}
+	return rule, nil
+}
+
+// CreateNotificationRule accepts a notification rule instance and insert it to database
+func CreateNotificationRule(rule *models.NotificationRule) (*models.NotificationRule, errors.Error) {
+	rule.ID = 0
+	if err := validateNotificationRule(rule); err != nil {
+		return nil, err
+	}
+	if err := db.Create(rule); err != nil {
+		return nil, errors.Default.Wrap(err, "error creating notification rule")
+	}
+	maskNotificationRule(rule)
+	return rule, nil
+}
+
+// PatchNotificationRule updates the notification rule with the given body,
+// masked secrets in the channel config would be kept as is
+func PatchNotificationRule(id uint64, body map[string]interface{}) (*models.NotificationRule, errors.Error) {
+	rule, err := getNotificationRule(id)
+	if err != nil {
+		return nil, err
+	}
+	originChannelConfig := rule.ChannelConfig
+	err = helper.DecodeMapStruct(body, rule, true)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	unmaskNotificationRule(rule, originChannelConfig)
+	if err = validateNotificationRule(rule); err != nil {
+		return nil, err
+	}
+	if err = db.Update(rule); err != nil {
+		return nil, errors.Default.Wrap(err, "error updating notification rule")
+	}
+	maskNotificationRule(rule)
+	return rule, nil
+}
+
+// DeleteNotificationRule deletes the notification rule, the delivery history would be kept
+func DeleteNotificationRule(id uint64) errors.Error {
+	if _, err := getNotificationRule(id); err != nil {
+		return err
+	}
+	return db.Delete(&models.NotificationRule{}, dal.Where("id = ?", id))
+}
+
+// GetNotifications returns a paginated list of delivered notifications based on `query`
+func GetNotifications(query *NotificationQuery) ([]*models.Notification, int64, errors.Error) {
+	clauses := []dal.Clause{dal.From(&models.Notification{})}
+	if query.RuleId != 0 {
+		clauses = append(clauses, dal.Where("rule_id = ?", query.RuleId))
+	}
+	if query.PipelineId != 0 {
+		clauses = append(clauses, dal.Where("pipeline_id = ?", query.PipelineId))
+	}
+	if query.Status != "" {
+		clauses = append(clauses, dal.Where("status = ?", query.Status))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of notifications")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id DESC"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	notifications := make([]*models.Notification, 0)
+	err = db.All(&notifications, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB notifications")
+	}
+	return notifications, count, nil
+}
+
+func validateNotificationRule(rule *models.NotificationRule) errors.Error {
+	if err := VerifyStruct(rule); err != nil {
+		return err
+	}
+	if rule.MaxRetries < 0 {
+		return errors.BadInput.New("maxRetries must not be negative")
+	}
+	if _, err := newNotificationChannel(rule); err != nil {
+		return err
+	}
+	// render the templates with a fake pipeline to catch errors early
+	_, err := renderNotificationMessage(rule, &NotificationTemplateData{
+		Event:    rule.Events[0],
+		Pipeline: &models.Pipeline{Status: models.TASK_COMPLETED},
+	})
+	return err
+}
+
+func maskNotificationRule(rule *models.NotificationRule) {
+	for _, field := range notificationChannelSecretFields {
+		if v, ok := rule.ChannelConfig[field]; ok && v != nil && v != "" {
+			rule.ChannelConfig[field] = notificationSecretMask
+		}
+	}
+	for _, field := range notificationChannelSecretMapFields {
+		values, ok := toStringMap(rule.ChannelConfig[field])
+		if !ok {
+			continue
+		}
+		masked := make(map[string]interface{}, len(values))
+		for key, value := range values {
+			if value != "" {
+				value = notificationSecretMask
+			}
+			masked[key] = value
+		}
+		rule.ChannelConfig[field] = masked
+	}
+}
+
+// unmaskNotificationRule restores the masked secrets in the channel config of the rule from the stored one
+func unmaskNotificationRule(rule *models.NotificationRule, originChannelConfig map[string]interface{}) {
+	for _, field := range notificationChannelSecretFields {
+		if v, ok := rule.ChannelConfig[field].(string); ok && v == notificationSecretMask {
+			rule.ChannelConfig[field] = originChannelConfig[field]
+		}
+	}
+	for _, field := range notificationChannelSecretMapFields {
+		values, ok := toStringMap(rule.ChannelConfig[field])
+		if !ok {
+			continue
+		}
+		originValues, _ := toStringMap(originChannelConfig[field])
+		unmasked := make(map[string]interface{}, len(values))
+		for key, value := range values {
+			if value == notificationSecretMask {
+				// a header newly added with the mask as its value is dropped
+				originValue, ok := originValues[key]
+				if !ok {
+					continue
+				}
+				value = originValue
+			}
+			unmasked[key] = value
+		}
+		rule.ChannelConfig[field] = unmasked
+	}
+}
+
+// toStringMap converts the map decoded from json, i.e. headers of the channel config, to map[string]string
+func toStringMap(v interface{}) (map[string]string, bool) {
+	switch m := v.(type) {
+	case map[string]string:
+		return m, true
+	case map[string]interface{}:
+		result := make(map[string]string, len(m))
+		for key, value := range m {
+			result[key] = fmt.Sprintf("%v", value)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// getNotificationEvents maps the final status of the pipeline to notification events
+func getNotificationEvents(pipeline *models.Pipeline) ([]string, errors.Error) {
+	switch pipeline.Status {
+	case models.TASK_FAILED:
+		return []string{models.NOTIFICATION_EVENT_FAILURE}, nil
+	case models.TASK_PARTIAL:
+		return []string{models.NOTIFICATION_EVENT_PARTIAL}, nil
+	case models.TASK_COMPLETED:
+		events := []string{models.NOTIFICATION_EVENT_SUCCESS}
+		if pipeline.BlueprintId == 0 {
+			return events, nil
+		}
+		// it is a recovery if the previous finished pipeline of the same blueprint was not successful
+		previous := &models.Pipeline{}
+		err := db.First(previous,
+			dal.Where(
+				"blueprint_id = ? AND id < ? AND status IN ?",
+				pipeline.BlueprintId, pipeline.ID, []string{models.TASK_COMPLETED, models.TASK_FAILED, models.TASK_PARTIAL},
+			),
+			dal.Orderby("id DESC"),
+		)
+		if err != nil {
+			if db.IsErrorNotFound(err) {
+				return events, nil
+			}
+			return nil, err
+		}
+		if previous.Status != models.TASK_COMPLETED {
+			// prefer recovery to success for rules subscribed to both
+			events = append([]string{models.NOTIFICATION_EVENT_RECOVERY}, events...)
+		}
+		return events, nil
+	}
+	return nil, nil
+}
+
+func findMatchingNotificationRules(pipeline *models.Pipeline, projectName string) ([]*models.NotificationRule, errors.Error) {
+	rules := make([]*models.NotificationRule, 0)
+	err := db.All(&rules,
+		dal.Where(
+			"enable = ? AND (project_name = '' OR project_name = ?) AND (blueprint_id = 0 OR blueprint_id = ?)",
+			true, projectName, pipeline.BlueprintId,
+		),
+	)
+	return rules, err
+}
+
+// dispatchNotificationRules sends notifications of the finished pipeline to all matching rules in background
+func dispatchNotificationRules(pipeline *models.Pipeline, projectName string) {
+	events, err := getNotificationEvents(pipeline)
+	if err != nil {
+		globalPipelineLog.Error(err, "failed to determine notification events for pipeline #%d", pipeline.ID)
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+	rules, err := findMatchingNotificationRules(pipeline, projectName)
+	if err != nil {
+		globalPipelineLog.Error(err, "failed to find notification rules for pipeline #%d", pipeline.ID)
+		return
+	}
+	var data *NotificationTemplateData
+	for _, rule := range rules {
+		event := matchNotificationEvent(rule, events)
+		if event == "" {
+			continue
+		}
+		if data == nil {
+			data, err = newNotificationTemplateData(pipeline, projectName)
+			if err != nil {
+				globalPipelineLog.Error(err, "failed to load tasks of pipeline #%d for notification", pipeline.ID)
+				return
+			}
+		}
+		ruleData := *data
+		ruleData.Event = event
+		go deliverNotification(rule, &ruleData)
+	}
+}
+
+func matchNotificationEvent(rule *models.NotificationRule, events []string) string {
+	for _, event := range events {
+		for _, e := range rule.Events {
+			if e == event {
+				return event
+			}
+		}
+	}
+	return ""
+}
+
+func newNotificationTemplateData(pipeline *models.Pipeline, projectName string) (*NotificationTemplateData, errors.Error) {
+	tasks, err := GetTasksWithLastStatus(pipeline.ID, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	failedTasks := make([]*models.Task, 0)
+	for _, task := range tasks {
+		if task.Status == models.TASK_FAILED {
+			failedTasks = append(failedTasks, task)
+		}
+	}
+	return &NotificationTemplateData{
+		ProjectName: projectName,
+		Pipeline:    pipeline,
+		Tasks:       tasks,
+		FailedTasks: failedTasks,
+	}, nil
+}
+
+// deliverNotification sends the notification through the channel of the rule with exponential backoff,
+// and keeps the outcome in the _devlake_notifications table
+func deliverNotification(rule *models.NotificationRule, data *NotificationTemplateData) {
+	notification := &models.Notification{
+		Type:        models.NotificationPipelineStatusChanged,
+		RuleId:      rule.ID,
+		ChannelType: rule.ChannelType,
+		PipelineId:  data.Pipeline.ID,
+		Event:       data.Event,
+		Status:      models.NOTIFICATION_PENDING,
+	}
+	channel, err := newNotificationChannel(rule)
+	var message *NotificationMessage
+	if err == nil {
+		notification.Endpoint = channel.Endpoint()
+		message, err = renderNotificationMessage(rule, data)
+	}
+	if err != nil {
+		notification.Status = models.NOTIFICATION_FAILED
+		notification.Message = err.Error()
+		if err := db.Create(notification); err != nil {
+			globalPipelineLog.Error(err, "failed to save notification of rule #%d", rule.ID)
+		}
+		return
+	}
+	notification.Data = message.Body
+	if err := db.Create(notification); err != nil {
+		globalPipelineLog.Error(err, "failed to save notification of rule #%d", rule.ID)
+		return
+	}
+	maxRetries := rule.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultNotificationMaxRetries
+	}
+	delay := notificationRetryDelay
+	for {
+		notification.Attempts++
+		delivery, err := channel.Send(message)
+		if delivery != nil {
+			notification.ResponseCode = delivery.ResponseCode
+			notification.Response = delivery.Response
+		}
+		if err == nil {
+			notification.Status = models.NOTIFICATION_DELIVERED
+			notification.Message = ""
+			break
+		}
+		notification.Message = err.Error()
+		if notification.Attempts > maxRetries {
+			notification.Status = models.NOTIFICATION_FAILED
+			globalPipelineLog.Error(err, "failed to deliver notification #%d after %d attempts", notification.ID, notification.Attempts)
+			break
+		}
+		if err := db.Update(notification); err != nil {
+			globalPipelineLog.Error(err, "failed to update notification #%d", notification.ID)
+		}
+		time.Sleep(delay)
+		delay *= 2
+		if delay > notificationMaxRetryDelay {
+			delay = notificationMaxRetryDelay
+		}
+	}
+	if err := db.Update(notification); err != nil {
+		globalPipelineLog.Error(err, "failed to update notification #%d", notification.ID)
+	}
+}
@@ -110,6 +110,13 @@ func pipelineServiceInit() {
 	// run pipeline with independent goroutine
 	if cfg.GetBool("CONSUME_PIPELINES") {
 		go RunPipelineInQueue(pipelineMaxParallel)
+		// tasks are executed by workers, see RunWorker
+		if isDistributedTaskExecution() {
+			go runTaskLeaseReclaimer()
+		}
+		if interval := getRawDataPruneInterval(); interval > 0 {
+			go runRawDataPruner(interval)
+		}
 	}
 }
 
@@ -272,6 +279,11 @@ func dequeuePipeline(runningParallelLabels []string) (pipeline *models.Pipeline,
 	if len(top_priorities) > 0 {
 		top_priority = top_priorities[0]
 	}
+	queued, err := tx.Count(dal.From(pipeline), where_status)
+	if err != nil {
+		panic(err)
+	}
+	pipelineQueueDepth.Set(float64(queued))
 	// 2. pick the earlier runnable pipeline with the highest priority
 	err = tx.First(pipeline,
 		where_status,
@@ -305,7 +317,7 @@ func dequeuePipeline(runningParallelLabels []string) (pipeline *models.Pipeline,
 		if err != nil {
 			panic(err)
 		}
-
+		pipelineQueueDepth.Dec()
 		return
 	}
 	if tx.IsErrorNotFound(err) {
@@ -363,6 +375,11 @@ func RunPipelineInQueue(pipelineMaxParallel int64) {
 				globalPipelineLog.Info("finish pipeline #%d, now runningParallelLabels is %s", pipelineId, runningParallelLabels)
 			}()
 			globalPipelineLog.Info("run pipeline, %d, now running runningParallelLabels are %s", pipelineId, runningParallelLabels)
+			publishPipelineEvent(&PipelineEvent{
+				Type:       PipelineEventPipelineStatus,
+				PipelineId: pipelineId,
+				Status:     models.TASK_RUNNING,
+			})
 			// Notify that the pipeline has started
 			err = NotifyExternal(pipelineId)
 			if err != nil {
@@ -398,11 +415,6 @@ func getProjectName(pipeline *models.Pipeline) (string, errors.Error) {
 
 // NotifyExternal FIXME ...
 func NotifyExternal(pipelineId uint64) errors.Error {
-	notification := GetPipelineNotificationService()
-	if notification == nil {
-		return nil
-	}
-	// send notification to an external web endpoint
 	pipeline, err := GetPipeline(pipelineId, true)
 	if err != nil {
 		return err
@@ -411,8 +423,16 @@ func NotifyExternal(pipelineId uint64) errors.Error {
 	if err != nil {
 		return err
 	}
+	// deliver notifications configured by the NotificationRules asynchronously
+	dispatchNotificationRules(pipeline, projectName)
+	notification := GetPipelineNotificationService()
+	if notification == nil {
+		return nil
+	}
+	// send notification to an external web endpoint
 	err = notification.PipelineStatusChanged(PipelineNotificationParam{
 		ProjectName: projectName,
+		BlueprintID: pipeline.BlueprintId,
 		PipelineID:  pipeline.ID,
 		CreatedAt:   pipeline.CreatedAt,
 		UpdatedAt:   pipeline.UpdatedAt,
@@ -451,6 +471,11 @@ func CancelPipeline(pipelineId uint64) errors.Error {
 		if err != nil {
 			return errors.Default.Wrap(err, "faile to update pipeline tasks")
 		}
+		publishPipelineEvent(&PipelineEvent{
+			Type:       PipelineEventPipelineStatus,
+			PipelineId: pipelineId,
+			Status:     models.TASK_CANCELLED,
+		})
 		// the target pipeline is pending, no running, no need to perform the actual cancel operation
 		return nil
 	}
@@ -0,0 +1,110 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models"
+)
+
+const (
+	PipelineEventPipelineStatus  = "pipelineStatus"
+	PipelineEventTaskStatus      = "taskStatus"
+	PipelineEventSubtaskStarted  = "subtaskStarted"
+	PipelineEventSubtaskFinished = "subtaskFinished"
+	PipelineEventProgress        = "progress"
+)
+
+// minimal interval between 2 `progress` events of the same task, status events are never throttled
+const pipelineEventProgressInterval = 500 * time.Millisecond
+
+// PipelineEvent represents a change of a running pipeline, which would be pushed to the subscribers
+type PipelineEvent struct {
+	Type           string                     `json:"type"`
+	PipelineId     uint64                     `json:"pipelineId"`
+	TaskId         uint64                     `json:"taskId,omitempty"`
+	Plugin         string                     `json:"plugin,omitempty"`
+	Status         string                     `json:"status,omitempty"`
+	SubTaskName    string                     `json:"subTaskName,omitempty"`
+	SubTaskNumber  int                        `json:"subTaskNumber,omitempty"`
+	ProgressDetail *models.TaskProgressDetail `json:"progressDetail,omitempty"`
+	Time           time.Time                  `json:"time"`
+}
+
+// IsFinal returns true if no more events would be published for the pipeline after this one
+func (e *PipelineEvent) IsFinal() bool {
+	return e.Type == PipelineEventPipelineStatus && IsFinishedStatus(e.Status)
+}
+
+// IsFinishedStatus returns true if the pipeline/task with the status would not be changed anymore
+func IsFinishedStatus(status string) bool {
+	for _, finishedStatus := range models.FinishedTaskStatus {
+		if status == finishedStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// pipelineEventBroker fans out events of pipelines to their subscribers in memory, slow subscribers would miss
+// events instead of blocking the task runner
+type pipelineEventBroker struct {
+	mu          sync.Mutex
+	subscribers map[uint64]map[chan *PipelineEvent]struct{}
+}
+
+var pipelineEvents = &pipelineEventBroker{
+	subscribers: make(map[uint64]map[chan *PipelineEvent]struct{}),
+}
+
+// SubscribePipelineEvents returns a channel receiving events of the specified pipeline and a function to unsubscribe
+func SubscribePipelineEvents(pipelineId uint64) (<-chan *PipelineEvent, func()) {
+	ch := make(chan *PipelineEvent, 100)
+	pipelineEvents.mu.Lock()
+	defer pipelineEvents.mu.Unlock()
+	if pipelineEvents.subscribers[pipelineId] == nil {
+		pipelineEvents.subscribers[pipelineId] = make(map[chan *PipelineEvent]struct{})
+	}
+	pipelineEvents.subscribers[pipelineId][ch] = struct{}{}
+	return ch, func() {
+		pipelineEvents.mu.Lock()
+		defer pipelineEvents.mu.Unlock()
+		if subscribers, ok := pipelineEvents.subscribers[pipelineId]; ok {
+			delete(subscribers, ch)
+			if len(subscribers) == 0 {
+				delete(pipelineEvents.subscribers, pipelineId)
+			}
+		}
+	}
+}
+
+func publishPipelineEvent(event *PipelineEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	pipelineEvents.mu.Lock()
+	defer pipelineEvents.mu.Unlock()
+	for ch := range pipelineEvents.subscribers[event.PipelineId] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineEvents(t *testing.T) {
+	events1, unsubscribe1 := SubscribePipelineEvents(1)
+	events2, unsubscribe2 := SubscribePipelineEvents(2)
+	defer unsubscribe2()
+
+	publishPipelineEvent(&PipelineEvent{Type: PipelineEventTaskStatus, PipelineId: 1, TaskId: 3, Status: models.TASK_RUNNING})
+	event := <-events1
+	assert.Equal(t, uint64(3), event.TaskId)
+	assert.False(t, event.Time.IsZero())
+	assert.False(t, event.IsFinal())
+	assert.Len(t, events2, 0)
+
+	publishPipelineEvent(&PipelineEvent{Type: PipelineEventPipelineStatus, PipelineId: 1, Status: models.TASK_COMPLETED})
+	event = <-events1
+	assert.True(t, event.IsFinal())
+
+	// slow subscribers must not block the publisher
+	for i := 0; i < 200; i++ {
+		publishPipelineEvent(&PipelineEvent{Type: PipelineEventProgress, PipelineId: 2})
+	}
+	assert.Len(t, events2, cap(events2))
+
+	unsubscribe1()
+	publishPipelineEvent(&PipelineEvent{Type: PipelineEventProgress, PipelineId: 1})
+	assert.Len(t, events1, 0)
+}
+
+func TestRunPipelinePublishesFailureOnError(t *testing.T) {
+	useSqliteDb(t)
+	events, unsubscribe := SubscribePipelineEvents(42)
+	defer unsubscribe()
+
+	// the pipeline can't be loaded
+	assert.NotNil(t, runPipeline(42))
+	assert.Len(t, events, 1)
+	event := <-events
+	assert.Equal(t, PipelineEventPipelineStatus, event.Type)
+	assert.Equal(t, models.TASK_FAILED, event.Status)
+	assert.True(t, event.IsFinal())
+}
@@ -32,6 +32,9 @@ var createDbPipelineLock sync.Mutex
 
 // CreateDbPipeline returns a NewPipeline
 func CreateDbPipeline(newPipeline *models.NewPipeline) (pipeline *models.Pipeline, err errors.Error) {
+	if _, err = newPipeline.Plan.Dependencies(); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid plan")
+	}
 	createDbPipelineLock.Lock()
 	defer createDbPipelineLock.Unlock()
 	pipeline = &models.Pipeline{}
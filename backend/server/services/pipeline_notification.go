@@ -24,6 +24,7 @@ import (
 
 type PipelineNotificationParam struct {
 	ProjectName string // can be an empty string, if pipeline is created and triggered by API
+	BlueprintID uint64 // zero if pipeline is not bound to a blueprint
 	PipelineID  uint64
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
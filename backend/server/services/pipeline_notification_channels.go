@@ -0,0 +1,297 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+const defaultNotificationSubjectTemplate = `[DevLake] Pipeline #{{.Pipeline.ID}}{{if .ProjectName}} of project {{.ProjectName}}{{end}}: {{.Event}}`
+
+const defaultNotificationTemplate = `Pipeline #{{.Pipeline.ID}}{{if .ProjectName}} of project {{.ProjectName}}{{end}} finished with status {{.Pipeline.Status}} ({{.Event}}) in {{.Pipeline.SpentSeconds}}s
+{{- range .FailedTasks}}
+- {{.Plugin}} task #{{.ID}} failed{{if .FailedSubTask}} at {{.FailedSubTask}}{{end}}: {{.Message}}
+{{- end}}`
+
+const defaultHttpNotificationTemplate = `{{json .}}`
+
+var notificationHttpClient = &http.Client{Timeout: 30 * time.Second}
+
+var notificationTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+	"formatTime": func(t *time.Time, layout string) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(layout)
+	},
+}
+
+// NotificationTemplateData contains fields available to the templates of NotificationRules
+type NotificationTemplateData struct {
+	Event       string           `json:"event"`
+	ProjectName string           `json:"projectName"`
+	Pipeline    *models.Pipeline `json:"pipeline"`
+	Tasks       []*models.Task   `json:"tasks"`
+	FailedTasks []*models.Task   `json:"failedTasks"`
+}
+
+// NotificationMessage is the rendered message to be sent by a NotificationChannel
+type NotificationMessage struct {
+	Subject string
+	Body    string
+}
+
+// NotificationDelivery is the outcome of a single delivery attempt
+type NotificationDelivery struct {
+	ResponseCode int
+	Response     string
+}
+
+// NotificationChannel sends rendered messages to a destination
+type NotificationChannel interface {
+	// Endpoint returns a description of the destination for the history, it must not contain any secret
+	Endpoint() string
+	// Send delivers the message, error would be returned if the delivery should be retried
+	Send(message *NotificationMessage) (*NotificationDelivery, errors.Error)
+}
+
+// SlackNotificationConfig works for Slack incoming webhooks and alike (Mattermost, Rocket.Chat, etc.)
+type SlackNotificationConfig struct {
+	WebhookUrl string `mapstructure:"webhookUrl" json:"webhookUrl" validate:"required,url"`
+}
+
+// EmailNotificationConfig sends messages through a SMTP server
+type EmailNotificationConfig struct {
+	Host     string   `mapstructure:"host" json:"host" validate:"required"`
+	Port     int      `mapstructure:"port" json:"port"`
+	Username string   `mapstructure:"username" json:"username"`
+	Password string   `mapstructure:"password" json:"password"`
+	From     string   `mapstructure:"from" json:"from" validate:"required,email"`
+	To       []string `mapstructure:"to" json:"to" validate:"required,min=1,dive,email"`
+	// Subject is a template like the NotificationRule.Template
+	Subject string `mapstructure:"subject" json:"subject"`
+}
+
+// HttpNotificationConfig sends the rendered template as the request body to any http endpoint
+type HttpNotificationConfig struct {
+	Url         string            `mapstructure:"url" json:"url" validate:"required,url"`
+	Method      string            `mapstructure:"method" json:"method" validate:"omitempty,oneof=POST PUT PATCH"`
+	ContentType string            `mapstructure:"contentType" json:"contentType"`
+	Headers     map[string]string `mapstructure:"headers" json:"headers"`
+}
+
+// secret fields of the channel configs, which would be masked in api outputs
+var notificationChannelSecretFields = []string{"webhookUrl", "password"}
+
+// secret map fields of the channel configs, values of which would be masked one by one to keep the shape of the map
+var notificationChannelSecretMapFields = []string{"headers"}
+
+const notificationSecretMask = "********"
+
+func newNotificationChannel(rule *models.NotificationRule) (NotificationChannel, errors.Error) {
+	var channel NotificationChannel
+	var config interface{}
+	switch rule.ChannelType {
+	case models.NOTIFICATION_CHANNEL_SLACK:
+		c := &slackNotificationChannel{}
+		config, channel = &c.SlackNotificationConfig, c
+	case models.NOTIFICATION_CHANNEL_EMAIL:
+		c := &emailNotificationChannel{}
+		config, channel = &c.EmailNotificationConfig, c
+	case models.NOTIFICATION_CHANNEL_HTTP:
+		c := &httpNotificationChannel{}
+		config, channel = &c.HttpNotificationConfig, c
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("unsupported channel type: %s", rule.ChannelType))
+	}
+	if err := helper.Decode(rule.ChannelConfig, config, vld); err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid config for %s channel", rule.ChannelType))
+	}
+	return channel, nil
+}
+
+// renderNotificationMessage renders the subject and body of the message with the templates of the rule
+func renderNotificationMessage(rule *models.NotificationRule, data *NotificationTemplateData) (*NotificationMessage, errors.Error) {
+	bodyTemplate := rule.Template
+	if bodyTemplate == "" {
+		bodyTemplate = defaultNotificationTemplate
+		if rule.ChannelType == models.NOTIFICATION_CHANNEL_HTTP {
+			bodyTemplate = defaultHttpNotificationTemplate
+		}
+	}
+	subjectTemplate := defaultNotificationSubjectTemplate
+	if s, ok := rule.ChannelConfig["subject"].(string); ok && s != "" {
+		subjectTemplate = s
+	}
+	body, err := renderNotificationTemplate("body", bodyTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := renderNotificationTemplate("subject", subjectTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationMessage{Subject: subject, Body: body}, nil
+}
+
+func renderNotificationTemplate(name, text string, data *NotificationTemplateData) (string, errors.Error) {
+	tpl, err := template.New(name).Funcs(notificationTemplateFuncs).Parse(text)
+	if err != nil {
+		return "", errors.BadInput.Wrap(err, fmt.Sprintf("invalid %s template", name))
+	}
+	var buf bytes.Buffer
+	if err = tpl.Execute(&buf, data); err != nil {
+		return "", errors.BadInput.Wrap(err, fmt.Sprintf("failed to render %s template", name))
+	}
+	return buf.String(), nil
+}
+
+type slackNotificationChannel struct {
+	SlackNotificationConfig
+}
+
+func (c *slackNotificationChannel) Endpoint() string {
+	return redactUrl(c.WebhookUrl)
+}
+
+func (c *slackNotificationChannel) Send(message *NotificationMessage) (*NotificationDelivery, errors.Error) {
+	// the rendered body could be a full slack payload (i.e. with blocks), otherwise send it as text
+	payload := []byte(message.Body)
+	var obj map[string]interface{}
+	if json.Unmarshal(payload, &obj) != nil {
+		var err error
+		payload, err = json.Marshal(map[string]string{"text": message.Body})
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	return postNotification(http.MethodPost, c.WebhookUrl, "application/json", nil, payload)
+}
+
+type emailNotificationChannel struct {
+	EmailNotificationConfig
+}
+
+func (c *emailNotificationChannel) Endpoint() string {
+	return fmt.Sprintf("smtp://%s -> %s", c.addr(), strings.Join(c.To, ","))
+}
+
+func (c *emailNotificationChannel) addr() string {
+	port := c.Port
+	if port == 0 {
+		port = 25
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+// mail builds the email of the message, the subject is folded into one line and Q-encoded if it isn't
+// plain ascii, so that rendered values can't break the headers
+func (c *emailNotificationChannel) mail(message *NotificationMessage) []byte {
+	subject := strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(message.Subject)
+	var msg bytes.Buffer
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", c.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(c.To, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(message.Body)
+	return msg.Bytes()
+}
+
+func (c *emailNotificationChannel) Send(message *NotificationMessage) (*NotificationDelivery, errors.Error) {
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+	if err := smtp.SendMail(c.addr(), auth, c.From, c.To, c.mail(message)); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to send email")
+	}
+	return &NotificationDelivery{ResponseCode: 250}, nil
+}
+
+type httpNotificationChannel struct {
+	HttpNotificationConfig
+}
+
+func (c *httpNotificationChannel) Endpoint() string {
+	return redactUrl(c.Url)
+}
+
+func (c *httpNotificationChannel) Send(message *NotificationMessage) (*NotificationDelivery, errors.Error) {
+	method := c.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	contentType := c.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return postNotification(method, c.Url, contentType, c.Headers, []byte(message.Body))
+}
+
+func postNotification(method, endpoint, contentType string, headers map[string]string, body []byte) (*NotificationDelivery, errors.Error) {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to create notification request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	res, err := notificationHttpClient.Do(req)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to send notification request")
+	}
+	defer res.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	delivery := &NotificationDelivery{ResponseCode: res.StatusCode, Response: string(respBody)}
+	if res.StatusCode >= 300 {
+		return delivery, errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code %d", res.StatusCode))
+	}
+	return delivery, nil
+}
+
+// redactUrl removes credentials, query and path which might contain tokens, i.e. slack webhook urls
+func redactUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
@@ -0,0 +1,178 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderNotificationMessage(t *testing.T) {
+	data := &NotificationTemplateData{
+		Event:       models.NOTIFICATION_EVENT_FAILURE,
+		ProjectName: "devlake",
+		Pipeline:    &models.Pipeline{Status: models.TASK_FAILED, SpentSeconds: 10},
+		FailedTasks: []*models.Task{{Plugin: "github", FailedSubTask: "collectIssues", Message: "boom"}},
+	}
+	data.Pipeline.ID = 7
+
+	message, err := renderNotificationMessage(&models.NotificationRule{ChannelType: models.NOTIFICATION_CHANNEL_SLACK}, data)
+	assert.Nil(t, err)
+	assert.Equal(t, "[DevLake] Pipeline #7 of project devlake: failure", message.Subject)
+	assert.Contains(t, message.Body, "finished with status TASK_FAILED (failure) in 10s")
+	assert.Contains(t, message.Body, "- github task #0 failed at collectIssues: boom")
+
+	message, err = renderNotificationMessage(&models.NotificationRule{ChannelType: models.NOTIFICATION_CHANNEL_HTTP}, data)
+	assert.Nil(t, err)
+	var body map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(message.Body), &body))
+	assert.Equal(t, "failure", body["event"])
+
+	message, err = renderNotificationMessage(&models.NotificationRule{
+		Template:      `{{.Event}} {{join .Pipeline.Labels ","}}`,
+		ChannelConfig: map[string]interface{}{"subject": "{{.ProjectName}}"},
+	}, data)
+	assert.Nil(t, err)
+	assert.Equal(t, "devlake", message.Subject)
+	assert.Equal(t, "failure ", message.Body)
+
+	_, err = renderNotificationMessage(&models.NotificationRule{Template: "{{.Unknown"}, data)
+	assert.NotNil(t, err)
+}
+
+func TestNotificationChannels(t *testing.T) {
+	vld = validator.New()
+	var received *http.Request
+	var receivedBody string
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		b, _ := io.ReadAll(r.Body)
+		receivedBody = string(b)
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// slack wraps plain text into a payload
+	channel, err := newNotificationChannel(&models.NotificationRule{
+		ChannelType:   models.NOTIFICATION_CHANNEL_SLACK,
+		ChannelConfig: map[string]interface{}{"webhookUrl": server.URL + "/services/secret"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL, channel.Endpoint())
+	delivery, err := channel.Send(&NotificationMessage{Body: "hello"})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, delivery.ResponseCode)
+	assert.Equal(t, `{"text":"hello"}`, receivedBody)
+	assert.Equal(t, "/services/secret", received.URL.Path)
+
+	// http sends the body as is with custom headers
+	channel, err = newNotificationChannel(&models.NotificationRule{
+		ChannelType: models.NOTIFICATION_CHANNEL_HTTP,
+		ChannelConfig: map[string]interface{}{
+			"url":         server.URL,
+			"method":      http.MethodPut,
+			"contentType": "text/plain",
+			"headers":     map[string]interface{}{"Authorization": "Bearer token"},
+		},
+	})
+	assert.Nil(t, err)
+	_, err = channel.Send(&NotificationMessage{Body: "hello"})
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodPut, received.Method)
+	assert.Equal(t, "text/plain", received.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", received.Header.Get("Authorization"))
+	assert.Equal(t, "hello", receivedBody)
+
+	// failed deliveries would be retried
+	statusCode = http.StatusServiceUnavailable
+	delivery, err = channel.Send(&NotificationMessage{Body: "hello"})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseCode)
+
+	// invalid configs
+	_, err = newNotificationChannel(&models.NotificationRule{
+		ChannelType:   models.NOTIFICATION_CHANNEL_EMAIL,
+		ChannelConfig: map[string]interface{}{"host": "smtp.example.com", "from": "devlake@example.com"},
+	})
+	assert.NotNil(t, err)
+	_, err = newNotificationChannel(&models.NotificationRule{ChannelType: "pigeon"})
+	assert.NotNil(t, err)
+}
+
+func TestEmailNotificationMail(t *testing.T) {
+	channel := &emailNotificationChannel{EmailNotificationConfig{From: "devlake@example.com", To: []string{"a@example.com", "b@example.com"}}}
+	mail := string(channel.mail(&NotificationMessage{Subject: "failure\r\nBcc: evil@example.com\rX: y", Body: "hello"}))
+	assert.Equal(t, "From: devlake@example.com\r\n"+
+		"To: a@example.com, b@example.com\r\n"+
+		"Subject: failure Bcc: evil@example.com X: y\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n"+
+		"hello", mail)
+
+	mail = string(channel.mail(&NotificationMessage{Subject: "Pipeline of projet café"}))
+	assert.Contains(t, mail, "\r\nSubject: =?UTF-8?q?Pipeline_of_projet_caf=C3=A9?=\r\n")
+}
+
+func TestMaskNotificationRule(t *testing.T) {
+	rule := &models.NotificationRule{ChannelConfig: map[string]interface{}{
+		"host":     "smtp.example.com",
+		"password": "secret",
+		"username": "",
+	}}
+	maskNotificationRule(rule)
+	assert.Equal(t, notificationSecretMask, rule.ChannelConfig["password"])
+	assert.Equal(t, "smtp.example.com", rule.ChannelConfig["host"])
+	assert.NotContains(t, rule.ChannelConfig, "webhookUrl")
+}
+
+func TestMaskNotificationRuleHeaders(t *testing.T) {
+	origin := map[string]interface{}{
+		"url":     "https://example.com/hook",
+		"headers": map[string]interface{}{"Authorization": "Bearer token", "X-Empty": ""},
+	}
+	rule := &models.NotificationRule{
+		ChannelType:   models.NOTIFICATION_CHANNEL_HTTP,
+		ChannelConfig: map[string]interface{}{"url": origin["url"], "headers": origin["headers"]},
+	}
+	maskNotificationRule(rule)
+	// the shape of the headers must be kept, so the masked rule could be saved back
+	assert.Equal(t, map[string]interface{}{"Authorization": notificationSecretMask, "X-Empty": ""}, rule.ChannelConfig["headers"])
+	_, err := newNotificationChannel(rule)
+	assert.Nil(t, err)
+
+	// masked values are restored from the stored rule, new and changed values are kept
+	rule.ChannelConfig["headers"] = map[string]interface{}{
+		"Authorization": notificationSecretMask,
+		"X-Trace":       "on",
+		"X-Unknown":     notificationSecretMask,
+	}
+	unmaskNotificationRule(rule, origin)
+	assert.Equal(t, map[string]interface{}{"Authorization": "Bearer token", "X-Trace": "on"}, rule.ChannelConfig["headers"])
+	channel, err := newNotificationChannel(rule)
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer token", channel.(*httpNotificationChannel).Headers["Authorization"])
+}
@@ -25,11 +25,15 @@ import (
 	"github.com/apache/incubator-devlake/core/log"
 	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/runner"
+	"github.com/apache/incubator-devlake/helpers/tracinghelper"
 	"github.com/apache/incubator-devlake/impls/logruslog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"time"
 )
 
 type pipelineRunner struct {
+	ctx      context.Context
 	logger   log.Logger
 	pipeline *models.Pipeline
 }
@@ -39,7 +43,10 @@ func (p *pipelineRunner) runPipelineStandalone() errors.Error {
 		basicRes.ReplaceLogger(p.logger),
 		p.pipeline.ID,
 		func(taskIds []uint64) errors.Error {
-			return RunTasksStandalone(p.logger, taskIds)
+			if isDistributedTaskExecution() {
+				return RunTasksDistributed(p.ctx, p.logger, p.pipeline, taskIds)
+			}
+			return RunTasksStandalone(p.ctx, p.logger, taskIds)
 		},
 	)
 }
@@ -64,12 +71,30 @@ func GetPipelineLogger(pipeline *models.Pipeline) log.Logger {
 
 // runPipeline start a pipeline actually
 func runPipeline(pipelineId uint64) errors.Error {
+	// the final status is published on every exit path, so that the subscribers don't wait forever,
+	// it stays FAILED unless the status is computed and saved
+	finalStatus := models.TASK_FAILED
+	defer func() {
+		publishPipelineEvent(&PipelineEvent{
+			Type:       PipelineEventPipelineStatus,
+			PipelineId: pipelineId,
+			Status:     finalStatus,
+		})
+	}()
 	ppl, err := GetPipeline(pipelineId, false)
 	if err != nil {
 		return err
 	}
+	ctx, span := tracinghelper.Start(
+		context.Background(),
+		"pipeline",
+		attribute.Int64("devlake.pipeline.id", int64(pipelineId)),
+		attribute.Int64("devlake.blueprint.id", int64(ppl.BlueprintId)),
+	)
+	defer span.End()
 	pipelineRun := pipelineRunner{
-		logger:   GetPipelineLogger(ppl),
+		ctx:      ctx,
+		logger:   logruslog.WithTraceId(GetPipelineLogger(ppl), tracinghelper.TraceId(ctx)),
 		pipeline: ppl,
 	}
 	// run
@@ -102,6 +127,12 @@ func runPipeline(pipelineId uint64) errors.Error {
 		globalPipelineLog.Error(err, "update pipeline state failed")
 		return err
 	}
+	span.SetAttributes(attribute.String("devlake.pipeline.status", dbPipeline.Status))
+	if dbPipeline.Status != models.TASK_COMPLETED {
+		span.SetStatus(codes.Error, dbPipeline.Message)
+	}
+	recordPipelineFinished(dbPipeline)
+	finalStatus = dbPipeline.Status
 	// notify external webhook
 	return NotifyExternal(pipelineId)
 }
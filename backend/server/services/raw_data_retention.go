@@ -0,0 +1,268 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+// RawDataPruneResult tells how many records were deleted from the raw table
+type RawDataPruneResult struct {
+	Table    string `json:"table"`
+	PolicyId uint64 `json:"policyId"`
+	Deleted  int64  `json:"deleted"`
+}
+
+// GetRawDataRetentionPolicies returns all raw data retention policies
+func GetRawDataRetentionPolicies() ([]*models.RawDataRetentionPolicy, errors.Error) {
+	policies := make([]*models.RawDataRetentionPolicy, 0)
+	err := db.All(&policies, dal.Orderby("id"))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error finding DB raw data retention policies")
+	}
+	return policies, nil
+}
+
+// GetRawDataRetentionPolicy returns the raw data retention policy by id
+func GetRawDataRetentionPolicy(id uint64) (*models.RawDataRetentionPolicy, errors.Error) {
+	policy := &models.RawDataRetentionPolicy{}
+	err := db.First(policy, dal.Where("id = ?", id))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("raw data retention policy(id: %d) not found", id))
+		}
+		return nil, errors.Internal.Wrap(err, "error getting the raw data retention policy from database")
+	}
+	return policy, nil
+}
+
+// CreateRawDataRetentionPolicy accepts a raw data retention policy instance and insert it to database
+func CreateRawDataRetentionPolicy(policy *models.RawDataRetentionPolicy) (*models.RawDataRetentionPolicy, errors.Error) {
+	policy.ID = 0
+	if err := validateRawDataRetentionPolicy(policy); err != nil {
+		return nil, err
+	}
+	if err := db.Create(policy); err != nil {
+		if db.IsDuplicationError(err) {
+			return nil, errors.BadInput.New(fmt.Sprintf("raw data retention policy for %s already exists", policy.Table))
+		}
+		return nil, errors.Default.Wrap(err, "error creating raw data retention policy")
+	}
+	return policy, nil
+}
+
+// PatchRawDataRetentionPolicy updates the raw data retention policy with the given body
+func PatchRawDataRetentionPolicy(id uint64, body map[string]interface{}) (*models.RawDataRetentionPolicy, errors.Error) {
+	policy, err := GetRawDataRetentionPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	err = helper.DecodeMapStruct(body, policy, true)
+	if err != nil {
+		return nil, err
+	}
+	policy.ID = id
+	if err = validateRawDataRetentionPolicy(policy); err != nil {
+		return nil, err
+	}
+	if err = db.Update(policy); err != nil {
+		if db.IsDuplicationError(err) {
+			return nil, errors.BadInput.New(fmt.Sprintf("raw data retention policy for %s already exists", policy.Table))
+		}
+		return nil, errors.Default.Wrap(err, "error updating raw data retention policy")
+	}
+	return policy, nil
+}
+
+// DeleteRawDataRetentionPolicy deletes the raw data retention policy, records pruned already won't be restored
+func DeleteRawDataRetentionPolicy(id uint64) errors.Error {
+	if _, err := GetRawDataRetentionPolicy(id); err != nil {
+		return err
+	}
+	return db.Delete(&models.RawDataRetentionPolicy{}, dal.Where("id = ?", id))
+}
+
+func validateRawDataRetentionPolicy(policy *models.RawDataRetentionPolicy) errors.Error {
+	if err := VerifyStruct(policy); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(policy.Table, "_raw_") {
+		return errors.BadInput.New("table must start with _raw_")
+	}
+	if _, err := path.Match(policy.Table, ""); err != nil {
+		return errors.BadInput.Wrap(err, "table is not a valid pattern")
+	}
+	return nil
+}
+
+// PruneRawData deletes records of raw tables exceeding their retention policies
+func PruneRawData() ([]*RawDataPruneResult, errors.Error) {
+	policies := make([]*models.RawDataRetentionPolicy, 0)
+	err := db.All(&policies, dal.Where("enable = ?", true))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error finding DB raw data retention policies")
+	}
+	results := make([]*RawDataPruneResult, 0)
+	if len(policies) == 0 {
+		return results, nil
+	}
+	tables, err := db.AllTables()
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if !strings.HasPrefix(table, "_raw_") {
+			continue
+		}
+		policy := models.MatchRawDataRetentionPolicy(policies, table)
+		if policy == nil {
+			continue
+		}
+		deleted, err := pruneRawTable(table, policy, time.Now())
+		if err != nil {
+			return results, errors.Default.Wrap(err, fmt.Sprintf("failed to prune %s", table))
+		}
+		results = append(results, &RawDataPruneResult{Table: table, PolicyId: policy.ID, Deleted: deleted})
+	}
+	return results, nil
+}
+
+func pruneRawTable(table string, policy *models.RawDataRetentionPolicy, now time.Time) (int64, errors.Error) {
+	var deleted int64
+	deleteRecords := func(clauses ...dal.Clause) errors.Error {
+		clauses = append([]dal.Clause{dal.From(table)}, clauses...)
+		count, err := db.Count(clauses...)
+		if err != nil || count == 0 {
+			return err
+		}
+		deleted += count
+		return db.Delete(&helper.RawData{}, clauses...)
+	}
+	collections := make([]*models.RawDataCollection, 0)
+	err := db.All(
+		&collections,
+		dal.Where("raw_data_table = ?", table),
+		dal.Orderby("started_at DESC"),
+	)
+	if err != nil {
+		return deleted, err
+	}
+	collectionsByParams := make(map[string][]*models.RawDataCollection)
+	paramsList := make([]string, 0)
+	for _, collection := range collections {
+		if _, ok := collectionsByParams[collection.RawDataParams]; !ok {
+			paramsList = append(paramsList, collection.RawDataParams)
+		}
+		collectionsByParams[collection.RawDataParams] = append(collectionsByParams[collection.RawDataParams], collection)
+	}
+	// records without collections were collected before the collections were recorded, only KeepDays applies
+	if policy.KeepDays > 0 {
+		clauses := []dal.Clause{dal.Where("created_at < ?", now.AddDate(0, 0, -policy.KeepDays))}
+		if len(paramsList) > 0 {
+			clauses = append(clauses, dal.Where("params NOT IN ?", paramsList))
+		}
+		if err = deleteRecords(clauses...); err != nil {
+			return deleted, err
+		}
+	}
+	for _, params := range paramsList {
+		cutoff := getRawDataCutoff(collectionsByParams[params], policy, now)
+		if cutoff.IsZero() {
+			continue
+		}
+		err = deleteRecords(dal.Where("params = ? AND created_at < ?", params, cutoff))
+		if err != nil {
+			return deleted, err
+		}
+		err = db.Delete(
+			&models.RawDataCollection{},
+			dal.Where("raw_data_table = ? AND raw_data_params = ? AND started_at < ?", table, params, cutoff),
+		)
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// getRawDataCutoff returns the time before which records of the collections, sorted by started_at in descending
+// order, could be deleted, the zero time means none of them.
+// Only full collections are used as cut points, as an incremental collection is extracted along with records of
+// the collections before it, down to the latest full one. The records of the latest full collection are always kept.
+func getRawDataCutoff(collections []*models.RawDataCollection, policy *models.RawDataRetentionPolicy, now time.Time) time.Time {
+	fullCollectionStarts := make([]time.Time, 0)
+	for _, collection := range collections {
+		if !collection.Incremental {
+			fullCollectionStarts = append(fullCollectionStarts, collection.StartedAt)
+		}
+	}
+	var cutoff time.Time
+	if policy.KeepCollections > 0 && len(fullCollectionStarts) >= policy.KeepCollections {
+		cutoff = fullCollectionStarts[policy.KeepCollections-1]
+	}
+	if policy.KeepDays > 0 {
+		deadline := now.AddDate(0, 0, -policy.KeepDays)
+		// the latest full collection started before the deadline, records after it are still needed
+		for _, startedAt := range fullCollectionStarts {
+			if !startedAt.After(deadline) {
+				if startedAt.After(cutoff) {
+					cutoff = startedAt
+				}
+				break
+			}
+		}
+	}
+	return cutoff
+}
+
+// getRawDataPruneInterval returns the interval of the scheduled pruning, 0 means disabled
+func getRawDataPruneInterval() time.Duration {
+	if cfg.GetString("RAW_DATA_PRUNE_INTERVAL") == "" {
+		return 24 * time.Hour
+	}
+	hours := cfg.GetInt("RAW_DATA_PRUNE_INTERVAL")
+	if hours < 0 {
+		hours = 0
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// runRawDataPruner prunes raw tables by their retention policies periodically
+func runRawDataPruner(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		results, err := PruneRawData()
+		for _, result := range results {
+			if result.Deleted > 0 {
+				globalPipelineLog.Info("pruned %d records from %s", result.Deleted, result.Table)
+			}
+		}
+		if err != nil {
+			globalPipelineLog.Error(err, "failed to prune raw data")
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRawDataCutoff(t *testing.T) {
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days)
+	}
+	// sorted by started_at in descending order
+	collections := []*models.RawDataCollection{
+		{StartedAt: daysAgo(1), Incremental: true},
+		{StartedAt: daysAgo(10), Incremental: true},
+		{StartedAt: daysAgo(20), Incremental: true},
+		{StartedAt: daysAgo(40)},
+		{StartedAt: daysAgo(60)},
+	}
+	assert.Equal(t, daysAgo(40), getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepCollections: 1}, now))
+	assert.Equal(t, daysAgo(60), getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepCollections: 2}, now))
+	assert.True(t, getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepCollections: 3}, now).IsZero())
+	// the incremental collections within 7 days are based on the full collection 40 days ago
+	assert.Equal(t, daysAgo(40), getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepDays: 7}, now))
+	assert.Equal(t, daysAgo(60), getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepDays: 50}, now))
+	assert.True(t, getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepDays: 90}, now).IsZero())
+	// the more aggressive one wins
+	assert.Equal(t, daysAgo(40), getRawDataCutoff(collections, &models.RawDataRetentionPolicy{KeepDays: 50, KeepCollections: 1}, now))
+	// nothing could be deleted without full collections
+	assert.True(t, getRawDataCutoff(collections[:3], &models.RawDataRetentionPolicy{KeepDays: 1, KeepCollections: 1}, now).IsZero())
+}
+
+func TestPruneRawTableWithIncrementalCollections(t *testing.T) {
+	const table = "_raw_test_issues"
+	useSqliteDb(t, &models.RawDataCollection{})
+	assert.Nil(t, db.AutoMigrate(&helper.RawData{}, dal.From(table)))
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	collect := func(params string, daysAgo int, incremental bool) {
+		startedAt := now.AddDate(0, 0, -daysAgo)
+		assert.Nil(t, db.Create(&models.RawDataCollection{
+			RawDataTable:  table,
+			RawDataParams: params,
+			Incremental:   incremental,
+			StartedAt:     startedAt,
+			FinishedAt:    startedAt.Add(time.Hour),
+		}))
+		assert.Nil(t, db.Create(&helper.RawData{Params: params, Data: []byte("{}"), CreatedAt: startedAt.Add(time.Minute)}, dal.From(table)))
+	}
+	countRecords := func(params string) int64 {
+		count, err := db.Count(dal.From(table), dal.Where("params = ?", params))
+		assert.Nil(t, err)
+		return count
+	}
+	// one full collection followed by several incremental ones
+	collect("a", 40, false)
+	collect("a", 30, true)
+	collect("a", 20, true)
+	collect("a", 10, true)
+	collect("a", 1, true)
+	// an outdated full collection, and the one replacing it
+	collect("b", 60, false)
+	collect("b", 40, false)
+	collect("b", 1, true)
+	// collected before the collections were recorded
+	assert.Nil(t, db.Create(&helper.RawData{Params: "c", Data: []byte("{}"), CreatedAt: now.AddDate(0, 0, -60)}, dal.From(table)))
+
+	deleted, err := pruneRawTable(table, &models.RawDataRetentionPolicy{KeepCollections: 1}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, int64(5), countRecords("a"))
+	assert.Equal(t, int64(2), countRecords("b"))
+
+	deleted, err = pruneRawTable(table, &models.RawDataRetentionPolicy{KeepDays: 7}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, int64(0), countRecords("c"))
+	assert.Equal(t, int64(5), countRecords("a"))
+	assert.Equal(t, int64(2), countRecords("b"))
+
+	count, err := db.Count(dal.From(&models.RawDataCollection{}), dal.Where("raw_data_params = ?", "b"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
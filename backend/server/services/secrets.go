@@ -0,0 +1,153 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/runner"
+)
+
+// encryptedFrameworkColumns lists columns of the framework tables which are persisted by the `encdec` serializer
+var encryptedFrameworkColumns = map[string][]string{
+	"_devlake_pipelines":          {"plan"},
+	"_devlake_blueprints":         {"plan", "before_plan", "after_plan"},
+	"_devlake_tasks":              {"options"},
+	"_devlake_notification_rules": {"channel_config"},
+}
+
+// ReencryptSecrets re-encrypts all values in `_tool_*_connections`, `_devlake_pipelines`, `_devlake_blueprints`,
+// `_devlake_tasks` and `_devlake_notification_rules` that were not produced by the newest key of the SecretProvider, returns number of values updated per table.
+// It is safe to run it multiple times, values that are up-to-date or not encrypted at all would be left untouched.
+func ReencryptSecrets() (map[string]int, errors.Error) {
+	secretProvider := runner.GetSecretProvider()
+	if secretProvider == nil {
+		return nil, errors.Default.New("secret provider is not initialized")
+	}
+	result := make(map[string]int)
+	tables, err := db.AllTables()
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if !strings.HasPrefix(table, "_tool_") || !strings.HasSuffix(table, "_connections") {
+			continue
+		}
+		// plugins define their own encrypted fields, so check every text column of the connection tables
+		columns, err := dal.GetColumnNames(db, dal.DefaultTabler{Name: table}, func(columnMeta dal.ColumnMeta) bool {
+			isPrimaryKey, _ := columnMeta.PrimaryKey()
+			typeName := strings.ToUpper(columnMeta.DatabaseTypeName())
+			return !isPrimaryKey && (strings.Contains(typeName, "CHAR") || strings.Contains(typeName, "TEXT"))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if result[table], err = reencryptTable(secretProvider, table, columns); err != nil {
+			return nil, err
+		}
+	}
+	for table, columns := range encryptedFrameworkColumns {
+		if !db.HasTable(table) {
+			continue
+		}
+		if result[table], err = reencryptTable(secretProvider, table, columns); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func reencryptTable(secretProvider plugin.SecretProvider, table string, columns []string) (int, errors.Error) {
+	if len(columns) == 0 {
+		return 0, nil
+	}
+	pkColumns, err := dal.GetColumnNames(db, dal.DefaultTabler{Name: table}, func(columnMeta dal.ColumnMeta) bool {
+		isPrimaryKey, ok := columnMeta.PrimaryKey()
+		return isPrimaryKey && ok
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(pkColumns) == 0 {
+		return 0, errors.Default.New(fmt.Sprintf("table %s has no primary key", table))
+	}
+	cursor, err := db.Cursor(dal.From(table), dal.Select(strings.Join(append(pkColumns, columns...), ", ")))
+	if err != nil {
+		return 0, errors.Default.Wrap(err, fmt.Sprintf("failed to read table %s", table))
+	}
+	// collect changes first, the cursor should be released before updating
+	type pendingUpdate struct {
+		where []dal.Clause
+		sets  []dal.DalSet
+	}
+	var updates []pendingUpdate
+	count := 0
+	for cursor.Next() {
+		row := make(map[string]interface{})
+		if err = db.Fetch(cursor, &row); err != nil {
+			cursor.Close()
+			return 0, err
+		}
+		var sets []dal.DalSet
+		for _, column := range columns {
+			var value string
+			switch v := row[column].(type) {
+			case string:
+				value = v
+			case []byte:
+				value = string(v)
+			default:
+				continue
+			}
+			if value == "" || !secretProvider.IsStale(value) {
+				continue
+			}
+			plainText, e := secretProvider.Decrypt(value)
+			if e != nil {
+				// not an encrypted value
+				continue
+			}
+			cipherText, e := secretProvider.Encrypt(plainText)
+			if e != nil {
+				cursor.Close()
+				return 0, e
+			}
+			sets = append(sets, dal.DalSet{ColumnName: column, Value: cipherText})
+		}
+		if len(sets) == 0 {
+			continue
+		}
+		update := pendingUpdate{sets: sets}
+		for _, pkColumn := range pkColumns {
+			update.where = append(update.where, dal.Where(fmt.Sprintf("%s = ?", pkColumn), row[pkColumn]))
+		}
+		updates = append(updates, update)
+		count += len(sets)
+	}
+	cursor.Close()
+	for _, update := range updates {
+		if err = db.UpdateColumns(table, update.sets, update.where...); err != nil {
+			return 0, errors.Default.Wrap(err, fmt.Sprintf("failed to update table %s", table))
+		}
+	}
+	return count, nil
+}
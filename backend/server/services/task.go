@@ -164,8 +164,22 @@ func GetTask(taskId uint64) (*models.Task, errors.Error) {
 	return task, nil
 }
 
-// CancelTask FIXME ...
+// CancelTask cancels the task running in current process, or requests the worker executing the task to cancel it
 func CancelTask(taskId uint64) errors.Error {
+	err := cancelRunningTask(taskId)
+	if err == nil || !isDistributedTaskExecution() {
+		return err
+	}
+	// the task might be dispatched to a worker
+	leaseErr := requestTaskLeaseCancel(taskId)
+	if leaseErr != nil && leaseErr.GetType() == errors.NotFound {
+		return err
+	}
+	return leaseErr
+}
+
+// cancelRunningTask cancels the task running in current process
+func cancelRunningTask(taskId uint64) errors.Error {
 	cancel, err := runningTasks.Remove(taskId)
 	if err != nil {
 		return err
@@ -175,7 +189,7 @@ func CancelTask(taskId uint64) errors.Error {
 }
 
 // RunTasksStandalone run tasks in parallel
-func RunTasksStandalone(parentLogger log.Logger, taskIds []uint64) errors.Error {
+func RunTasksStandalone(ctx context.Context, parentLogger log.Logger, taskIds []uint64) errors.Error {
 	if len(taskIds) == 0 {
 		return nil
 	}
@@ -184,7 +198,7 @@ func RunTasksStandalone(parentLogger log.Logger, taskIds []uint64) errors.Error
 		go func(id uint64) {
 			taskLog.Info("run task #%d in background ", id)
 			var err errors.Error
-			taskErr := runTaskStandalone(parentLogger, id)
+			taskErr := runTaskStandalone(ctx, parentLogger, id)
 			if taskErr != nil {
 				err = errors.Default.Wrap(taskErr, fmt.Sprintf("Error running task %d.", id))
 			}
@@ -285,6 +299,15 @@ func GetSubTasksInfo(pipelineId uint64, shouldSanitize bool, tx dal.Dal) (*model
 		if err != nil {
 			return nil, err
 		}
+		metrics := []*models.SubtaskMetric{}
+		err = tx.All(&metrics, dal.Where("task_id = ?", task.ID))
+		if err != nil {
+			return nil, err
+		}
+		metricsByName := make(map[string]*models.SubtaskMetric, len(metrics))
+		for _, metric := range metrics {
+			metricsByName[metric.Name] = metric
+		}
 		for _, subtask := range subtasks {
 			t := &models.SubtaskDetails{
 				ID:              subtask.ID,
@@ -301,6 +324,7 @@ func GetSubTasksInfo(pipelineId uint64, shouldSanitize bool, tx dal.Dal) (*model
 				IsCollector:     subtask.IsCollector,
 				IsFailed:        subtask.IsFailed,
 				Message:         subtask.Message,
+				Metrics:         metricsByName[subtask.Name],
 			}
 			subTaskResult.SubtaskDetails = append(subTaskResult.SubtaskDetails, t)
 		}
@@ -0,0 +1,369 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/helpers/tracinghelper"
+)
+
+const TASK_EXECUTOR_DISTRIBUTED = "distributed"
+
+// taskLeasePollInterval is how often the api server checks the progress of dispatched tasks
+var taskLeasePollInterval = time.Second
+
+// isDistributedTaskExecution returns true if tasks should be executed by workers instead of the api server
+func isDistributedTaskExecution() bool {
+	return strings.EqualFold(cfg.GetString("TASK_EXECUTOR"), TASK_EXECUTOR_DISTRIBUTED)
+}
+
+// getWorkerLeaseTimeout returns the duration after which leases of workers without heartbeats are reclaimed
+func getWorkerLeaseTimeout() time.Duration {
+	timeout := cfg.GetInt("WORKER_LEASE_TIMEOUT")
+	if timeout <= 0 {
+		timeout = 60
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// getTaskLeaseQueueTimeout returns how long a task could wait in the queue before it fails, which happens when
+// no living worker matches its selectors
+func getTaskLeaseQueueTimeout() time.Duration {
+	timeout := cfg.GetInt("TASK_LEASE_QUEUE_TIMEOUT")
+	if timeout <= 0 {
+		timeout = 3600
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// getTaskLeaseMaxAttempts returns how many times a task could be leased, tasks reclaimed from dead workers
+// are not queued again once they reach it, so a task crashing its workers would not be retried forever
+func getTaskLeaseMaxAttempts() int {
+	attempts := cfg.GetInt("TASK_LEASE_MAX_ATTEMPTS")
+	if attempts <= 0 {
+		attempts = 3
+	}
+	return attempts
+}
+
+// getWorkerSelectors extracts worker selectors from labels of the pipeline
+func getWorkerSelectors(labels []string) []string {
+	selectors := make([]string, 0)
+	for _, label := range labels {
+		if strings.HasPrefix(label, models.WORKER_LABEL_PREFIX) {
+			selectors = append(selectors, strings.TrimPrefix(label, models.WORKER_LABEL_PREFIX))
+		}
+	}
+	return selectors
+}
+
+// matchWorkerSelectors checks if a worker with the labels satisfies all selectors
+func matchWorkerSelectors(labels []string, selectors []string) bool {
+	for _, selector := range selectors {
+		if !utils.StringsContains(labels, selector) {
+			return false
+		}
+	}
+	return true
+}
+
+// RunTasksDistributed dispatches tasks to workers and waits until all of them are finished, tasks are
+// executed in parallel just like RunTasksStandalone, and traced under the span carried by ctx. Unfinished tasks
+// are requested to be cancelled if ctx is done.
+func RunTasksDistributed(ctx context.Context, parentLogger log.Logger, pipeline *models.Pipeline, taskIds []uint64) errors.Error {
+	if len(taskIds) == 0 {
+		return nil
+	}
+	selectors := getWorkerSelectors(pipeline.Labels)
+	traceParent := tracinghelper.Inject(ctx)
+	for _, taskId := range taskIds {
+		if err := queueTaskLease(pipeline.ID, taskId, selectors, traceParent); err != nil {
+			return err
+		}
+	}
+	parentLogger.Info("dispatched tasks %v to workers with selectors %v", taskIds, selectors)
+	statuses := make(map[uint64]string, len(taskIds))
+	queuedSince := make(map[uint64]time.Time, len(taskIds))
+	queueTimeout := getTaskLeaseQueueTimeout()
+	var leases []*models.TaskLease
+	for {
+		leases = make([]*models.TaskLease, 0, len(taskIds))
+		err := db.All(&leases, dal.Where("task_id IN ?", taskIds))
+		if err != nil {
+			return err
+		}
+		finished := 0
+		for _, lease := range leases {
+			if lease.Status == models.TASK_LEASE_QUEUED && lease.CancelRequested {
+				if err := cancelQueuedTaskLease(lease); err != nil {
+					return err
+				}
+			}
+			if lease.Status != models.TASK_LEASE_QUEUED {
+				delete(queuedSince, lease.TaskId)
+			} else if since, ok := queuedSince[lease.TaskId]; !ok {
+				queuedSince[lease.TaskId] = time.Now()
+			} else if time.Since(since) > queueTimeout {
+				if err := failQueuedTaskLease(lease, queueTimeout); err != nil {
+					return err
+				}
+			}
+			if statuses[lease.TaskId] != lease.Status {
+				statuses[lease.TaskId] = lease.Status
+				publishTaskLeaseEvent(lease)
+			}
+			if isTaskLeaseFinished(lease.Status) {
+				finished++
+			}
+		}
+		if finished == len(taskIds) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			if err := cancelTaskLeases(taskIds); err != nil {
+				return err
+			}
+			return errors.Default.Wrap(ctx.Err(), fmt.Sprintf("stopped waiting for tasks %v", taskIds))
+		case <-time.After(taskLeasePollInterval):
+		}
+	}
+	// aggregate errors the same way as RunTasksStandalone
+	var sb strings.Builder
+	for _, lease := range leases {
+		switch lease.Status {
+		case models.TASK_LEASE_CANCELLED:
+			parentLogger.Info("task canceled")
+			return errors.Default.Wrap(context.Canceled, fmt.Sprintf("task %d was cancelled", lease.TaskId))
+		case models.TASK_LEASE_FAILED:
+			_, _ = sb.WriteString(fmt.Sprintf("Error running task %d.\n%s\n", lease.TaskId, lease.Message))
+		}
+	}
+	if sb.Len() > 0 {
+		return errors.Default.New(sb.String())
+	}
+	return nil
+}
+
+func isTaskLeaseFinished(status string) bool {
+	return status == models.TASK_LEASE_SUCCEEDED || status == models.TASK_LEASE_FAILED || status == models.TASK_LEASE_CANCELLED
+}
+
+// queueTaskLease puts the task into the queue, leases being executed by living workers are kept as is,
+// which happens when the api server was restarted with RESUME_PIPELINES enabled
+func queueTaskLease(pipelineId, taskId uint64, selectors []string, traceParent string) errors.Error {
+	lease := &models.TaskLease{}
+	err := db.First(lease, dal.Where("task_id = ?", taskId))
+	if err != nil && !db.IsErrorNotFound(err) {
+		return err
+	}
+	if err == nil && (lease.Status == models.TASK_LEASE_QUEUED || lease.Status == models.TASK_LEASE_LEASED) {
+		return nil
+	}
+	return db.CreateOrUpdate(&models.TaskLease{
+		TaskId:      taskId,
+		PipelineId:  pipelineId,
+		Selectors:   selectors,
+		Status:      models.TASK_LEASE_QUEUED,
+		TraceParent: traceParent,
+	})
+}
+
+func cancelQueuedTaskLease(lease *models.TaskLease) errors.Error {
+	err := db.UpdateColumn(&models.Task{}, "status", models.TASK_CANCELLED, dal.Where("id = ?", lease.TaskId))
+	if err != nil {
+		return err
+	}
+	lease.Status = models.TASK_LEASE_CANCELLED
+	return db.UpdateColumn(
+		&models.TaskLease{}, "status", models.TASK_LEASE_CANCELLED,
+		dal.Where("task_id = ? AND status = ?", lease.TaskId, models.TASK_LEASE_QUEUED),
+	)
+}
+
+// failQueuedTaskLease fails the task which was not leased by any worker within the timeout
+func failQueuedTaskLease(lease *models.TaskLease, timeout time.Duration) errors.Error {
+	message := fmt.Sprintf("no worker matching selectors %v leased the task within %s", lease.Selectors, timeout)
+	err := db.UpdateColumns(&models.TaskLease{}, []dal.DalSet{
+		{ColumnName: "status", Value: models.TASK_LEASE_FAILED},
+		{ColumnName: "message", Value: message},
+	}, dal.Where("task_id = ? AND status = ?", lease.TaskId, models.TASK_LEASE_QUEUED))
+	if err != nil {
+		return err
+	}
+	lease.Status, lease.Message = models.TASK_LEASE_FAILED, message
+	return db.UpdateColumns(&models.Task{}, []dal.DalSet{
+		{ColumnName: "status", Value: models.TASK_FAILED},
+		{ColumnName: "message", Value: message},
+	}, dal.Where("id = ?", lease.TaskId))
+}
+
+// cancelTaskLeases requests unfinished leases of the tasks to be cancelled, queued ones are cancelled right away
+// as no worker would lease them anymore
+func cancelTaskLeases(taskIds []uint64) errors.Error {
+	err := db.UpdateColumn(
+		&models.TaskLease{}, "cancel_requested", true,
+		dal.Where("task_id IN ? AND status IN ?", taskIds, []string{models.TASK_LEASE_QUEUED, models.TASK_LEASE_LEASED}),
+	)
+	if err != nil {
+		return err
+	}
+	queued := make([]*models.TaskLease, 0)
+	err = db.All(&queued, dal.Where("task_id IN ? AND status = ?", taskIds, models.TASK_LEASE_QUEUED))
+	if err != nil {
+		return err
+	}
+	for _, lease := range queued {
+		if err := cancelQueuedTaskLease(lease); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishTaskLeaseEvent publishes status changes of dispatched tasks, note that progress of tasks
+// is only available in the worker process
+func publishTaskLeaseEvent(lease *models.TaskLease) {
+	var status string
+	switch lease.Status {
+	case models.TASK_LEASE_QUEUED:
+		return
+	case models.TASK_LEASE_LEASED:
+		status = models.TASK_RUNNING
+	default:
+		task, err := GetTask(lease.TaskId)
+		if err != nil {
+			return
+		}
+		status = task.Status
+	}
+	publishPipelineEvent(&PipelineEvent{
+		Type:       PipelineEventTaskStatus,
+		PipelineId: lease.PipelineId,
+		TaskId:     lease.TaskId,
+		Status:     status,
+	})
+}
+
+// requestTaskLeaseCancel asks the worker executing the task to cancel it, the worker acknowledges it on its next
+// heartbeat by finishing the lease as cancelled, which is observed by RunTasksDistributed. Queued leases are
+// cancelled by RunTasksDistributed directly.
+func requestTaskLeaseCancel(taskId uint64) errors.Error {
+	lease := &models.TaskLease{}
+	err := db.First(lease, dal.Where("task_id = ? AND status IN ?", taskId, []string{models.TASK_LEASE_QUEUED, models.TASK_LEASE_LEASED}))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return errors.NotFound.New(fmt.Sprintf("task with id %d not found", taskId))
+		}
+		return err
+	}
+	return db.UpdateColumn(&models.TaskLease{}, "cancel_requested", true, dal.Where("task_id = ?", taskId))
+}
+
+// isTaskLeaseCancelRequested checks if the task leased by current worker was requested to be cancelled
+func isTaskLeaseCancelRequested(taskId uint64) (bool, errors.Error) {
+	count, err := db.Count(dal.From(&models.TaskLease{}), dal.Where("task_id = ? AND cancel_requested = ?", taskId, true))
+	return count > 0, err
+}
+
+// runTaskLeaseReclaimer reclaims leases of dead workers periodically
+func runTaskLeaseReclaimer() {
+	timeout := getWorkerLeaseTimeout()
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := reclaimTaskLeases(time.Now().Add(-timeout)); err != nil {
+			globalPipelineLog.Error(err, "failed to reclaim task leases")
+		}
+	}
+}
+
+// reclaimTaskLeases puts tasks leased by workers without heartbeats since `deadline` back to the queue, or fails
+// them if they were leased too many times
+func reclaimTaskLeases(deadline time.Time) errors.Error {
+	maxAttempts := getTaskLeaseMaxAttempts()
+	leases := make([]*models.TaskLease, 0)
+	err := db.All(&leases, dal.Where("status = ?", models.TASK_LEASE_LEASED))
+	if err != nil {
+		return err
+	}
+	var aliveWorkerIds []string
+	err = db.Pluck("id", &aliveWorkerIds, dal.From(&models.Worker{}), dal.Where("heartbeat_at >= ?", deadline))
+	if err != nil {
+		return err
+	}
+	alive := make(map[string]bool, len(aliveWorkerIds))
+	for _, id := range aliveWorkerIds {
+		alive[id] = true
+	}
+	for _, lease := range leases {
+		if alive[lease.WorkerId] {
+			continue
+		}
+		if lease.Attempts >= maxAttempts {
+			if err = failReclaimedTaskLease(lease); err != nil {
+				return err
+			}
+			continue
+		}
+		globalPipelineLog.Warn(nil, "reclaiming task #%d from dead worker %s", lease.TaskId, lease.WorkerId)
+		err = db.UpdateColumns(&models.TaskLease{}, []dal.DalSet{
+			{ColumnName: "status", Value: models.TASK_LEASE_QUEUED},
+			{ColumnName: "worker_id", Value: ""},
+			{ColumnName: "leased_at", Value: nil},
+			{ColumnName: "message", Value: fmt.Sprintf("reclaimed from dead worker %s", lease.WorkerId)},
+		}, dal.Where("task_id = ? AND status = ? AND worker_id = ?", lease.TaskId, models.TASK_LEASE_LEASED, lease.WorkerId))
+		if err != nil {
+			return err
+		}
+		err = db.UpdateColumn(
+			&models.Task{}, "status", models.TASK_CREATED,
+			dal.Where("id = ? AND status = ?", lease.TaskId, models.TASK_RUNNING),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return db.Delete(&models.Worker{}, dal.Where("heartbeat_at < ?", deadline))
+}
+
+// failReclaimedTaskLease fails the task of a dead worker instead of queueing it again
+func failReclaimedTaskLease(lease *models.TaskLease) errors.Error {
+	message := fmt.Sprintf("task was leased %d times, the last worker %s died", lease.Attempts, lease.WorkerId)
+	globalPipelineLog.Warn(nil, "failing task #%d: %s", lease.TaskId, message)
+	err := db.UpdateColumns(&models.TaskLease{}, []dal.DalSet{
+		{ColumnName: "status", Value: models.TASK_LEASE_FAILED},
+		{ColumnName: "message", Value: message},
+	}, dal.Where("task_id = ? AND status = ? AND worker_id = ?", lease.TaskId, models.TASK_LEASE_LEASED, lease.WorkerId))
+	if err != nil {
+		return err
+	}
+	return db.UpdateColumns(&models.Task{}, []dal.DalSet{
+		{ColumnName: "status", Value: models.TASK_FAILED},
+		{ColumnName: "message", Value: message},
+	}, dal.Where("id = ? AND status = ?", lease.TaskId, models.TASK_RUNNING))
+}
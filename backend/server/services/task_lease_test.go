@@ -0,0 +1,157 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/impls/dalgorm"
+	"github.com/apache/incubator-devlake/impls/logruslog"
+	"github.com/apache/incubator-devlake/impls/secretprovider"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// useSqliteDb replaces the db of the package with a sqlite one for the test
+func useSqliteDb(t *testing.T, tables ...interface{}) {
+	gormDb, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	origin := db
+	db = dalgorm.NewDalgorm(gormDb)
+	t.Cleanup(func() { db = origin })
+	for _, table := range tables {
+		assert.Nil(t, db.AutoMigrate(table))
+	}
+}
+
+func TestWorkerSelectors(t *testing.T) {
+	selectors := getWorkerSelectors([]string{"parallel/jira", "worker/region-eu", "worker/gpu", "nightly"})
+	assert.Equal(t, []string{"region-eu", "gpu"}, selectors)
+
+	assert.True(t, matchWorkerSelectors([]string{"gpu", "region-eu", "large"}, selectors))
+	assert.False(t, matchWorkerSelectors([]string{"region-eu"}, selectors))
+	assert.True(t, matchWorkerSelectors(nil, []string{}))
+	assert.True(t, matchWorkerSelectors([]string{"gpu"}, nil))
+}
+
+func TestRequestTaskLeaseCancel(t *testing.T) {
+	useSqliteDb(t, &models.TaskLease{})
+	assert.Nil(t, db.Create(&[]*models.TaskLease{
+		{TaskId: 1, Status: models.TASK_LEASE_LEASED, WorkerId: "w1"},
+		{TaskId: 2, Status: models.TASK_LEASE_LEASED, WorkerId: "w1"},
+		{TaskId: 3, Status: models.TASK_LEASE_SUCCEEDED, WorkerId: "w1"},
+	}))
+
+	// returns right away, the worker acknowledges it on its heartbeat
+	assert.Nil(t, requestTaskLeaseCancel(1))
+	cancelRequested, err := isTaskLeaseCancelRequested(1)
+	assert.Nil(t, err)
+	assert.True(t, cancelRequested)
+	cancelRequested, err = isTaskLeaseCancelRequested(2)
+	assert.Nil(t, err)
+	assert.False(t, cancelRequested)
+
+	// finished already
+	err = requestTaskLeaseCancel(3)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.NotFound, err.GetType())
+}
+
+func TestRunTasksDistributed(t *testing.T) {
+	dalgorm.Init(errors.Must1(secretprovider.NewDefaultSecretProvider("secret", nil, false)))
+	useSqliteDb(t, &models.TaskLease{}, &models.Task{})
+	taskLeasePollInterval = 10 * time.Millisecond
+	origin := cfg
+	defer func() { cfg = origin }()
+	v := viper.New()
+	v.Set("TASK_LEASE_QUEUE_TIMEOUT", 1)
+	cfg = v
+	pipeline := &models.Pipeline{Model: common.Model{ID: 1}, Labels: []string{"worker/gpu"}}
+	assert.Nil(t, db.Create(&[]*models.Task{
+		{Model: common.Model{ID: 1}, PipelineId: 1, Status: models.TASK_CREATED},
+		{Model: common.Model{ID: 2}, PipelineId: 1, Status: models.TASK_CREATED},
+	}))
+
+	// no worker has the gpu label
+	err := RunTasksDistributed(context.Background(), logruslog.Global, pipeline, []uint64{1})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no worker matching selectors [gpu]")
+	task, err := GetTask(1)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TASK_FAILED, task.Status)
+
+	// the pipeline was cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = RunTasksDistributed(ctx, logruslog.Global, pipeline, []uint64{2})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	lease := &models.TaskLease{}
+	assert.Nil(t, db.First(lease, dal.Where("task_id = ?", 2)))
+	assert.Equal(t, models.TASK_LEASE_CANCELLED, lease.Status)
+}
+
+func TestReclaimTaskLeases(t *testing.T) {
+	dalgorm.Init(errors.Must1(secretprovider.NewDefaultSecretProvider("secret", nil, false)))
+	useSqliteDb(t, &models.TaskLease{}, &models.Task{}, &models.Worker{})
+	origin := cfg
+	defer func() { cfg = origin }()
+	cfg = viper.New()
+	now := time.Now()
+	assert.Nil(t, db.Create(&[]*models.Worker{
+		{Id: "alive", HeartbeatAt: now},
+		{Id: "dead", HeartbeatAt: now.Add(-time.Hour)},
+	}))
+	assert.Nil(t, db.Create(&[]*models.Task{
+		{Model: common.Model{ID: 1}, PipelineId: 1, Status: models.TASK_RUNNING},
+		{Model: common.Model{ID: 2}, PipelineId: 1, Status: models.TASK_RUNNING},
+		{Model: common.Model{ID: 3}, PipelineId: 1, Status: models.TASK_RUNNING},
+	}))
+	assert.Nil(t, db.Create(&[]*models.TaskLease{
+		{TaskId: 1, Status: models.TASK_LEASE_LEASED, WorkerId: "alive", Attempts: 1},
+		{TaskId: 2, Status: models.TASK_LEASE_LEASED, WorkerId: "dead", Attempts: 1},
+		{TaskId: 3, Status: models.TASK_LEASE_LEASED, WorkerId: "dead", Attempts: 3},
+	}))
+
+	assert.Nil(t, reclaimTaskLeases(now.Add(-time.Minute)))
+	for taskId, status := range map[uint64]string{
+		1: models.TASK_LEASE_LEASED,
+		2: models.TASK_LEASE_QUEUED,
+		3: models.TASK_LEASE_FAILED,
+	} {
+		lease := &models.TaskLease{}
+		assert.Nil(t, db.First(lease, dal.Where("task_id = ?", taskId)))
+		assert.Equal(t, status, lease.Status, "task #%d", taskId)
+	}
+	task, err := GetTask(3)
+	assert.Nil(t, err)
+	assert.Equal(t, models.TASK_FAILED, task.Status)
+	workers, err := db.Count(dal.From(&models.Worker{}))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), workers)
+}
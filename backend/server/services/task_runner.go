@@ -26,12 +26,15 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/runner"
 	"sync"
+	"time"
 )
 
 // RunningTaskData FIXME ...
 type RunningTaskData struct {
 	Cancel         context.CancelFunc
 	ProgressDetail *models.TaskProgressDetail
+	PipelineId     uint64
+	Plugin         string
 }
 
 // RunningTask FIXME ...
@@ -41,16 +44,19 @@ type RunningTask struct {
 }
 
 // Add FIXME ...
-func (rt *RunningTask) Add(taskId uint64, cancel context.CancelFunc) errors.Error {
+func (rt *RunningTask) Add(task *models.Task, cancel context.CancelFunc) errors.Error {
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
-	if _, ok := rt.tasks[taskId]; ok {
-		return errors.Default.New(fmt.Sprintf("task with id %d already running", taskId))
+	if _, ok := rt.tasks[task.ID]; ok {
+		return errors.Default.New(fmt.Sprintf("task with id %d already running", task.ID))
 	}
-	rt.tasks[taskId] = &RunningTaskData{
+	rt.tasks[task.ID] = &RunningTaskData{
 		Cancel:         cancel,
 		ProgressDetail: &models.TaskProgressDetail{},
+		PipelineId:     task.PipelineId,
+		Plugin:         task.Plugin,
 	}
+	runningTasksGauge.WithLabelValues(task.Plugin).Inc()
 	return nil
 }
 
@@ -84,6 +90,7 @@ func (rt *RunningTask) Remove(taskId uint64) (context.CancelFunc, errors.Error)
 	defer rt.mu.Unlock()
 	if d, ok := rt.tasks[taskId]; ok {
 		delete(rt.tasks, taskId)
+		runningTasksGauge.WithLabelValues(d.Plugin).Dec()
 		return d.Cancel, nil
 	}
 	return nil, errors.NotFound.New(fmt.Sprintf("task with id %d not found", taskId))
@@ -96,17 +103,39 @@ func init() {
 	runningTasks.tasks = make(map[uint64]*RunningTaskData)
 }
 
-func runTaskStandalone(parentLog log.Logger, taskId uint64) errors.Error {
+func runTaskStandalone(parentCtx context.Context, parentLog log.Logger, taskId uint64) errors.Error {
 	// deferring cleaning up
 	defer func() {
 		_, _ = runningTasks.Remove(taskId)
 	}()
+	task, err := GetTask(taskId)
+	if err != nil {
+		return err
+	}
 	// for task cancelling
-	ctx, cancel := context.WithCancel(context.Background())
-	err := runningTasks.Add(taskId, cancel)
+	ctx, cancel := context.WithCancel(parentCtx)
+	err = runningTasks.Add(task, cancel)
 	if err != nil {
 		return err
 	}
+	// the heartbeat of the worker could see the cancel request before the task was added, check it again
+	if isLeasedByWorker(taskId) {
+		cancelRequested, e := isTaskLeaseCancelRequested(taskId)
+		if e != nil {
+			return e
+		}
+		if cancelRequested {
+			taskLog.Info("task #%d was requested to be cancelled before started", taskId)
+			cancel()
+		}
+	}
+	publishPipelineEvent(&PipelineEvent{
+		Type:       PipelineEventTaskStatus,
+		PipelineId: task.PipelineId,
+		TaskId:     taskId,
+		Plugin:     task.Plugin,
+		Status:     models.TASK_RUNNING,
+	})
 	// now , create a progress update channel and kick off
 	progress := make(chan plugin.RunningProgress, 100)
 	doneSignal := make(chan struct{})
@@ -120,6 +149,16 @@ func runTaskStandalone(parentLog log.Logger, taskId uint64) errors.Error {
 	close(progress)
 	// wait all progresses are handled
 	<-doneSignal
+	// the final status was decided and persisted by the runner
+	if finishedTask, e := GetTask(taskId); e == nil {
+		publishPipelineEvent(&PipelineEvent{
+			Type:       PipelineEventTaskStatus,
+			PipelineId: finishedTask.PipelineId,
+			TaskId:     taskId,
+			Plugin:     finishedTask.Plugin,
+			Status:     finishedTask.Status,
+		})
+	}
 	return err
 }
 
@@ -136,12 +175,38 @@ func updateTaskProgress(done chan struct{}, taskId uint64, progress chan plugin.
 		return
 	}
 	progressDetail := data.ProgressDetail
+	var lastProgressEventAt time.Time
 	for {
 		p, hasMore := <-progress
 		if hasMore {
 			runningTasks.mu.Lock()
+			finishedSubTask := progressDetail.SubTaskName
 			runner.UpdateProgressDetail(basicRes, taskId, progressDetail, &p)
+			detail := *progressDetail
 			runningTasks.mu.Unlock()
+			event := &PipelineEvent{
+				PipelineId:     data.PipelineId,
+				TaskId:         taskId,
+				Plugin:         data.Plugin,
+				SubTaskName:    detail.SubTaskName,
+				SubTaskNumber:  detail.SubTaskNumber,
+				ProgressDetail: &detail,
+			}
+			switch p.Type {
+			case plugin.SetCurrentSubTask:
+				event.Type = PipelineEventSubtaskStarted
+			case plugin.TaskIncProgress:
+				// the progress of the task is increased right after a subtask was finished
+				event.Type = PipelineEventSubtaskFinished
+				event.SubTaskName = finishedSubTask
+			default:
+				if time.Since(lastProgressEventAt) < pipelineEventProgressInterval {
+					continue
+				}
+				lastProgressEventAt = time.Now()
+				event.Type = PipelineEventProgress
+			}
+			publishPipelineEvent(event)
 		} else {
 			done <- struct{}{}
 			break
@@ -0,0 +1,235 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
+	"github.com/apache/incubator-devlake/helpers/tracinghelper"
+	"github.com/apache/incubator-devlake/impls/logruslog"
+	"golang.org/x/sync/semaphore"
+)
+
+var workerLog = logruslog.Global.Nested("worker")
+
+// workerPollInterval is how often an idle worker checks the queue
+var workerPollInterval = time.Second
+
+// leases being executed by current worker process
+var workerLeases = struct {
+	sync.Mutex
+	taskIds map[uint64]bool
+}{taskIds: make(map[uint64]bool)}
+
+// RunWorker leases tasks dispatched by the api server and executes them, it never returns.
+// The worker could be configured by:
+//
+//	WORKER_LABELS: comma separated capabilities, tasks of pipelines labeled `worker/<label>` require the label
+//	WORKER_CONCURRENCY: max number of tasks to be executed in parallel, default 4
+//	WORKER_HEARTBEAT_INTERVAL: seconds between heartbeats, must be less than WORKER_LEASE_TIMEOUT of the api server
+func RunWorker() {
+	worker := errors.Must1(registerWorker())
+	workerLog.Info("worker %s started with labels %v", worker.Id, worker.Labels)
+	go runWorkerHeartbeat(worker)
+	go shutdownWorkerOnSignal(worker)
+	sema := semaphore.NewWeighted(int64(worker.Concurrency))
+	for {
+		errors.Must(sema.Acquire(context.TODO(), 1))
+		var lease *models.TaskLease
+		for {
+			var err errors.Error
+			lease, err = leaseTask(worker)
+			if err != nil {
+				workerLog.Error(err, "lease task failed")
+			}
+			if lease != nil {
+				break
+			}
+			time.Sleep(workerPollInterval)
+		}
+		go func(lease *models.TaskLease) {
+			defer sema.Release(1)
+			executeTaskLease(worker, lease)
+		}(lease)
+	}
+}
+
+// shutdownWorkerOnSignal flushes the pending spans and exits on SIGINT/SIGTERM, leases of the running tasks
+// are reclaimed by the api server once the heartbeats stop
+func shutdownWorkerOnSignal(worker *models.Worker) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	<-sigc
+	workerLog.Info("worker %s is shutting down", worker.Id)
+	Shutdown()
+	os.Exit(0)
+}
+
+func registerWorker() (*models.Worker, errors.Error) {
+	hostName, e := os.Hostname()
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	suffix, err := utils.RandLetterBytes(6)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, 0)
+	for _, label := range strings.Split(cfg.GetString("WORKER_LABELS"), ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	concurrency := cfg.GetInt("WORKER_CONCURRENCY")
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	now := time.Now()
+	worker := &models.Worker{
+		Id:          fmt.Sprintf("%s-%d-%s", hostName, os.Getpid(), suffix),
+		HostName:    hostName,
+		Labels:      labels,
+		Concurrency: concurrency,
+		StartedAt:   now,
+		HeartbeatAt: now,
+	}
+	return worker, db.Create(worker)
+}
+
+// runWorkerHeartbeat keeps the leases of the worker alive, and cancels tasks which were
+// requested to be cancelled or reclaimed by the api server
+func runWorkerHeartbeat(worker *models.Worker) {
+	interval := cfg.GetInt("WORKER_HEARTBEAT_INTERVAL")
+	if interval <= 0 {
+		interval = 10
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		worker.HeartbeatAt = time.Now()
+		// the record would be deleted if the worker was considered dead, so create it again
+		if err := db.CreateOrUpdate(worker); err != nil {
+			workerLog.Error(err, "failed to send heartbeat")
+			continue
+		}
+		leases := make([]*models.TaskLease, 0)
+		err := db.All(&leases, dal.Where("worker_id = ? AND status = ?", worker.Id, models.TASK_LEASE_LEASED))
+		if err != nil {
+			workerLog.Error(err, "failed to load leases")
+			continue
+		}
+		owned := make(map[uint64]bool, len(leases))
+		for _, lease := range leases {
+			owned[lease.TaskId] = !lease.CancelRequested
+		}
+		workerLeases.Lock()
+		for taskId := range workerLeases.taskIds {
+			if owned[taskId] {
+				continue
+			}
+			workerLog.Info("cancelling task #%d as it was cancelled or reclaimed", taskId)
+			// the task might not be running yet, runTaskStandalone would check the cancel request after it is added
+			_ = cancelRunningTask(taskId)
+		}
+		workerLeases.Unlock()
+	}
+}
+
+// isLeasedByWorker checks if the task is leased by current worker process
+func isLeasedByWorker(taskId uint64) bool {
+	workerLeases.Lock()
+	defer workerLeases.Unlock()
+	return workerLeases.taskIds[taskId]
+}
+
+// leaseTask picks the earliest queued task matching labels of the worker
+func leaseTask(worker *models.Worker) (lease *models.TaskLease, err errors.Error) {
+	txHelper := dbhelper.NewTxHelper(basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+	errors.Must(tx.LockTables(dal.LockTables{{Table: "_devlake_task_leases", Exclusive: true}}))
+	candidates := make([]*models.TaskLease, 0)
+	errors.Must(tx.All(
+		&candidates,
+		dal.Where("status = ? AND cancel_requested = ?", models.TASK_LEASE_QUEUED, false),
+		dal.Orderby("created_at ASC, task_id ASC"),
+		dal.Limit(100),
+	))
+	for _, candidate := range candidates {
+		if !matchWorkerSelectors(worker.Labels, candidate.Selectors) {
+			continue
+		}
+		now := time.Now()
+		errors.Must(tx.UpdateColumns(&models.TaskLease{}, []dal.DalSet{
+			{ColumnName: "status", Value: models.TASK_LEASE_LEASED},
+			{ColumnName: "worker_id", Value: worker.Id},
+			{ColumnName: "leased_at", Value: now},
+			{ColumnName: "attempts", Value: dal.Expr("attempts + 1")},
+		}, dal.Where("task_id = ?", candidate.TaskId)))
+		candidate.Status = models.TASK_LEASE_LEASED
+		candidate.WorkerId = worker.Id
+		candidate.LeasedAt = &now
+		return candidate, nil
+	}
+	return nil, nil
+}
+
+func executeTaskLease(worker *models.Worker, lease *models.TaskLease) {
+	workerLeases.Lock()
+	workerLeases.taskIds[lease.TaskId] = true
+	workerLeases.Unlock()
+	defer func() {
+		workerLeases.Lock()
+		delete(workerLeases.taskIds, lease.TaskId)
+		workerLeases.Unlock()
+	}()
+	workerLog.Info("executing task #%d of pipeline #%d", lease.TaskId, lease.PipelineId)
+	pipeline, err := GetDbPipeline(lease.PipelineId)
+	if err == nil {
+		// continue the trace of the pipeline, so spans and logs of the task share its trace id
+		ctx := tracinghelper.Extract(context.Background(), lease.TraceParent)
+		err = runTaskStandalone(ctx, GetPipelineLogger(pipeline), lease.TaskId)
+	}
+	status, message := models.TASK_LEASE_SUCCEEDED, ""
+	if err != nil {
+		status, message = models.TASK_LEASE_FAILED, err.Error()
+		if errors.Is(err, context.Canceled) {
+			status = models.TASK_LEASE_CANCELLED
+		}
+	}
+	// the lease might be reclaimed by the api server in the meantime, leave it alone
+	err = db.UpdateColumns(&models.TaskLease{}, []dal.DalSet{
+		{ColumnName: "status", Value: status},
+		{ColumnName: "message", Value: message},
+	}, dal.Where("task_id = ? AND worker_id = ? AND status = ?", lease.TaskId, worker.Id, models.TASK_LEASE_LEASED))
+	if err != nil {
+		workerLog.Error(err, "failed to finish lease of task #%d", lease.TaskId)
+	}
+}
@@ -36,6 +36,10 @@ const starrocks = [
         order_by: {},
         extra: {}, // will append to create table sql
         domain_layer: '', // priority over tables
+        sink_type: 'starrocks', // starrocks, clickhouse or parquet
+        incremental: false, // export rows updated after the last export by update_column, propagate deletes and schema changes
+        clickhouse: { url: '', user: '', password: '', database: '' }, // used when sink_type is clickhouse
+        parquet: { dir: '' }, // used when sink_type is parquet
       },
     },
   ],